@@ -0,0 +1,281 @@
+// Package cmd contains the CLI commands for cdev.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/brianly1003/cdev/internal/app"
+	"github.com/brianly1003/cdev/internal/config"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/brianly1003/cdev/internal/rpc/transport"
+	"github.com/brianly1003/cdev/internal/server/mcp"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mcpStdio  bool
+	mcpMode   string
+	mcpClient string
+)
+
+// mcpCmd serves the cdev MCP tools to an agent over stdin/stdout.
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve cdev as an MCP server for Claude Code and Codex",
+	Long: `Serve cdev as a Model Context Protocol server over stdin/stdout, so agents
+can list workspaces, read their files, git status, diff and log, look up
+agent session history and create agent tasks.
+
+The running daemon also serves MCP over streamable HTTP at /mcp. The
+permission policy applies to every tool call: tools are named
+mcp__cdev__<tool>, so mcp rules of the policy match them.
+
+Modes:
+  auto        Proxy to the running daemon, or run in-process if none is running
+  proxy       Proxy to the running daemon; fail if it is not running
+  in-process  Run cdev inside this process without listening on a port
+
+Examples:
+  cdev mcp install
+  cdev mcp --stdio
+  claude mcp add cdev -- cdev mcp --stdio`,
+	RunE: runMCP,
+}
+
+// mcpInstallCmd registers cdev in the MCP configuration of agents.
+var mcpInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Register cdev as an MCP server for Claude Code and Codex",
+	RunE:  runMCPInstall,
+}
+
+// mcpUninstallCmd removes cdev from the MCP configuration of agents.
+var mcpUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove cdev from the MCP servers of Claude Code and Codex",
+	RunE:  runMCPUninstall,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+	mcpCmd.AddCommand(mcpInstallCmd)
+	mcpCmd.AddCommand(mcpUninstallCmd)
+
+	mcpCmd.Flags().BoolVar(&mcpStdio, "stdio", false, "serve over stdin/stdout (required)")
+	mcpCmd.Flags().StringVar(&mcpMode, "mode", rpcModeAuto, "auto, proxy or in-process")
+	mcpCmd.Flags().StringVar(&repoPath, "repo", "", "path to repository for in-process mode (default: current directory)")
+	mcpCmd.Flags().BoolVar(&verbose, "verbose", false, "enable verbose/debug logging output")
+
+	for _, c := range []*cobra.Command{mcpInstallCmd, mcpUninstallCmd} {
+		c.Flags().StringVar(&mcpClient, "client", "all", "claude, codex or all")
+	}
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	if !mcpStdio {
+		return fmt.Errorf("--stdio is required: the daemon serves HTTP at /mcp")
+	}
+	if mcpMode != rpcModeAuto && mcpMode != rpcModeProxy && mcpMode != rpcModeInProcess {
+		return fmt.Errorf("unknown mode %q: expected auto, proxy or in-process", mcpMode)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if repoPath != "" {
+		cfg.Repository.Path = repoPath
+	}
+	if err := config.Validate(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	setupLogging(cfg)
+
+	stdio := transport.NewStdioTransport()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if mcpMode != rpcModeInProcess {
+		if daemonRunning(cfg) {
+			log.Info().Str("url", daemonHTTPURL(cfg, "/mcp")).Msg("proxying MCP to the running daemon")
+			return proxyMCP(ctx, cfg, stdio, &http.Client{})
+		}
+		if mcpMode == rpcModeProxy {
+			return fmt.Errorf("cdev server is not running on %s:%d — start it first with: cdev start", cfg.Server.Host, cfg.Server.Port)
+		}
+	}
+
+	log.Info().Str("repo", cfg.Repository.Path).Msg("serving MCP in-process")
+	// Claude must never take over the terminal: stdout carries the MCP stream
+	cfg.Server.Headless = true
+	application, err := app.New(cfg, version)
+	if err != nil {
+		return fmt.Errorf("failed to create application: %w", err)
+	}
+	application.SetLocalMCPTransport(stdio)
+	return application.Start(ctx)
+}
+
+// proxyMCP posts each message read from stdio to the daemon's /mcp endpoint
+// and writes the responses back. Messages are posted concurrently, so a tool
+// call waiting for a permission decision does not hold up the others.
+func proxyMCP(ctx context.Context, cfg *config.Config, stdio transport.Transport, client *http.Client) error {
+	url := daemonHTTPURL(cfg, "/mcp")
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		data, err := stdio.Read(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, transport.ErrTransportClosed) || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := postMCP(ctx, cfg, client, url, data)
+			if err != nil {
+				log.Warn().Err(err).Msg("MCP request to the daemon failed")
+				resp = mcpErrorResponse(data, err)
+			}
+			if resp == nil {
+				return
+			}
+			if err := stdio.Write(ctx, resp); err != nil {
+				log.Debug().Err(err).Msg("failed to write MCP response")
+			}
+		}()
+	}
+}
+
+// postMCP sends one message to the daemon. It returns nil for accepted
+// notifications.
+func postMCP(ctx context.Context, cfg *config.Config, client *http.Client, url string, data []byte) ([]byte, error) {
+	// Tokens are short-lived and the daemon checks every request, so each
+	// request gets a fresh one
+	header, err := daemonAuthHeader(cfg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return bytes.TrimSpace(body), nil
+	case http.StatusAccepted:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("server returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+}
+
+// mcpErrorResponse answers a request the daemon could not, so the agent
+// does not wait forever. Notifications and batches get no answer.
+func mcpErrorResponse(data []byte, err error) []byte {
+	req, parseErr := message.ParseRequest(data)
+	if parseErr != nil || req.IsNotification() {
+		return nil
+	}
+	resp, marshalErr := json.Marshal(message.NewErrorResponse(req.ID, message.ErrInternalError(err.Error())))
+	if marshalErr != nil {
+		return nil
+	}
+	return resp
+}
+
+// mcpClients returns the clients selected by --client.
+func mcpClients() ([]string, error) {
+	switch mcpClient {
+	case "all":
+		return []string{mcp.ClientClaude, mcp.ClientCodex}, nil
+	case mcp.ClientClaude, mcp.ClientCodex:
+		return []string{mcpClient}, nil
+	default:
+		return nil, fmt.Errorf("unknown client %q: expected claude, codex or all", mcpClient)
+	}
+}
+
+// newMCPInstaller returns an installer registering this binary.
+func newMCPInstaller() (*mcp.Installer, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find home directory: %w", err)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cdev binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return mcp.NewInstaller(homeDir, exe), nil
+}
+
+func runMCPInstall(cmd *cobra.Command, args []string) error {
+	clients, err := mcpClients()
+	if err != nil {
+		return err
+	}
+	installer, err := newMCPInstaller()
+	if err != nil {
+		return err
+	}
+	for _, client := range clients {
+		if err := installer.Install(client); err != nil {
+			return fmt.Errorf("failed to register cdev for %s: %w", client, err)
+		}
+		fmt.Printf("registered cdev MCP server for %s in %s\n", client, installer.ConfigPath(client))
+	}
+	fmt.Println("restart running agents to pick it up")
+	return nil
+}
+
+func runMCPUninstall(cmd *cobra.Command, args []string) error {
+	clients, err := mcpClients()
+	if err != nil {
+		return err
+	}
+	installer, err := newMCPInstaller()
+	if err != nil {
+		return err
+	}
+	for _, client := range clients {
+		if err := installer.Uninstall(client); err != nil {
+			return fmt.Errorf("failed to remove cdev for %s: %w", client, err)
+		}
+		fmt.Printf("removed cdev MCP server for %s from %s\n", client, installer.ConfigPath(client))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/config"
+	"github.com/brianly1003/cdev/internal/rpc/transport"
+)
+
+func TestProxyMCP(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		body, _ := io.ReadAll(r.Body)
+		if countRequests(body) == 0 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	}))
+	defer daemon.Close()
+
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(daemon.URL, "http://"))
+	cfg := &config.Config{}
+	cfg.Server.Host = host
+	cfg.Server.Port, _ = strconv.Atoi(port)
+
+	input := `{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"
+	var output bytes.Buffer
+	stdio := transport.NewStdioTransportWithIO(strings.NewReader(input), &output)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := proxyMCP(ctx, cfg, stdio, daemon.Client()); err != nil {
+		t.Fatalf("proxyMCP() error = %v", err)
+	}
+
+	if got := strings.TrimSpace(output.String()); got != `{"jsonrpc":"2.0","id":1,"result":{}}` {
+		t.Errorf("output = %q, want only the ping response", got)
+	}
+	if len(paths) != 2 || paths[0] != "/mcp" {
+		t.Errorf("posted to %v, want /mcp twice", paths)
+	}
+}
+
+func TestMCPErrorResponse(t *testing.T) {
+	resp := mcpErrorResponse([]byte(`{"jsonrpc":"2.0","id":7,"method":"tools/call"}`), io.ErrUnexpectedEOF)
+	if !strings.Contains(string(resp), `"id":7`) || !strings.Contains(string(resp), `"error"`) {
+		t.Errorf("response = %s, want an error for id 7", resp)
+	}
+	if resp := mcpErrorResponse([]byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`), io.ErrUnexpectedEOF); resp != nil {
+		t.Errorf("notification answered with %s", resp)
+	}
+}
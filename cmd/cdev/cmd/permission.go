@@ -0,0 +1,223 @@
+// Package cmd contains the CLI commands for cdev.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/config"
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/spf13/cobra"
+)
+
+var (
+	simulateWorkspaces []string
+	simulateSince      string
+	simulateAgents     []string
+	simulateAll        bool
+	simulateLimit      int
+	simulateJSON       bool
+)
+
+// permissionCmd groups permission utilities.
+var permissionCmd = &cobra.Command{
+	Use:   "permission",
+	Short: "Permission policy utilities",
+}
+
+// permissionSimulateCmd replays session history through the current policies.
+var permissionSimulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Dry-run permission policies against past sessions",
+	Long: `Replay the tool calls of past Claude sessions and Codex rollouts through the
+current permission policies and workspace rules, and report which calls would
+have been allowed, denied or prompted.
+
+Nothing is asked, remembered or audited, and session memory does not apply.
+Use it to check a policy change before rolling it out.
+
+Examples:
+  cdev permission simulate                          # All configured workspaces
+  cdev permission simulate --workspace cdev         # One workspace (ID, name or path)
+  cdev permission simulate --since 168h --agent codex
+  cdev permission simulate --all --json             # List allowed calls too, as JSON`,
+	RunE: runPermissionSimulate,
+}
+
+func init() {
+	rootCmd.AddCommand(permissionCmd)
+	permissionCmd.AddCommand(permissionSimulateCmd)
+
+	permissionSimulateCmd.Flags().StringArrayVarP(&simulateWorkspaces, "workspace", "w", nil, "workspace ID, name or path to replay (repeatable; default all)")
+	permissionSimulateCmd.Flags().StringVar(&simulateSince, "since", "", "only replay calls newer than a duration (e.g. 72h) or an RFC3339 time")
+	permissionSimulateCmd.Flags().StringArrayVar(&simulateAgents, "agent", nil, "session type to replay: claude or codex (repeatable; default both)")
+	permissionSimulateCmd.Flags().BoolVar(&simulateAll, "all", false, "list allowed calls too")
+	permissionSimulateCmd.Flags().IntVar(&simulateLimit, "limit", 50, "calls listed per workspace (0 for no limit)")
+	permissionSimulateCmd.Flags().BoolVar(&simulateJSON, "json", false, "output the report as JSON")
+}
+
+func runPermissionSimulate(cmd *cobra.Command, args []string) error {
+	opts := permission.HistoryOptions{
+		AgentTypes:     simulateAgents,
+		IncludeAllowed: simulateAll,
+		Limit:          simulateLimit,
+	}
+	for _, agent := range simulateAgents {
+		if agent != permission.HistoryAgentClaude && agent != permission.HistoryAgentCodex {
+			return fmt.Errorf("unknown agent %q: expected claude or codex", agent)
+		}
+	}
+	if simulateLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	if simulateSince != "" {
+		since, err := parseSince(simulateSince)
+		if err != nil {
+			return err
+		}
+		opts.Since = since
+	}
+
+	workspaces, err := simulateTargets(simulateWorkspaces)
+	if err != nil {
+		return err
+	}
+
+	rules, err := permission.LoadRuleStore(config.DefaultPermissionRulesPath())
+	if err != nil {
+		return fmt.Errorf("failed to load permission rules: %w", err)
+	}
+	paths := make(map[string]string, len(workspaces))
+	for _, ws := range workspaces {
+		paths[ws.ID] = ws.Path
+	}
+	policy := permission.NewPolicyEngine(
+		config.DefaultPermissionPolicyPath(),
+		config.DefaultWorkspacePolicyDir(),
+		func(workspaceID string) string { return paths[workspaceID] },
+	)
+
+	report := permission.NewSimulator(policy, rules).Replay(workspaces, opts)
+	if simulateJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	printSimulationReport(report)
+	return nil
+}
+
+// parseSince accepts a duration before now or an RFC3339 time.
+func parseSince(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: expected a duration (72h) or an RFC3339 time", value)
+}
+
+// simulateTargets resolves the workspaces to replay from the workspace
+// registry. Without selectors every configured workspace is replayed.
+func simulateTargets(selectors []string) ([]permission.HistoryWorkspace, error) {
+	registryPath := config.DefaultWorkspacesPath()
+	var definitions []config.WorkspaceDefinition
+	if _, err := os.Stat(registryPath); err == nil {
+		cfg, err := config.LoadWorkspaces(registryPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load workspaces: %w", err)
+		}
+		definitions = cfg.Workspaces
+	}
+
+	if len(selectors) == 0 {
+		if len(definitions) == 0 {
+			return nil, fmt.Errorf("no workspaces configured; pass --workspace with a repository path")
+		}
+		workspaces := make([]permission.HistoryWorkspace, 0, len(definitions))
+		for _, def := range definitions {
+			workspaces = append(workspaces, permission.HistoryWorkspace{ID: def.ID, Path: def.Path})
+		}
+		return workspaces, nil
+	}
+
+	workspaces := make([]permission.HistoryWorkspace, 0, len(selectors))
+	for _, selector := range selectors {
+		ws, ok := matchWorkspace(definitions, selector)
+		if !ok {
+			// An unregistered repository: only global policies apply
+			abs, err := filepath.Abs(selector)
+			if err != nil {
+				return nil, err
+			}
+			if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+				return nil, fmt.Errorf("workspace not found: %s", selector)
+			}
+			ws = permission.HistoryWorkspace{Path: abs}
+		}
+		workspaces = append(workspaces, ws)
+	}
+	return workspaces, nil
+}
+
+func matchWorkspace(definitions []config.WorkspaceDefinition, selector string) (permission.HistoryWorkspace, bool) {
+	abs, _ := filepath.Abs(selector)
+	for _, def := range definitions {
+		if def.ID == selector || strings.EqualFold(def.Name, selector) || filepath.Clean(def.Path) == abs {
+			return permission.HistoryWorkspace{ID: def.ID, Path: def.Path}, true
+		}
+	}
+	return permission.HistoryWorkspace{}, false
+}
+
+func printSimulationReport(report *permission.SimulationReport) {
+	for _, ws := range report.Workspaces {
+		fmt.Printf("%s (%s)\n", ws.Path, workspaceLabel(ws.WorkspaceID))
+		fmt.Printf("  %d sessions, %d calls: %d allowed, %d denied, %d prompted\n",
+			ws.Sessions, ws.Total, ws.Allowed, ws.Denied, ws.Prompted)
+		if len(ws.Calls) > 0 {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, call := range ws.Calls {
+				decidedBy := call.Source
+				if call.Rule != "" {
+					decidedBy += " " + call.Rule
+				}
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n",
+					formatSimulatedTime(call.Timestamp),
+					call.Decision,
+					decidedBy,
+					call.AgentType,
+					call.Risk,
+					permission.GenerateReadableDescription(call.ToolName, call.ToolInput),
+				)
+			}
+			_ = w.Flush()
+		}
+		if ws.Truncated {
+			fmt.Printf("  ... more calls not listed (raise --limit)\n")
+		}
+		fmt.Println()
+	}
+	fmt.Printf("total: %d calls, %d allowed, %d denied, %d prompted\n",
+		report.Totals.Total, report.Totals.Allowed, report.Totals.Denied, report.Totals.Prompted)
+}
+
+func workspaceLabel(id string) string {
+	if id == "" {
+		return "unregistered"
+	}
+	return id
+}
+
+func formatSimulatedTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,318 @@
+// Package cmd contains the CLI commands for cdev.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/app"
+	"github.com/brianly1003/cdev/internal/config"
+	"github.com/brianly1003/cdev/internal/rpc/transport"
+	"github.com/brianly1003/cdev/internal/security"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// Modes of `cdev rpc`.
+const (
+	rpcModeAuto      = "auto"
+	rpcModeProxy     = "proxy"
+	rpcModeInProcess = "in-process"
+)
+
+var (
+	rpcStdio bool
+	rpcLSP   bool
+	rpcMode  string
+)
+
+// rpcCmd serves JSON-RPC to a local tool over stdin/stdout.
+var rpcCmd = &cobra.Command{
+	Use:   "rpc",
+	Short: "Serve JSON-RPC over stdin/stdout for editors and scripts",
+	Long: `Serve the cdev JSON-RPC API over stdin/stdout. Requests are read from stdin,
+responses and event notifications (event/*) are written to stdout, logs go
+to stderr. No network port is opened and no token is needed.
+
+Messages are newline-delimited JSON by default, or framed with
+Content-Length headers like the Language Server Protocol with --lsp.
+
+Modes:
+  auto        Proxy to the running daemon, or run in-process if none is running
+  proxy       Proxy to the running daemon; fail if it is not running
+  in-process  Run cdev inside this process without listening on a port
+
+The proxy signs in with a token minted from the local token secret, so it
+needs read access to ~/.cdev like the daemon itself.
+
+Examples:
+  cdev rpc --stdio
+  cdev rpc --stdio --lsp
+  cdev rpc --stdio --mode in-process --repo /path/to/project
+  echo '{"jsonrpc":"2.0","id":1,"method":"status/get"}' | cdev rpc --stdio`,
+	RunE: runRPC,
+}
+
+func init() {
+	rootCmd.AddCommand(rpcCmd)
+
+	rpcCmd.Flags().BoolVar(&rpcStdio, "stdio", false, "serve over stdin/stdout (required)")
+	rpcCmd.Flags().BoolVar(&rpcLSP, "lsp", false, "use LSP Content-Length framing instead of newline-delimited JSON")
+	rpcCmd.Flags().StringVar(&rpcMode, "mode", rpcModeAuto, "auto, proxy or in-process")
+	rpcCmd.Flags().StringVar(&repoPath, "repo", "", "path to repository for in-process mode (default: current directory)")
+	rpcCmd.Flags().BoolVar(&verbose, "verbose", false, "enable verbose/debug logging output")
+}
+
+func runRPC(cmd *cobra.Command, args []string) error {
+	if !rpcStdio {
+		return fmt.Errorf("--stdio is required: it is the only transport of cdev rpc")
+	}
+	if rpcMode != rpcModeAuto && rpcMode != rpcModeProxy && rpcMode != rpcModeInProcess {
+		return fmt.Errorf("unknown mode %q: expected auto, proxy or in-process", rpcMode)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if repoPath != "" {
+		cfg.Repository.Path = repoPath
+	}
+	if err := config.Validate(cfg); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	setupLogging(cfg)
+
+	mode := transport.StdioModeNewline
+	if rpcLSP {
+		mode = transport.StdioModeLSP
+	}
+	stdio := transport.NewStdioTransport(transport.WithStdioMode(mode))
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if rpcMode != rpcModeInProcess {
+		if daemonRunning(cfg) {
+			log.Info().Str("url", daemonWebSocketURL(cfg)).Msg("proxying stdio to the running daemon")
+			return proxyRPC(ctx, cfg, stdio)
+		}
+		if rpcMode == rpcModeProxy {
+			return fmt.Errorf("cdev server is not running on %s:%d — start it first with: cdev start", cfg.Server.Host, cfg.Server.Port)
+		}
+	}
+
+	log.Info().Str("repo", cfg.Repository.Path).Msg("serving stdio in-process")
+	// Claude must never take over the terminal: stdout carries the RPC stream
+	cfg.Server.Headless = true
+	application, err := app.New(cfg, version)
+	if err != nil {
+		return fmt.Errorf("failed to create application: %w", err)
+	}
+	application.SetLocalTransport(stdio)
+	return application.Start(ctx)
+}
+
+// daemonHost returns the host to dial for the daemon's listener.
+func daemonHost(cfg *config.Config) string {
+	host := strings.TrimSpace(cfg.Server.Host)
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		return "127.0.0.1"
+	}
+	return host
+}
+
+// daemonWebSocketURL returns the daemon's JSON-RPC WebSocket URL.
+func daemonWebSocketURL(cfg *config.Config) string {
+	scheme := "ws"
+	if cfg.Security.TLSCertFile != "" && cfg.Security.TLSKeyFile != "" {
+		scheme = "wss"
+	}
+	return fmt.Sprintf("%s://%s:%d/ws", scheme, daemonHost(cfg), cfg.Server.Port)
+}
+
+// daemonHTTPURL returns the URL of an HTTP endpoint of the daemon.
+func daemonHTTPURL(cfg *config.Config, path string) string {
+	scheme := "http"
+	if cfg.Security.TLSCertFile != "" && cfg.Security.TLSKeyFile != "" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s:%d%s", scheme, daemonHost(cfg), cfg.Server.Port, path)
+}
+
+// daemonRunning reports whether the daemon answers its health endpoint.
+func daemonRunning(cfg *config.Config) bool {
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(daemonHTTPURL(cfg, "/health"))
+	if err != nil {
+		return false
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return resp.StatusCode == http.StatusOK
+}
+
+// proxyRPC connects to the daemon's WebSocket and relays messages between
+// it and stdio until either side closes.
+func proxyRPC(ctx context.Context, cfg *config.Config, stdio transport.Transport) error {
+	header, err := daemonAuthHeader(cfg)
+	if err != nil {
+		return err
+	}
+
+	dialer := websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	conn, _, err := dialer.DialContext(ctx, daemonWebSocketURL(cfg), header)
+	if err != nil {
+		return fmt.Errorf("failed to connect to server: %w", err)
+	}
+	ws := transport.NewWebSocketTransport(conn)
+	defer func() { _ = ws.Close() }()
+
+	return relayRPC(ctx, stdio, ws)
+}
+
+// daemonAuthHeader returns the headers that sign a request in to the
+// daemon, if it requires authentication.
+func daemonAuthHeader(cfg *config.Config) (http.Header, error) {
+	header := http.Header{}
+	if !cfg.Security.RequireAuth {
+		return header, nil
+	}
+	// Same secret as the daemon, so the token is accepted like a paired device's
+	tokenManager, err := security.NewTokenManager(cfg.Security.TokenExpirySecs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load token secret: %w", err)
+	}
+	token, _, err := tokenManager.GenerateAccessToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access token: %w", err)
+	}
+	header.Set("Authorization", "Bearer "+token)
+	return header, nil
+}
+
+// relayRPC copies requests from local to remote and everything from remote
+// to local. When local reaches EOF, it keeps relaying until the requests
+// already sent are answered, so piping a script into cdev rpc works.
+func relayRPC(ctx context.Context, local, remote transport.Transport) error {
+	var (
+		mu      sync.Mutex
+		pending int
+		eof     bool
+	)
+	finished := make(chan struct{})
+	var finishOnce sync.Once
+	finish := func() { finishOnce.Do(func() { close(finished) }) }
+	errc := make(chan error, 2)
+
+	go func() {
+		for {
+			data, err := local.Read(ctx)
+			if err != nil {
+				if !errors.Is(err, io.EOF) && !errors.Is(err, transport.ErrTransportClosed) {
+					errc <- err
+					return
+				}
+				mu.Lock()
+				eof = true
+				done := pending <= 0
+				mu.Unlock()
+				if done {
+					finish()
+				}
+				return
+			}
+			mu.Lock()
+			pending += countRequests(data)
+			mu.Unlock()
+			if err := remote.Write(ctx, data); err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	go func() {
+		for {
+			data, err := remote.Read(ctx)
+			if err != nil {
+				if errors.Is(err, io.EOF) || errors.Is(err, transport.ErrTransportClosed) {
+					err = fmt.Errorf("server closed the connection")
+				}
+				errc <- err
+				return
+			}
+			if err := local.Write(ctx, data); err != nil {
+				errc <- err
+				return
+			}
+			mu.Lock()
+			pending -= countResponses(data)
+			done := eof && pending <= 0
+			mu.Unlock()
+			if done {
+				finish()
+			}
+		}
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// rpcEnvelope holds the fields that tell requests, notifications and
+// responses apart.
+type rpcEnvelope struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+// countRequests returns how many messages of a frame expect a response.
+func countRequests(data []byte) int {
+	count := 0
+	for _, msg := range rpcEnvelopes(data) {
+		if msg.Method != "" && len(msg.ID) > 0 && string(msg.ID) != "null" {
+			count++
+		}
+	}
+	return count
+}
+
+// countResponses returns how many messages of a frame are responses.
+func countResponses(data []byte) int {
+	count := 0
+	for _, msg := range rpcEnvelopes(data) {
+		if msg.Method == "" && len(msg.ID) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// rpcEnvelopes decodes a single message or a batch.
+func rpcEnvelopes(data []byte) []rpcEnvelope {
+	var batch []rpcEnvelope
+	if err := json.Unmarshal(data, &batch); err == nil {
+		return batch
+	}
+	var msg rpcEnvelope
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil
+	}
+	return []rpcEnvelope{msg}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/rpc/transport"
+)
+
+func TestCountRequestsAndResponses(t *testing.T) {
+	tests := []struct {
+		data      string
+		requests  int
+		responses int
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"status/get"}`, 1, 0},
+		{`{"jsonrpc":"2.0","method":"event/heartbeat","params":{}}`, 0, 0},
+		{`{"jsonrpc":"2.0","id":1,"result":{}}`, 0, 1},
+		{`{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`, 0, 1},
+		{`[{"jsonrpc":"2.0","id":1,"method":"a"},{"jsonrpc":"2.0","method":"b"},{"jsonrpc":"2.0","id":"x","method":"c"}]`, 2, 0},
+		{`not json`, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := countRequests([]byte(tt.data)); got != tt.requests {
+			t.Errorf("countRequests(%s) = %d, want %d", tt.data, got, tt.requests)
+		}
+		if got := countResponses([]byte(tt.data)); got != tt.responses {
+			t.Errorf("countResponses(%s) = %d, want %d", tt.data, got, tt.responses)
+		}
+	}
+}
+
+func TestRelayRPC_WaitsForResponsesAtEOF(t *testing.T) {
+	input := `{"jsonrpc":"2.0","id":1,"method":"status/get"}` + "\n" +
+		`{"jsonrpc":"2.0","method":"client/ping"}` + "\n"
+	var output bytes.Buffer
+	local := transport.NewStdioTransportWithIO(strings.NewReader(input), &output)
+
+	// The fake daemon answers requests after a delay and sends an event first
+	daemonInR, daemonInW := io.Pipe()
+	daemonOutR, daemonOutW := io.Pipe()
+	remote := transport.NewStdioTransportWithIO(daemonOutR, daemonInW)
+	go func() {
+		scanner := bufio.NewScanner(daemonInR)
+		for scanner.Scan() {
+			if countRequests(scanner.Bytes()) == 0 {
+				continue
+			}
+			time.Sleep(50 * time.Millisecond)
+			_, _ = daemonOutW.Write([]byte(`{"jsonrpc":"2.0","method":"event/heartbeat","params":{}}` + "\n"))
+			_, _ = daemonOutW.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"ok":true}}` + "\n"))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := relayRPC(ctx, local, remote); err != nil {
+		t.Fatalf("relayRPC() error = %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("relayRPC returned only after the timeout")
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"id":1`) {
+		t.Fatalf("output = %q, want the event and the response", output.String())
+	}
+}
@@ -43,6 +43,8 @@ type Manager struct {
 	command           string
 	args              []string
 	launchArgs        []string
+	launchEnv         []string
+	launchPermission  string
 	timeout           time.Duration
 	hub               ports.EventHub
 	logDir            string
@@ -196,6 +198,26 @@ func (m *Manager) SetLaunchArgs(args []string) {
 	m.launchArgs = append([]string(nil), args...)
 }
 
+// SetLaunchEnv sets session-scoped KEY=VALUE environment entries added on top of
+// cdev's own environment when spawning Claude.
+func (m *Manager) SetLaunchEnv(env []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(env) == 0 {
+		m.launchEnv = nil
+		return
+	}
+	m.launchEnv = append([]string(nil), env...)
+}
+
+// SetLaunchPermissionMode sets the session's permission mode, used whenever a
+// prompt does not request one explicitly.
+func (m *Manager) SetLaunchPermissionMode(permissionMode string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.launchPermission = permissionMode
+}
+
 // SetOnPTYComplete sets a callback that's called when PTY streaming finishes.
 // Used by session manager to emit claude_message with stop_reason.
 func (m *Manager) SetOnPTYComplete(callback func(sessionID string)) {
@@ -211,7 +233,8 @@ func (m *Manager) Start(ctx context.Context, prompt string) error {
 
 // StartWithSession spawns Claude CLI with session control.
 // permissionMode controls how Claude handles permissions:
-// - "" or "default": Use skipPermissions config setting
+// - "": Use the session's launch permission mode, if any
+// - "default": Use skipPermissions config setting
 // - "acceptEdits": Auto-accept file edits
 // - "bypassPermissions": Skip all permission checks
 // - "plan": Plan mode only
@@ -256,7 +279,7 @@ func (m *Manager) StartWithSession(ctx context.Context, prompt string, mode Sess
 	// Build command arguments
 	cmdArgs := make([]string, len(m.args))
 	copy(cmdArgs, m.args)
-	permissionMode = normalizePermissionMode(permissionMode)
+	permissionMode = normalizePermissionMode(resolvePermissionMode(permissionMode, m.launchPermission))
 	if m.stripWorktreeArgs {
 		var removed []string
 		cmdArgs, removed = stripWorktreeFlags(cmdArgs)
@@ -317,6 +340,9 @@ func (m *Manager) StartWithSession(ctx context.Context, prompt string, mode Sess
 	if m.workDir != "" {
 		m.cmd.Dir = m.workDir
 	}
+	if len(m.launchEnv) > 0 {
+		m.cmd.Env = append(os.Environ(), m.launchEnv...)
+	}
 
 	// Setup process for platform-specific handling
 	m.setupProcess(m.cmd)
@@ -517,6 +543,15 @@ func hasCLIArg(args []string, target string) bool {
 	return false
 }
 
+// resolvePermissionMode returns the mode a prompt runs with. Only an absent
+// mode falls back to the launch mode; an explicit "default" overrides it.
+func resolvePermissionMode(permissionMode, launchPermission string) string {
+	if permissionMode == "" {
+		return launchPermission
+	}
+	return permissionMode
+}
+
 func normalizePermissionMode(permissionMode string) string {
 	switch permissionMode {
 	case "dangerouslySkipPermissions":
@@ -578,6 +613,8 @@ func (m *Manager) StartWithPTY(ctx context.Context, prompt string, mode SessionM
 
 	if yoloMode {
 		cmdArgs = append(cmdArgs, "--allow-dangerously-skip-permissions")
+	} else if shouldAppendPermissionModeFlag(m.launchPermission, cmdArgs) {
+		cmdArgs = append(cmdArgs, "--permission-mode", m.launchPermission)
 	}
 
 	// NOTE: We do NOT add the prompt as a CLI argument!
@@ -606,6 +643,7 @@ func (m *Manager) StartWithPTY(ctx context.Context, prompt string, mode SessionM
 		"COLUMNS=120",
 		"LINES=40",
 	)
+	m.cmd.Env = append(m.cmd.Env, m.launchEnv...)
 
 	// Start with PTY - this creates a pseudo-terminal
 	ptmx, err := pty.Start(m.cmd)
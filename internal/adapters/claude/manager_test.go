@@ -395,6 +395,28 @@ func TestNormalizePermissionMode(t *testing.T) {
 	}
 }
 
+func TestResolvePermissionMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested string
+		launch    string
+		want      string
+	}{
+		{name: "absent uses launch mode", requested: "", launch: "bypassPermissions", want: "bypassPermissions"},
+		{name: "explicit default overrides launch mode", requested: "default", launch: "bypassPermissions", want: "default"},
+		{name: "explicit mode overrides launch mode", requested: "plan", launch: "bypassPermissions", want: "plan"},
+		{name: "absent without launch mode", requested: "", launch: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolvePermissionMode(tt.requested, tt.launch); got != tt.want {
+				t.Fatalf("resolvePermissionMode(%q, %q) = %q, want %q", tt.requested, tt.launch, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestShouldAppendPermissionModeFlag(t *testing.T) {
 	tests := []struct {
 		name           string
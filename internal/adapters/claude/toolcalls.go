@@ -0,0 +1,117 @@
+package claude
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/adapters/jsonl"
+	"github.com/rs/zerolog/log"
+)
+
+// ToolCall is a tool use recorded in a Claude session file.
+type ToolCall struct {
+	ID        string
+	SessionID string
+	Name      string
+	Input     map[string]interface{}
+	Cwd       string
+	Timestamp time.Time
+}
+
+// toolCallRecord is the part of an assistant line needed to find tool uses.
+type toolCallRecord struct {
+	Type      string `json:"type"`
+	SessionID string `json:"sessionId"`
+	Cwd       string `json:"cwd"`
+	Timestamp string `json:"timestamp"`
+	Message   struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+// ListSessionFiles returns the session files of a repository.
+func ListSessionFiles(repoPath string) ([]string, error) {
+	sessionsDir := getSessionsDir(repoPath)
+	entries, err := os.ReadDir(sessionsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && uuidPattern.MatchString(entry.Name()) {
+			files = append(files, filepath.Join(sessionsDir, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// ReadSessionToolCalls returns the tool uses of a session file in order.
+func ReadSessionToolCalls(path string) ([]ToolCall, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	defaultSessionID := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	reader := jsonl.NewReader(file, 0)
+
+	var calls []ToolCall
+	for {
+		line, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line.TooLong || len(line.Data) == 0 {
+			continue
+		}
+
+		var record toolCallRecord
+		if err := json.Unmarshal(line.Data, &record); err != nil || record.Type != "assistant" {
+			continue
+		}
+		var blocks []struct {
+			Type  string                 `json:"type"`
+			ID    string                 `json:"id"`
+			Name  string                 `json:"name"`
+			Input map[string]interface{} `json:"input"`
+		}
+		if err := json.Unmarshal(record.Message.Content, &blocks); err != nil {
+			continue // Plain text content
+		}
+
+		sessionID := record.SessionID
+		if sessionID == "" {
+			sessionID = defaultSessionID
+		}
+		ts, err := time.Parse(time.RFC3339Nano, record.Timestamp)
+		if err != nil {
+			log.Debug().Err(err).Str("timestamp", record.Timestamp).Msg("invalid tool use timestamp")
+		}
+		for _, block := range blocks {
+			if block.Type != "tool_use" || block.Name == "" {
+				continue
+			}
+			calls = append(calls, ToolCall{
+				ID:        block.ID,
+				SessionID: sessionID,
+				Name:      block.Name,
+				Input:     block.Input,
+				Cwd:       record.Cwd,
+				Timestamp: ts,
+			})
+		}
+	}
+	return calls, nil
+}
@@ -0,0 +1,56 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadSessionToolCalls(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+
+	repoPath := "/tmp/test-repo"
+	sessionsDir := getSessionsDir(repoPath)
+	if err := os.MkdirAll(sessionsDir, 0755); err != nil {
+		t.Fatalf("failed to create sessions dir: %v", err)
+	}
+
+	content := `{"type":"user","message":{"role":"user","content":"run the tests"}}
+{"type":"assistant","cwd":"/tmp/test-repo","timestamp":"2026-01-31T12:00:00.000Z","message":{"role":"assistant","content":[{"type":"text","text":"Running"},{"type":"tool_use","id":"toolu_1","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"assistant","sessionId":"other","message":{"role":"assistant","content":"plain text"}}
+{"type":"assistant","cwd":"/tmp/test-repo","message":{"role":"assistant","content":[{"type":"tool_use","id":"toolu_2","name":"Edit","input":{"file_path":"main.go"}}]}}
+`
+	sessionFile := filepath.Join(sessionsDir, "11111111-1111-1111-1111-111111111111.jsonl")
+	if err := os.WriteFile(sessionFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write session: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(sessionsDir, "notes.jsonl"), []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("failed to write notes: %v", err)
+	}
+
+	files, err := ListSessionFiles(repoPath)
+	if err != nil {
+		t.Fatalf("ListSessionFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != sessionFile {
+		t.Fatalf("ListSessionFiles() = %v, want only the session file", files)
+	}
+
+	calls, err := ReadSessionToolCalls(sessionFile)
+	if err != nil {
+		t.Fatalf("ReadSessionToolCalls() error = %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("got %d calls, want 2: %+v", len(calls), calls)
+	}
+	if calls[0].ID != "toolu_1" || calls[0].Name != "Bash" || calls[0].Input["command"] != "go test ./..." {
+		t.Errorf("first call = %+v", calls[0])
+	}
+	if calls[0].SessionID != "11111111-1111-1111-1111-111111111111" || calls[0].Cwd != "/tmp/test-repo" || calls[0].Timestamp.IsZero() {
+		t.Errorf("first call metadata = %+v", calls[0])
+	}
+	if calls[1].Name != "Edit" || !calls[1].Timestamp.IsZero() {
+		t.Errorf("second call = %+v", calls[1])
+	}
+}
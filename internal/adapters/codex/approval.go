@@ -0,0 +1,179 @@
+package codex
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ApprovalKind identifies what a Codex approval prompt asks for.
+type ApprovalKind string
+
+const (
+	// ApprovalExec asks to run a shell command.
+	ApprovalExec ApprovalKind = "exec"
+	// ApprovalPatch asks to apply file edits.
+	ApprovalPatch ApprovalKind = "patch"
+)
+
+// approvalPromptMaxLines bounds how far after the header the options may
+// appear before a half-seen prompt is abandoned.
+const approvalPromptMaxLines = 40
+
+// ApprovalPrompt is an approval request detected in the Codex TUI output.
+type ApprovalPrompt struct {
+	Kind    ApprovalKind `json:"kind"`
+	Command string       `json:"command,omitempty"` // exec: command Codex wants to run
+	Files   []string     `json:"files,omitempty"`   // patch: files the edit touches
+	Reason  string       `json:"reason,omitempty"`  // Optional explanation shown by Codex
+
+	// Multiline is set when the command spans several lines. They are
+	// joined with newlines, but may be one line wrapped by the TUI, so the
+	// command is not exact and must not be allowed without a prompt.
+	Multiline bool `json:"multiline,omitempty"`
+}
+
+// ToolCall normalises the prompt into the tool call the permission rules
+// understand: Bash for commands (with the reason as description), Edit for
+// a patch of a single file and apply_patch with a files list for a patch
+// touching several files.
+func (p *ApprovalPrompt) ToolCall() (string, map[string]interface{}) {
+	if p.Kind == ApprovalPatch {
+		if len(p.Files) == 1 {
+			return "Edit", map[string]interface{}{"file_path": p.Files[0]}
+		}
+		files := make([]interface{}, len(p.Files))
+		for i, f := range p.Files {
+			files[i] = f
+		}
+		return "apply_patch", map[string]interface{}{"files": files}
+	}
+	input := map[string]interface{}{"command": p.Command}
+	if p.Reason != "" {
+		input["description"] = p.Reason
+	}
+	return "Bash", input
+}
+
+var (
+	approvalExecHeader  = regexp.MustCompile(`(?i)would you like to run the following command\?|allow command\?`)
+	approvalPatchHeader = regexp.MustCompile(`(?i)would you like to (?:make|apply) the following (?:edits|changes)\?|allow (?:edits|changes|patch)\?`)
+	approvalOption      = regexp.MustCompile(`(?i)^(?:[›>❯]\s*)?(?:\d+\.\s*)?yes\b`)
+	approvalReason      = regexp.MustCompile(`(?i)^reason:\s*(.+)$`)
+	approvalFile        = regexp.MustCompile(`^(?:(?:Added|Created|Edited|Updated|Modified|Deleted|Renamed)\s+)?(\S+)\s+\(\+\d+\s+-\d+\)$`)
+	// The TUI positions text with cursor moves that are stripped with the
+	// ANSI codes, so markers may end up on one line. Split them apart again.
+	// Options and commands need a wider gap, so text inside a command is
+	// not split.
+	approvalSegment = regexp.MustCompile(`(?i)\s(would you like to |allow (?:command|edits|changes|patch)\?|reason:)|\s\s((?:[›>❯]\s*)?(?:\d+\.\s*)?(?:yes|no)[, ]|\$ )`)
+)
+
+// ApprovalDetector finds approval prompts in sanitized Codex PTY output fed
+// line by line. A prompt is reported once, when its options are shown;
+// redraws of the same prompt are ignored until Reset is called.
+type ApprovalDetector struct {
+	active   *ApprovalPrompt
+	lines    int
+	reported bool
+}
+
+// NewApprovalDetector creates a detector with no open prompt.
+func NewApprovalDetector() *ApprovalDetector {
+	return &ApprovalDetector{}
+}
+
+// Reset marks the reported prompt as answered, so the next prompt is
+// reported again.
+func (d *ApprovalDetector) Reset() {
+	d.active = nil
+	d.lines = 0
+	d.reported = false
+}
+
+// Feed processes one line of sanitized output and returns the prompt once
+// it is complete.
+func (d *ApprovalDetector) Feed(line string) *ApprovalPrompt {
+	var found *ApprovalPrompt
+	for _, segment := range splitApprovalSegments(line) {
+		if prompt := d.feedSegment(segment); prompt != nil {
+			found = prompt
+		}
+	}
+	return found
+}
+
+func (d *ApprovalDetector) feedSegment(line string) *ApprovalPrompt {
+	line = strings.TrimSpace(strings.Trim(line, " \t│┃║▌╭╮╰╯─•└├"))
+	if line == "" {
+		return nil
+	}
+
+	kind := ApprovalKind("")
+	switch {
+	case approvalExecHeader.MatchString(line):
+		kind = ApprovalExec
+	case approvalPatchHeader.MatchString(line):
+		kind = ApprovalPatch
+	}
+	if kind != "" {
+		if d.reported {
+			return nil // Redraw of the prompt waiting for an answer
+		}
+		d.active = &ApprovalPrompt{Kind: kind}
+		d.lines = 0
+		return nil
+	}
+
+	if d.active == nil {
+		return nil
+	}
+	d.lines++
+	if d.lines > approvalPromptMaxLines {
+		d.active = nil
+		return nil
+	}
+
+	switch {
+	case approvalOption.MatchString(line):
+		prompt := d.active
+		d.active = nil
+		d.reported = true
+		return prompt
+	case approvalReason.MatchString(line):
+		if d.active.Reason == "" {
+			d.active.Reason = strings.TrimSpace(approvalReason.FindStringSubmatch(line)[1])
+		}
+	case d.active.Kind == ApprovalExec && strings.HasPrefix(line, "$ "):
+		if d.active.Command == "" {
+			d.active.Command = strings.TrimSpace(line[2:])
+		}
+	case d.active.Kind == ApprovalExec && d.active.Command != "":
+		// Lines up to the options continue the command
+		d.active.Command += "\n" + line
+		d.active.Multiline = true
+	case d.active.Kind == ApprovalPatch:
+		if m := approvalFile.FindStringSubmatch(line); m != nil && !slices.Contains(d.active.Files, m[1]) {
+			d.active.Files = append(d.active.Files, m[1])
+		}
+	}
+	return nil
+}
+
+// splitApprovalSegments breaks a line before every prompt marker.
+func splitApprovalSegments(line string) []string {
+	idx := approvalSegment.FindAllStringSubmatchIndex(line, -1)
+	if len(idx) == 0 {
+		return []string{line}
+	}
+	segments := make([]string, 0, len(idx)+1)
+	start := 0
+	for _, m := range idx {
+		at := m[2]
+		if at < 0 {
+			at = m[4]
+		}
+		segments = append(segments, line[start:at])
+		start = at
+	}
+	return append(segments, line[start:])
+}
@@ -0,0 +1,149 @@
+package codex
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func feedLines(d *ApprovalDetector, text string) []*ApprovalPrompt {
+	var prompts []*ApprovalPrompt
+	for _, line := range strings.Split(text, "\n") {
+		if prompt := d.Feed(line); prompt != nil {
+			prompts = append(prompts, prompt)
+		}
+	}
+	return prompts
+}
+
+func TestApprovalDetector_Prompts(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   *ApprovalPrompt
+	}{
+		{
+			name: "exec prompt",
+			output: `• Running tests
+Would you like to run the following command?
+
+Reason: run the unit tests outside the sandbox
+
+$ go test ./...
+
+› 1. Yes, proceed (y)
+  2. Yes, and don't ask again for this command (a)
+  3. No, and tell Codex what to do differently (esc)`,
+			want: &ApprovalPrompt{Kind: ApprovalExec, Command: "go test ./...", Reason: "run the unit tests outside the sandbox"},
+		},
+		{
+			name: "boxed exec prompt",
+			output: `│ Allow command? │
+│ $ rm -rf build │
+│ Yes (y)   No (n) │`,
+			want: &ApprovalPrompt{Kind: ApprovalExec, Command: "rm -rf build"},
+		},
+		{
+			name:   "markers on one line",
+			output: `  Would you like to run the following command?  $ npm install left-pad  › 1. Yes, proceed (y)  2. No, and tell Codex what to do differently (esc)`,
+			want:   &ApprovalPrompt{Kind: ApprovalExec, Command: "npm install left-pad"},
+		},
+		{
+			name: "single file patch",
+			output: `Would you like to make the following edits?
+
+• Edited src/main.go (+3 -1)
+
+› 1. Yes, proceed (y)
+  2. No, and tell Codex what to do differently (esc)`,
+			want: &ApprovalPrompt{Kind: ApprovalPatch, Files: []string{"src/main.go"}},
+		},
+		{
+			name: "multi file patch",
+			output: `Would you like to make the following edits?
+  └ README.md (+2 -0)
+  └ Added docs/usage.md (+40 -0)
+› 1. Yes, proceed (y)`,
+			want: &ApprovalPrompt{Kind: ApprovalPatch, Files: []string{"README.md", "docs/usage.md"}},
+		},
+		{
+			name: "wrapped command",
+			output: `Would you like to run the following command?
+$ curl -sSL https://example.com/install.sh -o /tmp/install.sh && bash
+/tmp/install.sh --prefix /usr/local
+
+› 1. Yes, proceed (y)`,
+			want: &ApprovalPrompt{Kind: ApprovalExec, Command: "curl -sSL https://example.com/install.sh -o /tmp/install.sh && bash\n/tmp/install.sh --prefix /usr/local", Multiline: true},
+		},
+		{
+			name:   "plain output",
+			output: "Yes, the tests pass.\n$ go test ./...\nok",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompts := feedLines(NewApprovalDetector(), tt.output)
+			if tt.want == nil {
+				if len(prompts) != 0 {
+					t.Fatalf("prompts = %+v, want none", prompts)
+				}
+				return
+			}
+			if len(prompts) != 1 {
+				t.Fatalf("got %d prompts, want 1", len(prompts))
+			}
+			if !reflect.DeepEqual(prompts[0], tt.want) {
+				t.Errorf("prompt = %+v, want %+v", prompts[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestApprovalDetector_RedrawAndReset(t *testing.T) {
+	prompt := "Would you like to run the following command?\n$ make\n› 1. Yes, proceed (y)"
+	d := NewApprovalDetector()
+
+	if got := feedLines(d, prompt); len(got) != 1 {
+		t.Fatalf("first prompt: got %d prompts, want 1", len(got))
+	}
+	if got := feedLines(d, prompt); len(got) != 0 {
+		t.Fatalf("redraw before answer: got %d prompts, want 0", len(got))
+	}
+
+	d.Reset()
+	if got := feedLines(d, prompt); len(got) != 1 {
+		t.Fatalf("prompt after reset: got %d prompts, want 1", len(got))
+	}
+}
+
+func TestApprovalPrompt_ToolCall(t *testing.T) {
+	tests := []struct {
+		prompt    ApprovalPrompt
+		wantTool  string
+		wantInput map[string]interface{}
+	}{
+		{
+			prompt:    ApprovalPrompt{Kind: ApprovalExec, Command: "ls -la"},
+			wantTool:  "Bash",
+			wantInput: map[string]interface{}{"command": "ls -la"},
+		},
+		{
+			prompt:    ApprovalPrompt{Kind: ApprovalPatch, Files: []string{"a.go"}},
+			wantTool:  "Edit",
+			wantInput: map[string]interface{}{"file_path": "a.go"},
+		},
+		{
+			prompt:    ApprovalPrompt{Kind: ApprovalPatch, Files: []string{"a.go", "b.go"}},
+			wantTool:  "apply_patch",
+			wantInput: map[string]interface{}{"files": []interface{}{"a.go", "b.go"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tool, input := tt.prompt.ToolCall()
+		if tool != tt.wantTool || !reflect.DeepEqual(input, tt.wantInput) {
+			t.Errorf("ToolCall(%+v) = %s %v, want %s %v", tt.prompt, tool, input, tt.wantTool, tt.wantInput)
+		}
+	}
+}
@@ -22,6 +22,7 @@ type SessionInfo struct {
 	MessageCount  int       `json:"message_count"`
 	LastUpdated   time.Time `json:"last_updated"`
 	WorkspacePath string    `json:"workspace_path"`
+	Path          string    `json:"-"` // Session file, set by ListSessionsForWorkspace
 }
 
 // Message is a normalized Codex session message.
@@ -123,6 +124,7 @@ func ListSessionsForWorkspace(workspacePath string) ([]SessionInfo, error) {
 			return nil
 		}
 
+		info.Path = path
 		sessions = append(sessions, info)
 		return nil
 	})
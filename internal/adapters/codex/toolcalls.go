@@ -0,0 +1,132 @@
+package codex
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ToolCall is a Codex tool call normalized to the tool names the permission
+// engine understands: Bash for commands, Edit or apply_patch for patches.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Input     map[string]interface{}
+	Cwd       string
+	Timestamp time.Time
+}
+
+// patchFileHeader matches the file headers of an apply_patch payload.
+var patchFileHeader = regexp.MustCompile(`(?m)^\*\*\* (?:Add|Update|Delete) File: (.+?)\s*$|^\*\*\* Move to: (.+?)\s*$`)
+
+// ReadSessionToolCalls returns the commands and patches of a Codex session
+// file in order. Other tools (plans, image views, MCP calls) are skipped.
+// cwd is used when a call does not name its own working directory.
+func ReadSessionToolCalls(path, cwd string) ([]ToolCall, error) {
+	items, err := ReadConversationItems(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []ToolCall
+	for _, item := range items {
+		ts, _ := parseTimestamp(item.Timestamp)
+		for _, block := range item.Content {
+			if block.Type != "tool_use" {
+				continue
+			}
+			name, input := normalizeToolCall(block.ToolName, block.ToolInput)
+			if name == "" {
+				continue
+			}
+			callCwd := cwd
+			if workdir, ok := block.ToolInput["workdir"].(string); ok && workdir != "" {
+				callCwd = workdir
+			}
+			calls = append(calls, ToolCall{
+				ID:        block.ToolID,
+				Name:      name,
+				Input:     input,
+				Cwd:       callCwd,
+				Timestamp: ts,
+			})
+		}
+	}
+	return calls, nil
+}
+
+// normalizeToolCall maps a Codex tool call to the shape of its approval
+// prompt. Returns an empty name for tools that never ask for approval.
+func normalizeToolCall(name string, input map[string]interface{}) (string, map[string]interface{}) {
+	switch name {
+	case "exec_command", "shell", "local_shell":
+		command := shellCommand(input)
+		if command == "" {
+			return "", nil
+		}
+		prompt := &ApprovalPrompt{Kind: ApprovalExec, Command: command}
+		if reason, ok := input["justification"].(string); ok {
+			prompt.Reason = reason
+		}
+		return prompt.ToolCall()
+	case "apply_patch":
+		patch, _ := input["input"].(string)
+		if patch == "" {
+			patch, _ = input["patch"].(string)
+		}
+		files := patchFiles(patch)
+		if len(files) == 0 {
+			return "", nil
+		}
+		return (&ApprovalPrompt{Kind: ApprovalPatch, Files: files}).ToolCall()
+	}
+	return "", nil
+}
+
+// shellCommand returns the command line of an exec_command or shell call,
+// unwrapping the `bash -lc "<script>"` form Codex uses for shell.
+func shellCommand(input map[string]interface{}) string {
+	for _, key := range []string{"cmd", "command"} {
+		switch v := input[key].(type) {
+		case string:
+			return strings.TrimSpace(v)
+		case []interface{}:
+			argv := make([]string, 0, len(v))
+			for _, arg := range v {
+				if s, ok := arg.(string); ok {
+					argv = append(argv, s)
+				}
+			}
+			if len(argv) == 3 && (argv[1] == "-lc" || argv[1] == "-c") && isShell(argv[0]) {
+				return strings.TrimSpace(argv[2])
+			}
+			return strings.TrimSpace(strings.Join(argv, " "))
+		}
+	}
+	return ""
+}
+
+func isShell(path string) bool {
+	switch path[strings.LastIndex(path, "/")+1:] {
+	case "bash", "sh", "zsh":
+		return true
+	}
+	return false
+}
+
+// patchFiles returns the files an apply_patch payload touches, in order.
+func patchFiles(patch string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, m := range patchFileHeader.FindAllStringSubmatch(patch, -1) {
+		file := m[1]
+		if file == "" {
+			file = m[2]
+		}
+		if file != "" && !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	return files
+}
@@ -0,0 +1,46 @@
+package codex
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadSessionToolCalls_NormalizesApprovals(t *testing.T) {
+	content := `{"timestamp":"2026-01-31T12:00:00Z","type":"session_meta","payload":{"id":"s1","cwd":"/repo"}}
+{"timestamp":"2026-01-31T12:00:01Z","type":"response_item","payload":{"type":"function_call","name":"exec_command","arguments":"{\"cmd\":\"go test ./...\",\"justification\":\"run tests\"}","call_id":"call_1"}}
+{"timestamp":"2026-01-31T12:00:02Z","type":"response_item","payload":{"type":"function_call","name":"shell","arguments":"{\"command\":[\"bash\",\"-lc\",\"rm -rf build\"],\"workdir\":\"/repo/sub\"}","call_id":"call_2"}}
+{"timestamp":"2026-01-31T12:00:03Z","type":"response_item","payload":{"type":"custom_tool_call","name":"apply_patch","input":"*** Begin Patch\n*** Update File: main.go\n@@\n-a\n+b\n*** End Patch","call_id":"call_3"}}
+{"timestamp":"2026-01-31T12:00:04Z","type":"response_item","payload":{"type":"custom_tool_call","name":"apply_patch","input":"*** Begin Patch\n*** Add File: a.go\n+x\n*** Update File: b.go\n*** Move to: c.go\n*** End Patch","call_id":"call_4"}}
+{"timestamp":"2026-01-31T12:00:05Z","type":"response_item","payload":{"type":"function_call","name":"update_plan","arguments":"{\"plan\":[]}","call_id":"call_5"}}
+`
+	path := filepath.Join(t.TempDir(), "rollout.jsonl")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write session: %v", err)
+	}
+
+	calls, err := ReadSessionToolCalls(path, "/repo")
+	if err != nil {
+		t.Fatalf("ReadSessionToolCalls() error = %v", err)
+	}
+	if len(calls) != 4 {
+		t.Fatalf("got %d calls, want 4 (update_plan skipped): %+v", len(calls), calls)
+	}
+
+	if calls[0].Name != "Bash" || calls[0].Input["command"] != "go test ./..." || calls[0].Input["description"] != "run tests" {
+		t.Errorf("exec_command = %+v", calls[0])
+	}
+	if calls[0].Cwd != "/repo" || calls[0].ID != "call_1" || calls[0].Timestamp.IsZero() {
+		t.Errorf("exec_command metadata = %+v", calls[0])
+	}
+	if calls[1].Name != "Bash" || calls[1].Input["command"] != "rm -rf build" || calls[1].Cwd != "/repo/sub" {
+		t.Errorf("shell = %+v, want unwrapped command in workdir", calls[1])
+	}
+	if calls[2].Name != "Edit" || calls[2].Input["file_path"] != "main.go" {
+		t.Errorf("single file patch = %+v", calls[2])
+	}
+	files, _ := calls[3].Input["files"].([]interface{})
+	if calls[3].Name != "apply_patch" || len(files) != 3 {
+		t.Errorf("multi file patch = %+v, want a.go, b.go and c.go", calls[3])
+	}
+}
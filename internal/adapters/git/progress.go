@@ -0,0 +1,80 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/brianly1003/cdev/internal/domain/ports"
+)
+
+// progressLine matches the progress git prints with --progress, e.g.
+// "Receiving objects:  45% (450/1000), 1.20 MiB | 2.00 MiB/s".
+var progressLine = regexp.MustCompile(`^(?:remote: )?([A-Z][a-z]+(?: [a-z]+)*):\s+(\d+)%`)
+
+// progressPhases maps the phases of a fetch to the share of the whole
+// operation they cover, so that the percentage reported never goes back.
+var progressPhases = map[string][2]int{
+	"Enumerating objects": {0, 5},
+	"Counting objects":    {5, 10},
+	"Compressing objects": {10, 20},
+	"Receiving objects":   {20, 80},
+	"Resolving deltas":    {80, 95},
+	"Updating files":      {95, 100},
+}
+
+// progressWriter reads the stderr of a git command run with --progress. It
+// reports progress lines to the ProgressFunc of its context and writes the
+// other lines to out.
+type progressWriter struct {
+	ctx     context.Context
+	out     io.Writer
+	buf     []byte
+	percent int
+}
+
+// gitStderr returns the writer to use as stderr of a git command writing its
+// output to out, and whether the command should be run with --progress.
+func gitStderr(ctx context.Context, out io.Writer) (io.Writer, bool) {
+	if !ports.HasProgress(ctx) {
+		return out, false
+	}
+	return &progressWriter{ctx: ctx, out: out}, true
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.buf[:i+1]
+		if !w.report(string(line)) {
+			if _, err := w.out.Write(line); err != nil {
+				return len(p), err
+			}
+		}
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// report reports line if it is a progress line.
+func (w *progressWriter) report(line string) bool {
+	m := progressLine.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return false
+	}
+	phase := m[1]
+	percent, _ := strconv.Atoi(m[2])
+	if span, ok := progressPhases[phase]; ok {
+		if overall := span[0] + (span[1]-span[0])*percent/100; overall > w.percent {
+			w.percent = overall
+		}
+	}
+	ports.ReportProgress(w.ctx, phase, w.percent)
+	return true
+}
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/domain/ports"
+)
+
+func TestProgressWriter(t *testing.T) {
+	type report struct {
+		message string
+		percent int
+	}
+	var reports []report
+	ctx := ports.WithProgress(context.Background(), func(message string, percent int) {
+		reports = append(reports, report{message, percent})
+	})
+
+	var out bytes.Buffer
+	w, progress := gitStderr(ctx, &out)
+	if !progress {
+		t.Fatal("gitStderr() did not enable progress")
+	}
+	// Written in pieces, as git does
+	stderr := "From github.com:owner/repo\n" +
+		"remote: Counting objects: 100% (10/10), done.\n" +
+		"Receiving objects:  50% (5/10)\rReceiving objects: 100% (10/10), done.\n" +
+		"Resolving deltas:  50% (1/2)\r" +
+		"Counting objects: 10%\r" + // Would go back, kept at the previous percentage
+		" * [new branch]      main       -> origin/main\n"
+	for _, chunk := range strings.SplitAfter(stderr, "(") {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []report{
+		{"Counting objects", 10},
+		{"Receiving objects", 50},
+		{"Receiving objects", 80},
+		{"Resolving deltas", 87},
+		{"Counting objects", 87},
+	}
+	if len(reports) != len(want) {
+		t.Fatalf("reports = %v, want %v", reports, want)
+	}
+	for i := range want {
+		if reports[i] != want[i] {
+			t.Errorf("report %d = %v, want %v", i, reports[i], want[i])
+		}
+	}
+	if got := out.String(); got != "From github.com:owner/repo\n * [new branch]      main       -> origin/main\n" {
+		t.Errorf("output = %q, want the lines other than progress", got)
+	}
+}
+
+func TestGitStderr_WithoutProgress(t *testing.T) {
+	var out bytes.Buffer
+	if w, progress := gitStderr(context.Background(), &out); progress || w != &out {
+		t.Error("gitStderr() wrapped the output without a progress listener")
+	}
+}
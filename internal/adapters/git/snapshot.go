@@ -0,0 +1,496 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/domain"
+)
+
+// SnapshotRefPrefix is the hidden ref namespace used for working-tree snapshots.
+// Refs under this prefix are never checked out and do not appear in branch listings.
+const SnapshotRefPrefix = "refs/cdev/snapshots/"
+
+// DefaultMaxSnapshots is the number of snapshots kept per session when no limit is given.
+const DefaultMaxSnapshots = 50
+
+// Snapshot describes a captured working-tree state stored under a hidden ref.
+type Snapshot struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	Ref       string    `json:"ref"`
+	Commit    string    `json:"commit"`
+	Tree      string    `json:"tree"`
+	Head      string    `json:"head,omitempty"` // HEAD commit when the snapshot was taken
+	Label     string    `json:"label,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SnapshotFileChange describes a single file difference between two snapshots.
+type SnapshotFileChange struct {
+	Path      string `json:"path"`
+	Status    string `json:"status"` // A, M, D, T
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+// SnapshotDiffResult is the result of comparing two snapshots.
+type SnapshotDiffResult struct {
+	From        string               `json:"from"`
+	To          string               `json:"to"` // "working_tree" when compared against the current state
+	Files       []SnapshotFileChange `json:"files"`
+	Diff        string               `json:"diff"`
+	IsTruncated bool                 `json:"is_truncated"`
+}
+
+// SnapshotRestoreResult is the result of restoring a snapshot.
+type SnapshotRestoreResult struct {
+	Success  bool      `json:"success"`
+	Snapshot string    `json:"snapshot"`
+	Backup   *Snapshot `json:"backup,omitempty"` // State captured right before the restore
+	Restored []string  `json:"restored"`
+	Deleted  []string  `json:"deleted"`
+}
+
+// SnapshotWorkingTree is the pseudo snapshot ID for the current working tree in diffs.
+const SnapshotWorkingTree = "working_tree"
+
+// CreateSnapshot captures the current working tree (tracked and untracked, non-ignored
+// files) as a commit stored under refs/cdev/snapshots/<session>/<id>.
+// The user's index, HEAD and branches are never modified. If the working tree is
+// identical to the session's latest snapshot, that snapshot is returned instead.
+// maxSnapshots limits how many snapshots are kept for the session (<= 0 uses the default).
+func (t *Tracker) CreateSnapshot(ctx context.Context, sessionID, label string, maxSnapshots int) (*Snapshot, bool, error) {
+	if !t.IsGitRepo() {
+		return nil, false, domain.ErrNotGitRepo
+	}
+	if err := validateSnapshotComponent("session_id", sessionID); err != nil {
+		return nil, false, err
+	}
+
+	tree, err := t.workingTreeObject(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := t.ListSnapshots(ctx, sessionID)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(existing) > 0 && existing[0].Tree == tree {
+		return &existing[0], false, nil
+	}
+
+	head, _ := t.runGit(ctx, nil, "rev-parse", "--verify", "-q", "HEAD")
+	head = strings.TrimSpace(head)
+
+	if label == "" {
+		label = "cdev snapshot"
+	}
+	args := []string{"commit-tree", "-m", label, "-m", "cdev-session: " + sessionID}
+	if head != "" {
+		args = append(args, "-p", head)
+	}
+	args = append(args, tree)
+	commit, err := t.runGit(ctx, snapshotIdentityEnv(), args...)
+	if err != nil {
+		return nil, false, domain.NewGitError("commit-tree", err)
+	}
+	commit = strings.TrimSpace(commit)
+
+	now := time.Now().UTC()
+	id := strconv.FormatInt(now.UnixNano(), 10)
+	ref := snapshotRef(sessionID, id)
+	if _, err := t.runGit(ctx, nil, "update-ref", ref, commit); err != nil {
+		return nil, false, domain.NewGitError("update-ref", err)
+	}
+
+	snapshot := &Snapshot{
+		ID:        id,
+		SessionID: sessionID,
+		Ref:       ref,
+		Commit:    commit,
+		Tree:      tree,
+		Head:      head,
+		Label:     label,
+		CreatedAt: now,
+	}
+
+	t.pruneSnapshots(ctx, append([]Snapshot{*snapshot}, existing...), maxSnapshots)
+
+	return snapshot, true, nil
+}
+
+// ListSnapshots returns the snapshots recorded for a session, newest first.
+func (t *Tracker) ListSnapshots(ctx context.Context, sessionID string) ([]Snapshot, error) {
+	if !t.IsGitRepo() {
+		return nil, domain.ErrNotGitRepo
+	}
+	if err := validateSnapshotComponent("session_id", sessionID); err != nil {
+		return nil, err
+	}
+
+	output, err := t.runGit(ctx, nil, "for-each-ref",
+		"--format=%(refname)%00%(objectname)%00%(tree)%00%(parent)%00%(contents:subject)",
+		SnapshotRefPrefix+sessionID+"/")
+	if err != nil {
+		return nil, domain.NewGitError("for-each-ref", err)
+	}
+
+	snapshots := make([]Snapshot, 0)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, "\x00")
+		if len(parts) < 5 {
+			continue
+		}
+		id := strings.TrimPrefix(parts[0], SnapshotRefPrefix+sessionID+"/")
+		nanos, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{
+			ID:        id,
+			SessionID: sessionID,
+			Ref:       parts[0],
+			Commit:    parts[1],
+			Tree:      parts[2],
+			Head:      parts[3],
+			Label:     parts[4],
+			CreatedAt: time.Unix(0, nanos).UTC(),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+
+	return snapshots, nil
+}
+
+// DiffSnapshots compares two snapshots. Each side is addressed by the session it
+// was recorded under and its snapshot ID; a toID of "" or SnapshotWorkingTree
+// compares against the current working tree. maxDiffSizeKB caps the patch text.
+func (t *Tracker) DiffSnapshots(ctx context.Context, fromSession, fromID, toSession, toID string, maxDiffSizeKB int) (*SnapshotDiffResult, error) {
+	if !t.IsGitRepo() {
+		return nil, domain.ErrNotGitRepo
+	}
+
+	from, err := t.resolveSnapshotObject(ctx, fromSession, fromID)
+	if err != nil {
+		return nil, err
+	}
+	if toID == "" {
+		toID = SnapshotWorkingTree
+	}
+	to, err := t.resolveSnapshotObject(ctx, toSession, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := t.snapshotChanges(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := t.runGit(ctx, nil, "diff", "--no-color", "--no-renames", from, to)
+	if err != nil {
+		return nil, domain.NewGitError("diff", err)
+	}
+	diff, truncated := TruncateDiff(diff, maxDiffSizeKB)
+
+	return &SnapshotDiffResult{
+		From:        fromID,
+		To:          toID,
+		Files:       files,
+		Diff:        diff,
+		IsTruncated: truncated,
+	}, nil
+}
+
+// RestoreSnapshot rewrites the working tree to match a snapshot. Files that exist
+// now but not in the snapshot are deleted; ignored files are left untouched.
+// The current state is captured as a backup snapshot first so the restore can be
+// undone, and neither the index nor HEAD is modified.
+func (t *Tracker) RestoreSnapshot(ctx context.Context, sessionID, snapshotID string, maxSnapshots int) (*SnapshotRestoreResult, error) {
+	if !t.IsGitRepo() {
+		return nil, domain.ErrNotGitRepo
+	}
+	if snapshotID == SnapshotWorkingTree {
+		return nil, fmt.Errorf("cannot restore %s", SnapshotWorkingTree)
+	}
+
+	target, err := t.resolveSnapshotObject(ctx, sessionID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	backup, _, err := t.CreateSnapshot(ctx, sessionID, "before restore of "+snapshotID, maxSnapshots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture pre-restore snapshot: %w", err)
+	}
+
+	changes, err := t.snapshotChanges(ctx, backup.Commit, target)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SnapshotRestoreResult{
+		Snapshot: snapshotID,
+		Backup:   backup,
+		Restored: make([]string, 0),
+		Deleted:  make([]string, 0),
+	}
+
+	for _, change := range changes {
+		if change.Status == "D" {
+			// Present now, absent in the snapshot.
+			if err := os.Remove(filepath.Join(t.repoRoot, filepath.FromSlash(change.Path))); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to delete %s: %w", change.Path, err)
+			}
+			result.Deleted = append(result.Deleted, change.Path)
+			continue
+		}
+		result.Restored = append(result.Restored, change.Path)
+	}
+
+	if len(result.Restored) > 0 {
+		if err := t.checkoutSnapshotFiles(ctx, target, result.Restored); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Success = true
+	return result, nil
+}
+
+// DeleteSnapshots removes all snapshot refs recorded for a session.
+func (t *Tracker) DeleteSnapshots(ctx context.Context, sessionID string) error {
+	snapshots, err := t.ListSnapshots(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if _, err := t.runGit(ctx, nil, "update-ref", "-d", snapshot.Ref); err != nil {
+			return domain.NewGitError("update-ref -d", err)
+		}
+	}
+	return nil
+}
+
+// MoveSnapshots re-files every snapshot of a session under another session ID,
+// keeping the snapshot IDs. It is used for snapshots taken before the session
+// they belong to had an ID.
+func (t *Tracker) MoveSnapshots(ctx context.Context, fromSession, toSession string) ([]Snapshot, error) {
+	if err := validateSnapshotComponent("session_id", toSession); err != nil {
+		return nil, err
+	}
+	snapshots, err := t.ListSnapshots(ctx, fromSession)
+	if err != nil {
+		return nil, err
+	}
+	for i := range snapshots {
+		ref := snapshotRef(toSession, snapshots[i].ID)
+		if _, err := t.runGit(ctx, nil, "update-ref", ref, snapshots[i].Commit); err != nil {
+			return nil, domain.NewGitError("update-ref", err)
+		}
+		if _, err := t.runGit(ctx, nil, "update-ref", "-d", snapshots[i].Ref); err != nil {
+			return nil, domain.NewGitError("update-ref -d", err)
+		}
+		snapshots[i].SessionID = toSession
+		snapshots[i].Ref = ref
+	}
+	return snapshots, nil
+}
+
+// workingTreeObject writes the current working tree into a throwaway index and
+// returns the resulting tree object ID.
+func (t *Tracker) workingTreeObject(ctx context.Context) (string, error) {
+	indexFile, cleanup, err := t.tempIndex(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	env := []string{"GIT_INDEX_FILE=" + indexFile}
+	if _, err := t.runGit(ctx, env, "add", "-A", "--", "."); err != nil {
+		return "", domain.NewGitError("add", err)
+	}
+	tree, err := t.runGit(ctx, env, "write-tree")
+	if err != nil {
+		return "", domain.NewGitError("write-tree", err)
+	}
+	return strings.TrimSpace(tree), nil
+}
+
+// tempIndex creates a private copy of the repository index so snapshot
+// operations benefit from cached stat data without touching the real index.
+func (t *Tracker) tempIndex(ctx context.Context) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "cdev-snapshot-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	indexFile := filepath.Join(dir, "index")
+
+	indexPath, err := t.runGit(ctx, nil, "rev-parse", "--path-format=absolute", "--git-path", "index")
+	if err == nil {
+		if src, openErr := os.Open(strings.TrimSpace(indexPath)); openErr == nil {
+			dst, createErr := os.Create(indexFile)
+			if createErr == nil {
+				_, _ = io.Copy(dst, src)
+				_ = dst.Close()
+			}
+			_ = src.Close()
+		}
+	}
+
+	return indexFile, cleanup, nil
+}
+
+// resolveSnapshotObject maps a snapshot ID (or SnapshotWorkingTree) to a git object.
+func (t *Tracker) resolveSnapshotObject(ctx context.Context, sessionID, snapshotID string) (string, error) {
+	if snapshotID == SnapshotWorkingTree {
+		return t.workingTreeObject(ctx)
+	}
+	if err := validateSnapshotComponent("session_id", sessionID); err != nil {
+		return "", err
+	}
+	if err := validateSnapshotComponent("snapshot_id", snapshotID); err != nil {
+		return "", err
+	}
+
+	commit, err := t.runGit(ctx, nil, "rev-parse", "--verify", "-q", snapshotRef(sessionID, snapshotID)+"^{commit}")
+	if err != nil || strings.TrimSpace(commit) == "" {
+		return "", fmt.Errorf("snapshot not found: %s", snapshotID)
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+// snapshotChanges lists the per-file differences between two tree-ish objects.
+func (t *Tracker) snapshotChanges(ctx context.Context, from, to string) ([]SnapshotFileChange, error) {
+	statusOut, err := t.runGit(ctx, nil, "diff", "--name-status", "--no-renames", "-z", from, to)
+	if err != nil {
+		return nil, domain.NewGitError("diff --name-status", err)
+	}
+	numstatOut, err := t.runGit(ctx, nil, "diff", "--numstat", "--no-renames", "-z", from, to)
+	if err != nil {
+		return nil, domain.NewGitError("diff --numstat", err)
+	}
+
+	stats := make(map[string][2]int)
+	for _, record := range strings.Split(numstatOut, "\x00") {
+		fields := strings.SplitN(record, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		additions, _ := strconv.Atoi(fields[0]) // "-" for binary files
+		deletions, _ := strconv.Atoi(fields[1])
+		stats[fields[2]] = [2]int{additions, deletions}
+	}
+
+	changes := make([]SnapshotFileChange, 0)
+	tokens := strings.Split(strings.TrimSuffix(statusOut, "\x00"), "\x00")
+	for i := 0; i+1 < len(tokens); i += 2 {
+		path := tokens[i+1]
+		stat := stats[path]
+		changes = append(changes, SnapshotFileChange{
+			Path:      path,
+			Status:    tokens[i][:1],
+			Additions: stat[0],
+			Deletions: stat[1],
+		})
+	}
+	return changes, nil
+}
+
+// checkoutSnapshotFiles writes the given paths from a snapshot into the working
+// tree using a throwaway index.
+func (t *Tracker) checkoutSnapshotFiles(ctx context.Context, commit string, paths []string) error {
+	dir, err := os.MkdirTemp("", "cdev-restore-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(dir, "index")}
+	if _, err := t.runGit(ctx, env, "read-tree", commit); err != nil {
+		return domain.NewGitError("read-tree", err)
+	}
+
+	cmd := exec.CommandContext(ctx, t.command, "checkout-index", "-f", "-z", "--stdin")
+	cmd.Dir = t.repoRoot
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return domain.NewGitError("checkout-index", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output))))
+	}
+	return nil
+}
+
+// pruneSnapshots deletes the oldest snapshots beyond the retention limit.
+// snapshots must be sorted newest first.
+func (t *Tracker) pruneSnapshots(ctx context.Context, snapshots []Snapshot, maxSnapshots int) {
+	if maxSnapshots <= 0 {
+		maxSnapshots = DefaultMaxSnapshots
+	}
+	for i := maxSnapshots; i < len(snapshots); i++ {
+		_, _ = t.runGit(ctx, nil, "update-ref", "-d", snapshots[i].Ref)
+	}
+}
+
+// runGit runs a git command in the repository root with optional extra environment.
+func (t *Tracker) runGit(ctx context.Context, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, t.command, args...)
+	cmd.Dir = t.repoRoot
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return string(output), fmt.Errorf("%w: %s", err, msg)
+		}
+		return string(output), err
+	}
+	return string(output), nil
+}
+
+// snapshotIdentityEnv pins the author of snapshot commits so creation works even
+// when the user has no git identity configured.
+func snapshotIdentityEnv() []string {
+	return []string{
+		"GIT_AUTHOR_NAME=cdev",
+		"GIT_AUTHOR_EMAIL=cdev@localhost",
+		"GIT_COMMITTER_NAME=cdev",
+		"GIT_COMMITTER_EMAIL=cdev@localhost",
+	}
+}
+
+func snapshotRef(sessionID, snapshotID string) string {
+	return SnapshotRefPrefix + sessionID + "/" + snapshotID
+}
+
+// validateSnapshotComponent rejects values that would escape the snapshot ref namespace.
+func validateSnapshotComponent(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required", name)
+	}
+	if strings.ContainsAny(value, "/\\ ~^:?*[") || strings.Contains(value, "..") || strings.HasPrefix(value, ".") || strings.HasSuffix(value, ".lock") {
+		return fmt.Errorf("invalid %s: %s", name, value)
+	}
+	return nil
+}
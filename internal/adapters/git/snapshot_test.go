@@ -0,0 +1,215 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func initSnapshotTestRepo(t *testing.T) (*Tracker, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), snapshotIdentityEnv()...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	writeSnapshotTestFile(t, repo, "tracked.txt", "v1\n")
+	writeSnapshotTestFile(t, repo, ".gitignore", "ignored.log\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "initial")
+
+	return NewTracker(repo, "git", nil), repo
+}
+
+func writeSnapshotTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestSnapshot_CreateListRestore(t *testing.T) {
+	tracker, repo := initSnapshotTestRepo(t)
+	ctx := context.Background()
+
+	// Uncommitted and untracked work that the snapshot must capture.
+	writeSnapshotTestFile(t, repo, "tracked.txt", "v2\n")
+	writeSnapshotTestFile(t, repo, "untracked.txt", "new\n")
+	statusBefore := gitOutput(t, repo, "status", "--porcelain")
+	headBefore := gitOutput(t, repo, "rev-parse", "HEAD")
+
+	snap, created, err := tracker.CreateSnapshot(ctx, "sess-1", "turn 1", 0)
+	if err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+	if !created {
+		t.Fatal("CreateSnapshot() created = false, want true")
+	}
+	if snap.Head != headBefore {
+		t.Errorf("Head = %q, want %q", snap.Head, headBefore)
+	}
+
+	// Snapshotting must not touch the index, HEAD or branches.
+	if got := gitOutput(t, repo, "status", "--porcelain"); got != statusBefore {
+		t.Errorf("status changed after snapshot:\n%s\nwant:\n%s", got, statusBefore)
+	}
+	if got := gitOutput(t, repo, "rev-parse", "HEAD"); got != headBefore {
+		t.Errorf("HEAD moved to %s", got)
+	}
+	if branches := gitOutput(t, repo, "branch", "--list"); strings.Contains(branches, "cdev") {
+		t.Errorf("snapshot leaked into branches: %s", branches)
+	}
+
+	// An unchanged working tree reuses the latest snapshot.
+	again, created, err := tracker.CreateSnapshot(ctx, "sess-1", "turn 2", 0)
+	if err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+	if created || again.ID != snap.ID {
+		t.Errorf("expected dedup to snapshot %s, got %s (created=%v)", snap.ID, again.ID, created)
+	}
+
+	// Simulate a bad agent edit.
+	writeSnapshotTestFile(t, repo, "tracked.txt", "broken\n")
+	writeSnapshotTestFile(t, repo, "agent.txt", "junk\n")
+	if err := os.Remove(filepath.Join(repo, "untracked.txt")); err != nil {
+		t.Fatal(err)
+	}
+	writeSnapshotTestFile(t, repo, "ignored.log", "keep me\n")
+
+	diff, err := tracker.DiffSnapshots(ctx, "sess-1", snap.ID, "sess-1", "", 100)
+	if err != nil {
+		t.Fatalf("DiffSnapshots() error = %v", err)
+	}
+	statuses := make(map[string]string)
+	for _, f := range diff.Files {
+		statuses[f.Path] = f.Status
+	}
+	if statuses["tracked.txt"] != "M" || statuses["agent.txt"] != "A" || statuses["untracked.txt"] != "D" {
+		t.Errorf("unexpected diff files: %+v", diff.Files)
+	}
+	if _, ok := statuses["ignored.log"]; ok {
+		t.Error("ignored files should not appear in snapshot diffs")
+	}
+
+	result, err := tracker.RestoreSnapshot(ctx, "sess-1", snap.ID, 0)
+	if err != nil {
+		t.Fatalf("RestoreSnapshot() error = %v", err)
+	}
+	if result.Backup == nil {
+		t.Fatal("RestoreSnapshot() should capture a backup snapshot")
+	}
+
+	for name, want := range map[string]string{"tracked.txt": "v2\n", "untracked.txt": "new\n", "ignored.log": "keep me\n"} {
+		data, err := os.ReadFile(filepath.Join(repo, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(repo, "agent.txt")); !os.IsNotExist(err) {
+		t.Error("agent.txt should have been deleted by restore")
+	}
+	if got := gitOutput(t, repo, "status", "--porcelain"); got != statusBefore {
+		t.Errorf("status after restore:\n%s\nwant:\n%s", got, statusBefore)
+	}
+
+	list, err := tracker.ListSnapshots(ctx, "sess-1")
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(list) != 2 || list[0].ID != result.Backup.ID {
+		t.Errorf("ListSnapshots() = %+v, want backup first then original", list)
+	}
+}
+
+func TestSnapshot_Prune(t *testing.T) {
+	tracker, repo := initSnapshotTestRepo(t)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		writeSnapshotTestFile(t, repo, "tracked.txt", strings.Repeat("x", i+1))
+		if _, _, err := tracker.CreateSnapshot(ctx, "sess-prune", "", 2); err != nil {
+			t.Fatalf("CreateSnapshot() error = %v", err)
+		}
+	}
+
+	list, err := tracker.ListSnapshots(ctx, "sess-prune")
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(list) != 2 {
+		t.Errorf("len(ListSnapshots()) = %d, want 2", len(list))
+	}
+}
+
+func TestValidateSnapshotComponent(t *testing.T) {
+	valid := []string{"sess-1", "0b7a4f1e-2c7d-4b9a-9a2f-1c2d3e4f5a6b", "1700000000000000000"}
+	for _, v := range valid {
+		if err := validateSnapshotComponent("id", v); err != nil {
+			t.Errorf("validateSnapshotComponent(%q) = %v, want nil", v, err)
+		}
+	}
+
+	invalid := []string{"", "a/b", "..", ".hidden", "x.lock", "a b", "a:b"}
+	for _, v := range invalid {
+		if err := validateSnapshotComponent("id", v); err == nil {
+			t.Errorf("validateSnapshotComponent(%q) = nil, want error", v)
+		}
+	}
+}
+
+func TestSnapshot_Move(t *testing.T) {
+	tracker, repo := initSnapshotTestRepo(t)
+	ctx := context.Background()
+
+	writeSnapshotTestFile(t, repo, "tracked.txt", "v2\n")
+	snap, _, err := tracker.CreateSnapshot(ctx, "pending-1", "before: start", 0)
+	if err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+
+	moved, err := tracker.MoveSnapshots(ctx, "pending-1", "sess-1")
+	if err != nil {
+		t.Fatalf("MoveSnapshots() error = %v", err)
+	}
+	if len(moved) != 1 || moved[0].ID != snap.ID || moved[0].SessionID != "sess-1" {
+		t.Fatalf("MoveSnapshots() = %+v, want %s under sess-1", moved, snap.ID)
+	}
+
+	if list, _ := tracker.ListSnapshots(ctx, "pending-1"); len(list) != 0 {
+		t.Errorf("pending snapshots left behind: %+v", list)
+	}
+	list, err := tracker.ListSnapshots(ctx, "sess-1")
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(list) != 1 || list[0].Commit != snap.Commit || list[0].Label != "before: start" {
+		t.Errorf("ListSnapshots(sess-1) = %+v, want the moved snapshot", list)
+	}
+}
@@ -984,12 +984,16 @@ func (t *Tracker) Pull(ctx context.Context, rebase bool) (*PullResult, error) {
 		args = append(args, "--rebase")
 	}
 
+	var stdout, stderr bytes.Buffer
+	errOut, progress := gitStderr(ctx, &stderr)
+	if progress {
+		args = append(args, "--progress")
+	}
+
 	cmd := exec.CommandContext(ctx, t.command, args...)
 	cmd.Dir = t.repoRoot
-
-	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	cmd.Stderr = errOut
 
 	err := cmd.Run()
 	outputStr := stdout.String() + stderr.String()
@@ -1337,10 +1341,20 @@ func (t *Tracker) Fetch(ctx context.Context, remote string, prune bool) (*FetchR
 	}
 	args = append(args, "--verbose")
 
+	// Both streams go through the same writer, which exec then feeds from
+	// a single pipe, as CombinedOutput does
+	var output bytes.Buffer
+	combined, progress := gitStderr(ctx, &output)
+	if progress {
+		args = append(args, "--progress")
+	}
+
 	cmd := exec.CommandContext(ctx, t.command, args...)
 	cmd.Dir = t.repoRoot
-	output, err := cmd.CombinedOutput()
-	outputStr := string(output)
+	cmd.Stdout = combined
+	cmd.Stderr = combined
+	err := cmd.Run()
+	outputStr := output.String()
 
 	if err != nil {
 		return &FetchResult{
@@ -0,0 +1,95 @@
+// Package procstat samples resource usage of agent process trees.
+package procstat
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrUnsupported is returned on platforms without a process sampler.
+var ErrUnsupported = errors.New("process sampling is not supported on this platform")
+
+// ErrProcessNotFound is returned when the root process no longer exists.
+var ErrProcessNotFound = errors.New("process not found")
+
+// Usage is the resource usage of a process and all of its descendants.
+type Usage struct {
+	PID        int       `json:"pid"`
+	Processes  int       `json:"processes"`   // Root process plus descendants
+	CPUPercent float64   `json:"cpu_percent"` // Since the previous sample, 100 = one core
+	RSSBytes   uint64    `json:"rss_bytes"`
+	OpenFiles  int       `json:"open_files"`
+	SampledAt  time.Time `json:"sampled_at"`
+}
+
+// treeStats is the raw, platform-specific reading of a process tree.
+type treeStats struct {
+	processes int
+	cpuTime   time.Duration // Cumulative user+system time of live processes
+	rssBytes  uint64
+	openFiles int
+}
+
+type cpuReading struct {
+	cpuTime time.Duration
+	at      time.Time
+}
+
+// Sampler samples process trees. CPU usage is derived from the difference
+// between successive samples of the same root PID, so the first sample of a
+// tree always reports 0% CPU.
+type Sampler struct {
+	mu   sync.Mutex
+	prev map[int]cpuReading
+	now  func() time.Time
+}
+
+// NewSampler creates a new process tree sampler.
+func NewSampler() *Sampler {
+	return &Sampler{
+		prev: make(map[int]cpuReading),
+		now:  time.Now,
+	}
+}
+
+// Sample reads the current usage of rootPID and its descendants.
+func (s *Sampler) Sample(rootPID int) (*Usage, error) {
+	stats, err := readTree(rootPID)
+	if err != nil {
+		return nil, err
+	}
+	return s.usage(rootPID, stats), nil
+}
+
+// Forget drops the CPU baseline for rootPID once its process has exited.
+func (s *Sampler) Forget(rootPID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.prev, rootPID)
+}
+
+func (s *Sampler) usage(rootPID int, stats treeStats) *Usage {
+	now := s.now()
+
+	s.mu.Lock()
+	prev, ok := s.prev[rootPID]
+	s.prev[rootPID] = cpuReading{cpuTime: stats.cpuTime, at: now}
+	s.mu.Unlock()
+
+	var cpuPercent float64
+	// Exited children take their CPU time with them, so the tree total can
+	// go down between samples; report 0 rather than a negative value.
+	if elapsed := now.Sub(prev.at); ok && elapsed > 0 && stats.cpuTime > prev.cpuTime {
+		cpuPercent = float64(stats.cpuTime-prev.cpuTime) / float64(elapsed) * 100
+	}
+
+	return &Usage{
+		PID:        rootPID,
+		Processes:  stats.processes,
+		CPUPercent: cpuPercent,
+		RSSBytes:   stats.rssBytes,
+		OpenFiles:  stats.openFiles,
+		SampledAt:  now.UTC(),
+	}
+}
@@ -0,0 +1,126 @@
+//go:build linux
+
+package procstat
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// userHZ is the kernel's USER_HZ, the unit of utime/stime in /proc/<pid>/stat.
+// It is 100 on every mainstream Linux architecture.
+const userHZ = 100
+
+var procRoot = "/proc"
+
+type procStat struct {
+	ppid     int
+	ticks    uint64
+	rssPages uint64
+}
+
+// readTree walks /proc once, links processes by parent PID and sums the
+// usage of rootPID and everything below it.
+func readTree(rootPID int) (treeStats, error) {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return treeStats{}, err
+	}
+
+	stats := make(map[int]procStat, len(entries))
+	children := make(map[int][]int)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		st, err := readProcStat(pid)
+		if err != nil {
+			continue // Process exited while walking
+		}
+		stats[pid] = st
+		children[st.ppid] = append(children[st.ppid], pid)
+	}
+
+	if _, ok := stats[rootPID]; !ok {
+		return treeStats{}, fmt.Errorf("%w: %d", ErrProcessNotFound, rootPID)
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	var result treeStats
+	var ticks uint64
+	queue := []int{rootPID}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+
+		st := stats[pid]
+		result.processes++
+		ticks += st.ticks
+		result.rssBytes += st.rssPages * pageSize
+		result.openFiles += countOpenFiles(pid)
+		queue = append(queue, children[pid]...)
+	}
+	result.cpuTime = time.Duration(ticks) * time.Second / userHZ
+
+	return result, nil
+}
+
+// readProcStat parses /proc/<pid>/stat. The comm field is parenthesised and
+// may itself contain spaces or parentheses, so fields are counted from the
+// last ')'.
+func readProcStat(pid int) (procStat, error) {
+	data, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+	return parseProcStat(data)
+}
+
+func parseProcStat(data []byte) (procStat, error) {
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return procStat{}, fmt.Errorf("malformed stat line")
+	}
+	// fields[0] is state (field 3 in proc(5)).
+	fields := bytes.Fields(data[end+1:])
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("malformed stat line: %d fields", len(fields))
+	}
+
+	ppid, err := strconv.Atoi(string(fields[1]))
+	if err != nil {
+		return procStat{}, err
+	}
+	utime, err := strconv.ParseUint(string(fields[11]), 10, 64)
+	if err != nil {
+		return procStat{}, err
+	}
+	stime, err := strconv.ParseUint(string(fields[12]), 10, 64)
+	if err != nil {
+		return procStat{}, err
+	}
+	rss, err := strconv.ParseInt(string(fields[21]), 10, 64)
+	if err != nil {
+		return procStat{}, err
+	}
+	if rss < 0 {
+		rss = 0
+	}
+
+	return procStat{ppid: ppid, ticks: utime + stime, rssPages: uint64(rss)}, nil
+}
+
+// countOpenFiles counts /proc/<pid>/fd entries. Processes owned by other
+// users are unreadable and count as zero.
+func countOpenFiles(pid int) int {
+	entries, err := os.ReadDir(filepath.Join(procRoot, strconv.Itoa(pid), "fd"))
+	if err != nil {
+		return 0
+	}
+	return len(entries)
+}
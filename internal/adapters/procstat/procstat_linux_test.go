@@ -0,0 +1,60 @@
+//go:build linux
+
+package procstat
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestParseProcStat(t *testing.T) {
+	line := []byte("1234 (weird) (name) S 1 1234 1234 0 -1 4194304 100 0 0 0 250 50 0 0 20 0 1 0 100 1000000 300 18446744073709551615")
+	st, err := parseProcStat(line)
+	if err != nil {
+		t.Fatalf("parseProcStat() error = %v", err)
+	}
+	if st.ppid != 1 || st.ticks != 300 || st.rssPages != 300 {
+		t.Fatalf("parseProcStat() = %+v, want ppid=1 ticks=300 rss=300", st)
+	}
+
+	if _, err := parseProcStat([]byte("garbage")); err == nil {
+		t.Fatal("parseProcStat() should reject malformed input")
+	}
+}
+
+func TestSampler_SampleIncludesChildren(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("/proc not available")
+	}
+
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start child: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	usage, err := NewSampler().Sample(os.Getpid())
+	if err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if usage.Processes < 2 {
+		t.Errorf("Processes = %d, want at least 2 (self + child)", usage.Processes)
+	}
+	if usage.RSSBytes == 0 {
+		t.Error("RSSBytes should be non-zero")
+	}
+	if usage.OpenFiles == 0 {
+		t.Error("OpenFiles should be non-zero")
+	}
+}
+
+func TestSampler_SampleMissingProcess(t *testing.T) {
+	if _, err := NewSampler().Sample(1 << 30); !errors.Is(err, ErrProcessNotFound) {
+		t.Fatalf("Sample() error = %v, want ErrProcessNotFound", err)
+	}
+}
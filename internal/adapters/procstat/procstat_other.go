@@ -0,0 +1,7 @@
+//go:build !linux
+
+package procstat
+
+func readTree(rootPID int) (treeStats, error) {
+	return treeStats{}, ErrUnsupported
+}
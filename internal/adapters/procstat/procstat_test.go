@@ -0,0 +1,35 @@
+package procstat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSamplerUsage_CPUPercentFromDelta(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := NewSampler()
+	s.now = func() time.Time { return now }
+
+	first := s.usage(42, treeStats{processes: 2, cpuTime: 10 * time.Second})
+	if first.CPUPercent != 0 {
+		t.Fatalf("first sample CPUPercent = %v, want 0", first.CPUPercent)
+	}
+
+	now = now.Add(2 * time.Second)
+	second := s.usage(42, treeStats{processes: 2, cpuTime: 13 * time.Second})
+	if second.CPUPercent != 150 {
+		t.Fatalf("CPUPercent = %v, want 150", second.CPUPercent)
+	}
+
+	// A child exiting drops the tree total; never report negative usage.
+	now = now.Add(2 * time.Second)
+	third := s.usage(42, treeStats{processes: 1, cpuTime: 5 * time.Second})
+	if third.CPUPercent != 0 {
+		t.Fatalf("CPUPercent after child exit = %v, want 0", third.CPUPercent)
+	}
+
+	s.Forget(42)
+	if _, ok := s.prev[42]; ok {
+		t.Fatal("Forget() should drop the CPU baseline")
+	}
+}
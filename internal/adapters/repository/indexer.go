@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/brianly1003/cdev/internal/domain/ports"
 	"github.com/brianly1003/cdev/internal/pathutil"
 	"github.com/rs/zerolog/log"
 	_ "modernc.org/sqlite"
@@ -403,6 +404,7 @@ func (idx *SQLiteIndexer) FullScan(ctx context.Context) error {
 	}()
 
 	// Scan all files
+	ports.ReportProgress(ctx, "Scanning files", 0)
 	files, err := idx.scanner.ScanAll(ctx)
 	if err != nil {
 		return fmt.Errorf("scan failed: %w", err)
@@ -467,6 +469,9 @@ func (idx *SQLiteIndexer) FullScan(ctx context.Context) error {
 			idx.status.IndexedFiles = i
 			idx.mu.Unlock()
 		}
+		if i%100 == 0 {
+			ports.ReportProgress(ctx, fmt.Sprintf("Indexing files (%d/%d)", i, len(files)), 5+85*i/len(files))
+		}
 	}
 
 	// Commit transaction
@@ -475,6 +480,7 @@ func (idx *SQLiteIndexer) FullScan(ctx context.Context) error {
 	}
 
 	// Rebuild FTS5 index (required for external content FTS tables after bulk operations)
+	ports.ReportProgress(ctx, "Rebuilding search index", 90)
 	if _, err := idx.db.Exec("INSERT INTO repository_files_fts(repository_files_fts) VALUES('rebuild')"); err != nil {
 		log.Warn().Err(err).Msg("failed to rebuild FTS5 index")
 	}
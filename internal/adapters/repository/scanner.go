@@ -292,10 +292,20 @@ func (s *Scanner) isBinaryFile(ext, name string) bool {
 
 // isSensitiveFile returns true if the file matches sensitive patterns.
 func (s *Scanner) isSensitiveFile(name, path string) bool {
+	return matchesSensitivePattern(s.sensitivePatterns, name, path)
+}
+
+// IsSensitiveFile reports whether a file, given by its base name and its path,
+// matches the default SensitivePatterns.
+func IsSensitiveFile(name, path string) bool {
+	return matchesSensitivePattern(SensitivePatterns, name, path)
+}
+
+func matchesSensitivePattern(patterns []string, name, path string) bool {
 	lowerName := strings.ToLower(name)
 	lowerPath := strings.ToLower(path)
 
-	for _, pattern := range s.sensitivePatterns {
+	for _, pattern := range patterns {
 		// Check name match
 		if matched, _ := filepath.Match(strings.ToLower(pattern), lowerName); matched {
 			return true
@@ -1424,6 +1424,14 @@ func (a *WorkspacePathResolverAdapter) GetWorkspacePath(workspaceID string) (str
 	return ws.Definition.Path, nil
 }
 
+// ListWorkspaceIDs returns the IDs of all configured workspaces.
+func (a *WorkspacePathResolverAdapter) ListWorkspaceIDs() []string {
+	if a.configManager == nil {
+		return nil
+	}
+	return a.configManager.GetAllWorkspaceIDs()
+}
+
 // TaskWorkspaceResolverAdapter implements httpserver.WorkspaceResolver.
 // It resolves workspace identifiers (ID, name, or path) to workspace IDs
 // for the webhook handler.
@@ -1444,3 +1452,31 @@ func (a *TaskWorkspaceResolverAdapter) ResolveWorkspaceID(idOrName string) (stri
 	}
 	return ws.Definition.ID, nil
 }
+
+// PermissionWorkspaceResolverAdapter implements methods.WorkspaceResolver and
+// httpserver.WorkspaceResolver for the permission hook bridge.
+// It maps a tool call's cwd to the configured workspace containing it, so
+// workspace permission rules are keyed by real workspace IDs. Directories
+// outside any configured workspace fall back to their base name.
+type PermissionWorkspaceResolverAdapter struct {
+	configManager *workspace.ConfigManager
+	fallback      *methods.WorkspaceIDResolver
+}
+
+// NewPermissionWorkspaceResolverAdapter creates a new permission workspace resolver adapter.
+func NewPermissionWorkspaceResolverAdapter(configManager *workspace.ConfigManager) *PermissionWorkspaceResolverAdapter {
+	return &PermissionWorkspaceResolverAdapter{
+		configManager: configManager,
+		fallback:      methods.NewWorkspaceIDResolver(),
+	}
+}
+
+// ResolveWorkspaceID resolves a cwd to a workspace ID.
+func (a *PermissionWorkspaceResolverAdapter) ResolveWorkspaceID(cwd string) (string, error) {
+	if a.configManager != nil {
+		if ws, err := a.configManager.ResolveWorkspace(cwd); err == nil {
+			return ws.Definition.ID, nil
+		}
+	}
+	return a.fallback.ResolveWorkspaceID(cwd)
+}
@@ -28,9 +28,11 @@ import (
 	"github.com/brianly1003/cdev/internal/permission"
 	"github.com/brianly1003/cdev/internal/rpc/handler"
 	"github.com/brianly1003/cdev/internal/rpc/handler/methods"
+	"github.com/brianly1003/cdev/internal/rpc/transport"
 	"github.com/brianly1003/cdev/internal/security"
 	httpserver "github.com/brianly1003/cdev/internal/server/http"
 	httpMiddleware "github.com/brianly1003/cdev/internal/server/http/middleware"
+	"github.com/brianly1003/cdev/internal/server/mcp"
 	"github.com/brianly1003/cdev/internal/server/unified"
 	"github.com/brianly1003/cdev/internal/services/imagestorage"
 	"github.com/brianly1003/cdev/internal/session"
@@ -48,6 +50,7 @@ type App struct {
 
 	// Core components
 	hub                 *hub.Hub
+	eventJournal        *hub.Journal
 	claudeManager       *claude.Manager
 	fileWatcher         *watcher.Watcher
 	gitTracker          *git.Tracker
@@ -75,6 +78,9 @@ type App struct {
 
 	// Permission hook bridge
 	permissionManager *permission.MemoryManager
+	permissionPolicy  *permission.PolicyEngine
+	permissionAudit   *permission.AuditLog
+	permissionRouter  *permission.Router
 
 	// Claude Code hooks for external session capture
 	hooksManager *hooks.Manager
@@ -85,6 +91,14 @@ type App struct {
 	// Terminal mode support (headless=false)
 	terminalRunner *terminal.Runner
 
+	// Local transport served instead of the HTTP listener (cdev rpc --stdio,
+	// or cdev mcp --stdio when localMCP is set)
+	localTransport transport.Transport
+	localMCP       bool
+
+	// MCP server exposing curated methods to agents
+	mcpServer *mcp.Server
+
 	// Session info
 	sessionID string
 	startTime time.Time
@@ -118,6 +132,21 @@ func New(cfg *config.Config, version string) (*App, error) {
 	return app, nil
 }
 
+// SetLocalTransport makes Start serve a single JSON-RPC client over t
+// instead of listening on the HTTP port. Claude Code hooks are left alone
+// and nothing is printed to stdout, so t may use stdin/stdout. Start then
+// returns when the transport closes. Call it before Start.
+func (a *App) SetLocalTransport(t transport.Transport) {
+	a.localTransport = t
+}
+
+// SetLocalMCPTransport is SetLocalTransport for an MCP client: t is served
+// the MCP tools instead of the JSON-RPC API.
+func (a *App) SetLocalMCPTransport(t transport.Transport) {
+	a.localTransport = t
+	a.localMCP = true
+}
+
 // Start starts the application and blocks until context is cancelled.
 func (a *App) Start(ctx context.Context) error {
 	a.mu.Lock()
@@ -129,6 +158,23 @@ func (a *App) Start(ctx context.Context) error {
 	a.startTime = time.Now()
 	a.mu.Unlock()
 
+	// Event journal: sequences events and replays them to reconnecting clients.
+	// The journal file belongs to the daemon: processes serving a local
+	// transport (one per agent session for cdev mcp --stdio) keep theirs in
+	// memory, so they do not compact or renumber the daemon's events.
+	if a.cfg.Events.Journal.Enabled && a.localTransport == nil {
+		journalPath := config.DefaultEventJournalPath()
+		journal, err := hub.OpenJournal(journalPath, a.cfg.Events.Journal.MaxEvents)
+		if err != nil {
+			log.Warn().Err(err).Str("journal_path", journalPath).Msg("failed to open event journal, keeping it in memory")
+			journal = hub.NewJournal(a.cfg.Events.Journal.MaxEvents)
+		}
+		a.eventJournal = journal
+	} else {
+		a.eventJournal = hub.NewJournal(a.cfg.Events.Journal.MaxEvents)
+	}
+	a.hub.SetJournal(a.eventJournal)
+
 	// Start event hub
 	if err := a.hub.Start(); err != nil {
 		return fmt.Errorf("failed to start event hub: %w", err)
@@ -268,16 +314,19 @@ func (a *App) Start(ctx context.Context) error {
 
 	// Initialize Claude Code Hooks Manager for external session capture
 	// This allows cdev to receive real-time events from Claude running in VS Code, Cursor, or terminal
-	a.hooksManager = hooks.NewManager(a.cfg.Server.Port)
-	if !a.hooksManager.IsInstalled() {
-		log.Info().Msg("Claude Code hooks not installed - installing for external session capture...")
-		if err := a.hooksManager.Install(); err != nil {
-			log.Warn().Err(err).Msg("failed to install Claude Code hooks - external session capture will not work")
+	// Skipped when serving a local transport: without a listener the hooks have nowhere to post to
+	if a.localTransport == nil {
+		a.hooksManager = hooks.NewManager(a.cfg.Server.Port)
+		if !a.hooksManager.IsInstalled() {
+			log.Info().Msg("Claude Code hooks not installed - installing for external session capture...")
+			if err := a.hooksManager.Install(); err != nil {
+				log.Warn().Err(err).Msg("failed to install Claude Code hooks - external session capture will not work")
+			} else {
+				log.Info().Msg("Claude Code hooks installed - external Claude sessions will now be captured")
+			}
 		} else {
-			log.Info().Msg("Claude Code hooks installed - external Claude sessions will now be captured")
+			log.Debug().Str("status", a.hooksManager.Status()).Msg("Claude Code hooks already installed")
 		}
-	} else {
-		log.Debug().Str("status", a.hooksManager.Status()).Msg("Claude Code hooks already installed")
 	}
 
 	// Initialize GitTrackerManager for cached git operations
@@ -300,6 +349,13 @@ func (a *App) Start(ctx context.Context) error {
 	// Connect session manager to git tracker manager
 	a.sessionManager.SetGitTrackerManager(a.gitTrackerManager)
 
+	handoffStorePath := config.DefaultHandoffStorePath()
+	handoffStore, err := session.LoadHandoffStore(handoffStorePath)
+	if err != nil {
+		log.Warn().Err(err).Str("handoff_store_path", handoffStorePath).Msg("failed to load handoff links")
+	}
+	a.sessionManager.SetHandoffStore(handoffStore)
+
 	// Register persisted workspaces with the session manager
 	registered := 0
 	for _, ws := range a.workspaceConfigManager.ListWorkspaces() {
@@ -333,10 +389,46 @@ func (a *App) Start(ctx context.Context) error {
 			MaxPatterns: a.cfg.Permissions.SessionMemory.MaxPatterns,
 		}
 		a.permissionManager = permission.NewMemoryManager(permConfig, log.Logger)
+		rulesPath := config.DefaultPermissionRulesPath()
+		ruleStore, err := permission.LoadRuleStore(rulesPath)
+		if err != nil {
+			log.Warn().Err(err).Str("rules_path", rulesPath).Msg("failed to load permission rules")
+		}
+		a.permissionManager.SetRuleStore(ruleStore)
 		a.permissionManager.StartCleanup(ctx)
 		log.Info().Msg("permission hook bridge enabled")
 	}
 
+	// Declarative permission policies (global and per workspace), evaluated before memory and mobile
+	workspacePaths := NewWorkspacePathResolverAdapter(a.workspaceConfigManager)
+	a.permissionPolicy = permission.NewPolicyEngine(
+		config.DefaultPermissionPolicyPath(),
+		config.DefaultWorkspacePolicyDir(),
+		func(workspaceID string) string {
+			path, _ := workspacePaths.GetWorkspacePath(workspaceID)
+			return path
+		},
+	)
+
+	// Durable audit trail of every permission decision
+	auditPath := config.DefaultPermissionAuditPath()
+	if auditLog, err := permission.OpenAuditLog(auditPath); err != nil {
+		log.Warn().Err(err).Str("audit_path", auditPath).Msg("failed to open permission audit log")
+	} else {
+		a.permissionAudit = auditLog
+	}
+
+	// Multi-device routing of permission prompts (primary devices, escalation, quorum)
+	routing := a.cfg.Permissions.Routing
+	a.permissionRouter = permission.NewRouter(permission.RoutingConfig{
+		Enabled:           routing.Enabled,
+		PrimaryDevices:    routing.PrimaryDevices,
+		EscalateAfter:     time.Duration(routing.EscalateAfterSeconds) * time.Second,
+		HighRisk:          routing.HighRisk,
+		HighRiskApprovals: routing.HighRiskApprovals,
+		TimeoutDecision:   permission.Decision(routing.TimeoutDecision),
+	})
+
 	// Initialize Agent Task system (task store, spawner)
 	if a.cfg.AgentTask.Enabled {
 		store, err := taskstore.NewStore()
@@ -429,29 +521,34 @@ func (a *App) Start(ctx context.Context) error {
 		Msg("session started")
 
 	// Print connection info
-	a.printConnectionInfo()
+	if a.localTransport == nil {
+		a.printConnectionInfo()
+	}
 
 	// Create RPC registry and dispatcher for JSON-RPC methods
 	rpcRegistry := handler.NewRegistry()
 
-	// Register RPC method services
+	// RPC method services, registered together below. The generated client
+	// is built from the same list (methods.SchemaServices).
+	var services methods.Services
+
 	// Status service
 	statusService := methods.NewStatusService(a)
-	statusService.RegisterMethods(rpcRegistry)
+	services.Status = statusService
 
 	// Agent service (using Claude manager wrapped as AgentManager)
 	agentService := methods.NewAgentService(NewClaudeAgentAdapter(a.claudeManager))
-	agentService.RegisterMethods(rpcRegistry)
+	services.Agent = agentService
 
 	// Git service
 	if a.gitTracker != nil {
 		gitService := methods.NewGitService(NewGitProviderAdapter(a.gitTracker), a.cfg.Limits.MaxDiffSizeKB)
-		gitService.RegisterMethods(rpcRegistry)
+		services.Git = gitService
 	}
 
 	// File service
 	fileService := methods.NewFileService(NewFileProviderAdapter(a.gitTracker), a.cfg.Limits.MaxFileSizeKB)
-	fileService.RegisterMethods(rpcRegistry)
+	services.File = fileService
 
 	// Session service (pass streamer for real-time watching via RPC)
 	sessionService := methods.NewSessionService(a.sessionStreamer)
@@ -468,11 +565,11 @@ func (a *App) Start(ctx context.Context) error {
 	if a.workspaceConfigManager != nil {
 		sessionService.SetWorkspaceResolver(NewWorkspacePathResolverAdapter(a.workspaceConfigManager))
 	}
-	sessionService.RegisterMethods(rpcRegistry)
+	services.Session = sessionService
 
 	// Workspace config service (workspace/list, workspace/add, etc.)
 	workspaceConfigService := methods.NewWorkspaceConfigService(a.sessionManager, a.workspaceConfigManager, a.hub, a.authRegistry)
-	workspaceConfigService.RegisterMethods(rpcRegistry)
+	services.WorkspaceConfig = workspaceConfigService
 
 	// Session manager service (session/start, session/stop, session/send, etc.)
 	sessionManagerService := methods.NewSessionManagerService(a.sessionManager)
@@ -480,12 +577,16 @@ func (a *App) Start(ctx context.Context) error {
 	if a.permissionManager != nil {
 		sessionManagerService.SetPermissionManager(a.permissionManager)
 	}
-	sessionManagerService.RegisterMethods(rpcRegistry)
+	if a.sessionManager != nil {
+		// Codex PTY sessions run outside the session manager; sample them too
+		a.sessionManager.SetAgentProcessProvider(sessionManagerService)
+	}
+	services.SessionManager = sessionManagerService
 
 	// Repository service (repository/search, repository/files/list, etc.)
 	if a.repoIndexer != nil {
 		repositoryService := methods.NewRepositoryService(a.repoIndexer)
-		repositoryService.RegisterMethods(rpcRegistry)
+		services.Repository = repositoryService
 	}
 
 	// Lifecycle service with capabilities
@@ -526,25 +627,62 @@ func (a *App) Start(ctx context.Context) error {
 		RuntimeRegistry: methods.DefaultRuntimeRegistryWithAgents(supportedAgents),
 	}
 	lifecycleService := methods.NewLifecycleService(a.version, caps)
-	lifecycleService.RegisterMethods(rpcRegistry)
+	if a.sessionManager != nil {
+		lifecycleService.SetLaunchProfileProvider(a.sessionManager)
+	}
+	services.Lifecycle = lifecycleService
 
 	// Subscription service (for workspace event filtering)
 	subscriptionService := methods.NewSubscriptionService()
-	subscriptionService.RegisterMethods(rpcRegistry)
+	services.Subscription = subscriptionService
 
 	// Client service (for multi-device session awareness)
 	// Provider will be set after unified server is created
 	clientService := methods.NewClientService(nil)
-	clientService.RegisterMethods(rpcRegistry)
+	services.Client = clientService
+
+	// Task service (agent tasks created over JSON-RPC and MCP)
+	if a.taskStore != nil {
+		taskService := methods.NewTaskService(a.taskStore, a.hub)
+		if a.taskSpawner != nil {
+			taskService.SetSpawner(a.taskSpawner)
+		}
+		if a.workspaceConfigManager != nil {
+			taskService.SetWorkspaceResolver(NewTaskWorkspaceResolverAdapter(a.workspaceConfigManager))
+		}
+		services.Task = taskService
+	}
 
 	// Permission service (for hook bridge)
+	var permissionService *methods.PermissionService
 	if a.permissionManager != nil {
-		permissionService := methods.NewPermissionService(
+		permissionService = methods.NewPermissionService(
 			a.permissionManager,
 			a.hub,
-			methods.NewWorkspaceIDResolver(),
+			NewPermissionWorkspaceResolverAdapter(a.workspaceConfigManager),
 		)
-		permissionService.RegisterMethods(rpcRegistry)
+		permissionService.SetWorkspacePathResolver(NewWorkspacePathResolverAdapter(a.workspaceConfigManager))
+		permissionService.SetPolicyEvaluator(a.permissionPolicy)
+		permissionService.SetRouter(a.permissionRouter)
+		if a.permissionAudit != nil {
+			permissionService.SetAuditLog(a.permissionAudit)
+		}
+		services.Permission = permissionService
+
+		// Codex approval prompts go through the same flow as Claude hooks
+		sessionManagerService.SetCodexApprovalDecider(permissionService)
+	}
+
+	services.Register(rpcRegistry)
+
+	// MCP server: a curated subset of the registry for agents, with the
+	// permission policy applied to each tool call
+	a.mcpServer = mcp.NewServer(rpcRegistry, a.version)
+	if a.workspaceConfigManager != nil {
+		a.mcpServer.SetWorkspaceResolver(NewTaskWorkspaceResolverAdapter(a.workspaceConfigManager))
+	}
+	if permissionService != nil {
+		a.mcpServer.SetAuthorizer(permissionService)
 	}
 
 	a.rpcDispatcher = handler.NewDispatcher(rpcRegistry)
@@ -563,11 +701,18 @@ func (a *App) Start(ctx context.Context) error {
 	subscriptionService.SetProvider(a.unifiedServer)
 	// Set git watcher manager (session manager starts/stops git watchers on subscribe/unsubscribe)
 	subscriptionService.SetGitWatcherManager(a.sessionManager)
+	subscriptionService.SetEventReplayer(a.hub)
+	// Event streams (/api/events) resume and start git watchers like workspace/subscribe
+	a.unifiedServer.SetEventReplayer(a.hub)
+	a.unifiedServer.SetGitWatcherStarter(a.sessionManager)
 	// Set disconnect handler for cleanup when clients disconnect (git watchers, session streamers)
 	a.unifiedServer.SetDisconnectHandler(a.sessionManager)
 	// Set client focus provider (unified server tracks multi-device session awareness)
 	clientFocusAdapter := NewClientFocusAdapter(a.unifiedServer)
 	clientService.SetProvider(clientFocusAdapter)
+	clientService.SetDeliveryProvider(a.unifiedServer)
+	// Route permission prompts to the paired devices that are connected
+	a.permissionRouter.SetDeviceLister(a.unifiedServer)
 	// Set viewer provider for workspace/list to include session viewers
 	workspaceConfigService.SetViewerProvider(a.unifiedServer)
 	// Set focus provider for session manager so workspace/session/watch updates viewers
@@ -702,7 +847,14 @@ func (a *App) Start(ctx context.Context) error {
 	if a.permissionManager != nil {
 		hooksHandler.SetPermissionManager(a.permissionManager)
 	}
-	hooksHandler.SetWorkspaceResolver(methods.NewWorkspaceIDResolver())
+	hooksHandler.SetWorkspaceResolver(NewPermissionWorkspaceResolverAdapter(a.workspaceConfigManager))
+	hooksHandler.SetWorkspacePathResolver(NewWorkspacePathResolverAdapter(a.workspaceConfigManager))
+	hooksHandler.SetPolicyEvaluator(a.permissionPolicy)
+	hooksHandler.SetPermissionRouter(a.permissionRouter)
+	if a.permissionAudit != nil {
+		hooksHandler.SetAuditRecorder(a.permissionAudit)
+		a.httpServer.SetPermissionAuditHandler(httpserver.NewPermissionAuditHandler(a.permissionAudit))
+	}
 	a.httpServer.SetHooksHandler(hooksHandler)
 
 	// Set up agent task handler (webhook + REST API)
@@ -721,8 +873,12 @@ func (a *App) Start(ctx context.Context) error {
 	a.httpServer.SetRPCRegistry(rpcRegistry)
 	// Set WebSocket handler for port consolidation
 	a.httpServer.SetWebSocketHandler(a.unifiedServer.HandleWebSocket)
-	if err := a.httpServer.Start(); err != nil {
-		return fmt.Errorf("failed to start HTTP server: %w", err)
+	a.httpServer.SetEventStreamHandler(a.unifiedServer.HandleEventStream)
+	a.httpServer.SetMCPHandler(a.mcpServer)
+	if a.localTransport == nil {
+		if err := a.httpServer.Start(); err != nil {
+			return fmt.Errorf("failed to start HTTP server: %w", err)
+		}
 	}
 
 	// Start file watcher
@@ -744,6 +900,18 @@ func (a *App) Start(ctx context.Context) error {
 		a.version,
 	))
 
+	if a.localTransport != nil {
+		// Serve the local client until it disconnects
+		serve := a.unifiedServer.ServeTransport
+		if a.localMCP {
+			serve = a.mcpServer.ServeTransport
+		}
+		if err := serve(ctx, a.localTransport); err != nil && ctx.Err() == nil {
+			log.Warn().Err(err).Msg("local transport closed with error")
+		}
+		return a.shutdown()
+	}
+
 	// Wait for context cancellation
 	<-ctx.Done()
 
@@ -809,6 +977,13 @@ func (a *App) shutdown() error {
 		}
 	}
 
+	// Close permission audit log
+	if a.permissionAudit != nil {
+		if err := a.permissionAudit.Close(); err != nil {
+			log.Error().Err(err).Msg("error closing permission audit log")
+		}
+	}
+
 	// Stop repository indexer
 	if a.repoIndexer != nil {
 		if err := a.repoIndexer.Stop(); err != nil {
@@ -859,6 +1034,13 @@ func (a *App) shutdown() error {
 		log.Error().Err(err).Msg("error stopping event hub")
 	}
 
+	// Close event journal after the hub stops appending
+	if a.eventJournal != nil {
+		if err := a.eventJournal.Close(); err != nil {
+			log.Error().Err(err).Msg("error closing event journal")
+		}
+	}
+
 	return nil
 }
 
@@ -19,10 +19,12 @@ type Config struct {
 	Git         GitConfig         `mapstructure:"git"`
 	Logging     LoggingConfig     `mapstructure:"logging"`
 	Limits      LimitsConfig      `mapstructure:"limits"`
+	Resources   ResourcesConfig   `mapstructure:"resources"`
 	Pairing     PairingConfig     `mapstructure:"pairing"`
 	Indexer     IndexerConfig     `mapstructure:"indexer"`
 	Security    SecurityConfig    `mapstructure:"security"`
 	Permissions PermissionsConfig `mapstructure:"permissions"`
+	Events      EventsConfig      `mapstructure:"events"`
 	Debug       DebugConfig       `mapstructure:"debug"`
 	Discovery   DiscoverySettings `mapstructure:"discovery"`
 	AgentTask   AgentTaskConfig   `mapstructure:"agent_task"`
@@ -75,6 +77,14 @@ type GitConfig struct {
 	Enabled      bool   `mapstructure:"enabled"`
 	Command      string `mapstructure:"command"`
 	DiffOnChange bool   `mapstructure:"diff_on_change"`
+
+	Snapshots GitSnapshotConfig `mapstructure:"snapshots"`
+}
+
+// GitSnapshotConfig holds per-turn working-tree snapshot configuration.
+type GitSnapshotConfig struct {
+	Enabled       bool `mapstructure:"enabled"`         // Capture a snapshot at the start of each prompt turn
+	MaxPerSession int  `mapstructure:"max_per_session"` // Snapshots kept per session (oldest are pruned)
 }
 
 // LoggingConfig holds logging configuration.
@@ -101,6 +111,18 @@ type LimitsConfig struct {
 	MaxPromptLen  int `mapstructure:"max_prompt_len"`
 }
 
+// ResourcesConfig holds per-session resource monitoring configuration.
+// Agent process trees are sampled from /proc (Linux only). A limit of 0 disables it.
+type ResourcesConfig struct {
+	Enabled         bool    `mapstructure:"enabled"`          // Sample agent process trees periodically
+	IntervalSeconds int     `mapstructure:"interval_seconds"` // Sampling interval
+	MaxCPUPercent   float64 `mapstructure:"max_cpu_percent"`  // Whole tree, 100 = one core
+	MaxRSSMB        int     `mapstructure:"max_rss_mb"`       // Whole tree resident memory
+	MaxOpenFiles    int     `mapstructure:"max_open_files"`   // Whole tree open file descriptors
+	MaxProcesses    int     `mapstructure:"max_processes"`    // Agent process plus descendants
+	LimitSamples    int     `mapstructure:"limit_samples"`    // Consecutive over-limit samples before the session is stopped
+}
+
 // PairingConfig holds pairing/QR code configuration.
 type PairingConfig struct {
 	TokenExpirySecs  int  `mapstructure:"token_expiry_secs"`
@@ -136,7 +158,8 @@ type RateLimitConfig struct {
 
 // PermissionsConfig holds permission hook bridge configuration.
 type PermissionsConfig struct {
-	SessionMemory SessionMemoryConfig `mapstructure:"session_memory"`
+	SessionMemory SessionMemoryConfig     `mapstructure:"session_memory"`
+	Routing       PermissionRoutingConfig `mapstructure:"routing"`
 }
 
 // SessionMemoryConfig holds session memory configuration for the permission system.
@@ -146,6 +169,27 @@ type SessionMemoryConfig struct {
 	MaxPatterns int  `mapstructure:"max_patterns"` // Max patterns per session (default: 100)
 }
 
+// PermissionRoutingConfig holds multi-device routing configuration for permission prompts.
+type PermissionRoutingConfig struct {
+	Enabled              bool     `mapstructure:"enabled"`                // Route prompts by device instead of broadcasting
+	PrimaryDevices       []string `mapstructure:"primary_devices"`        // Device IDs asked first
+	EscalateAfterSeconds int      `mapstructure:"escalate_after_seconds"` // Ask every device after this many seconds (default: 30)
+	HighRisk             []string `mapstructure:"high_risk"`              // Patterns that need several approvals, e.g. "Bash(git push:*)"
+	HighRiskApprovals    int      `mapstructure:"high_risk_approvals"`    // Approvals from distinct devices for high-risk requests (default: 2)
+	TimeoutDecision      string   `mapstructure:"timeout_decision"`       // Decision when nobody answers: "deny" (default) or "ask"
+}
+
+// EventsConfig holds event stream configuration.
+type EventsConfig struct {
+	Journal EventJournalConfig `mapstructure:"journal"`
+}
+
+// EventJournalConfig holds the journal used to replay events to reconnecting clients.
+type EventJournalConfig struct {
+	Enabled   bool `mapstructure:"enabled"`    // Persist the journal to disk; otherwise it is kept in memory
+	MaxEvents int  `mapstructure:"max_events"` // Events kept per workspace (default: 1000)
+}
+
 // DebugConfig holds debug and profiling configuration.
 type DebugConfig struct {
 	Enabled      bool `mapstructure:"enabled"`       // Enable debug endpoints (default: false)
@@ -278,6 +322,8 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("git.enabled", true)
 	v.SetDefault("git.command", "git")
 	v.SetDefault("git.diff_on_change", true)
+	v.SetDefault("git.snapshots.enabled", true)
+	v.SetDefault("git.snapshots.max_per_session", 50)
 
 	// Logging defaults
 	v.SetDefault("logging.level", "info")
@@ -294,6 +340,15 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("limits.max_log_buffer", 1000)
 	v.SetDefault("limits.max_prompt_len", 10000)
 
+	// Resource monitoring defaults (limits disabled)
+	v.SetDefault("resources.enabled", true)
+	v.SetDefault("resources.interval_seconds", 5)
+	v.SetDefault("resources.max_cpu_percent", 0)
+	v.SetDefault("resources.max_rss_mb", 0)
+	v.SetDefault("resources.max_open_files", 0)
+	v.SetDefault("resources.max_processes", 0)
+	v.SetDefault("resources.limit_samples", 3)
+
 	// Pairing defaults
 	v.SetDefault("pairing.token_expiry_secs", 3600)
 	v.SetDefault("pairing.show_qr_in_terminal", false)
@@ -320,6 +375,14 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("permissions.session_memory.enabled", true)
 	v.SetDefault("permissions.session_memory.ttl_seconds", 3600) // 1 hour idle timeout
 	v.SetDefault("permissions.session_memory.max_patterns", 100)
+	v.SetDefault("permissions.routing.enabled", false)
+	v.SetDefault("permissions.routing.escalate_after_seconds", 30)
+	v.SetDefault("permissions.routing.high_risk_approvals", 2)
+	v.SetDefault("permissions.routing.timeout_decision", "deny")
+
+	// Event journal defaults (replay on reconnect)
+	v.SetDefault("events.journal.enabled", true)
+	v.SetDefault("events.journal.max_events", 1000)
 
 	// Debug defaults - disabled by default for security
 	v.SetDefault("debug.enabled", false)
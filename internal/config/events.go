@@ -0,0 +1,16 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultEventJournalPath returns the default path for the event replay journal.
+func DefaultEventJournalPath() string {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, ".cdev", "events.jsonl")
+	}
+	return filepath.Join(configDir, "events.jsonl")
+}
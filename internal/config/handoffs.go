@@ -0,0 +1,16 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultHandoffStorePath returns the default path for cross-runtime handoff links.
+func DefaultHandoffStorePath() string {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, ".cdev", "handoffs.json")
+	}
+	return filepath.Join(configDir, "handoffs.json")
+}
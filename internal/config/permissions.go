@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultPermissionRulesPath returns the default path for persistent workspace permission rules.
+func DefaultPermissionRulesPath() string {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, ".cdev", "permission_rules.json")
+	}
+	return filepath.Join(configDir, "permission_rules.json")
+}
+
+// DefaultPermissionPolicyPath returns the default path for the global permission policy.
+func DefaultPermissionPolicyPath() string {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, ".cdev", "permission_policy.yaml")
+	}
+	return filepath.Join(configDir, "permission_policy.yaml")
+}
+
+// DefaultWorkspacePolicyDir returns the default directory for per-workspace
+// permission policies, stored as <workspace_id>.yaml. They live outside the
+// repository so an agent cannot rewrite its own policy.
+func DefaultWorkspacePolicyDir() string {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, ".cdev", "policies")
+	}
+	return filepath.Join(configDir, "policies")
+}
+
+// DefaultPermissionAuditPath returns the default path for the permission decision audit database.
+func DefaultPermissionAuditPath() string {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, ".cdev", "permission_audit.db")
+	}
+	return filepath.Join(configDir, "permission_audit.db")
+}
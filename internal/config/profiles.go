@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LaunchProfile is a named set of agent launch settings defined per workspace.
+// Profiles are selected with the "profile" param of session/start.
+//
+// Env entries use the KEY=VALUE form. Values are expanded against cdev's own
+// environment at launch time, so secrets should be referenced as
+// KEY=${HOST_VAR} rather than written into workspaces.yaml.
+type LaunchProfile struct {
+	Name           string   `mapstructure:"name" yaml:"name" json:"name"`
+	Description    string   `mapstructure:"description" yaml:"description,omitempty" json:"description,omitempty"`
+	Env            []string `mapstructure:"env" yaml:"env,omitempty" json:"-"`
+	Args           []string `mapstructure:"args" yaml:"args,omitempty" json:"args,omitempty"`
+	Model          string   `mapstructure:"model" yaml:"model,omitempty" json:"model,omitempty"`
+	PermissionMode string   `mapstructure:"permission_mode" yaml:"permission_mode,omitempty" json:"permission_mode,omitempty"`
+	AllowedTools   []string `mapstructure:"allowed_tools" yaml:"allowed_tools,omitempty" json:"allowed_tools,omitempty"`
+	WorkDir        string   `mapstructure:"work_dir" yaml:"work_dir,omitempty" json:"work_dir,omitempty"` // Relative to the workspace path
+}
+
+// LaunchProfilePermissionModes are the permission modes a profile may set.
+// "interactive" is a transport choice made per request, not a profile setting.
+var LaunchProfilePermissionModes = []string{"default", "acceptEdits", "bypassPermissions", "plan"}
+
+var (
+	launchProfileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	envKeyPattern            = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// FindLaunchProfile returns the profile with the given name.
+func (d *WorkspaceDefinition) FindLaunchProfile(name string) (*LaunchProfile, bool) {
+	for i := range d.Profiles {
+		if d.Profiles[i].Name == name {
+			return &d.Profiles[i], true
+		}
+	}
+	return nil, false
+}
+
+// EnvKeys returns the sorted variable names set by the profile, without values.
+func (p *LaunchProfile) EnvKeys() []string {
+	keys := make([]string, 0, len(p.Env))
+	for _, entry := range p.Env {
+		if key, _, ok := strings.Cut(entry, "="); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ResolveEnv expands the profile's env entries using lookup (typically
+// os.LookupEnv). Referencing an unset variable is an error so a session is
+// never launched with a silently empty credential.
+func (p *LaunchProfile) ResolveEnv(lookup func(string) (string, bool)) ([]string, error) {
+	if len(p.Env) == 0 {
+		return nil, nil
+	}
+
+	var missing []string
+	resolved := make([]string, 0, len(p.Env))
+	for _, entry := range p.Env {
+		key, value, _ := strings.Cut(entry, "=")
+		value = os.Expand(value, func(name string) string {
+			v, ok := lookup(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+			return v
+		})
+		resolved = append(resolved, key+"="+value)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("profile %q references unset environment variables: %s", p.Name, strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// ResolveWorkDir returns the absolute working directory for the profile,
+// falling back to workspacePath when no subdirectory is configured.
+func (p *LaunchProfile) ResolveWorkDir(workspacePath string) (string, error) {
+	if p.WorkDir == "" {
+		return workspacePath, nil
+	}
+	dir := filepath.Join(workspacePath, p.WorkDir)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("profile %q work_dir: %w", p.Name, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("profile %q work_dir is not a directory: %s", p.Name, dir)
+	}
+	return dir, nil
+}
+
+// ValidateLaunchProfiles validates the launch profiles of a workspace.
+func ValidateLaunchProfiles(profiles []LaunchProfile) error {
+	names := make(map[string]bool, len(profiles))
+	for i, p := range profiles {
+		if !launchProfileNamePattern.MatchString(p.Name) {
+			return fmt.Errorf("profile %d: invalid name %q", i, p.Name)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("profile %d: duplicate name: %s", i, p.Name)
+		}
+		names[p.Name] = true
+
+		for _, entry := range p.Env {
+			key, _, ok := strings.Cut(entry, "=")
+			if !ok || !envKeyPattern.MatchString(key) {
+				return fmt.Errorf("profile %s: env entry must be KEY=VALUE: %q", p.Name, entry)
+			}
+		}
+
+		if p.PermissionMode != "" && !containsString(LaunchProfilePermissionModes, p.PermissionMode) {
+			return fmt.Errorf("profile %s: permission_mode must be one of: %s",
+				p.Name, strings.Join(LaunchProfilePermissionModes, ", "))
+		}
+
+		if p.WorkDir != "" {
+			clean := filepath.Clean(p.WorkDir)
+			if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("profile %s: work_dir must be a subdirectory of the workspace: %s", p.Name, p.WorkDir)
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateLaunchProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []LaunchProfile
+		wantErr  string
+	}{
+		{
+			name: "valid profiles",
+			profiles: []LaunchProfile{
+				{Name: "review", Model: "sonnet", PermissionMode: "plan", AllowedTools: []string{"Read"}},
+				{Name: "frontend", WorkDir: "web", Env: []string{"API_KEY=${HOST_KEY}"}},
+			},
+		},
+		{
+			name:     "empty name",
+			profiles: []LaunchProfile{{Name: ""}},
+			wantErr:  "invalid name",
+		},
+		{
+			name:     "duplicate name",
+			profiles: []LaunchProfile{{Name: "a"}, {Name: "a"}},
+			wantErr:  "duplicate name",
+		},
+		{
+			name:     "malformed env",
+			profiles: []LaunchProfile{{Name: "a", Env: []string{"NO_VALUE"}}},
+			wantErr:  "KEY=VALUE",
+		},
+		{
+			name:     "interactive permission mode",
+			profiles: []LaunchProfile{{Name: "a", PermissionMode: "interactive"}},
+			wantErr:  "permission_mode must be one of",
+		},
+		{
+			name:     "work_dir escapes workspace",
+			profiles: []LaunchProfile{{Name: "a", WorkDir: "../other"}},
+			wantErr:  "subdirectory of the workspace",
+		},
+		{
+			name:     "absolute work_dir",
+			profiles: []LaunchProfile{{Name: "a", WorkDir: "/tmp"}},
+			wantErr:  "subdirectory of the workspace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLaunchProfiles(tt.profiles)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateLaunchProfiles() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateLaunchProfiles() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLaunchProfileResolveEnv(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		if name == "HOST_KEY" {
+			return "secret", true
+		}
+		return "", false
+	}
+
+	p := LaunchProfile{Name: "p", Env: []string{"API_KEY=${HOST_KEY}", "MODE=dev"}}
+	env, err := p.ResolveEnv(lookup)
+	if err != nil {
+		t.Fatalf("ResolveEnv() error = %v", err)
+	}
+	if want := []string{"API_KEY=secret", "MODE=dev"}; !reflect.DeepEqual(env, want) {
+		t.Errorf("ResolveEnv() = %v, want %v", env, want)
+	}
+
+	missing := LaunchProfile{Name: "p", Env: []string{"API_KEY=${UNSET_KEY}"}}
+	if _, err := missing.ResolveEnv(lookup); err == nil || !strings.Contains(err.Error(), "UNSET_KEY") {
+		t.Errorf("ResolveEnv() error = %v, want unset variable error", err)
+	}
+
+	if keys := p.EnvKeys(); !reflect.DeepEqual(keys, []string{"API_KEY", "MODE"}) {
+		t.Errorf("EnvKeys() = %v", keys)
+	}
+}
+
+func TestLaunchProfileResolveWorkDir(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	p := LaunchProfile{Name: "p", WorkDir: "web"}
+	dir, err := p.ResolveWorkDir(root)
+	if err != nil || dir != filepath.Join(root, "web") {
+		t.Errorf("ResolveWorkDir() = %q, %v", dir, err)
+	}
+
+	p.WorkDir = "missing"
+	if _, err := p.ResolveWorkDir(root); err == nil {
+		t.Error("ResolveWorkDir() should fail for a missing directory")
+	}
+}
+
+func TestWorkspaceTags(t *testing.T) {
+	tags := NormalizeWorkspaceTags([]string{" Web ", "web", "", "API"})
+	if len(tags) != 2 || tags[0] != "web" || tags[1] != "api" {
+		t.Fatalf("NormalizeWorkspaceTags() = %v", tags)
+	}
+
+	ws := &WorkspaceDefinition{Tags: tags}
+	if !ws.HasTag("WEB") || ws.HasTag("mobile") {
+		t.Fatalf("HasTag() mismatch for %v", ws.Tags)
+	}
+}
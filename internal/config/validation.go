@@ -39,6 +39,11 @@ func Validate(cfg *Config) error {
 		return err
 	}
 
+	// Validate resource monitoring config
+	if err := validateResources(&cfg.Resources); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -221,3 +226,19 @@ func validateLimits(cfg *LimitsConfig) error {
 	}
 	return nil
 }
+
+func validateResources(cfg *ResourcesConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.IntervalSeconds < 1 {
+		return fmt.Errorf("resources.interval_seconds must be at least 1")
+	}
+	if cfg.MaxCPUPercent < 0 || cfg.MaxRSSMB < 0 || cfg.MaxOpenFiles < 0 || cfg.MaxProcesses < 0 {
+		return fmt.Errorf("resources limits cannot be negative")
+	}
+	if cfg.LimitSamples < 1 {
+		return fmt.Errorf("resources.limit_samples must be at least 1")
+	}
+	return nil
+}
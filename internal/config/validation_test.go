@@ -450,3 +450,55 @@ func TestValidate_FullConfig(t *testing.T) {
 		t.Errorf("Validate() error = %v, want nil", err)
 	}
 }
+
+func TestValidateResources(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ResourcesConfig
+		wantErr string
+	}{
+		{
+			name:    "disabled ignores other fields",
+			cfg:     ResourcesConfig{Enabled: false, IntervalSeconds: 0},
+			wantErr: "",
+		},
+		{
+			name:    "valid config",
+			cfg:     ResourcesConfig{Enabled: true, IntervalSeconds: 5, MaxRSSMB: 1024, LimitSamples: 3},
+			wantErr: "",
+		},
+		{
+			name:    "interval too low",
+			cfg:     ResourcesConfig{Enabled: true, IntervalSeconds: 0, LimitSamples: 3},
+			wantErr: "interval_seconds must be at least 1",
+		},
+		{
+			name:    "negative limit",
+			cfg:     ResourcesConfig{Enabled: true, IntervalSeconds: 5, MaxCPUPercent: -1, LimitSamples: 3},
+			wantErr: "cannot be negative",
+		},
+		{
+			name:    "limit samples too low",
+			cfg:     ResourcesConfig{Enabled: true, IntervalSeconds: 5},
+			wantErr: "limit_samples must be at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateResources(&tt.cfg)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateResources() error = %v, want nil", err)
+				}
+			} else {
+				if err == nil {
+					t.Errorf("validateResources() error = nil, want error containing %q", tt.wantErr)
+				} else if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("validateResources() error = %v, want error containing %q", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
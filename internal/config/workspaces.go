@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -37,6 +38,36 @@ type WorkspaceDefinition struct {
 	ConfigFile   string    `mapstructure:"config_file,omitempty" yaml:"config_file,omitempty"`
 	CreatedAt    time.Time `mapstructure:"created_at" yaml:"created_at"`
 	LastAccessed time.Time `mapstructure:"last_accessed" yaml:"last_accessed"`
+	Tags         []string  `mapstructure:"tags" yaml:"tags,omitempty"`
+
+	// Profiles are named agent launch settings selectable in session/start.
+	Profiles []LaunchProfile `mapstructure:"profiles" yaml:"profiles,omitempty"`
+}
+
+// HasTag reports whether the workspace carries tag (case-insensitive).
+func (d *WorkspaceDefinition) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, t := range d.Tags {
+		if strings.ToLower(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeWorkspaceTags trims, lowercases and de-duplicates workspace tags.
+func NormalizeWorkspaceTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
 }
 
 // WorkspaceDefaults holds default settings for all workspaces.
@@ -151,6 +151,10 @@ func validateWorkspaceDefinitions(workspaces []WorkspaceDefinition, managerCfg *
 				return fmt.Errorf("workspace %d: config file does not exist: %s", i, ws.ConfigFile)
 			}
 		}
+
+		if err := ValidateLaunchProfiles(ws.Profiles); err != nil {
+			return fmt.Errorf("workspace %d: %w", i, err)
+		}
 	}
 
 	return nil
@@ -28,6 +28,25 @@ type PTYPermissionPayload struct {
 	Options     []PTYPromptOption `json:"options"`               // available choices
 	SessionID   string            `json:"session_id,omitempty"`
 	WorkspaceID string            `json:"workspace_id,omitempty"` // workspace ID (for hook bridge)
+	Risk        *PermissionRisk   `json:"risk,omitempty"`         // risk assessment (for hook bridge)
+	PermissionRouting
+}
+
+// PermissionRisk is the risk assessment of a hook bridge permission prompt, so
+// the app can highlight the prompts that matter.
+type PermissionRisk struct {
+	Level   string   `json:"level"`             // "low", "medium", "high", "critical"
+	Score   int      `json:"score"`             // 0-100
+	Reasons []string `json:"reasons,omitempty"` // What raised the score, most significant first
+}
+
+// PermissionRouting describes which paired devices a hook bridge permission
+// prompt is routed to and how many approvals it needs.
+type PermissionRouting struct {
+	TargetDevices     []string `json:"target_devices,omitempty"`     // Devices asked to answer; empty means every device
+	Escalated         bool     `json:"escalated,omitempty"`          // Re-sent to every device after the primaries did not answer
+	RequiredApprovals int      `json:"required_approvals,omitempty"` // Approvals from distinct devices needed to allow
+	Approvals         []string `json:"approvals,omitempty"`          // Devices that already approved
 }
 
 // PTYPromptOption represents a choice in a permission prompt.
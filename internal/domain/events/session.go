@@ -199,3 +199,78 @@ func NewSessionIDFailedEvent(temporaryID, workspaceID, reason, message string) *
 		Message:     message,
 	}, workspaceID, temporaryID)
 }
+
+// SessionSnapshotPayload is the payload for session_snapshot events.
+// Emitted when a working-tree snapshot is captured at the start of a prompt turn
+// or when a session is rolled back to a snapshot.
+type SessionSnapshotPayload struct {
+	Action     string `json:"action"` // "created" or "restored"
+	SnapshotID string `json:"snapshot_id"`
+	Label      string `json:"label,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+	BackupID   string `json:"backup_id,omitempty"` // Snapshot of the state replaced by a restore
+}
+
+// NewSessionSnapshotEvent creates a new session_snapshot event.
+func NewSessionSnapshotEvent(workspaceID, sessionID string, payload SessionSnapshotPayload) *BaseEvent {
+	return NewEventWithContext(EventTypeSessionSnapshot, payload, workspaceID, sessionID)
+}
+
+// SessionResourcesPayload is the payload for session_resources events.
+// Emitted on every sample of a session's agent process tree.
+type SessionResourcesPayload struct {
+	PID           int       `json:"pid"`
+	Processes     int       `json:"processes"`
+	CPUPercent    float64   `json:"cpu_percent"`
+	RSSBytes      uint64    `json:"rss_bytes"`
+	OpenFiles     int       `json:"open_files"`
+	SampledAt     time.Time `json:"sampled_at"`
+	LimitExceeded string    `json:"limit_exceeded,omitempty"` // Name of the configured limit currently exceeded
+	Stopped       bool      `json:"stopped,omitempty"`        // Session was stopped for staying over the limit
+}
+
+// NewSessionResourcesEvent creates a new session_resources event.
+func NewSessionResourcesEvent(workspaceID, sessionID string, payload SessionResourcesPayload) *BaseEvent {
+	return NewEventWithContext(EventTypeSessionResources, payload, workspaceID, sessionID)
+}
+
+// SessionBatchItem is the state of one workspace in a session/batch_start run.
+type SessionBatchItem struct {
+	WorkspaceID string `json:"workspace_id"`
+	Status      string `json:"status"` // "pending", "starting", "started" or "failed"
+	SessionID   string `json:"session_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// SessionBatchPayload is the payload for session_batch events.
+// Emitted whenever an item of a batch changes state, with aggregate counts.
+type SessionBatchPayload struct {
+	BatchID   string            `json:"batch_id"`
+	AgentType string            `json:"agent_type"`
+	Total     int               `json:"total"`
+	Pending   int               `json:"pending"`
+	Started   int               `json:"started"`
+	Failed    int               `json:"failed"`
+	Done      bool              `json:"done"`
+	Item      *SessionBatchItem `json:"item,omitempty"` // The item that changed
+}
+
+// NewSessionBatchEvent creates a new session_batch event.
+func NewSessionBatchEvent(workspaceID, sessionID string, payload SessionBatchPayload) *BaseEvent {
+	return NewEventWithContext(EventTypeSessionBatch, payload, workspaceID, sessionID)
+}
+
+// SessionHandoffPayload is the payload for session_handoff events.
+// Emitted when a conversation is continued in a session on another runtime.
+type SessionHandoffPayload struct {
+	HandoffID       string `json:"handoff_id"`
+	SourceSessionID string `json:"source_session_id"`
+	SourceAgent     string `json:"source_agent"`
+	TargetSessionID string `json:"target_session_id"`
+	TargetAgent     string `json:"target_agent"`
+}
+
+// NewSessionHandoffEvent creates a new session_handoff event.
+func NewSessionHandoffEvent(workspaceID, sessionID string, payload SessionHandoffPayload) *BaseEvent {
+	return NewEventWithContext(EventTypeSessionHandoff, payload, workspaceID, sessionID)
+}
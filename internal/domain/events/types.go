@@ -37,6 +37,10 @@ const (
 	EventTypeSessionIDResolved   EventType = "session_id_resolved" // Real session ID from .claude/projects
 	EventTypeSessionIDTimeout    EventType = "session_id_timeout"  // Timeout waiting for real session ID
 	EventTypeSessionIDFailed     EventType = "session_id_failed"   // Failed to get real session ID (user declined trust)
+	EventTypeSessionSnapshot     EventType = "session_snapshot"    // Working-tree snapshot created or restored
+	EventTypeSessionResources    EventType = "session_resources"   // Periodic resource usage of the agent process tree
+	EventTypeSessionBatch        EventType = "session_batch"       // Progress of a session/batch_start run
+	EventTypeSessionHandoff      EventType = "session_handoff"     // Conversation continued on another runtime
 
 	// Workspace events
 	EventTypeWorkspaceRemoved EventType = "workspace_removed"
@@ -51,6 +55,8 @@ const (
 
 	// Stream events
 	EventTypeStreamReadComplete EventType = "stream_read_complete" // JSONL file reader caught up to end
+	EventTypeStreamGap          EventType = "stream_gap"           // Missed events are no longer in the journal
+	EventTypeStreamLagging      EventType = "stream_lagging"       // Client fell behind and events were dropped
 
 	// Claude Hook events (from external Claude sessions via hooks)
 	EventTypeClaudeHookSession    EventType = "claude_hook_session"    // SessionStart hook
@@ -68,6 +74,26 @@ const (
 	EventTypeTaskRejected  EventType = "task_rejected"
 )
 
+// IsCritical reports whether an event must reach a subscriber: it is never
+// coalesced, dropped or filtered out by event type, even when the client is
+// lagging.
+func IsCritical(eventType EventType) bool {
+	switch eventType {
+	case EventTypePTYPermission,
+		EventTypePTYPermissionResolved,
+		EventTypeClaudePermission,
+		EventTypeClaudeHookPermission,
+		EventTypeClaudeWaiting,
+		EventTypeSessionEnd,
+		EventTypeSessionStopped,
+		EventTypeStreamGap,
+		EventTypeStreamLagging,
+		EventTypeError:
+		return true
+	}
+	return false
+}
+
 // Event is the base interface for all events.
 type Event interface {
 	// Type returns the event type.
@@ -95,6 +121,7 @@ type BaseEvent struct {
 	AgentType   string      `json:"agent_type,omitempty"`
 	Payload     interface{} `json:"payload"`
 	RequestID   string      `json:"request_id,omitempty"`
+	Seq         uint64      `json:"seq,omitempty"` // Per-workspace sequence, set by the hub
 }
 
 // SetContext sets the workspace and session context for an event.
@@ -264,3 +291,37 @@ func NewStreamReadCompleteEvent(sessionID string, messagesEmitted int, fileOffse
 		FileSize:        fileSize,
 	}, "", sessionID)
 }
+
+// StreamGapPayload represents the payload for stream_gap events.
+// It is sent instead of a replay when the journal no longer holds every
+// event after the requested sequence; the client must refetch state.
+type StreamGapPayload struct {
+	RequestedSeq uint64 `json:"requested_seq"` // since_seq asked for
+	OldestSeq    uint64 `json:"oldest_seq"`    // Oldest sequence still journaled (0 when none)
+	CurrentSeq   uint64 `json:"current_seq"`   // Latest sequence of the workspace
+}
+
+// NewStreamGapEvent creates a new stream_gap event.
+func NewStreamGapEvent(workspaceID string, requestedSeq, oldestSeq, currentSeq uint64) *BaseEvent {
+	return NewEventWithContext(EventTypeStreamGap, StreamGapPayload{
+		RequestedSeq: requestedSeq,
+		OldestSeq:    oldestSeq,
+		CurrentSeq:   currentSeq,
+	}, workspaceID, "")
+}
+
+// StreamLaggingPayload represents the payload for stream_lagging events.
+// It is sent when a client's send queue stayed full and queued events were
+// dropped; the client must resubscribe with since_seq to catch up.
+type StreamLaggingPayload struct {
+	Dropped      int    `json:"dropped"`       // Events dropped since the queue filled up
+	ResyncMethod string `json:"resync_method"` // Method to call with since_seq per workspace
+}
+
+// NewStreamLaggingEvent creates a new stream_lagging event.
+func NewStreamLaggingEvent(dropped int) *BaseEvent {
+	return NewEvent(EventTypeStreamLagging, StreamLaggingPayload{
+		Dropped:      dropped,
+		ResyncMethod: "workspace/subscribe",
+	})
+}
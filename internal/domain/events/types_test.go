@@ -263,3 +263,12 @@ func BenchmarkEvent_ToJSON(b *testing.B) {
 		_, _ = event.ToJSON()
 	}
 }
+
+func TestIsCritical(t *testing.T) {
+	if !IsCritical(EventTypePTYPermission) || !IsCritical(EventTypeStreamLagging) {
+		t.Error("permission prompts and stream_lagging must be critical")
+	}
+	if IsCritical(EventTypeClaudeLog) {
+		t.Error("claude_log must not be critical")
+	}
+}
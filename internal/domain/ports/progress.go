@@ -0,0 +1,32 @@
+package ports
+
+import "context"
+
+// ProgressFunc receives the progress of a long-running operation.
+// percentage is between 0 and 100, or negative when the total is unknown.
+type ProgressFunc func(message string, percentage int)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose long-running operations report their
+// progress to fn.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	if fn == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// HasProgress reports whether anyone listens to the progress of ctx, for
+// operations that need extra work to measure it.
+func HasProgress(ctx context.Context) bool {
+	_, ok := ctx.Value(progressKey{}).(ProgressFunc)
+	return ok
+}
+
+// ReportProgress reports progress to the ProgressFunc of ctx, if any.
+func ReportProgress(ctx context.Context, message string, percentage int) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(message, percentage)
+	}
+}
@@ -193,18 +193,11 @@ try {
     $null = Invoke-WebRequest -Uri "http://127.0.0.1:${CdevPort}/health" -TimeoutSec 1 -ErrorAction Stop
 } catch { exit 0 }
 try {
-    $resp = Invoke-WebRequest -Uri "http://127.0.0.1:${CdevPort}/api/hooks/permission-request" -Method POST -ContentType "application/json" -Body $Input -TimeoutSec $Timeout -ErrorAction Stop
-    $json = $resp.Content | ConvertFrom-Json
-    $decision = $json.decision
+    $resp = Invoke-WebRequest -Uri "http://127.0.0.1:${CdevPort}/api/hooks/permission-request?format=claude" -Method POST -ContentType "application/json" -Body $Input -TimeoutSec $Timeout -ErrorAction Stop
 } catch { exit 0 }
-if (-not $decision) { exit 0 }
-if ($decision -eq "allow") {
-    Write-Output '{"hookSpecificOutput":{"hookEventName":"PreToolUse","permissionDecision":"allow","permissionDecisionReason":"Approved by cdev"}}'
-} elseif ($decision -eq "deny") {
-    Write-Output '{"hookSpecificOutput":{"hookEventName":"PreToolUse","permissionDecision":"deny","permissionDecisionReason":"Denied by cdev"}}'
-} else {
-    Write-Output '{"hookSpecificOutput":{"hookEventName":"PreToolUse","permissionDecision":"ask"}}'
-}
+# cdev answers in Claude's hook output format (including any edited tool input)
+if ($resp.Content -notmatch '"hookSpecificOutput"') { exit 0 }
+Write-Output $resp.Content
 exit 0
 `, m.cdevPort, DefaultPermissionTimeout)
 	} else {
@@ -214,6 +207,7 @@ exit 0
 #
 # Returns to Claude:
 #   - {"hookSpecificOutput":{"permissionDecision":"allow"}} - allow the tool
+#     (with "updatedInput" when the user edited the request before approving)
 #   - {"hookSpecificOutput":{"permissionDecision":"deny"}} - deny the tool
 #   - {"hookSpecificOutput":{"permissionDecision":"ask"}} - fallback to desktop prompt
 #   - (empty/error) - fallback to desktop prompt
@@ -234,37 +228,21 @@ fi
 # This will:
 # 1. Check pattern memory for stored decisions
 # 2. If no match, forward to iOS and wait for response
-# 3. Return the decision
+# 3. Return the decision in Claude's hook output format
 RESPONSE=$(echo "$INPUT" | curl -s -X POST \
     -H "Content-Type: application/json" \
     --max-time ${TIMEOUT} \
-    "http://127.0.0.1:${CDEV_PORT}/api/hooks/permission-request" \
+    "http://127.0.0.1:${CDEV_PORT}/api/hooks/permission-request?format=claude" \
     -d @- 2>/dev/null)
 
 # Check if we got a valid response
-if [ -z "$RESPONSE" ]; then
+if ! echo "$RESPONSE" | grep -q '"hookSpecificOutput"'; then
     # No response (timeout or error) - let Claude handle it
     exit 0
 fi
 
-# Extract decision from response
-# Response format: {"decision":"allow|deny","scope":"once|session",...}
-DECISION=$(echo "$RESPONSE" | grep -o '"decision":"[^"]*"' | cut -d'"' -f4)
-
-if [ -z "$DECISION" ]; then
-    # Could not parse decision - let Claude handle it
-    exit 0
-fi
-
-# Return decision to Claude in the expected format
-if [ "$DECISION" = "allow" ]; then
-    echo '{"hookSpecificOutput":{"hookEventName":"PreToolUse","permissionDecision":"allow","permissionDecisionReason":"Approved by cdev"}}'
-elif [ "$DECISION" = "deny" ]; then
-    echo '{"hookSpecificOutput":{"hookEventName":"PreToolUse","permissionDecision":"deny","permissionDecisionReason":"Denied by cdev"}}'
-else
-    # Unknown decision - let Claude handle it
-    echo '{"hookSpecificOutput":{"hookEventName":"PreToolUse","permissionDecision":"ask"}}'
-fi
+# Pass the decision (and any edited tool input) through to Claude
+echo "$RESPONSE"
 
 exit 0
 `, m.cdevPort, DefaultPermissionTimeout)
@@ -0,0 +1,87 @@
+package hub
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+)
+
+// EventFilter selects the events a subscriber receives and trims their
+// payloads. Empty lists do not filter. Critical events (events.IsCritical),
+// such as permission prompts and stream_lagging, pass the type lists, and
+// stream_gap events always pass so a client can recover from missed events.
+type EventFilter struct {
+	Types        []events.EventType                     `json:"event_types,omitempty"`         // Only these event types
+	ExcludeTypes []events.EventType                     `json:"exclude_event_types,omitempty"` // Never these event types; wins over Types
+	SessionIDs   []string                               `json:"session_ids,omitempty"`         // Only events of these sessions; events without a session pass
+	Projections  map[events.EventType]PayloadProjection `json:"projections,omitempty"`         // Payload fields per event type
+}
+
+// PayloadProjection trims the top-level fields of an event payload.
+type PayloadProjection struct {
+	Fields []string `json:"fields,omitempty"` // Keep only these fields
+	Omit   []string `json:"omit,omitempty"`   // Drop these fields, e.g. "diff" of git_diff
+}
+
+// IsEmpty reports whether the filter lets every event through unchanged.
+func (f EventFilter) IsEmpty() bool {
+	return len(f.Types) == 0 && len(f.ExcludeTypes) == 0 && len(f.SessionIDs) == 0 && len(f.Projections) == 0
+}
+
+// allows reports whether an event passes the type and session lists.
+func (f *EventFilter) allows(event events.Event) bool {
+	eventType := event.Type()
+	if eventType == events.EventTypeStreamGap {
+		return true
+	}
+	if !events.IsCritical(eventType) {
+		if slices.Contains(f.ExcludeTypes, eventType) {
+			return false
+		}
+		if len(f.Types) > 0 && !slices.Contains(f.Types, eventType) {
+			return false
+		}
+	}
+	if sessionID := event.GetSessionID(); sessionID != "" && len(f.SessionIDs) > 0 && !slices.Contains(f.SessionIDs, sessionID) {
+		return false
+	}
+	return true
+}
+
+// project returns the event with its payload trimmed, or the event itself
+// when no projection applies. The shared event is never modified.
+func (f *EventFilter) project(event events.Event) events.Event {
+	projection, ok := f.Projections[event.Type()]
+	if !ok || (len(projection.Fields) == 0 && len(projection.Omit) == 0) {
+		return event
+	}
+	base, ok := event.(*events.BaseEvent)
+	if !ok || base.Payload == nil {
+		return event
+	}
+
+	data, err := json.Marshal(base.Payload)
+	if err != nil {
+		return event
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return event // Not an object payload
+	}
+
+	if len(projection.Fields) > 0 {
+		for key := range payload {
+			if !slices.Contains(projection.Fields, key) {
+				delete(payload, key)
+			}
+		}
+	}
+	for _, key := range projection.Omit {
+		delete(payload, key)
+	}
+
+	projected := *base
+	projected.Payload = payload
+	return &projected
+}
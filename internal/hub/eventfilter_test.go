@@ -0,0 +1,92 @@
+package hub
+
+import (
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/testutil"
+)
+
+func TestFilteredSubscriber_EventFilter_Types(t *testing.T) {
+	inner := testutil.NewMockSubscriber("client-1")
+	fs := NewFilteredSubscriber(inner)
+	fs.SetEventFilter(EventFilter{
+		Types:        []events.EventType{events.EventTypeClaudeMessage, events.EventTypeClaudeLog},
+		ExcludeTypes: []events.EventType{events.EventTypeClaudeLog},
+	})
+
+	_ = fs.Send(events.NewEvent(events.EventTypeClaudeMessage, nil))
+	_ = fs.Send(events.NewEvent(events.EventTypeClaudeLog, nil))   // Excluded wins
+	_ = fs.Send(events.NewEvent(events.EventTypeFileChanged, nil)) // Not included
+	_ = fs.Send(events.NewStreamGapEvent("ws-1", 1, 5, 9))         // Always passes
+	_ = fs.Send(events.NewStreamLaggingEvent(12))                  // Critical, passes the type lists
+
+	fs.SetEventFilter(EventFilter{ExcludeTypes: []events.EventType{events.EventTypePTYPermission}})
+	_ = fs.Send(events.NewEvent(events.EventTypePTYPermission, nil)) // Critical, cannot be excluded
+
+	got := inner.Events()
+	want := []events.EventType{events.EventTypeClaudeMessage, events.EventTypeStreamGap, events.EventTypeStreamLagging, events.EventTypePTYPermission}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events forwarded, got %d", len(want), len(got))
+	}
+	for i, eventType := range want {
+		if got[i].Type() != eventType {
+			t.Errorf("event %d = %s, want %s", i, got[i].Type(), eventType)
+		}
+	}
+}
+
+func TestFilteredSubscriber_EventFilter_SessionIDs(t *testing.T) {
+	inner := testutil.NewMockSubscriber("client-1")
+	fs := NewFilteredSubscriber(inner)
+	fs.SetEventFilter(EventFilter{SessionIDs: []string{"session-abc"}})
+
+	_ = fs.Send(events.NewEventWithContext(events.EventTypeClaudeMessage, nil, "ws-1", "session-abc"))
+	_ = fs.Send(events.NewEventWithContext(events.EventTypeClaudeMessage, nil, "ws-1", "session-xyz"))
+	_ = fs.Send(events.NewEventWithContext(events.EventTypeGitStatusChanged, nil, "ws-1", ""))
+
+	if inner.EventCount() != 2 {
+		t.Errorf("expected 2 events forwarded (own session and session-less), got %d", inner.EventCount())
+	}
+}
+
+func TestFilteredSubscriber_EventFilter_Projection(t *testing.T) {
+	inner := testutil.NewMockSubscriber("client-1")
+	fs := NewFilteredSubscriber(inner)
+	fs.SetEventFilter(EventFilter{Projections: map[events.EventType]PayloadProjection{
+		events.EventTypeGitDiff:     {Omit: []string{"diff"}},
+		events.EventTypeFileChanged: {Fields: []string{"path"}},
+	}})
+
+	diff := events.NewEvent(events.EventTypeGitDiff, map[string]interface{}{"file": "main.go", "diff": "@@ -1 +1 @@"})
+	changed := events.NewEvent(events.EventTypeFileChanged, map[string]interface{}{"path": "main.go", "change": "modified", "size": 10})
+	_ = fs.Send(diff)
+	_ = fs.Send(changed)
+
+	got := inner.Events()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events forwarded, got %d", len(got))
+	}
+	diffPayload := got[0].(*events.BaseEvent).Payload.(map[string]interface{})
+	if _, ok := diffPayload["diff"]; ok || diffPayload["file"] != "main.go" {
+		t.Errorf("expected diff omitted and file kept, got %v", diffPayload)
+	}
+	changedPayload := got[1].(*events.BaseEvent).Payload.(map[string]interface{})
+	if len(changedPayload) != 1 || changedPayload["path"] != "main.go" {
+		t.Errorf("expected only path kept, got %v", changedPayload)
+	}
+
+	// The shared event other subscribers receive is untouched
+	if _, ok := diff.Payload.(map[string]interface{})["diff"]; !ok {
+		t.Error("projection modified the original event")
+	}
+}
+
+func TestEventFilter_IsEmpty(t *testing.T) {
+	if !(EventFilter{}).IsEmpty() {
+		t.Error("zero filter should be empty")
+	}
+	if (EventFilter{SessionIDs: []string{"s"}}).IsEmpty() {
+		t.Error("filter with session IDs should not be empty")
+	}
+}
@@ -2,6 +2,7 @@
 package hub
 
 import (
+	"slices"
 	"sync"
 
 	"github.com/brianly1003/cdev/internal/domain/events"
@@ -13,6 +14,7 @@ import (
 // Events without a workspace ID (global events) are always forwarded.
 // If no workspaces are subscribed, all events are forwarded (backward compatible).
 // Permission filtering only applies to pty_permission and pty_permission_resolved events.
+// Clients may also narrow events by type and session and trim payloads (see EventFilter).
 // When a client has a focused workspace, permission events from any session in that
 // workspace are forwarded (enabling multi-session badge indicators on iOS).
 // Clients that never call SetSessionFocus receive all permission events (backward compatible).
@@ -25,6 +27,12 @@ type FilteredSubscriber struct {
 	focusedSessionID   string // Session ID the client is currently focused on
 	hasFocus           bool   // Whether the client has set a session focus
 
+	// Paired device of the client; permission prompts routed to other devices are skipped
+	deviceID string
+
+	// Event types, sessions and payload projections negotiated by the client
+	eventFilter EventFilter
+
 	mu sync.RWMutex
 }
 
@@ -46,6 +54,9 @@ func (f *FilteredSubscriber) Send(event events.Event) error {
 	if !f.shouldForward(event) {
 		return nil // Silently skip events that don't match filter
 	}
+	f.mu.RLock()
+	event = f.eventFilter.project(event)
+	f.mu.RUnlock()
 	return f.inner.Send(event)
 }
 
@@ -100,6 +111,20 @@ func (f *FilteredSubscriber) IsFiltering() bool {
 	return len(f.workspaces) > 0
 }
 
+// SetEventFilter replaces the event type, session and projection filter.
+func (f *FilteredSubscriber) SetEventFilter(filter EventFilter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.eventFilter = filter
+}
+
+// EventFilter returns the current event type, session and projection filter.
+func (f *FilteredSubscriber) EventFilter() EventFilter {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.eventFilter
+}
+
 // SetSessionFocus sets the session and workspace the client is focused on.
 // Only pty_permission and pty_permission_resolved events are workspace-filtered.
 func (f *FilteredSubscriber) SetSessionFocus(workspaceID, sessionID string) {
@@ -144,6 +169,36 @@ func (f *FilteredSubscriber) GetFocusedSessionID() (string, bool) {
 	return f.focusedSessionID, f.hasFocus
 }
 
+// SetDeviceID sets the paired device of the client. Clients without a device
+// ID receive every permission prompt (backward compatible).
+func (f *FilteredSubscriber) SetDeviceID(deviceID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deviceID = deviceID
+}
+
+// DeviceID returns the paired device of the client, if known.
+func (f *FilteredSubscriber) DeviceID() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.deviceID
+}
+
+// permissionTargets returns the devices a pty_permission event is routed to.
+func permissionTargets(event events.Event) []string {
+	base, ok := event.(*events.BaseEvent)
+	if !ok {
+		return nil
+	}
+	switch payload := base.Payload.(type) {
+	case events.PTYPermissionPayload:
+		return payload.TargetDevices
+	case *events.PTYPermissionPayload:
+		return payload.TargetDevices
+	}
+	return nil
+}
+
 // isPermissionEvent returns true for event types that should be filtered by workspace focus.
 func isPermissionEvent(eventType events.EventType) bool {
 	return eventType == events.EventTypePTYPermission || eventType == events.EventTypePTYPermissionResolved
@@ -163,6 +218,11 @@ func (f *FilteredSubscriber) shouldForward(event events.Event) bool {
 		}
 	}
 
+	// Event type and session lists negotiated through workspace/subscribe
+	if !f.eventFilter.allows(event) {
+		return false
+	}
+
 	// Workspace-scoped permission filtering: when a client has a focused workspace,
 	// permission events from any session in that workspace pass through.
 	if f.hasFocus && isPermissionEvent(event.Type()) {
@@ -173,5 +233,12 @@ func (f *FilteredSubscriber) shouldForward(event events.Event) bool {
 		}
 	}
 
+	// Device routing: a prompt sent to specific devices skips the other paired devices
+	if f.deviceID != "" && event.Type() == events.EventTypePTYPermission {
+		if targets := permissionTargets(event); len(targets) > 0 && !slices.Contains(targets, f.deviceID) {
+			return false
+		}
+	}
+
 	return true
 }
@@ -385,3 +385,36 @@ func TestIsPermissionEvent(t *testing.T) {
 		})
 	}
 }
+
+// --- Device-routed permission prompts ---
+
+func TestFilteredSubscriber_PermissionTargetDevices(t *testing.T) {
+	routed := events.NewEvent(events.EventTypePTYPermission, events.PTYPermissionPayload{
+		ToolUseID:         "tool-1",
+		PermissionRouting: events.PermissionRouting{TargetDevices: []string{"iphone-1"}},
+	})
+	broadcast := events.NewEvent(events.EventTypePTYPermission, events.PTYPermissionPayload{ToolUseID: "tool-2"})
+
+	tests := []struct {
+		name     string
+		deviceID string
+		want     int
+	}{
+		{name: "target device", deviceID: "iphone-1", want: 2},
+		{name: "other device", deviceID: "ipad-1", want: 1},
+		{name: "no device ID", deviceID: "", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := testutil.NewMockSubscriber("client-1")
+			fs := NewFilteredSubscriber(inner)
+			fs.SetDeviceID(tt.deviceID)
+
+			_ = fs.Send(routed)
+			_ = fs.Send(broadcast)
+			if inner.EventCount() != tt.want {
+				t.Errorf("expected %d events forwarded, got %d", tt.want, inner.EventCount())
+			}
+		})
+	}
+}
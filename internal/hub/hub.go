@@ -2,6 +2,7 @@
 package hub
 
 import (
+	"errors"
 	"sync"
 
 	"github.com/brianly1003/cdev/internal/domain/events"
@@ -9,7 +10,11 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// ErrNotRunning is returned by Resume when the hub is stopped.
+var ErrNotRunning = errors.New("event hub is not running")
+
 // Hub is the central event dispatcher that fans out events to all subscribers.
+// Every event is numbered and journaled before it is fanned out.
 type Hub struct {
 	// subscribers holds all active subscribers
 	subscribers map[string]ports.Subscriber
@@ -23,6 +28,12 @@ type Hub struct {
 	// unregister channel receives subscriber IDs to remove
 	unregister chan string
 
+	// resume channel receives replay requests of reconnecting subscribers
+	resume chan resumeRequest
+
+	// journal sequences events and keeps recent ones for replay
+	journal *Journal
+
 	// mu protects subscribers map
 	mu sync.RWMutex
 
@@ -40,10 +51,20 @@ func New() *Hub {
 		broadcast:   make(chan events.Event, 256),
 		register:    make(chan ports.Subscriber),
 		unregister:  make(chan string),
+		resume:      make(chan resumeRequest),
+		journal:     NewJournal(DefaultJournalSize),
 		done:        make(chan struct{}),
 	}
 }
 
+// SetJournal replaces the in-memory journal, e.g. with one backed by a file.
+// Must be called before Start.
+func (h *Hub) SetJournal(journal *Journal) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.journal = journal
+}
+
 // Start begins the hub's main loop.
 func (h *Hub) Start() error {
 	h.mu.Lock()
@@ -125,7 +146,12 @@ func (h *Hub) run() {
 			h.mu.Unlock()
 			log.Debug().Str("subscriber_id", id).Msg("subscriber unregistered")
 
+		case req := <-h.resume:
+			req.reply <- h.replay(req)
+
 		case event := <-h.broadcast:
+			h.journal.Append(event)
+
 			// Collect failed subscribers while holding read lock
 			h.mu.RLock()
 			var failed []string
@@ -217,3 +243,102 @@ func (h *Hub) IsRunning() bool {
 	defer h.mu.RUnlock()
 	return h.running
 }
+
+// ResumeResult describes the outcome of Resume.
+type ResumeResult struct {
+	Replayed   int    `json:"replayed"`    // Events sent from the journal
+	CurrentSeq uint64 `json:"current_seq"` // Latest sequence of the workspace
+	Gap        bool   `json:"gap"`         // A stream_gap event was sent instead of a replay
+}
+
+type resumeRequest struct {
+	sub         ports.Subscriber
+	workspaceID string
+	sinceSeq    uint64
+	attach      func()
+	reply       chan ResumeResult
+}
+
+// CurrentSeq returns the latest event sequence of a workspace.
+func (h *Hub) CurrentSeq(workspaceID string) uint64 {
+	return h.journal.CurrentSeq(workspaceID)
+}
+
+// Resume replays the journaled events of a workspace after sinceSeq to a
+// subscriber. It runs in the event loop: attach is called first (typically
+// to add the workspace to the subscriber's filter), and no live event is
+// fanned out until the replay is done, so none is delivered twice or out
+// of order. When the journal no longer covers sinceSeq a stream_gap event
+// is sent instead. A subscriber that is not subscribed yet is subscribed
+// in the same step.
+func (h *Hub) Resume(sub ports.Subscriber, workspaceID string, sinceSeq uint64, attach func()) (ResumeResult, error) {
+	// Capture done channel under lock to avoid race with Stop()
+	h.mu.RLock()
+	done := h.done
+	running := h.running
+	h.mu.RUnlock()
+
+	if done == nil || !running {
+		return ResumeResult{}, ErrNotRunning
+	}
+
+	req := resumeRequest{
+		sub:         sub,
+		workspaceID: workspaceID,
+		sinceSeq:    sinceSeq,
+		attach:      attach,
+		reply:       make(chan ResumeResult, 1),
+	}
+	select {
+	case h.resume <- req:
+	case <-done:
+		return ResumeResult{}, ErrNotRunning
+	}
+	select {
+	case result := <-req.reply:
+		return result, nil
+	case <-done:
+		return ResumeResult{}, ErrNotRunning
+	}
+}
+
+// replay serves a resume request from the event loop.
+func (h *Hub) replay(req resumeRequest) ResumeResult {
+	if req.attach != nil {
+		req.attach()
+	}
+	h.mu.Lock()
+	if _, ok := h.subscribers[req.sub.ID()]; !ok {
+		h.subscribers[req.sub.ID()] = req.sub
+	}
+	h.mu.Unlock()
+
+	entries, oldest, current, complete := h.journal.Since(req.workspaceID, req.sinceSeq)
+	result := ResumeResult{CurrentSeq: current}
+	if !complete {
+		result.Gap = true
+		if err := req.sub.Send(events.NewStreamGapEvent(req.workspaceID, req.sinceSeq, oldest, current)); err != nil {
+			log.Warn().Str("subscriber_id", req.sub.ID()).Err(err).Msg("failed to send stream gap")
+		}
+		return result
+	}
+
+	for _, event := range entries {
+		if err := req.sub.Send(event); err != nil {
+			log.Warn().
+				Str("subscriber_id", req.sub.ID()).
+				Int("replayed", result.Replayed).
+				Err(err).
+				Msg("event replay interrupted")
+			break
+		}
+		result.Replayed++
+	}
+	log.Debug().
+		Str("subscriber_id", req.sub.ID()).
+		Str("workspace_id", req.workspaceID).
+		Uint64("since_seq", req.sinceSeq).
+		Int("replayed", result.Replayed).
+		Msg("replayed journaled events")
+	return result
+}
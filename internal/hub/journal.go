@@ -0,0 +1,238 @@
+package hub
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultJournalSize is the number of events kept per workspace.
+const DefaultJournalSize = 1000
+
+// maxJournalLine bounds a single journaled event when loading from disk.
+const maxJournalLine = 16 * 1024 * 1024
+
+// Journal numbers events with a per-workspace sequence and keeps the latest
+// events of each workspace so reconnecting clients can replay what they
+// missed. Events without a workspace share the "" sequence.
+//
+// A journal with an empty path is kept in memory only. Otherwise every
+// event is appended to a JSONL file that is compacted once it holds twice
+// the retained events, and sequences continue across restarts.
+type Journal struct {
+	mu      sync.Mutex
+	path    string
+	size    int
+	file    *os.File
+	lines   int // Lines in the file since the last compaction
+	seqs    map[string]uint64
+	entries map[string][]*events.BaseEvent
+}
+
+// NewJournal creates an in-memory journal keeping size events per workspace.
+func NewJournal(size int) *Journal {
+	if size <= 0 {
+		size = DefaultJournalSize
+	}
+	return &Journal{
+		size:    size,
+		seqs:    make(map[string]uint64),
+		entries: make(map[string][]*events.BaseEvent),
+	}
+}
+
+// OpenJournal loads a journal file, creating it when missing.
+func OpenJournal(path string, size int) (*Journal, error) {
+	j := NewJournal(size)
+	j.path = path
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	if err := j.compact(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// load reads journaled events; unreadable lines are skipped.
+func (j *Journal) load() error {
+	file, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxJournalLine)
+	for scanner.Scan() {
+		var event events.BaseEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || event.Seq == 0 {
+			continue
+		}
+		j.retain(&event)
+	}
+	return scanner.Err()
+}
+
+// retain keeps an event in memory, dropping the oldest beyond the size.
+func (j *Journal) retain(event *events.BaseEvent) {
+	if event.Seq > j.seqs[event.WorkspaceID] {
+		j.seqs[event.WorkspaceID] = event.Seq
+	}
+	list := append(j.entries[event.WorkspaceID], event)
+	if len(list) > j.size {
+		list = list[len(list)-j.size:]
+	}
+	j.entries[event.WorkspaceID] = list
+}
+
+// Append assigns the next sequence of the event's workspace and journals it.
+// Events other than *events.BaseEvent are left untouched.
+func (j *Journal) Append(event events.Event) {
+	base, ok := event.(*events.BaseEvent)
+	if !ok {
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	base.Seq = j.seqs[base.WorkspaceID] + 1
+	j.retain(base)
+
+	if j.path == "" {
+		return
+	}
+	data, err := json.Marshal(base)
+	if err != nil {
+		log.Warn().Err(err).Str("event_type", string(base.EventType)).Msg("failed to journal event")
+		return
+	}
+	if j.file == nil {
+		return // A failed compaction closed the file; memory still serves replays
+	}
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		log.Warn().Err(err).Str("path", j.path).Msg("failed to write event journal")
+		return
+	}
+	j.lines++
+	if j.lines > 2*j.retained() && j.lines > j.size {
+		if err := j.compact(); err != nil {
+			log.Warn().Err(err).Str("path", j.path).Msg("failed to compact event journal")
+		}
+	}
+}
+
+func (j *Journal) retained() int {
+	n := 0
+	for _, list := range j.entries {
+		n += len(list)
+	}
+	return n
+}
+
+// compact rewrites the file with the retained events and reopens it for
+// appending.
+func (j *Journal) compact() error {
+	if j.file != nil {
+		_ = j.file.Close()
+		j.file = nil
+	}
+
+	tmp := j.path + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	lines := 0
+	for _, list := range j.entries {
+		for _, event := range list {
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			_, _ = w.Write(append(data, '\n'))
+			lines++
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	j.file = file
+	j.lines = lines
+	return nil
+}
+
+// CurrentSeq returns the latest sequence of a workspace.
+func (j *Journal) CurrentSeq(workspaceID string) uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.seqs[workspaceID]
+}
+
+// Since returns the journaled events of a workspace after seq, the oldest
+// sequence still journaled (0 when none) and the current sequence.
+// complete is false when events after seq are no longer journaled, or when
+// seq is ahead of the workspace (the sequence was reset).
+func (j *Journal) Since(workspaceID string, seq uint64) (entries []*events.BaseEvent, oldest, current uint64, complete bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	current = j.seqs[workspaceID]
+	list := j.entries[workspaceID]
+	if len(list) > 0 {
+		oldest = list[0].Seq
+	}
+	if seq > current {
+		return nil, oldest, current, false
+	}
+	if seq == current {
+		return nil, oldest, current, true
+	}
+	if oldest == 0 || oldest > seq+1 {
+		return nil, oldest, current, false
+	}
+	for _, event := range list {
+		if event.Seq > seq {
+			entries = append(entries, event)
+		}
+	}
+	return entries, oldest, current, true
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
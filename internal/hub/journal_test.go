@@ -0,0 +1,197 @@
+package hub
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/testutil"
+)
+
+func newWorkspaceEvent(workspaceID string) *events.BaseEvent {
+	return events.NewEventWithContext(events.EventTypeClaudeLog, map[string]string{"line": "x"}, workspaceID, "session-1")
+}
+
+func TestJournal_SequencesPerWorkspace(t *testing.T) {
+	j := NewJournal(10)
+
+	a1, a2, b1 := newWorkspaceEvent("ws-a"), newWorkspaceEvent("ws-a"), newWorkspaceEvent("ws-b")
+	for _, event := range []*events.BaseEvent{a1, b1, a2} {
+		j.Append(event)
+	}
+
+	if a1.Seq != 1 || a2.Seq != 2 || b1.Seq != 1 {
+		t.Errorf("seqs = %d, %d, %d, want 1, 2 and 1", a1.Seq, a2.Seq, b1.Seq)
+	}
+	if got := j.CurrentSeq("ws-a"); got != 2 {
+		t.Errorf("CurrentSeq(ws-a) = %d, want 2", got)
+	}
+}
+
+func TestJournal_Since(t *testing.T) {
+	j := NewJournal(3)
+	for i := 0; i < 5; i++ {
+		j.Append(newWorkspaceEvent("ws-a"))
+	}
+
+	tests := []struct {
+		name         string
+		since        uint64
+		wantEntries  int
+		wantComplete bool
+	}{
+		{"covered", 2, 3, true},
+		{"partially covered", 3, 2, true},
+		{"up to date", 5, 0, true},
+		{"trimmed away", 1, 0, false},
+		{"ahead of the workspace", 9, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, oldest, current, complete := j.Since("ws-a", tt.since)
+			if len(entries) != tt.wantEntries || complete != tt.wantComplete {
+				t.Errorf("got %d entries complete=%v, want %d complete=%v", len(entries), complete, tt.wantEntries, tt.wantComplete)
+			}
+			if oldest != 3 || current != 5 {
+				t.Errorf("oldest = %d current = %d, want 3 and 5", oldest, current)
+			}
+		})
+	}
+}
+
+func TestJournal_PersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	j, err := OpenJournal(path, 2)
+	if err != nil {
+		t.Fatalf("OpenJournal() error = %v", err)
+	}
+	// Enough appends to trigger a compaction
+	for i := 0; i < 7; i++ {
+		j.Append(newWorkspaceEvent("ws-a"))
+	}
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	j, err = OpenJournal(path, 2)
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+	defer func() { _ = j.Close() }()
+
+	entries, oldest, current, complete := j.Since("ws-a", 5)
+	if !complete || len(entries) != 2 || oldest != 6 || current != 7 {
+		t.Fatalf("Since(5) = %d entries oldest=%d current=%d complete=%v, want events 6 and 7", len(entries), oldest, current, complete)
+	}
+	if entries[0].WorkspaceID != "ws-a" || entries[0].Type() != events.EventTypeClaudeLog {
+		t.Errorf("reloaded event = %+v", entries[0])
+	}
+
+	next := newWorkspaceEvent("ws-a")
+	j.Append(next)
+	if next.Seq != 8 {
+		t.Errorf("seq after restart = %d, want 8", next.Seq)
+	}
+}
+
+func TestHub_ResumeReplaysBeforeLiveEvents(t *testing.T) {
+	h := New()
+	_ = h.Start()
+	defer func() { _ = h.Stop() }()
+
+	for i := 0; i < 3; i++ {
+		h.Publish(newWorkspaceEvent("ws-a"))
+	}
+	h.Publish(newWorkspaceEvent("ws-b"))
+	time.Sleep(50 * time.Millisecond)
+
+	sub := testutil.NewMockSubscriber("phone")
+	filtered := NewFilteredSubscriber(sub)
+	h.Subscribe(filtered)
+	time.Sleep(50 * time.Millisecond)
+
+	attached := false
+	result, err := h.Resume(filtered, "ws-a", 1, func() {
+		filtered.SubscribeWorkspace("ws-a")
+		attached = true
+	})
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if !attached || result.Replayed != 2 || result.CurrentSeq != 3 || result.Gap {
+		t.Errorf("result = %+v attached=%v, want 2 replayed up to seq 3", result, attached)
+	}
+
+	h.Publish(newWorkspaceEvent("ws-a"))
+	h.Publish(newWorkspaceEvent("ws-b")) // Filtered out
+	time.Sleep(50 * time.Millisecond)
+
+	var seqs []uint64
+	for _, event := range sub.Events() {
+		seqs = append(seqs, event.(*events.BaseEvent).Seq)
+	}
+	if len(seqs) != 3 || seqs[0] != 2 || seqs[1] != 3 || seqs[2] != 4 {
+		t.Errorf("received seqs = %v, want [2 3 4]", seqs)
+	}
+}
+
+func TestHub_ResumeSubscribesNewSubscriber(t *testing.T) {
+	h := New()
+	_ = h.Start()
+	defer func() { _ = h.Stop() }()
+
+	h.Publish(newWorkspaceEvent("ws-a"))
+	time.Sleep(50 * time.Millisecond)
+
+	sub := testutil.NewMockSubscriber("stream")
+	filtered := NewFilteredSubscriber(sub)
+	if _, err := h.Resume(filtered, "ws-a", 0, func() { filtered.SubscribeWorkspace("ws-a") }); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if h.SubscriberCount() != 1 {
+		t.Fatalf("SubscriberCount() = %d, want 1", h.SubscriberCount())
+	}
+
+	h.Publish(newWorkspaceEvent("ws-a"))
+	time.Sleep(50 * time.Millisecond)
+	if sub.EventCount() != 2 {
+		t.Errorf("received %d events, want the replayed and the live one", sub.EventCount())
+	}
+}
+
+func TestHub_ResumeSendsGap(t *testing.T) {
+	h := New()
+	h.SetJournal(NewJournal(2))
+	_ = h.Start()
+	defer func() { _ = h.Stop() }()
+
+	for i := 0; i < 5; i++ {
+		h.Publish(newWorkspaceEvent("ws-a"))
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	sub := testutil.NewMockSubscriber("phone")
+	result, err := h.Resume(sub, "ws-a", 1, nil)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if !result.Gap || result.Replayed != 0 {
+		t.Errorf("result = %+v, want a gap", result)
+	}
+	if sub.EventCount() != 1 || sub.Events()[0].Type() != events.EventTypeStreamGap {
+		t.Fatalf("received %v, want one stream_gap", sub.Events())
+	}
+	gap := sub.Events()[0].(*events.BaseEvent).Payload.(events.StreamGapPayload)
+	if gap.RequestedSeq != 1 || gap.OldestSeq != 4 || gap.CurrentSeq != 5 {
+		t.Errorf("gap payload = %+v", gap)
+	}
+}
+
+func TestHub_ResumeWhenStopped(t *testing.T) {
+	h := New()
+	if _, err := h.Resume(testutil.NewMockSubscriber("phone"), "ws-a", 0, nil); err != ErrNotRunning {
+		t.Errorf("Resume() error = %v, want ErrNotRunning", err)
+	}
+}
@@ -0,0 +1,434 @@
+package permission
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// AuditSource identifies what decided a permission request.
+type AuditSource string
+
+const (
+	AuditSourcePolicy       AuditSource = "policy"       // A declarative policy rule
+	AuditSourceRule         AuditSource = "rule"         // A persistent workspace rule
+	AuditSourceMemory       AuditSource = "memory"       // A remembered session decision
+	AuditSourceDevice       AuditSource = "device"       // A human on a paired device
+	AuditSourceBypass       AuditSource = "bypass"       // The runtime's bypass permission mode
+	AuditSourceTimeout      AuditSource = "timeout"      // Nobody answered in time
+	AuditSourceCancelled    AuditSource = "cancelled"    // The requester went away
+	AuditSourceNoClients    AuditSource = "no_clients"   // No device was connected to ask
+	AuditSourceUnconfigured AuditSource = "unconfigured" // Permission memory is disabled
+)
+
+// Audit export formats.
+const (
+	AuditFormatJSON  = "json"
+	AuditFormatJSONL = "jsonl"
+	AuditFormatCSV   = "csv"
+)
+
+// maxAuditInputValue caps string values of a recorded tool input so that
+// large Write contents do not bloat the audit database.
+const maxAuditInputValue = 2048
+
+// AuditEntry is one permission request and how it was decided.
+type AuditEntry struct {
+	ID           int64                  `json:"id"`
+	ToolUseID    string                 `json:"tool_use_id"`
+	SessionID    string                 `json:"session_id"`
+	WorkspaceID  string                 `json:"workspace_id,omitempty"`
+	ToolName     string                 `json:"tool_name"`
+	Target       string                 `json:"target,omitempty"`     // Command, file or MCP target
+	ToolInput    map[string]interface{} `json:"tool_input,omitempty"` // Long string values are truncated
+	Risk         RiskLevel              `json:"risk,omitempty"`       // Risk tier of the request
+	Decision     Decision               `json:"decision"`             // allow, deny or ask
+	Modified     bool                   `json:"modified,omitempty"`   // Approved with edited input
+	UpdatedInput map[string]interface{} `json:"updated_input,omitempty"`
+	Scope        Scope                  `json:"scope,omitempty"`
+	Source       AuditSource            `json:"source"`
+	Pattern      string                 `json:"pattern,omitempty"`      // Matched or remembered pattern
+	RuleID       string                 `json:"rule_id,omitempty"`      // Matched workspace rule
+	PolicyRule   string                 `json:"policy_rule,omitempty"`  // Matched policy rule name
+	RespondedBy  string                 `json:"responded_by,omitempty"` // Device or client that answered
+	ApprovedBy   []string               `json:"approved_by,omitempty"`  // Devices that approved a multi-approval request
+	Host         string                 `json:"host"`                   // Machine the request was raised on
+	Channel      string                 `json:"channel"`                // "hook", "rpc", "codex" or "mcp"
+	Message      string                 `json:"message,omitempty"`
+	RequestedAt  time.Time              `json:"requested_at"`
+	DecidedAt    time.Time              `json:"decided_at"`
+	LatencyMs    int64                  `json:"latency_ms"`
+}
+
+// WithPolicy returns a copy of the entry decided by a policy.
+func (e AuditEntry) WithPolicy(policy *PolicyDecision) AuditEntry {
+	e.Decision = policy.Decision
+	e.Scope = ScopeOnce
+	e.Source = AuditSourcePolicy
+	e.PolicyRule = policy.Rule
+	e.Message = policy.Reason
+	return e
+}
+
+// WithStored returns a copy of the entry decided by a workspace rule or a
+// remembered session decision.
+func (e AuditEntry) WithStored(stored *StoredDecision) AuditEntry {
+	e.Decision = stored.Decision
+	e.Scope = stored.EffectiveScope()
+	e.Source = AuditSourceMemory
+	e.Pattern = stored.Pattern
+	if stored.RuleID != "" {
+		e.Source = AuditSourceRule
+		e.RuleID = stored.RuleID
+		e.RespondedBy = stored.CreatedBy
+	}
+	return e
+}
+
+// WithResponse returns a copy of the entry decided by a response.
+func (e AuditEntry) WithResponse(response *Response, source AuditSource) AuditEntry {
+	e.Decision = response.Decision
+	e.Scope = response.Scope
+	e.Source = source
+	e.Pattern = response.Pattern
+	e.RespondedBy = response.RespondedBy
+	e.ApprovedBy = response.ApprovedBy
+	e.Message = response.Message
+	e.Modified = response.UpdatedInput != nil
+	e.UpdatedInput = response.UpdatedInput
+	return e
+}
+
+// AuditFilter selects audit entries. Empty fields match everything.
+type AuditFilter struct {
+	WorkspaceID string
+	SessionID   string
+	ToolName    string
+	Decision    string
+	Source      string
+	Risk        string
+	RespondedBy string
+	Modified    bool // Only approvals with edited input
+	Since       time.Time
+	Until       time.Time
+	Limit       int // 0 means no limit
+	Offset      int
+}
+
+// AuditLog persists permission decisions to SQLite so they can be queried
+// after the fact ("who allowed this command on which machine").
+type AuditLog struct {
+	db   *sql.DB
+	host string
+	mu   sync.Mutex
+}
+
+// OpenAuditLog opens or creates the audit database at path.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit dir: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit database: %w", err)
+	}
+
+	pragmas := []string{
+		"PRAGMA journal_mode=WAL",
+		"PRAGMA synchronous=NORMAL",
+		"PRAGMA busy_timeout=5000",
+	}
+	for _, pragma := range pragmas {
+		if _, err := db.Exec(pragma); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to set pragma %q: %w", pragma, err)
+		}
+	}
+
+	schema := `
+	CREATE TABLE IF NOT EXISTS permission_audit (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tool_use_id TEXT NOT NULL,
+		session_id TEXT NOT NULL,
+		workspace_id TEXT NOT NULL DEFAULT '',
+		tool_name TEXT NOT NULL,
+		target TEXT NOT NULL DEFAULT '',
+		tool_input TEXT NOT NULL DEFAULT '',
+		decision TEXT NOT NULL,
+		scope TEXT NOT NULL DEFAULT '',
+		source TEXT NOT NULL,
+		pattern TEXT NOT NULL DEFAULT '',
+		rule_id TEXT NOT NULL DEFAULT '',
+		policy_rule TEXT NOT NULL DEFAULT '',
+		responded_by TEXT NOT NULL DEFAULT '',
+		host TEXT NOT NULL DEFAULT '',
+		channel TEXT NOT NULL DEFAULT '',
+		message TEXT NOT NULL DEFAULT '',
+		requested_at INTEGER NOT NULL,
+		decided_at INTEGER NOT NULL,
+		latency_ms INTEGER NOT NULL DEFAULT 0
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_audit_decided ON permission_audit(decided_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_audit_workspace ON permission_audit(workspace_id);
+	CREATE INDEX IF NOT EXISTS idx_audit_session ON permission_audit(session_id);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create audit schema: %w", err)
+	}
+
+	// Schema migrations — add columns that may not exist yet
+	migrations := []string{
+		"ALTER TABLE permission_audit ADD COLUMN modified INTEGER NOT NULL DEFAULT 0",
+		"ALTER TABLE permission_audit ADD COLUMN updated_input TEXT NOT NULL DEFAULT ''",
+		"ALTER TABLE permission_audit ADD COLUMN approved_by TEXT NOT NULL DEFAULT ''",
+		"ALTER TABLE permission_audit ADD COLUMN risk TEXT NOT NULL DEFAULT ''",
+	}
+	for _, m := range migrations {
+		_, _ = db.Exec(m) // ignore errors (column already exists)
+	}
+
+	host, _ := os.Hostname()
+	return &AuditLog{db: db, host: host}, nil
+}
+
+// Close closes the audit database.
+func (a *AuditLog) Close() error {
+	return a.db.Close()
+}
+
+// Record stores an audit entry. Host, DecidedAt and LatencyMs are filled in
+// when unset.
+func (a *AuditLog) Record(entry AuditEntry) error {
+	if entry.DecidedAt.IsZero() {
+		entry.DecidedAt = time.Now()
+	}
+	if entry.RequestedAt.IsZero() {
+		entry.RequestedAt = entry.DecidedAt
+	}
+	if entry.LatencyMs == 0 {
+		entry.LatencyMs = entry.DecidedAt.Sub(entry.RequestedAt).Milliseconds()
+	}
+	if entry.Host == "" {
+		entry.Host = a.host
+	}
+	if entry.Target == "" {
+		entry.Target = ExtractTarget(entry.ToolName, entry.ToolInput)
+	}
+
+	inputJSON, err := encodeAuditInput(entry.ToolInput)
+	if err != nil {
+		return fmt.Errorf("failed to encode tool input: %w", err)
+	}
+	updatedJSON, err := encodeAuditInput(entry.UpdatedInput)
+	if err != nil {
+		return fmt.Errorf("failed to encode updated input: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, err = a.db.Exec(`
+		INSERT INTO permission_audit (
+			tool_use_id, session_id, workspace_id, tool_name, target, tool_input, risk,
+			decision, modified, updated_input, scope, source, pattern, rule_id, policy_rule,
+			responded_by, approved_by, host, channel, message,
+			requested_at, decided_at, latency_ms
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.ToolUseID, entry.SessionID, entry.WorkspaceID, entry.ToolName, entry.Target, inputJSON, string(entry.Risk),
+		string(entry.Decision), entry.Modified, updatedJSON, string(entry.Scope), string(entry.Source), entry.Pattern, entry.RuleID, entry.PolicyRule,
+		entry.RespondedBy, strings.Join(entry.ApprovedBy, ","), entry.Host, entry.Channel, entry.Message,
+		entry.RequestedAt.UnixMilli(), entry.DecidedAt.UnixMilli(), entry.LatencyMs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// Query returns matching entries, newest first, and the total number of
+// matches ignoring Limit and Offset.
+func (a *AuditLog) Query(filter AuditFilter) ([]AuditEntry, int, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	for _, cond := range []struct {
+		column string
+		value  string
+	}{
+		{"workspace_id", filter.WorkspaceID},
+		{"session_id", filter.SessionID},
+		{"tool_name", filter.ToolName},
+		{"decision", filter.Decision},
+		{"source", filter.Source},
+		{"risk", filter.Risk},
+	} {
+		if cond.value != "" {
+			where += " AND " + cond.column + " = ?"
+			args = append(args, cond.value)
+		}
+	}
+	if filter.RespondedBy != "" {
+		// A device matches requests it answered or co-approved
+		where += " AND (responded_by = ? OR ',' || approved_by || ',' LIKE ?)"
+		args = append(args, filter.RespondedBy, "%,"+filter.RespondedBy+",%")
+	}
+	if filter.Modified {
+		where += " AND modified = 1"
+	}
+	if !filter.Since.IsZero() {
+		where += " AND decided_at >= ?"
+		args = append(args, filter.Since.UnixMilli())
+	}
+	if !filter.Until.IsZero() {
+		where += " AND decided_at < ?"
+		args = append(args, filter.Until.UnixMilli())
+	}
+
+	var total int
+	if err := a.db.QueryRow("SELECT COUNT(*) FROM permission_audit"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, tool_use_id, session_id, workspace_id, tool_name, target, tool_input, risk,
+			decision, modified, updated_input, scope, source, pattern, rule_id, policy_rule,
+			responded_by, approved_by, host, channel, message,
+			requested_at, decided_at, latency_ms
+		FROM permission_audit` + where + " ORDER BY decided_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	} else {
+		query += " LIMIT -1"
+	}
+	if filter.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, filter.Offset)
+	}
+
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var (
+			entry                  AuditEntry
+			inputJSON, updatedJSON string
+			approvedBy, risk       string
+			decision, scope, src   string
+			requestedAt, decidedAt int64
+		)
+		if err := rows.Scan(
+			&entry.ID, &entry.ToolUseID, &entry.SessionID, &entry.WorkspaceID, &entry.ToolName, &entry.Target, &inputJSON, &risk,
+			&decision, &entry.Modified, &updatedJSON, &scope, &src, &entry.Pattern, &entry.RuleID, &entry.PolicyRule,
+			&entry.RespondedBy, &approvedBy, &entry.Host, &entry.Channel, &entry.Message,
+			&requestedAt, &decidedAt, &entry.LatencyMs,
+		); err != nil {
+			return nil, 0, err
+		}
+		entry.Decision = Decision(decision)
+		entry.Scope = Scope(scope)
+		entry.Source = AuditSource(src)
+		entry.Risk = RiskLevel(risk)
+		entry.RequestedAt = time.UnixMilli(requestedAt).UTC()
+		entry.DecidedAt = time.UnixMilli(decidedAt).UTC()
+		if inputJSON != "" {
+			_ = json.Unmarshal([]byte(inputJSON), &entry.ToolInput)
+		}
+		if updatedJSON != "" {
+			_ = json.Unmarshal([]byte(updatedJSON), &entry.UpdatedInput)
+		}
+		if approvedBy != "" {
+			entry.ApprovedBy = strings.Split(approvedBy, ",")
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
+
+// ValidAuditFormat reports whether format is a supported export format.
+func ValidAuditFormat(format string) bool {
+	switch format {
+	case AuditFormatJSON, AuditFormatJSONL, AuditFormatCSV:
+		return true
+	}
+	return false
+}
+
+// WriteAuditExport writes entries in the given export format.
+func WriteAuditExport(w io.Writer, entries []AuditEntry, format string) error {
+	switch format {
+	case AuditFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+
+	case AuditFormatJSONL:
+		enc := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := enc.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case AuditFormatCSV:
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{
+			"id", "decided_at", "requested_at", "latency_ms", "host", "channel",
+			"workspace_id", "session_id", "tool_use_id", "tool_name", "target",
+			"decision", "modified", "scope", "source", "pattern", "rule_id", "policy_rule", "responded_by", "message", "approved_by", "risk",
+		})
+		for _, e := range entries {
+			_ = cw.Write([]string{
+				strconv.FormatInt(e.ID, 10), e.DecidedAt.Format(time.RFC3339Nano), e.RequestedAt.Format(time.RFC3339Nano),
+				strconv.FormatInt(e.LatencyMs, 10), e.Host, e.Channel,
+				e.WorkspaceID, e.SessionID, e.ToolUseID, e.ToolName, e.Target,
+				string(e.Decision), strconv.FormatBool(e.Modified), string(e.Scope), string(e.Source), e.Pattern, e.RuleID, e.PolicyRule, e.RespondedBy, e.Message,
+				strings.Join(e.ApprovedBy, ","), string(e.Risk),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return fmt.Errorf("unsupported audit format %q", format)
+}
+
+// encodeAuditInput encodes a tool input for storage, or "" when empty.
+func encodeAuditInput(input map[string]interface{}) (string, error) {
+	if len(input) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(truncateAuditInput(input))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// truncateAuditInput copies a tool input, shortening long string values.
+func truncateAuditInput(input map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(input))
+	for key, value := range input {
+		if s, ok := value.(string); ok && len(s) > maxAuditInputValue {
+			value = s[:maxAuditInputValue] + fmt.Sprintf("... (%d bytes truncated)", len(s)-maxAuditInputValue)
+		}
+		out[key] = value
+	}
+	return out
+}
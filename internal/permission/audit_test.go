@@ -0,0 +1,223 @@
+package permission
+
+import (
+	"bytes"
+	"encoding/csv"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTestAuditLog(t *testing.T) *AuditLog {
+	t.Helper()
+	audit, err := OpenAuditLog(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("OpenAuditLog: %v", err)
+	}
+	t.Cleanup(func() { _ = audit.Close() })
+	return audit
+}
+
+func TestAuditLog_RecordAndQuery(t *testing.T) {
+	audit := openTestAuditLog(t)
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	entries := []AuditEntry{
+		{
+			ToolUseID: "t1", SessionID: "s1", WorkspaceID: "ws-1", ToolName: "Bash",
+			ToolInput: map[string]interface{}{"command": "rm -rf build"},
+			Decision:  DecisionAllow, Scope: ScopeOnce, Source: AuditSourceDevice, RespondedBy: "iphone-1",
+			Channel: "hook", RequestedAt: base, DecidedAt: base.Add(1500 * time.Millisecond), Risk: RiskHigh,
+		},
+		{
+			ToolUseID: "t2", SessionID: "s1", WorkspaceID: "ws-1", ToolName: "Write",
+			ToolInput: map[string]interface{}{"file_path": "/repo/a.go"},
+			Decision:  DecisionDeny, Source: AuditSourcePolicy, PolicyRule: "no-writes",
+			Channel: "hook", RequestedAt: base.Add(time.Minute), DecidedAt: base.Add(time.Minute),
+		},
+		{
+			ToolUseID: "t3", SessionID: "s2", WorkspaceID: "ws-2", ToolName: "Bash",
+			ToolInput: map[string]interface{}{"command": "go test ./..."},
+			Decision:  DecisionAllow, Scope: ScopePath, Source: AuditSourceRule, RuleID: "r1",
+			Channel: "rpc", RequestedAt: base.Add(2 * time.Minute), DecidedAt: base.Add(2 * time.Minute),
+		},
+	}
+	for _, entry := range entries {
+		if err := audit.Record(entry); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	all, total, err := audit.Query(AuditFilter{})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if total != 3 || len(all) != 3 {
+		t.Fatalf("got %d entries (total %d), want 3", len(all), total)
+	}
+	if all[0].ToolUseID != "t3" {
+		t.Errorf("entries should be newest first, got %s", all[0].ToolUseID)
+	}
+
+	first := all[2]
+	if first.Target != "rm -rf build" {
+		t.Errorf("target = %q, want the command", first.Target)
+	}
+	if first.LatencyMs != 1500 {
+		t.Errorf("latency = %d, want 1500", first.LatencyMs)
+	}
+	if first.Host == "" {
+		t.Error("host should default to the local hostname")
+	}
+	if first.RespondedBy != "iphone-1" || first.ToolInput["command"] != "rm -rf build" || first.Risk != RiskHigh {
+		t.Errorf("unexpected round trip: %+v", first)
+	}
+
+	tests := []struct {
+		name   string
+		filter AuditFilter
+		want   []string
+	}{
+		{"workspace", AuditFilter{WorkspaceID: "ws-1"}, []string{"t2", "t1"}},
+		{"session", AuditFilter{SessionID: "s2"}, []string{"t3"}},
+		{"tool", AuditFilter{ToolName: "Bash"}, []string{"t3", "t1"}},
+		{"decision", AuditFilter{Decision: "deny"}, []string{"t2"}},
+		{"device", AuditFilter{RespondedBy: "iphone-1"}, []string{"t1"}},
+		{"risk", AuditFilter{Risk: "high"}, []string{"t1"}},
+		{"since", AuditFilter{Since: base.Add(time.Minute)}, []string{"t3", "t2"}},
+		{"until", AuditFilter{Until: base.Add(time.Minute)}, []string{"t1"}},
+		{"page", AuditFilter{Limit: 1, Offset: 1}, []string{"t2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := audit.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			var ids []string
+			for _, e := range got {
+				ids = append(ids, e.ToolUseID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	_, total, _ = audit.Query(AuditFilter{ToolName: "Bash", Limit: 1})
+	if total != 2 {
+		t.Errorf("total should ignore the limit, got %d", total)
+	}
+}
+
+func TestAuditLog_TruncatesLargeInput(t *testing.T) {
+	audit := openTestAuditLog(t)
+	content := strings.Repeat("x", maxAuditInputValue*2)
+	if err := audit.Record(AuditEntry{
+		ToolUseID: "t1", SessionID: "s1", ToolName: "Write",
+		ToolInput: map[string]interface{}{"file_path": "/repo/big.txt", "content": content},
+		Decision:  DecisionAllow, Source: AuditSourceDevice,
+	}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	got, _, err := audit.Query(AuditFilter{})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("Query: %v (%d entries)", err, len(got))
+	}
+	stored, _ := got[0].ToolInput["content"].(string)
+	if len(stored) >= len(content) || !strings.Contains(stored, "truncated") {
+		t.Errorf("content should be truncated, got %d bytes", len(stored))
+	}
+	if got[0].ToolInput["file_path"] != "/repo/big.txt" {
+		t.Errorf("short values should be kept, got %v", got[0].ToolInput["file_path"])
+	}
+}
+
+func TestAuditEntry_WithStored(t *testing.T) {
+	base := AuditEntry{ToolUseID: "t1"}
+
+	memory := base.WithStored(&StoredDecision{Pattern: "Bash(git:*)", Decision: DecisionAllow})
+	if memory.Source != AuditSourceMemory || memory.Scope != ScopeSession || memory.Pattern != "Bash(git:*)" {
+		t.Errorf("unexpected memory entry: %+v", memory)
+	}
+
+	rule := base.WithStored(&StoredDecision{Pattern: "Edit(/repo/*)", Decision: DecisionDeny, Scope: ScopePath, RuleID: "r1", CreatedBy: "ipad"})
+	if rule.Source != AuditSourceRule || rule.RuleID != "r1" || rule.RespondedBy != "ipad" {
+		t.Errorf("unexpected rule entry: %+v", rule)
+	}
+	if base.Source != "" {
+		t.Error("WithStored should not modify the receiver")
+	}
+}
+
+func TestAuditLog_RecordsEditedApprovals(t *testing.T) {
+	audit := openTestAuditLog(t)
+	base := AuditEntry{
+		SessionID: "s1", ToolName: "Bash", Channel: "rpc",
+		ToolInput: map[string]interface{}{"command": "make deploy"},
+	}
+
+	plain := base
+	plain.ToolUseID = "t1"
+	edited := base
+	edited.ToolUseID = "t2"
+	for _, entry := range []AuditEntry{
+		plain.WithResponse(&Response{Decision: DecisionAllow, RespondedBy: "iphone-1"}, AuditSourceDevice),
+		edited.WithResponse(&Response{
+			Decision:     DecisionAllow,
+			UpdatedInput: map[string]interface{}{"command": "make deploy --dry-run"},
+			RespondedBy:  "iphone-1",
+		}, AuditSourceDevice),
+	} {
+		if err := audit.Record(entry); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	got, total, err := audit.Query(AuditFilter{Modified: true})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if total != 1 || got[0].ToolUseID != "t2" || !got[0].Modified {
+		t.Fatalf("expected only the edited approval, got %d: %+v", total, got)
+	}
+	if got[0].UpdatedInput["command"] != "make deploy --dry-run" || got[0].ToolInput["command"] != "make deploy" {
+		t.Errorf("both original and edited input should be kept, got %+v", got[0])
+	}
+}
+
+func TestWriteAuditExport(t *testing.T) {
+	entries := []AuditEntry{
+		{ID: 2, ToolUseID: "t2", ToolName: "Bash", Target: "echo \"hi\", there", Decision: DecisionDeny, Source: AuditSourceTimeout},
+		{ID: 1, ToolUseID: "t1", ToolName: "Read", Decision: DecisionAllow, Source: AuditSourceDevice, RespondedBy: "iphone"},
+	}
+
+	var jsonl bytes.Buffer
+	if err := WriteAuditExport(&jsonl, entries, AuditFormatJSONL); err != nil {
+		t.Fatalf("jsonl: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(jsonl.String()), "\n"); len(lines) != 2 {
+		t.Errorf("jsonl should have one line per entry, got %d", len(lines))
+	}
+
+	var out bytes.Buffer
+	if err := WriteAuditExport(&out, entries, AuditFormatCSV); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("csv output should parse: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("csv should have a header and 2 rows, got %d", len(records))
+	}
+	if records[1][10] != "echo \"hi\", there" {
+		t.Errorf("target column = %q", records[1][10])
+	}
+
+	if err := WriteAuditExport(&out, entries, "xml"); err == nil {
+		t.Error("unknown format should fail")
+	}
+}
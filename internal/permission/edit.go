@@ -0,0 +1,161 @@
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// inputKind is the JSON type of a tool input field.
+type inputKind string
+
+const (
+	kindString inputKind = "string"
+	kindNumber inputKind = "number"
+	kindBool   inputKind = "boolean"
+	kindArray  inputKind = "array"
+	kindObject inputKind = "object"
+)
+
+// toolInputSchema describes the input of a built-in Claude tool.
+type toolInputSchema struct {
+	fields   map[string]inputKind
+	required []string
+}
+
+// toolInputSchemas lists the built-in tools whose input can be edited before
+// approval. Inputs of other tools (e.g. MCP) are checked against the shape
+// of the original request instead.
+var toolInputSchemas = map[string]toolInputSchema{
+	"Bash": {
+		fields:   map[string]inputKind{"command": kindString, "description": kindString, "timeout": kindNumber, "run_in_background": kindBool},
+		required: []string{"command"},
+	},
+	"Write": {
+		fields:   map[string]inputKind{"file_path": kindString, "content": kindString},
+		required: []string{"file_path", "content"},
+	},
+	"Edit": {
+		fields:   map[string]inputKind{"file_path": kindString, "old_string": kindString, "new_string": kindString, "replace_all": kindBool},
+		required: []string{"file_path", "old_string", "new_string"},
+	},
+	"MultiEdit": {
+		fields:   map[string]inputKind{"file_path": kindString, "edits": kindArray},
+		required: []string{"file_path", "edits"},
+	},
+	"Read": {
+		fields:   map[string]inputKind{"file_path": kindString, "offset": kindNumber, "limit": kindNumber},
+		required: []string{"file_path"},
+	},
+	"NotebookEdit": {
+		fields:   map[string]inputKind{"notebook_path": kindString, "cell_id": kindString, "new_source": kindString, "cell_type": kindString, "edit_mode": kindString},
+		required: []string{"notebook_path", "new_source"},
+	},
+	"WebFetch": {
+		fields:   map[string]inputKind{"url": kindString, "prompt": kindString},
+		required: []string{"url", "prompt"},
+	},
+}
+
+// ValidateUpdatedInput checks an edited tool input before it is approved.
+// Built-in tools are checked against their input schema: no unknown fields,
+// matching types and non-empty required fields. Other tools may only use the
+// fields of the original request, with the same types.
+func ValidateUpdatedInput(toolName string, original, updated map[string]interface{}) error {
+	if len(updated) == 0 {
+		return fmt.Errorf("updated_input must not be empty")
+	}
+
+	if schema, ok := toolInputSchemas[toolName]; ok {
+		for _, key := range sortedKeys(updated) {
+			want, known := schema.fields[key]
+			if !known {
+				return fmt.Errorf("%s input has no field %q", toolName, key)
+			}
+			if got := kindOf(updated[key]); got != want {
+				return fmt.Errorf("%s input field %q must be of type %s, got %s", toolName, key, want, got)
+			}
+		}
+		for _, key := range schema.required {
+			value, ok := updated[key]
+			if !ok {
+				return fmt.Errorf("%s input requires field %q", toolName, key)
+			}
+			if s, isString := value.(string); isString && strings.TrimSpace(s) == "" && key != "new_string" && key != "content" {
+				return fmt.Errorf("%s input field %q must not be empty", toolName, key)
+			}
+		}
+		return nil
+	}
+
+	for _, key := range sortedKeys(updated) {
+		orig, ok := original[key]
+		if !ok {
+			return fmt.Errorf("%s input has no field %q in the original request", toolName, key)
+		}
+		if want, got := kindOf(orig), kindOf(updated[key]); orig != nil && want != got {
+			return fmt.Errorf("%s input field %q must be of type %s, got %s", toolName, key, want, got)
+		}
+	}
+	return nil
+}
+
+// InputModified reports whether an edited input differs from the original.
+func InputModified(original, updated map[string]interface{}) bool {
+	return !reflect.DeepEqual(normalizeInput(original), normalizeInput(updated))
+}
+
+// EditInstruction tells Claude to retry a rejected tool call with edited
+// input. It is used where the runtime cannot take edited input directly,
+// such as Claude's interactive terminal prompt.
+func EditInstruction(toolName string, updated map[string]interface{}) string {
+	if command, ok := updated["command"].(string); ok && toolName == "Bash" {
+		return fmt.Sprintf("I edited your Bash command before approving it. Run exactly this command instead: %s", command)
+	}
+	data, _ := json.Marshal(updated)
+	return fmt.Sprintf("I edited your %s call before approving it. Call %s again with exactly this input instead: %s", toolName, toolName, data)
+}
+
+// kindOf returns the JSON type of a decoded JSON value.
+func kindOf(value interface{}) inputKind {
+	switch value.(type) {
+	case string:
+		return kindString
+	case float64, float32, int, int64, json.Number:
+		return kindNumber
+	case bool:
+		return kindBool
+	case []interface{}:
+		return kindArray
+	case map[string]interface{}:
+		return kindObject
+	case nil:
+		return "null"
+	}
+	return inputKind(fmt.Sprintf("%T", value))
+}
+
+// normalizeInput round-trips an input through JSON so that values decoded
+// from different sources compare equal.
+func normalizeInput(input map[string]interface{}) map[string]interface{} {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return input
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return input
+	}
+	return out
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
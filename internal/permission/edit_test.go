@@ -0,0 +1,113 @@
+package permission
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateUpdatedInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     string
+		original map[string]interface{}
+		updated  map[string]interface{}
+		wantErr  string
+	}{
+		{
+			name:    "bash dry run",
+			tool:    "Bash",
+			updated: map[string]interface{}{"command": "make deploy --dry-run", "timeout": float64(60000)},
+		},
+		{
+			name:    "bash empty command",
+			tool:    "Bash",
+			updated: map[string]interface{}{"command": "  "},
+			wantErr: "must not be empty",
+		},
+		{
+			name:    "bash unknown field",
+			tool:    "Bash",
+			updated: map[string]interface{}{"command": "ls", "sudo": true},
+			wantErr: `no field "sudo"`,
+		},
+		{
+			name:    "bash wrong type",
+			tool:    "Bash",
+			updated: map[string]interface{}{"command": []interface{}{"ls"}},
+			wantErr: "must be of type string",
+		},
+		{
+			name:    "write missing content",
+			tool:    "Write",
+			updated: map[string]interface{}{"file_path": "/repo/a.txt"},
+			wantErr: `requires field "content"`,
+		},
+		{
+			name:    "write empty content allowed",
+			tool:    "Write",
+			updated: map[string]interface{}{"file_path": "/repo/a.txt", "content": ""},
+		},
+		{
+			name:     "mcp keeps original shape",
+			tool:     "mcp__github__create_issue",
+			original: map[string]interface{}{"title": "Bug", "labels": []interface{}{"bug"}},
+			updated:  map[string]interface{}{"title": "Smaller bug"},
+		},
+		{
+			name:     "mcp new field",
+			tool:     "mcp__github__create_issue",
+			original: map[string]interface{}{"title": "Bug"},
+			updated:  map[string]interface{}{"title": "Bug", "assignee": "me"},
+			wantErr:  "original request",
+		},
+		{
+			name:     "mcp type change",
+			tool:     "mcp__github__create_issue",
+			original: map[string]interface{}{"labels": []interface{}{"bug"}},
+			updated:  map[string]interface{}{"labels": "bug"},
+			wantErr:  "must be of type array",
+		},
+		{
+			name:    "empty",
+			tool:    "Bash",
+			updated: map[string]interface{}{},
+			wantErr: "must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdatedInput(tt.tool, tt.original, tt.updated)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInputModified(t *testing.T) {
+	original := map[string]interface{}{"command": "ls", "timeout": 1000}
+	if InputModified(original, map[string]interface{}{"command": "ls", "timeout": float64(1000)}) {
+		t.Error("numerically equal input should not count as modified")
+	}
+	if !InputModified(original, map[string]interface{}{"command": "ls -la", "timeout": 1000}) {
+		t.Error("changed command should count as modified")
+	}
+}
+
+func TestEditInstruction(t *testing.T) {
+	bash := EditInstruction("Bash", map[string]interface{}{"command": "rm -rf build --dry-run"})
+	if !strings.HasSuffix(bash, ": rm -rf build --dry-run") {
+		t.Errorf("bash instruction should end with the command, got %q", bash)
+	}
+	edit := EditInstruction("Edit", map[string]interface{}{"file_path": "/repo/a.go"})
+	if !strings.Contains(edit, `{"file_path":"/repo/a.go"}`) {
+		t.Errorf("instruction should carry the edited input, got %q", edit)
+	}
+}
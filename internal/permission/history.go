@@ -0,0 +1,154 @@
+package permission
+
+import (
+	"os"
+	"slices"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/adapters/claude"
+	"github.com/brianly1003/cdev/internal/adapters/codex"
+	"github.com/rs/zerolog/log"
+)
+
+// Agent types whose session history can be replayed.
+const (
+	HistoryAgentClaude = "claude"
+	HistoryAgentCodex  = "codex"
+)
+
+// HistoryWorkspace is a workspace whose session history is replayed.
+type HistoryWorkspace struct {
+	ID   string
+	Path string
+}
+
+// HistoryOptions selects the session history to replay.
+type HistoryOptions struct {
+	Since          time.Time // Skip calls made before; zero replays everything
+	AgentTypes     []string  // claude and/or codex; empty means both
+	IncludeAllowed bool      // List allowed calls, not only count them
+	Limit          int       // Calls listed per workspace; 0 means no limit
+}
+
+func (o HistoryOptions) wants(agentType string) bool {
+	return len(o.AgentTypes) == 0 || slices.Contains(o.AgentTypes, agentType)
+}
+
+func (o HistoryOptions) skip(ts time.Time) bool {
+	return !o.Since.IsZero() && !ts.IsZero() && ts.Before(o.Since)
+}
+
+// Replay runs the tool calls of past Claude and Codex sessions of each
+// workspace through the simulator. Unreadable sessions are skipped.
+func (s *Simulator) Replay(workspaces []HistoryWorkspace, opts HistoryOptions) *SimulationReport {
+	report := &SimulationReport{
+		Workspaces:     make([]*WorkspaceSimulation, 0, len(workspaces)),
+		IncludeAllowed: opts.IncludeAllowed,
+		Limit:          opts.Limit,
+	}
+
+	for _, ws := range workspaces {
+		result := report.Workspace(ws.ID, ws.Path)
+		if opts.wants(HistoryAgentClaude) {
+			result.Sessions += s.replayClaude(report, ws, opts)
+		}
+		if opts.wants(HistoryAgentCodex) {
+			result.Sessions += s.replayCodex(report, ws, opts)
+		}
+	}
+
+	report.Sort()
+	return report
+}
+
+// replayClaude replays the Claude sessions of a workspace and returns how
+// many had calls in range.
+func (s *Simulator) replayClaude(report *SimulationReport, ws HistoryWorkspace, opts HistoryOptions) int {
+	files, err := claude.ListSessionFiles(ws.Path)
+	if err != nil {
+		log.Warn().Err(err).Str("workspace_id", ws.ID).Msg("failed to list Claude sessions for simulation")
+		return 0
+	}
+
+	sessions := 0
+	for _, file := range files {
+		if !opts.Since.IsZero() {
+			if info, err := os.Stat(file); err == nil && info.ModTime().Before(opts.Since) {
+				continue
+			}
+		}
+		calls, err := claude.ReadSessionToolCalls(file)
+		if err != nil {
+			log.Warn().Err(err).Str("file", file).Msg("failed to read Claude session for simulation")
+			continue
+		}
+
+		replayed := false
+		for _, call := range calls {
+			if opts.skip(call.Timestamp) {
+				continue
+			}
+			replayed = true
+			report.Add(s.Decide(SimulatedCall{
+				WorkspaceID:   ws.ID,
+				WorkspacePath: ws.Path,
+				SessionID:     call.SessionID,
+				AgentType:     HistoryAgentClaude,
+				ToolUseID:     call.ID,
+				ToolName:      call.Name,
+				ToolInput:     call.Input,
+				Cwd:           call.Cwd,
+				Timestamp:     call.Timestamp,
+			}))
+		}
+		if replayed {
+			sessions++
+		}
+	}
+	return sessions
+}
+
+// replayCodex replays the Codex sessions of a workspace and returns how
+// many had calls in range.
+func (s *Simulator) replayCodex(report *SimulationReport, ws HistoryWorkspace, opts HistoryOptions) int {
+	infos, err := codex.ListSessionsForWorkspace(ws.Path)
+	if err != nil {
+		log.Warn().Err(err).Str("workspace_id", ws.ID).Msg("failed to list Codex sessions for simulation")
+		return 0
+	}
+
+	sessions := 0
+	for _, info := range infos {
+		if opts.skip(info.LastUpdated) {
+			continue
+		}
+		calls, err := codex.ReadSessionToolCalls(info.Path, info.WorkspacePath)
+		if err != nil {
+			log.Warn().Err(err).Str("file", info.Path).Msg("failed to read Codex session for simulation")
+			continue
+		}
+
+		replayed := false
+		for _, call := range calls {
+			if opts.skip(call.Timestamp) {
+				continue
+			}
+			replayed = true
+			report.Add(s.Decide(SimulatedCall{
+				WorkspaceID:   ws.ID,
+				WorkspacePath: ws.Path,
+				SessionID:     info.SessionID,
+				AgentType:     HistoryAgentCodex,
+				ToolUseID:     call.ID,
+				ToolName:      call.Name,
+				ToolInput:     call.Input,
+				Cwd:           call.Cwd,
+				Timestamp:     call.Timestamp,
+			}))
+		}
+		if replayed {
+			sessions++
+		}
+	}
+	return sessions
+}
@@ -2,12 +2,23 @@ package permission
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 )
 
+var (
+	// ErrRequestNotPending is returned for a request that was already answered or timed out.
+	ErrRequestNotPending = errors.New("request not found or already responded")
+
+	// ErrAlreadyApproved is returned when a device approves the same request twice.
+	ErrAlreadyApproved = errors.New("request already approved by this device")
+)
+
 // MemoryManager manages permission decisions across Claude sessions.
 // It provides "Allow for Session" functionality by remembering decisions
 // and auto-responding to matching permission requests.
@@ -20,6 +31,9 @@ type MemoryManager struct {
 	// Pending requests waiting for mobile app response
 	pendingMu sync.RWMutex
 	pending   map[string]*Request // toolUseID -> request
+
+	// Persistent workspace rules (ScopePath decisions)
+	rules *RuleStore
 }
 
 // NewMemoryManager creates a new permission memory manager.
@@ -29,11 +43,65 @@ func NewMemoryManager(config SessionMemoryConfig, logger zerolog.Logger) *Memory
 		config:   config,
 		logger:   logger.With().Str("component", "permission_memory").Logger(),
 		pending:  make(map[string]*Request),
+		rules:    &RuleStore{Version: ruleStoreVersion},
 	}
 
 	return m
 }
 
+// SetRuleStore replaces the store used for persistent workspace rules.
+func (m *MemoryManager) SetRuleStore(store *RuleStore) {
+	if store == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules = store
+}
+
+func (m *MemoryManager) ruleStore() *RuleStore {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.rules
+}
+
+// AddRule stores a persistent workspace rule.
+func (m *MemoryManager) AddRule(rule Rule) (Rule, error) {
+	stored, err := m.ruleStore().Add(rule)
+	if err != nil {
+		return Rule{}, err
+	}
+	m.logger.Info().
+		Str("workspace_id", stored.WorkspaceID).
+		Str("rule_id", stored.ID).
+		Str("pattern", stored.String()).
+		Str("decision", string(stored.Decision)).
+		Msg("Stored persistent permission rule")
+	return stored, nil
+}
+
+// RemoveRule deletes a persistent workspace rule.
+func (m *MemoryManager) RemoveRule(workspaceID, ruleID string) (bool, error) {
+	return m.ruleStore().Remove(workspaceID, ruleID)
+}
+
+// ListRules returns the persistent rules of a workspace, or all when empty.
+func (m *MemoryManager) ListRules(workspaceID string) []Rule {
+	return m.ruleStore().List(workspaceID)
+}
+
+// ruleDecision converts a matched rule to the decision returned to callers.
+func ruleDecision(rule *Rule) *StoredDecision {
+	return &StoredDecision{
+		Pattern:   rule.String(),
+		Decision:  rule.Decision,
+		CreatedAt: rule.CreatedAt,
+		Scope:     ScopePath,
+		RuleID:    rule.ID,
+		CreatedBy: rule.CreatedBy,
+	}
+}
+
 // StartCleanup starts a goroutine that periodically cleans up expired sessions.
 func (m *MemoryManager) StartCleanup(ctx context.Context) {
 	go m.cleanupLoop(ctx)
@@ -99,9 +167,35 @@ func (m *MemoryManager) GetOrCreateSession(sessionID, workspaceID string) *Sessi
 
 // CheckMemory checks if there's a stored decision for a permission request.
 // Returns the decision if found, or nil if no matching pattern exists.
-// This method is thread-safe and holds the lock for the entire operation
-// to prevent TOCTOU race conditions.
-func (m *MemoryManager) CheckMemory(sessionID, toolName string, toolInput map[string]interface{}) *StoredDecision {
+//
+// Persistent workspace rules are consulted around session memory: a deny
+// rule wins over everything, then session decisions apply, then allow rules.
+// workspaceID may be empty, in which case only session memory is checked.
+func (m *MemoryManager) CheckMemory(sessionID, workspaceID, toolName string, toolInput map[string]interface{}) *StoredDecision {
+	rule := m.ruleStore().Match(workspaceID, toolName, toolInput)
+	if rule != nil && rule.Decision == DecisionDeny {
+		return ruleDecision(rule)
+	}
+
+	if decision := m.checkSessionMemory(sessionID, toolName, toolInput); decision != nil {
+		return decision
+	}
+
+	if rule != nil {
+		m.logger.Debug().
+			Str("session_id", sessionID).
+			Str("workspace_id", workspaceID).
+			Str("rule_id", rule.ID).
+			Str("pattern", rule.String()).
+			Msg("Found matching persistent permission rule")
+		return ruleDecision(rule)
+	}
+	return nil
+}
+
+// checkSessionMemory looks up a session's remembered decisions.
+// It holds the lock for the entire operation to prevent TOCTOU race conditions.
+func (m *MemoryManager) checkSessionMemory(sessionID, toolName string, toolInput map[string]interface{}) *StoredDecision {
 	// Generate pattern BEFORE acquiring lock (no shared state needed)
 	pattern := GeneratePattern(toolName, toolInput)
 
@@ -134,23 +228,29 @@ func (m *MemoryManager) CheckMemory(sessionID, toolName string, toolInput map[st
 		return &result
 	}
 
-	// Check for wildcard pattern matches
+	// Check for wildcard pattern matches, deny patterns first
+	entries := make([]patternDecision, 0, len(mem.Decisions))
 	for storedPattern, decision := range mem.Decisions {
-		if MatchPattern(storedPattern, toolName, toolInput) {
-			decision.UsageCount++
-			mem.Decisions[storedPattern] = decision
+		entries = append(entries, patternDecision{Pattern: storedPattern, Decision: decision.Decision})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Pattern < entries[j].Pattern })
 
-			m.logger.Debug().
-				Str("session_id", sessionID).
-				Str("stored_pattern", storedPattern).
-				Str("request_pattern", pattern).
-				Str("decision", string(decision.Decision)).
-				Msg("Found matching wildcard pattern in session memory")
-
-			// Return a copy to avoid data races on the returned struct
-			result := decision
-			return &result
-		}
+	if idx := resolveDecision(entries, toolName, toolInput); idx >= 0 {
+		storedPattern := entries[idx].Pattern
+		decision := mem.Decisions[storedPattern]
+		decision.UsageCount++
+		mem.Decisions[storedPattern] = decision
+
+		m.logger.Debug().
+			Str("session_id", sessionID).
+			Str("stored_pattern", storedPattern).
+			Str("request_pattern", pattern).
+			Str("decision", string(decision.Decision)).
+			Msg("Found matching wildcard pattern in session memory")
+
+		// Return a copy to avoid data races on the returned struct
+		result := decision
+		return &result
 	}
 
 	return nil
@@ -272,6 +372,59 @@ func (m *MemoryManager) GetPendingRequest(toolUseID string) *Request {
 	return m.pending[toolUseID]
 }
 
+// MarkNotified records the devices a pending request was sent to. escalated
+// marks the request as sent to every device. Returns false if the request is
+// no longer pending.
+func (m *MemoryManager) MarkNotified(toolUseID string, devices []string, escalated bool) bool {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+
+	req, exists := m.pending[toolUseID]
+	if !exists {
+		return false
+	}
+	for _, device := range devices {
+		if !slices.Contains(req.NotifiedDevices, device) {
+			req.NotifiedDevices = append(req.NotifiedDevices, device)
+		}
+	}
+	if escalated {
+		req.Escalated = true
+	}
+	return true
+}
+
+// AddApproval records an approval of a pending request by a device and
+// returns the approvals so far and the number required. A device can approve
+// a request only once. The request stays pending; the caller removes it once
+// enough devices approved.
+func (m *MemoryManager) AddApproval(toolUseID, device string) ([]string, int, error) {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+
+	req, exists := m.pending[toolUseID]
+	if !exists {
+		return nil, 0, ErrRequestNotPending
+	}
+	required := req.RequiredApprovals
+	if required < 1 {
+		required = 1
+	}
+	if slices.Contains(req.Approvals, device) {
+		return append([]string(nil), req.Approvals...), required, ErrAlreadyApproved
+	}
+	req.Approvals = append(req.Approvals, device)
+
+	m.logger.Info().
+		Str("tool_use_id", toolUseID).
+		Str("device", device).
+		Int("approvals", len(req.Approvals)).
+		Int("required", required).
+		Msg("Recorded permission approval")
+
+	return append([]string(nil), req.Approvals...), required, nil
+}
+
 // RemovePendingRequest removes a pending request and closes its response channel.
 func (m *MemoryManager) RemovePendingRequest(toolUseID string) {
 	m.pendingMu.Lock()
@@ -312,10 +465,8 @@ func (m *MemoryManager) RespondToRequest(toolUseID string, response *Response) b
 		return false
 	}
 
-	// Store decision if scope is session (do this before sending response)
-	if response.Scope == ScopeSession && response.Pattern != "" {
-		m.StoreDecision(req.SessionID, req.WorkspaceID, response.Pattern, response.Decision)
-	}
+	// Remember the decision before sending the response
+	m.Remember(req, response)
 
 	// Send response through channel (non-blocking)
 	// The channel has buffer size 1, so this should succeed unless
@@ -337,6 +488,40 @@ func (m *MemoryManager) RespondToRequest(toolUseID string, response *Response) b
 	}
 }
 
+// Remember stores a session or path scoped response to a request: session
+// decisions in session memory, path decisions as a workspace rule created by
+// the responding device. Once scoped responses are not remembered.
+func (m *MemoryManager) Remember(req *Request, response *Response) {
+	if response.Pattern == "" {
+		return
+	}
+	switch response.Scope {
+	case ScopeSession:
+		m.StoreDecision(req.SessionID, req.WorkspaceID, response.Pattern, response.Decision)
+	case ScopePath:
+		if req.WorkspaceID == "" {
+			return
+		}
+		tool, content, err := ParseRulePattern(response.Pattern)
+		if err == nil {
+			_, err = m.AddRule(Rule{
+				WorkspaceID: req.WorkspaceID,
+				Decision:    response.Decision,
+				Tool:        tool,
+				Pattern:     content,
+				CreatedBy:   response.RespondedBy,
+			})
+		}
+		if err != nil {
+			m.logger.Warn().
+				Err(err).
+				Str("workspace_id", req.WorkspaceID).
+				Str("pattern", response.Pattern).
+				Msg("Failed to store path-scoped permission decision")
+		}
+	}
+}
+
 // GetAndRemovePendingRequest atomically retrieves and removes a pending request.
 // Returns nil if the request doesn't exist.
 // This prevents race conditions where the request is accessed after removal.
@@ -359,7 +544,11 @@ func (m *MemoryManager) ListPendingRequests() []*Request {
 
 	requests := make([]*Request, 0, len(m.pending))
 	for _, req := range m.pending {
-		requests = append(requests, req)
+		// Copy so routing state can be read without holding the lock
+		snapshot := *req
+		snapshot.NotifiedDevices = append([]string(nil), req.NotifiedDevices...)
+		snapshot.Approvals = append([]string(nil), req.Approvals...)
+		requests = append(requests, &snapshot)
 	}
 	return requests
 }
@@ -384,5 +573,6 @@ func (m *MemoryManager) GetSessionStats() map[string]interface{} {
 		"active_sessions":  sessionCount,
 		"total_patterns":   totalPatterns,
 		"pending_requests": pendingCount,
+		"workspace_rules":  len(m.ListRules("")),
 	}
 }
@@ -30,7 +30,7 @@ func TestMemoryManager_ConcurrentCheckAndStore(t *testing.T) {
 		go func(i int) {
 			defer wg.Done()
 			toolInput := map[string]interface{}{"command": "rm file.txt"}
-			m.CheckMemory(sessionID, "Bash", toolInput)
+			m.CheckMemory(sessionID, workspaceID, "Bash", toolInput)
 		}(i)
 
 		// Concurrent StoreDecision
@@ -178,6 +178,46 @@ func TestMemoryManager_GetAndRemovePendingRequest_Atomic(t *testing.T) {
 	}
 }
 
+func TestMemoryManager_AddApproval(t *testing.T) {
+	m := NewMemoryManager(SessionMemoryConfig{Enabled: true, TTL: time.Hour, MaxPatterns: 100}, zerolog.Nop())
+
+	if _, _, err := m.AddApproval("missing", "phone"); err != ErrRequestNotPending {
+		t.Errorf("AddApproval(missing) error = %v, want ErrRequestNotPending", err)
+	}
+
+	m.AddPendingRequest(&Request{
+		ToolUseID:         "tool-1",
+		ToolName:          "Bash",
+		RequiredApprovals: 2,
+		ResponseChan:      make(chan *Response, 1),
+	})
+
+	if !m.MarkNotified("tool-1", []string{"phone", "phone"}, false) {
+		t.Fatal("MarkNotified should succeed for a pending request")
+	}
+	m.MarkNotified("tool-1", []string{"laptop"}, true)
+	pending := m.GetPendingRequest("tool-1")
+	if len(pending.NotifiedDevices) != 2 || !pending.Escalated {
+		t.Errorf("NotifiedDevices = %v, Escalated = %v; want [phone laptop], true", pending.NotifiedDevices, pending.Escalated)
+	}
+
+	approvals, required, err := m.AddApproval("tool-1", "phone")
+	if err != nil || len(approvals) != 1 || required != 2 {
+		t.Fatalf("first approval = %v, %d, %v; want [phone], 2, nil", approvals, required, err)
+	}
+	if _, _, err := m.AddApproval("tool-1", "phone"); err != ErrAlreadyApproved {
+		t.Errorf("repeat approval error = %v, want ErrAlreadyApproved", err)
+	}
+	approvals, _, err = m.AddApproval("tool-1", "laptop")
+	if err != nil || len(approvals) != 2 {
+		t.Errorf("second approval = %v, %v; want two approvals", approvals, err)
+	}
+
+	if m.MarkNotified("missing", []string{"phone"}, false) {
+		t.Error("MarkNotified should fail for an unknown request")
+	}
+}
+
 func TestMemoryManager_CheckMemory_ReturnsACopy(t *testing.T) {
 	logger := zerolog.Nop()
 	config := SessionMemoryConfig{
@@ -196,7 +236,7 @@ func TestMemoryManager_CheckMemory_ReturnsACopy(t *testing.T) {
 
 	// Get the decision
 	toolInput := map[string]interface{}{"command": "rm file.txt"}
-	result := m.CheckMemory(sessionID, "Bash", toolInput)
+	result := m.CheckMemory(sessionID, workspaceID, "Bash", toolInput)
 	if result == nil {
 		t.Fatal("Expected to find stored decision")
 	}
@@ -205,7 +245,7 @@ func TestMemoryManager_CheckMemory_ReturnsACopy(t *testing.T) {
 	result.Decision = DecisionDeny
 
 	// Check that the original is unchanged
-	result2 := m.CheckMemory(sessionID, "Bash", toolInput)
+	result2 := m.CheckMemory(sessionID, workspaceID, "Bash", toolInput)
 	if result2.Decision != DecisionAllow {
 		t.Error("Original decision was modified through returned copy")
 	}
@@ -236,7 +276,7 @@ func TestMemoryManager_CleanupWithConcurrentAccess(t *testing.T) {
 			sessionID := "session-" + string(rune(i%10))
 			m.GetOrCreateSession(sessionID, "workspace")
 			toolInput := map[string]interface{}{"command": "rm file.txt"}
-			m.CheckMemory(sessionID, "Bash", toolInput)
+			m.CheckMemory(sessionID, "", "Bash", toolInput)
 		}(i)
 
 		// Concurrent store
@@ -1,6 +1,8 @@
 package permission
 
 import (
+	"encoding/json"
+	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -15,6 +17,7 @@ var mcpToolNamePattern = regexp.MustCompile(`^mcp__([A-Za-z0-9._-]+)__([A-Za-z0-
 //   - Bash(rm:*) for any rm command
 //   - Write(*.py) for writing Python files
 //   - Edit(/path/to/*.json) for editing JSON files in a directory
+//   - apply_patch(["a.go","b.go"]) for a Codex patch of exactly these files
 func GeneratePattern(toolName string, toolInput map[string]interface{}) string {
 	switch toolName {
 	case "Bash":
@@ -25,6 +28,8 @@ func GeneratePattern(toolName string, toolInput map[string]interface{}) string {
 		return generateFilePattern("Edit", toolInput, "file_path")
 	case "Read":
 		return generateFilePattern("Read", toolInput, "file_path")
+	case "apply_patch":
+		return generatePatchPattern(toolInput)
 	default:
 		// Generic pattern for unknown tools
 		return toolName + "(*)"
@@ -32,16 +37,35 @@ func GeneratePattern(toolName string, toolInput map[string]interface{}) string {
 }
 
 // generateBashPattern generates a pattern for Bash commands.
+// A command is split into its segments first: a prefix pattern is only
+// generated when every segment shares it and nothing is written through a
+// redirection. Anything else is remembered as the exact command.
 func generateBashPattern(toolInput map[string]interface{}) string {
 	cmd, ok := toolInput["command"].(string)
-	if !ok || cmd == "" {
+	if !ok || strings.TrimSpace(cmd) == "" {
 		return "Bash(*)"
 	}
 
-	// Extract the base command (first word)
-	parts := strings.Fields(cmd)
+	parsed := ParseShellCommand(cmd)
+	if parsed.Unsupported != "" || parsed.WritesFiles() || len(parsed.Segments) == 0 {
+		return "Bash(" + cmd + ")"
+	}
+
+	pattern := ""
+	for _, seg := range parsed.Segments {
+		segPattern := bashSegmentPattern(seg.Args)
+		if segPattern == "" || (pattern != "" && segPattern != pattern) {
+			return "Bash(" + cmd + ")"
+		}
+		pattern = segPattern
+	}
+	return pattern
+}
+
+// bashSegmentPattern generates a prefix pattern for a single simple command.
+func bashSegmentPattern(parts []string) string {
 	if len(parts) == 0 {
-		return "Bash(*)"
+		return ""
 	}
 
 	baseCmd := parts[0]
@@ -100,6 +124,14 @@ func generateFilePattern(toolName string, toolInput map[string]interface{}, path
 	return toolName + "(*)"
 }
 
+// generatePatchPattern generates a pattern listing the files of a Codex
+// patch. Unlike single-file edits it is not widened to an extension or a
+// directory, so a remembered patch decision never covers other files.
+func generatePatchPattern(toolInput map[string]interface{}) string {
+	data, _ := json.Marshal(patchFiles(toolInput))
+	return "apply_patch(" + string(data) + ")"
+}
+
 // MatchPattern checks if a permission request matches a stored pattern.
 func MatchPattern(pattern, toolName string, toolInput map[string]interface{}) bool {
 	// Parse the pattern
@@ -118,39 +150,146 @@ func MatchPattern(pattern, toolName string, toolInput map[string]interface{}) bo
 		return matchBashPattern(patternContent, toolInput)
 	case "Write", "Edit", "Read":
 		return matchFilePattern(patternContent, toolInput)
+	case "apply_patch":
+		return matchPatchPattern(patternContent, toolInput)
 	default:
 		return patternContent == "*"
 	}
 }
 
 // matchBashPattern matches a Bash command against a pattern.
+// A prefix pattern such as "git status:*" matches a compound command only if
+// every segment matches it, and never a segment that writes to a file through
+// a redirection; so "git status && rm -rf build" is not covered.
 func matchBashPattern(patternContent string, toolInput map[string]interface{}) bool {
 	cmd, ok := toolInput["command"].(string)
 	if !ok || cmd == "" {
 		return false
 	}
 
-	// Handle patterns like "rm:*" or "git add:*"
-	if strings.HasSuffix(patternContent, ":*") {
-		prefix := strings.TrimSuffix(patternContent, ":*")
-		cmdParts := strings.Fields(cmd)
+	// Exact match
+	if !strings.HasSuffix(patternContent, ":*") {
+		return cmd == patternContent
+	}
 
-		// Check if command starts with the pattern prefix
-		prefixParts := strings.Fields(prefix)
-		if len(cmdParts) < len(prefixParts) {
+	parsed := ParseShellCommand(cmd)
+	if parsed.Unsupported != "" || len(parsed.Segments) == 0 {
+		return false
+	}
+	for _, seg := range parsed.Segments {
+		if !matchBashSegment(patternContent, seg, true) {
 			return false
 		}
+	}
+	return true
+}
+
+// matchBashSegment matches one segment of a command against a pattern. When
+// strict, segments writing to files never match, as for allow decisions.
+func matchBashSegment(patternContent string, seg ShellSegment, strict bool) bool {
+	if patternContent == "*" {
+		return true
+	}
+	if strict && seg.WritesFile() {
+		return false
+	}
+	if len(seg.Args) == 0 {
+		// Bare redirection such as "> file"
+		return false
+	}
 
+	// Handle patterns like "rm:*" or "git add:*"
+	if strings.HasSuffix(patternContent, ":*") {
+		prefixParts := strings.Fields(strings.TrimSuffix(patternContent, ":*"))
+		if len(seg.Args) < len(prefixParts) {
+			return false
+		}
 		for i, p := range prefixParts {
-			if cmdParts[i] != p {
+			if seg.Args[i] != p {
 				return false
 			}
 		}
 		return true
 	}
 
-	// Exact match
-	return cmd == patternContent
+	return seg.Text() == patternContent
+}
+
+// patternDecision is a remembered pattern with its decision.
+type patternDecision struct {
+	Pattern  string
+	Decision Decision
+}
+
+// resolveDecision returns the index of the entry that decides a tool call, or
+// -1 if none does. Deny entries win over allow entries. For Bash, a deny
+// pattern applies if it matches any segment of the command, and a compound
+// command is allowed when each of its segments is covered by some allow
+// pattern, even if no single pattern covers all of them.
+func resolveDecision(entries []patternDecision, toolName string, toolInput map[string]interface{}) int {
+	var segments []ShellSegment
+	splittable := false
+	if toolName == "Bash" {
+		if cmd, ok := toolInput["command"].(string); ok && cmd != "" {
+			parsed := ParseShellCommand(cmd)
+			segments = parsed.Segments
+			splittable = parsed.Unsupported == "" && parsed.Compound()
+		}
+	}
+
+	for i, entry := range entries {
+		if entry.Decision != DecisionDeny {
+			continue
+		}
+		if MatchPattern(entry.Pattern, toolName, toolInput) {
+			return i
+		}
+		if content, ok := bashPatternContent(entry.Pattern); ok {
+			for _, seg := range segments {
+				if matchBashSegment(content, seg, false) {
+					return i
+				}
+			}
+		}
+	}
+
+	for i, entry := range entries {
+		if entry.Decision == DecisionAllow && MatchPattern(entry.Pattern, toolName, toolInput) {
+			return i
+		}
+	}
+	if !splittable {
+		return -1
+	}
+
+	first := -1
+	for _, seg := range segments {
+		covered := -1
+		for i, entry := range entries {
+			if entry.Decision != DecisionAllow {
+				continue
+			}
+			if content, ok := bashPatternContent(entry.Pattern); ok && matchBashSegment(content, seg, true) {
+				covered = i
+				break
+			}
+		}
+		if covered < 0 {
+			return -1
+		}
+		if first < 0 {
+			first = covered
+		}
+	}
+	return first
+}
+
+// bashPatternContent returns the content of a "Bash(...)" pattern.
+func bashPatternContent(pattern string) (string, bool) {
+	if !strings.HasPrefix(pattern, "Bash(") || !strings.HasSuffix(pattern, ")") {
+		return "", false
+	}
+	return pattern[len("Bash(") : len(pattern)-1], true
 }
 
 // matchFilePattern matches a file path against a pattern.
@@ -179,6 +318,32 @@ func matchFilePattern(patternContent string, toolInput map[string]interface{}) b
 	return matched
 }
 
+// matchPatchPattern matches a Codex patch against a list of files: every
+// file the patch touches must be listed.
+func matchPatchPattern(patternContent string, toolInput map[string]interface{}) bool {
+	var allowed []string
+	if err := json.Unmarshal([]byte(patternContent), &allowed); err != nil {
+		return false
+	}
+	files := patchFiles(toolInput)
+	if len(files) == 0 {
+		return false
+	}
+	for _, f := range files {
+		found := false
+		for _, a := range allowed {
+			if filepath.Clean(f) == filepath.Clean(a) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 // GenerateReadableDescription generates a human-readable description of a permission request.
 func GenerateReadableDescription(toolName string, toolInput map[string]interface{}) string {
 	if serverName, toolID, isMCP := parseMCPToolName(toolName); isMCP {
@@ -221,6 +386,12 @@ func GenerateReadableDescription(toolName string, toolInput map[string]interface
 		}
 		return "Read file"
 
+	case "apply_patch":
+		if files := patchFiles(toolInput); len(files) > 0 {
+			return fmt.Sprintf("Edit %d files", len(files))
+		}
+		return "Apply patch"
+
 	default:
 		return "Use " + toolName + " tool"
 	}
@@ -237,7 +408,7 @@ func GeneratePermissionType(toolName string) string {
 		return "bash_command"
 	case "Write":
 		return "write_file"
-	case "Edit":
+	case "Edit", "apply_patch":
 		return "edit_file"
 	case "Read":
 		return "read_file"
@@ -264,13 +435,31 @@ func ExtractTarget(toolName string, toolInput map[string]interface{}) string {
 		if path, ok := toolInput["file_path"].(string); ok {
 			return path
 		}
+	case "apply_patch":
+		return strings.Join(patchFiles(toolInput), ", ")
 	}
 	return ""
 }
 
+// patchFiles returns the files of a Codex apply_patch call.
+func patchFiles(toolInput map[string]interface{}) []string {
+	raw, _ := toolInput["files"].([]interface{})
+	files := make([]string, 0, len(raw))
+	for _, f := range raw {
+		if path, ok := f.(string); ok {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
 // ExtractPreview extracts a preview (content snippet) from tool input.
 func ExtractPreview(toolName string, toolInput map[string]interface{}) string {
 	switch toolName {
+	case "Bash":
+		if cmd, ok := toolInput["command"].(string); ok {
+			return bashPreview(cmd)
+		}
 	case "Write":
 		if content, ok := toolInput["content"].(string); ok {
 			// Return first few lines
@@ -295,6 +484,57 @@ func ExtractPreview(toolName string, toolInput map[string]interface{}) string {
 	return ""
 }
 
+// bashPreview describes what a Bash command runs, one line per segment, and
+// flags redirections to files and command substitution. Returns empty for a
+// plain single command, where the target already says everything.
+func bashPreview(cmd string) string {
+	parsed := ParseShellCommand(cmd)
+	if !parsed.Compound() && !parsed.WritesFiles() && len(parsed.Substitutions) == 0 && parsed.Unsupported == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	if parsed.Compound() {
+		fmt.Fprintf(&b, "Runs %d commands:\n", len(parsed.Segments))
+	}
+	var writes []string
+	for _, seg := range parsed.Segments {
+		line := seg.Text()
+		if seg.Operator != "" {
+			line = seg.Operator + " " + line
+		}
+		switch {
+		case seg.Substituted:
+			line += "  [substitution]"
+		case seg.Subshell:
+			line += "  [subshell]"
+		}
+		if parsed.Compound() {
+			b.WriteString("  " + line + "\n")
+		}
+		for _, r := range seg.Redirections {
+			if r.WritesFile() {
+				writes = append(writes, r.Target)
+			}
+		}
+	}
+	if len(writes) > 0 {
+		b.WriteString("Writes to: " + strings.Join(writes, ", ") + "\n")
+	}
+	if len(parsed.Substitutions) > 0 {
+		b.WriteString("Uses command substitution\n")
+	}
+	if parsed.Unsupported != "" {
+		b.WriteString("Could not fully parse: " + parsed.Unsupported + "\n")
+	}
+
+	preview := strings.TrimSuffix(b.String(), "\n")
+	if len(preview) > 500 {
+		preview = preview[:497] + "..."
+	}
+	return preview
+}
+
 // SanitizeCommand sanitizes a command for display, removing sensitive info.
 func SanitizeCommand(cmd string) string {
 	// List of patterns to redact
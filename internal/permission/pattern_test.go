@@ -46,3 +46,31 @@ func TestExtractTarget_MCPFallback(t *testing.T) {
 		t.Fatalf("ExtractTarget() = %q, want %q", target, "playwright/click")
 	}
 }
+
+func TestPatchPattern_CoversOnlyItsFiles(t *testing.T) {
+	patch := func(files ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"files": files}
+	}
+
+	pattern := GeneratePattern("apply_patch", patch("src/a.go", "src/b.go"))
+	if pattern != `apply_patch(["src/a.go","src/b.go"])` {
+		t.Fatalf("GeneratePattern() = %q", pattern)
+	}
+
+	tests := []struct {
+		input map[string]interface{}
+		want  bool
+	}{
+		{patch("src/a.go", "src/b.go"), true},
+		{patch("src/b.go", "./src/a.go"), true},
+		{patch("src/a.go"), true},
+		{patch("src/a.go", ".env"), false},
+		{patch("src/a.go", "../other/b.go"), false},
+		{patch(), false},
+	}
+	for _, tt := range tests {
+		if got := MatchPattern(pattern, "apply_patch", tt.input); got != tt.want {
+			t.Errorf("MatchPattern(%q, %v) = %v, want %v", pattern, tt.input["files"], got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,625 @@
+package permission
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const policyVersion = 1
+
+// Policy is a declarative set of permission rules loaded from a YAML file.
+//
+// Example:
+//
+//	version: 1
+//	rules:
+//	  - name: no-force-push
+//	    decision: deny
+//	    reason: Force pushes must be done by hand
+//	    tools: [Bash]
+//	    commands: ["git push --force:*", "git push * --force*", "git push -f:*"]
+//	  - name: stay-in-repo
+//	    decision: deny
+//	    tools: [Write, Edit]
+//	    outside_workspace: true
+//	  - name: read-anything
+//	    decision: allow
+//	    tools: [Read, Glob, Grep]
+//	  - name: auto-approve-low-risk
+//	    decision: allow
+//	    max_risk: low
+type Policy struct {
+	Version int          `yaml:"version" json:"version"`
+	Rules   []PolicyRule `yaml:"rules" json:"rules"`
+}
+
+// PolicyRule matches tool calls on a set of conditions. Every condition that
+// is set must hold; list conditions match if any entry matches.
+type PolicyRule struct {
+	Name     string   `yaml:"name" json:"name,omitempty"`
+	Decision Decision `yaml:"decision" json:"decision"` // allow, deny or ask
+	Reason   string   `yaml:"reason" json:"reason,omitempty"`
+
+	Tools            []string          `yaml:"tools" json:"tools,omitempty"`                         // Tool name globs, e.g. "Bash", "mcp__*"
+	Paths            []string          `yaml:"paths" json:"paths,omitempty"`                         // Path globs; "**" spans directories, relative globs are anchored at the workspace root
+	OutsideWorkspace bool              `yaml:"outside_workspace" json:"outside_workspace,omitempty"` // Any touched path lies outside the workspace root
+	Commands         []string          `yaml:"commands" json:"commands,omitempty"`                   // Bash patterns ("git push:*") or wildcards ("git push * --force*"), matched per segment
+	MCP              *PolicyMCPMatch   `yaml:"mcp" json:"mcp,omitempty"`
+	SessionTypes     []string          `yaml:"session_types" json:"session_types,omitempty"` // Agent runtime: claude, codex
+	TimeWindow       *PolicyTimeWindow `yaml:"time_window" json:"time_window,omitempty"`
+	MinRisk          RiskLevel         `yaml:"min_risk" json:"min_risk,omitempty"` // Risk tier of the call is at least this (low, medium, high, critical)
+	MaxRisk          RiskLevel         `yaml:"max_risk" json:"max_risk,omitempty"` // Risk tier of the call is at most this
+}
+
+// PolicyMCPMatch matches MCP tools (mcp__<server>__<tool>) by server and tool glob.
+type PolicyMCPMatch struct {
+	Server string `yaml:"server" json:"server,omitempty"`
+	Tool   string `yaml:"tool" json:"tool,omitempty"`
+}
+
+// PolicyTimeWindow restricts a rule to local days and hours. Start and End
+// are "HH:MM"; a window whose end is before its start spans midnight.
+type PolicyTimeWindow struct {
+	Days  []string `yaml:"days" json:"days,omitempty"` // mon, tue, ... ; empty means every day
+	Start string   `yaml:"start" json:"start,omitempty"`
+	End   string   `yaml:"end" json:"end,omitempty"`
+}
+
+// PolicyInput describes a tool call to evaluate.
+type PolicyInput struct {
+	ToolName      string
+	ToolInput     map[string]interface{}
+	WorkspaceID   string
+	WorkspacePath string // Filled in by the engine when empty
+	Cwd           string
+	SessionType   string
+	Time          time.Time // Defaults to now
+}
+
+// PolicyDecision is the outcome of a policy evaluation.
+type PolicyDecision struct {
+	Decision Decision `json:"decision"`
+	Reason   string   `json:"reason"`
+	Rule     string   `json:"rule"`   // Rule name, or "#<index>" for unnamed rules
+	Source   string   `json:"source"` // "workspace" or "global"
+}
+
+// LoadPolicy reads and validates a policy file. A missing file yields a nil
+// policy and no error.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy parses and validates a YAML policy document.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, err
+	}
+	if policy.Version == 0 {
+		policy.Version = policyVersion
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks the policy for unknown decisions and malformed conditions.
+func (p *Policy) Validate() error {
+	if p.Version != policyVersion {
+		return fmt.Errorf("unsupported policy version %d", p.Version)
+	}
+	for i, rule := range p.Rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", rule.label(i), err)
+		}
+	}
+	return nil
+}
+
+func (r PolicyRule) label(index int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
+
+func (r PolicyRule) validate() error {
+	switch r.Decision {
+	case DecisionAllow, DecisionDeny, DecisionAsk:
+	default:
+		return fmt.Errorf("decision must be 'allow', 'deny' or 'ask'")
+	}
+	for _, glob := range append(append([]string{}, r.Tools...), r.Paths...) {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid glob %q", glob)
+		}
+	}
+	if r.MCP != nil {
+		for _, glob := range []string{r.MCP.Server, r.MCP.Tool} {
+			if _, err := path.Match(glob, ""); err != nil {
+				return fmt.Errorf("invalid mcp glob %q", glob)
+			}
+		}
+	}
+	for _, level := range []RiskLevel{r.MinRisk, r.MaxRisk} {
+		if level != "" && level.rank() < 0 {
+			return fmt.Errorf("invalid risk level %q: expected low, medium, high or critical", level)
+		}
+	}
+	if w := r.TimeWindow; w != nil {
+		for _, day := range w.Days {
+			if _, ok := policyWeekday(day); !ok {
+				return fmt.Errorf("invalid day %q", day)
+			}
+		}
+		for _, clock := range []string{w.Start, w.End} {
+			if clock == "" {
+				continue
+			}
+			if _, err := time.Parse("15:04", clock); err != nil {
+				return fmt.Errorf("invalid time %q: expected HH:MM", clock)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the decision of the first matching rule, except that a
+// matching deny rule always wins. Returns nil if no rule matches.
+func (p *Policy) Evaluate(in PolicyInput) *PolicyDecision {
+	if p == nil {
+		return nil
+	}
+	call := newPolicyCall(in)
+
+	var first *PolicyDecision
+	for i, rule := range p.Rules {
+		if !rule.matches(call) {
+			continue
+		}
+		decision := &PolicyDecision{Decision: rule.Decision, Reason: rule.Reason, Rule: rule.label(i)}
+		if decision.Reason == "" {
+			decision.Reason = fmt.Sprintf("Policy rule %s: %s", decision.Rule, rule.Decision)
+		}
+		if rule.Decision == DecisionDeny {
+			return decision
+		}
+		if first == nil {
+			first = decision
+		}
+	}
+	return first
+}
+
+// PolicyEngine evaluates the workspace policy and the global policy for a
+// tool call. Files are re-read when they change, so edits apply without a
+// restart.
+type PolicyEngine struct {
+	globalPath    string
+	workspaceDir  string
+	workspacePath func(workspaceID string) string
+
+	mu    sync.Mutex
+	cache map[string]cachedPolicy
+}
+
+type cachedPolicy struct {
+	modTime time.Time
+	size    int64
+	policy  *Policy
+	err     error
+}
+
+// NewPolicyEngine creates an engine reading the global policy from globalPath
+// and workspace policies from <workspaceDir>/<workspace_id>.yaml.
+// workspacePath resolves a workspace ID to its root directory; it may be nil.
+func NewPolicyEngine(globalPath, workspaceDir string, workspacePath func(workspaceID string) string) *PolicyEngine {
+	return &PolicyEngine{
+		globalPath:    globalPath,
+		workspaceDir:  workspaceDir,
+		workspacePath: workspacePath,
+		cache:         make(map[string]cachedPolicy),
+	}
+}
+
+// GlobalPolicyPath returns the path of the global policy file.
+func (e *PolicyEngine) GlobalPolicyPath() string {
+	return e.globalPath
+}
+
+// WorkspacePolicyPath returns the path of a workspace's policy file.
+func (e *PolicyEngine) WorkspacePolicyPath(workspaceID string) string {
+	if e.workspaceDir == "" || workspaceID == "" || strings.ContainsAny(workspaceID, `/\`) || workspaceID == ".." {
+		return ""
+	}
+	return filepath.Join(e.workspaceDir, workspaceID+".yaml")
+}
+
+// Evaluate checks the workspace policy, then the global policy. A deny from
+// either wins; otherwise the workspace decision takes precedence. A policy
+// file that cannot be loaded yields "ask", so a broken file never lets calls
+// through unreviewed.
+func (e *PolicyEngine) Evaluate(in PolicyInput) *PolicyDecision {
+	if e == nil {
+		return nil
+	}
+	if in.WorkspacePath == "" && in.WorkspaceID != "" && e.workspacePath != nil {
+		in.WorkspacePath = e.workspacePath(in.WorkspaceID)
+	}
+
+	sources := []struct {
+		name string
+		path string
+	}{
+		{name: "workspace", path: e.WorkspacePolicyPath(in.WorkspaceID)},
+		{name: "global", path: e.globalPath},
+	}
+
+	var result *PolicyDecision
+	for _, source := range sources {
+		if source.path == "" {
+			continue
+		}
+		policy, err := e.load(source.path)
+		var decision *PolicyDecision
+		if err != nil {
+			decision = &PolicyDecision{
+				Decision: DecisionAsk,
+				Reason:   fmt.Sprintf("Invalid %s policy %s: %v", source.name, source.path, err),
+				Rule:     "invalid-policy",
+			}
+		} else {
+			decision = policy.Evaluate(in)
+		}
+		if decision == nil {
+			continue
+		}
+		decision.Source = source.name
+		if decision.Decision == DecisionDeny {
+			return decision
+		}
+		if result == nil {
+			result = decision
+		}
+	}
+	return result
+}
+
+// load returns the parsed policy at path, re-reading it if it changed.
+func (e *PolicyEngine) load(path string) (*Policy, error) {
+	info, statErr := os.Stat(path)
+	if statErr != nil {
+		if os.IsNotExist(statErr) {
+			return nil, nil
+		}
+		return nil, statErr
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if cached, ok := e.cache[path]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.policy, cached.err
+	}
+	policy, err := LoadPolicy(path)
+	e.cache[path] = cachedPolicy{modTime: info.ModTime(), size: info.Size(), policy: policy, err: err}
+	return policy, err
+}
+
+// policyCall is a tool call prepared for rule matching.
+type policyCall struct {
+	in          PolicyInput
+	root        string
+	paths       []string
+	command     string
+	segments    []ShellSegment
+	unsupported string // Why the command could not be fully parsed
+	assessed    *Risk
+}
+
+func newPolicyCall(in PolicyInput) *policyCall {
+	if in.Time.IsZero() {
+		in.Time = time.Now()
+	}
+	call := &policyCall{in: in, root: in.WorkspacePath}
+	if call.root == "" {
+		call.root = in.Cwd
+	}
+	base := in.Cwd
+	if base == "" {
+		base = call.root
+	}
+
+	addPath := func(p string) {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			return
+		}
+		p = expandHome(p)
+		if !filepath.IsAbs(p) && base != "" {
+			p = filepath.Join(base, p)
+		}
+		call.paths = append(call.paths, filepath.Clean(p))
+	}
+
+	for _, key := range []string{"file_path", "notebook_path", "path"} {
+		if p, ok := in.ToolInput[key].(string); ok {
+			addPath(p)
+		}
+	}
+	// Codex patches touching several files list them all
+	if files, ok := in.ToolInput["files"].([]interface{}); ok {
+		for _, f := range files {
+			if p, ok := f.(string); ok {
+				addPath(p)
+			}
+		}
+	}
+	if in.ToolName == "Bash" {
+		if cmd, ok := in.ToolInput["command"].(string); ok {
+			call.command = cmd
+			parsed := ParseShellCommand(cmd)
+			call.segments = parsed.Segments
+			call.unsupported = parsed.Unsupported
+			for _, seg := range call.segments {
+				for _, r := range seg.Redirections {
+					if r.WritesFile() {
+						addPath(r.Target)
+					}
+				}
+			}
+		}
+	}
+	return call
+}
+
+func (r PolicyRule) matches(call *policyCall) bool {
+	if len(r.Tools) > 0 && !matchAnyGlob(r.Tools, call.in.ToolName) {
+		return false
+	}
+	if len(r.SessionTypes) > 0 && !containsFold(r.SessionTypes, call.in.SessionType) {
+		return false
+	}
+	if r.MCP != nil && !r.MCP.matches(call.in.ToolName) {
+		return false
+	}
+	if r.TimeWindow != nil && !r.TimeWindow.contains(call.in.Time) {
+		return false
+	}
+	if len(r.Commands) > 0 && !r.matchesCommand(call) {
+		return false
+	}
+	if len(r.Paths) > 0 && !r.matchesPath(call) {
+		return false
+	}
+	if r.OutsideWorkspace && !call.outsideWorkspace() {
+		return false
+	}
+	if r.MinRisk != "" && !call.risk().Level.AtLeast(r.MinRisk) {
+		return false
+	}
+	if r.MaxRisk != "" && !r.MaxRisk.AtLeast(call.risk().Level) {
+		return false
+	}
+	return true
+}
+
+func (r PolicyRule) matchesCommand(call *policyCall) bool {
+	if call.command == "" {
+		return false
+	}
+	for _, pattern := range r.Commands {
+		if !strings.HasSuffix(pattern, ":*") && wildcardMatch(pattern, call.command) {
+			return true
+		}
+		for _, seg := range call.segments {
+			if strings.HasSuffix(pattern, ":*") {
+				if matchBashSegment(pattern, seg, false) {
+					return true
+				}
+			} else if wildcardMatch(pattern, seg.Text()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r PolicyRule) matchesPath(call *policyCall) bool {
+	for _, p := range call.paths {
+		for _, glob := range r.Paths {
+			glob = expandHome(glob)
+			if !filepath.IsAbs(glob) {
+				if call.root != "" {
+					glob = filepath.Join(call.root, glob)
+				} else {
+					glob = "**/" + glob
+				}
+			}
+			if matchPathGlob(filepath.ToSlash(glob), filepath.ToSlash(p)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// outsideWorkspace reports whether any path of the call lies outside the
+// workspace root. Calls without paths, or without a known root, are inside.
+func (c *policyCall) outsideWorkspace() bool {
+	if c.root == "" {
+		return false
+	}
+	root := filepath.Clean(c.root)
+	for _, p := range c.paths {
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *PolicyMCPMatch) matches(toolName string) bool {
+	server, tool, ok := parseMCPToolName(toolName)
+	if !ok {
+		return false
+	}
+	if m.Server != "" && !matchAnyGlob([]string{m.Server}, server) {
+		return false
+	}
+	if m.Tool != "" && !matchAnyGlob([]string{m.Tool}, tool) {
+		return false
+	}
+	return true
+}
+
+var policyWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// policyWeekday parses "mon", "Monday" and the like.
+func policyWeekday(day string) (time.Weekday, bool) {
+	day = strings.ToLower(strings.TrimSpace(day))
+	if len(day) < 3 {
+		return 0, false
+	}
+	wd, ok := policyWeekdays[day[:3]]
+	return wd, ok
+}
+
+func (w *PolicyTimeWindow) contains(t time.Time) bool {
+	t = t.Local()
+	if len(w.Days) > 0 {
+		found := false
+		for _, day := range w.Days {
+			if wd, ok := policyWeekday(day); ok && wd == t.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	minutes := t.Hour()*60 + t.Minute()
+	start, end := 0, 24*60
+	if w.Start != "" {
+		start = clockMinutes(w.Start)
+	}
+	if w.End != "" {
+		end = clockMinutes(w.End)
+	}
+	if start <= end {
+		return minutes >= start && minutes < end
+	}
+	return minutes >= start || minutes < end
+}
+
+func clockMinutes(clock string) int {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0
+	}
+	return t.Hour()*60 + t.Minute()
+}
+
+func matchAnyGlob(globs []string, name string) bool {
+	for _, glob := range globs {
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathGlob matches a slash-separated path against a glob in which "**"
+// matches any number of directories.
+func matchPathGlob(glob, name string) bool {
+	return matchGlobParts(strings.Split(glob, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobParts(glob, name []string) bool {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlobParts(glob[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(glob[0], name[0]); !ok {
+			return false
+		}
+		glob, name = glob[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// wildcardMatch matches text against a pattern where "*" matches any run of
+// characters, including spaces and slashes, and "?" matches one character.
+func wildcardMatch(pattern, text string) bool {
+	p, t := []rune(pattern), []rune(text)
+	pi, ti := 0, 0
+	star, mark := -1, 0
+	for ti < len(t) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == t[ti]):
+			pi++
+			ti++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, ti
+			pi++
+		case star >= 0:
+			pi = star + 1
+			mark++
+			ti = mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+func expandHome(p string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(p, "~"))
+		}
+	}
+	return p
+}
@@ -0,0 +1,259 @@
+package permission
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPolicy = `
+version: 1
+rules:
+  - name: no-force-push
+    decision: deny
+    reason: Force pushes must be done by hand
+    tools: [Bash]
+    commands: ["git push --force:*", "git push -f:*", "git push * --force*"]
+  - name: stay-in-repo
+    decision: deny
+    tools: [Write, Edit, Bash]
+    outside_workspace: true
+  - name: secrets
+    decision: ask
+    paths: ["**/.env", "secrets/**"]
+  - name: reads
+    decision: allow
+    tools: [Read, Glob, Grep]
+  - name: playwright
+    decision: allow
+    mcp: {server: playwright, tool: "browser_*"}
+  - name: go-tooling
+    decision: allow
+    commands: ["go test:*", "go build:*"]
+    session_types: [claude]
+`
+
+func evaluateTestPolicy(t *testing.T, policy *Policy, tool string, input map[string]interface{}) *PolicyDecision {
+	t.Helper()
+	return policy.Evaluate(PolicyInput{
+		ToolName:      tool,
+		ToolInput:     input,
+		WorkspaceID:   "ws-1",
+		WorkspacePath: "/repo",
+		Cwd:           "/repo",
+		SessionType:   "claude",
+	})
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		tool  string
+		input map[string]interface{}
+		want  Decision
+		rule  string
+	}{
+		{name: "force push", tool: "Bash", input: map[string]interface{}{"command": "git status && git push --force origin main"}, want: DecisionDeny, rule: "no-force-push"},
+		{name: "force push flag after args", tool: "Bash", input: map[string]interface{}{"command": "git push origin main --force-with-lease"}, want: DecisionDeny, rule: "no-force-push"},
+		{name: "write outside repo", tool: "Write", input: map[string]interface{}{"file_path": "/etc/hosts"}, want: DecisionDeny, rule: "stay-in-repo"},
+		{name: "relative escape", tool: "Edit", input: map[string]interface{}{"file_path": "../other/main.go"}, want: DecisionDeny, rule: "stay-in-repo"},
+		{name: "bash redirect outside repo", tool: "Bash", input: map[string]interface{}{"command": "echo x >> ~/.bashrc"}, want: DecisionDeny, rule: "stay-in-repo"},
+		{name: "env file", tool: "Read", input: map[string]interface{}{"file_path": "/repo/config/.env"}, want: DecisionAsk, rule: "secrets"},
+		{name: "secrets dir", tool: "Edit", input: map[string]interface{}{"file_path": "/repo/secrets/a/key.pem"}, want: DecisionAsk, rule: "secrets"},
+		{name: "codex patch touching secrets", tool: "apply_patch", input: map[string]interface{}{"files": []interface{}{"main.go", "secrets/key.pem"}}, want: DecisionAsk, rule: "secrets"},
+		{name: "read", tool: "Read", input: map[string]interface{}{"file_path": "/repo/main.go"}, want: DecisionAllow, rule: "reads"},
+		{name: "mcp", tool: "mcp__playwright__browser_navigate", input: map[string]interface{}{"url": "https://example.com"}, want: DecisionAllow, rule: "playwright"},
+		{name: "go test", tool: "Bash", input: map[string]interface{}{"command": "go test ./..."}, want: DecisionAllow, rule: "go-tooling"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := evaluateTestPolicy(t, policy, tt.tool, tt.input)
+			if decision == nil {
+				t.Fatalf("expected %s, got no decision", tt.want)
+			}
+			if decision.Decision != tt.want || decision.Rule != tt.rule {
+				t.Errorf("got %s by %q, want %s by %q", decision.Decision, decision.Rule, tt.want, tt.rule)
+			}
+		})
+	}
+
+	for _, input := range []map[string]interface{}{
+		{"command": "rm -rf build"},
+		{"command": "git push origin main"},
+	} {
+		if decision := evaluateTestPolicy(t, policy, "Bash", input); decision != nil {
+			t.Errorf("expected no decision for %v, got %+v", input, decision)
+		}
+	}
+	if decision := evaluateTestPolicy(t, policy, "mcp__github__create_issue", nil); decision != nil {
+		t.Errorf("expected no decision for other MCP server, got %+v", decision)
+	}
+}
+
+func TestPolicy_DenyWinsOverEarlierAllow(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`
+rules:
+  - decision: allow
+    tools: [Bash]
+  - name: no-rm
+    decision: deny
+    commands: ["rm:*"]
+`))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+
+	decision := evaluateTestPolicy(t, policy, "Bash", map[string]interface{}{"command": "ls; rm -rf /"})
+	if decision == nil || decision.Decision != DecisionDeny || decision.Rule != "no-rm" {
+		t.Fatalf("expected deny by no-rm, got %+v", decision)
+	}
+	decision = evaluateTestPolicy(t, policy, "Bash", map[string]interface{}{"command": "ls"})
+	if decision == nil || decision.Decision != DecisionAllow || decision.Rule != "#1" {
+		t.Fatalf("expected allow by #1, got %+v", decision)
+	}
+}
+
+func TestPolicy_TimeWindow(t *testing.T) {
+	window := &PolicyTimeWindow{Days: []string{"mon", "Tuesday"}, Start: "09:00", End: "17:00"}
+	monday := time.Date(2026, 10, 19, 10, 0, 0, 0, time.Local)
+
+	if !window.contains(monday) {
+		t.Error("expected Monday 10:00 to be inside the window")
+	}
+	if window.contains(monday.Add(8 * time.Hour)) {
+		t.Error("expected Monday 18:00 to be outside the window")
+	}
+	if window.contains(monday.AddDate(0, 0, 5)) {
+		t.Error("expected Saturday to be outside the window")
+	}
+
+	overnight := &PolicyTimeWindow{Start: "22:00", End: "06:00"}
+	if !overnight.contains(time.Date(2026, 10, 19, 23, 30, 0, 0, time.Local)) || !overnight.contains(time.Date(2026, 10, 19, 5, 0, 0, 0, time.Local)) {
+		t.Error("expected overnight window to span midnight")
+	}
+	if overnight.contains(monday) {
+		t.Error("expected 10:00 to be outside the overnight window")
+	}
+}
+
+func TestPolicy_RiskThresholds(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`
+rules:
+  - name: review-critical
+    decision: deny
+    min_risk: critical
+  - name: auto-approve-low-risk
+    decision: allow
+    max_risk: low
+`))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+
+	tests := []struct {
+		command string
+		want    Decision
+		rule    string
+	}{
+		{command: "go test ./...", want: DecisionAllow, rule: "auto-approve-low-risk"},
+		{command: "sudo rm -rf /var/lib/app", want: DecisionDeny, rule: "review-critical"},
+		{command: "npm install left-pad"},
+	}
+	for _, tt := range tests {
+		decision := evaluateTestPolicy(t, policy, "Bash", map[string]interface{}{"command": tt.command})
+		if tt.want == "" {
+			if decision != nil {
+				t.Errorf("%q: expected no decision, got %+v", tt.command, decision)
+			}
+			continue
+		}
+		if decision == nil || decision.Decision != tt.want || decision.Rule != tt.rule {
+			t.Errorf("%q: got %+v, want %s by %q", tt.command, decision, tt.want, tt.rule)
+		}
+	}
+}
+
+func TestParsePolicy_Validation(t *testing.T) {
+	for _, doc := range []string{
+		"version: 2\nrules: []",
+		"rules:\n  - decision: maybe",
+		"rules:\n  - decision: allow\n    tools: ['[']",
+		"rules:\n  - decision: allow\n    time_window: {days: [someday]}",
+		"rules:\n  - decision: allow\n    time_window: {start: '9am'}",
+		"rules:\n  - decision: allow\n    max_risk: tiny",
+	} {
+		if _, err := ParsePolicy([]byte(doc)); err == nil {
+			t.Errorf("expected error for %q", doc)
+		}
+	}
+}
+
+func TestPolicyEngine_WorkspaceAndGlobal(t *testing.T) {
+	dir := t.TempDir()
+	globalPath := filepath.Join(dir, "permission_policy.yaml")
+	workspaceDir := filepath.Join(dir, "policies")
+	if err := os.MkdirAll(workspaceDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	writePolicy := func(path, doc string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writePolicy(globalPath, "rules:\n  - name: global-allow-bash\n    decision: allow\n    tools: [Bash]\n  - name: global-no-sudo\n    decision: deny\n    commands: ['sudo:*']\n")
+	writePolicy(filepath.Join(workspaceDir, "ws-1.yaml"), "rules:\n  - name: ws-ask-bash\n    decision: ask\n    tools: [Bash]\n")
+
+	engine := NewPolicyEngine(globalPath, workspaceDir, func(id string) string { return "/repo/" + id })
+	evaluate := func(workspaceID, command string) *PolicyDecision {
+		return engine.Evaluate(PolicyInput{ToolName: "Bash", ToolInput: map[string]interface{}{"command": command}, WorkspaceID: workspaceID})
+	}
+
+	if d := evaluate("ws-1", "ls"); d == nil || d.Decision != DecisionAsk || d.Source != "workspace" {
+		t.Errorf("expected workspace ask, got %+v", d)
+	}
+	if d := evaluate("ws-2", "ls"); d == nil || d.Decision != DecisionAllow || d.Source != "global" {
+		t.Errorf("expected global allow, got %+v", d)
+	}
+	if d := evaluate("ws-1", "sudo ls"); d == nil || d.Decision != DecisionDeny || d.Rule != "global-no-sudo" {
+		t.Errorf("expected global deny to win over workspace ask, got %+v", d)
+	}
+
+	// Edits are picked up, and a broken file forces asking.
+	writePolicy(globalPath, "rules: [")
+	if d := evaluate("ws-2", "ls"); d == nil || d.Decision != DecisionAsk || !strings.Contains(d.Reason, "Invalid global policy") {
+		t.Errorf("expected ask for invalid policy, got %+v", d)
+	}
+
+	if engine.WorkspacePolicyPath("../escape") != "" {
+		t.Error("expected workspace IDs with path separators to be rejected")
+	}
+}
+
+func TestMatchPathGlob(t *testing.T) {
+	tests := []struct {
+		glob string
+		path string
+		want bool
+	}{
+		{glob: "/repo/**", path: "/repo/a/b.go", want: true},
+		{glob: "/repo/**/*.go", path: "/repo/main.go", want: true},
+		{glob: "/repo/**/*.go", path: "/repo/a/b/c.go", want: true},
+		{glob: "/repo/*.go", path: "/repo/a/b.go", want: false},
+		{glob: "**/.env", path: "/home/me/app/.env", want: true},
+	}
+	for _, tt := range tests {
+		if got := matchPathGlob(tt.glob, tt.path); got != tt.want {
+			t.Errorf("matchPathGlob(%q, %q) = %v, want %v", tt.glob, tt.path, got, tt.want)
+		}
+	}
+}
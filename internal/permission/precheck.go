@@ -0,0 +1,70 @@
+package permission
+
+// MemoryChecker looks up workspace rules and remembered session decisions.
+type MemoryChecker interface {
+	CheckMemory(sessionID, workspaceID, toolName string, toolInput map[string]interface{}) *StoredDecision
+}
+
+// PrecheckInput is a tool call about to be decided.
+type PrecheckInput struct {
+	PolicyInput
+	SessionID string
+
+	// MustAsk is set when the input is not exact, e.g. a command cut by the
+	// terminal: denies still apply, but only a device may allow it.
+	MustAsk bool
+}
+
+// Precheck is the outcome of the checks a tool call goes through before a
+// device is asked.
+type Precheck struct {
+	Risk   Risk
+	Policy *PolicyDecision // Policy rule that decided the call, or asked for a device
+	Stored *StoredDecision // Remembered decision that decided the call
+	Route  Route           // Devices to ask, when nothing decided the call
+}
+
+// Decided reports whether the call was decided without a device.
+func (p *Precheck) Decided() bool {
+	return p.Stored != nil || (p.Policy != nil && p.Policy.Decision != DecisionAsk)
+}
+
+// RunPrecheck runs a tool call through policies, workspace rules and session
+// memory, in that order, and plans the devices to ask when none decides.
+// Policy denies win over remembered decisions, a policy ask skips them, and
+// a policy allow applies when nothing remembered says otherwise. Remembered
+// allows never skip the approvals a high-risk call needs. The risk
+// shown with the prompt is the one the policy's risk bounds were checked
+// against. policy and memory may be nil.
+func RunPrecheck(in PrecheckInput, policy PolicyChecker, memory MemoryChecker, router *Router) Precheck {
+	pre := Precheck{Risk: AssessRisk(in.PolicyInput)}
+
+	var decision *PolicyDecision
+	if policy != nil {
+		decision = policy.Evaluate(in.PolicyInput)
+	}
+	if decision != nil && decision.Decision == DecisionDeny {
+		pre.Policy = decision
+		return pre
+	}
+
+	if memory != nil && (decision == nil || decision.Decision != DecisionAsk) {
+		stored := memory.CheckMemory(in.SessionID, in.WorkspaceID, in.ToolName, in.ToolInput)
+		if stored != nil && stored.Decision == DecisionAllow && (in.MustAsk || router.IsHighRisk(in.ToolName, in.ToolInput)) {
+			stored = nil // Only devices may allow it, as many as the route needs
+		}
+		if stored != nil {
+			pre.Stored = stored
+			return pre
+		}
+	}
+	if decision != nil && !(in.MustAsk && decision.Decision == DecisionAllow) {
+		pre.Policy = decision
+		if pre.Decided() {
+			return pre
+		}
+	}
+
+	pre.Route = router.Plan(in.ToolName, in.ToolInput)
+	return pre
+}
@@ -0,0 +1,86 @@
+package permission
+
+import "testing"
+
+type policyFunc func(in PolicyInput) *PolicyDecision
+
+func (f policyFunc) Evaluate(in PolicyInput) *PolicyDecision { return f(in) }
+
+type memoryFunc func(sessionID, workspaceID, toolName string, toolInput map[string]interface{}) *StoredDecision
+
+func (f memoryFunc) CheckMemory(sessionID, workspaceID, toolName string, toolInput map[string]interface{}) *StoredDecision {
+	return f(sessionID, workspaceID, toolName, toolInput)
+}
+
+func TestRunPrecheck(t *testing.T) {
+	remembered := &StoredDecision{Pattern: "Bash(git status:*)", Decision: DecisionAllow}
+	memory := memoryFunc(func(string, string, string, map[string]interface{}) *StoredDecision { return remembered })
+	policyOf := func(decision Decision) PolicyChecker {
+		return policyFunc(func(PolicyInput) *PolicyDecision { return &PolicyDecision{Decision: decision, Rule: "r"} })
+	}
+	in := PrecheckInput{
+		PolicyInput: PolicyInput{ToolName: "Bash", ToolInput: map[string]interface{}{"command": "git status"}, WorkspaceID: "ws-1"},
+		SessionID:   "sess-1",
+	}
+
+	tests := []struct {
+		name       string
+		policy     PolicyChecker
+		memory     MemoryChecker
+		mustAsk    bool
+		wantPolicy Decision
+		wantStored bool
+		decided    bool
+	}{
+		{name: "nothing matches", decided: false},
+		{name: "policy deny wins over memory", policy: policyOf(DecisionDeny), memory: memory, wantPolicy: DecisionDeny, decided: true},
+		{name: "memory wins over policy allow", policy: policyOf(DecisionAllow), memory: memory, wantStored: true, decided: true},
+		{name: "policy allow", policy: policyOf(DecisionAllow), wantPolicy: DecisionAllow, decided: true},
+		{name: "policy ask skips memory", policy: policyOf(DecisionAsk), memory: memory, wantPolicy: DecisionAsk, decided: false},
+		{name: "must ask skips allows", policy: policyOf(DecisionAllow), memory: memory, mustAsk: true, decided: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := in
+			in.MustAsk = tt.mustAsk
+			pre := RunPrecheck(in, tt.policy, tt.memory, nil)
+			if pre.Decided() != tt.decided || (pre.Stored != nil) != tt.wantStored {
+				t.Fatalf("precheck = %+v, want decided %v, stored %v", pre, tt.decided, tt.wantStored)
+			}
+			var policy Decision
+			if pre.Policy != nil {
+				policy = pre.Policy.Decision
+			}
+			if policy != tt.wantPolicy {
+				t.Errorf("policy = %q, want %q", policy, tt.wantPolicy)
+			}
+			if !pre.Decided() && pre.Route.RequiredApprovals != 1 {
+				t.Errorf("route = %+v, want a plan for the devices", pre.Route)
+			}
+		})
+	}
+}
+
+func TestRunPrecheck_RememberedAllowKeepsHighRiskApprovals(t *testing.T) {
+	router := NewRouter(RoutingConfig{Enabled: true, HighRisk: []string{"Bash(git push:*)"}, HighRiskApprovals: 3})
+	memory := memoryFunc(func(string, string, string, map[string]interface{}) *StoredDecision {
+		return &StoredDecision{Pattern: "Bash(git push:*)", Decision: DecisionAllow, Scope: ScopeSession}
+	})
+	in := PrecheckInput{
+		PolicyInput: PolicyInput{ToolName: "Bash", ToolInput: map[string]interface{}{"command": "git push origin main"}, WorkspaceID: "ws-1"},
+		SessionID:   "sess-1",
+	}
+
+	pre := RunPrecheck(in, nil, memory, router)
+	if pre.Decided() || pre.Route.RequiredApprovals != 3 {
+		t.Fatalf("precheck = %+v, want 3 approvals despite the remembered allow", pre)
+	}
+
+	// A remembered deny still applies.
+	deny := memoryFunc(func(string, string, string, map[string]interface{}) *StoredDecision {
+		return &StoredDecision{Pattern: "Bash(git push:*)", Decision: DecisionDeny}
+	})
+	if pre := RunPrecheck(in, nil, deny, router); pre.Stored == nil || pre.Stored.Decision != DecisionDeny {
+		t.Fatalf("precheck = %+v, want the remembered deny", pre)
+	}
+}
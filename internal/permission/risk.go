@@ -0,0 +1,425 @@
+package permission
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/brianly1003/cdev/internal/adapters/repository"
+	"github.com/brianly1003/cdev/internal/domain/events"
+)
+
+// RiskLevel is the risk tier of a permission request.
+type RiskLevel string
+
+const (
+	RiskLow      RiskLevel = "low"
+	RiskMedium   RiskLevel = "medium"
+	RiskHigh     RiskLevel = "high"
+	RiskCritical RiskLevel = "critical"
+)
+
+// riskLevels lists the tiers from lowest to highest.
+var riskLevels = []RiskLevel{RiskLow, RiskMedium, RiskHigh, RiskCritical}
+
+// rank orders risk tiers; unknown tiers rank -1.
+func (l RiskLevel) rank() int {
+	for i, level := range riskLevels {
+		if level == l {
+			return i
+		}
+	}
+	return -1
+}
+
+// AtLeast reports whether l is the same tier as other or a higher one.
+func (l RiskLevel) AtLeast(other RiskLevel) bool {
+	return l.rank() >= other.rank()
+}
+
+// Score thresholds of the risk tiers.
+const (
+	riskMediumScore   = 20
+	riskHighScore     = 45
+	riskCriticalScore = 70
+)
+
+// Risk is the risk assessment of a tool call. Score runs from 0 to 100;
+// Reasons lists what raised it, most significant first.
+type Risk struct {
+	Level   RiskLevel `json:"level"`
+	Score   int       `json:"score"`
+	Reasons []string  `json:"reasons,omitempty"`
+}
+
+// ToEvent converts the assessment for the pty_permission event.
+func (r *Risk) ToEvent() *events.PermissionRisk {
+	if r == nil {
+		return nil
+	}
+	return &events.PermissionRisk{Level: string(r.Level), Score: r.Score, Reasons: r.Reasons}
+}
+
+// Tool base scores. Tools missing here score as toolBaseUnknown.
+var toolBaseScores = map[string]int{
+	"Read":         0,
+	"Glob":         0,
+	"Grep":         0,
+	"LS":           0,
+	"TodoWrite":    0,
+	"WebSearch":    5,
+	"WebFetch":     15,
+	"Edit":         10,
+	"MultiEdit":    10,
+	"apply_patch":  10,
+	"Write":        15,
+	"NotebookEdit": 10,
+	"Bash":         10,
+}
+
+const toolBaseUnknown = 15
+
+// readOnlyTools only read the paths they are given.
+var readOnlyTools = map[string]bool{"Read": true, "Glob": true, "Grep": true, "LS": true}
+
+// Commands that only wrap another command; the wrapped command is assessed.
+var wrapperCommands = map[string]bool{
+	"env": true, "nice": true, "nohup": true, "time": true, "timeout": true,
+	"xargs": true, "command": true, "exec": true, "stdbuf": true,
+}
+
+var privilegedCommands = map[string]bool{"sudo": true, "doas": true, "su": true, "pkexec": true}
+
+var destructiveCommands = map[string]bool{
+	"rm": true, "rmdir": true, "shred": true, "dd": true, "mkfs": true, "truncate": true,
+	"kill": true, "killall": true, "pkill": true, "chmod": true, "chown": true,
+	"shutdown": true, "reboot": true, "unlink": true,
+}
+
+var networkCommands = map[string]bool{
+	"curl": true, "wget": true, "ssh": true, "scp": true, "sftp": true, "rsync": true,
+	"nc": true, "ncat": true, "netcat": true, "telnet": true, "ftp": true,
+}
+
+var shellInterpreters = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "fish": true, "dash": true,
+	"python": true, "python3": true, "node": true, "perl": true, "ruby": true,
+}
+
+// packageInstalls maps package managers to the subcommands that install packages.
+var packageInstalls = map[string][]string{
+	"npm":     {"install", "i", "add", "ci"},
+	"yarn":    {"add", "install"},
+	"pnpm":    {"add", "install", "i"},
+	"bun":     {"add", "install", "i"},
+	"pip":     {"install"},
+	"pip3":    {"install"},
+	"gem":     {"install"},
+	"brew":    {"install", "reinstall"},
+	"apt":     {"install"},
+	"apt-get": {"install"},
+	"dnf":     {"install"},
+	"yum":     {"install"},
+	"pacman":  {"-S"},
+	"cargo":   {"install", "add"},
+	"go":      {"install", "get"},
+}
+
+// Destructive git subcommands, and whether they are destructive only with a force flag.
+var gitDestructive = map[string]bool{"reset": true, "clean": false, "push": true, "branch": true, "checkout": true}
+
+var gitNetwork = map[string]bool{"push": true, "pull": true, "fetch": true, "clone": true}
+
+// riskFactor is one contribution to a risk score.
+type riskFactor struct {
+	score  int
+	reason string
+}
+
+// AssessRisk scores a tool call by its tool, its Bash command (privileges,
+// destructive verbs, network access, package installs), the paths it touches
+// (outside the workspace, dotfiles, secrets) and the size of the change.
+func AssessRisk(in PolicyInput) Risk {
+	return newPolicyCall(in).risk()
+}
+
+// risk assesses the call once and caches the result.
+func (c *policyCall) risk() Risk {
+	if c.assessed == nil {
+		risk := assessRisk(c)
+		c.assessed = &risk
+	}
+	return *c.assessed
+}
+
+func assessRisk(call *policyCall) Risk {
+	in := call.in
+	base, ok := toolBaseScores[in.ToolName]
+	if !ok {
+		base = toolBaseUnknown
+	}
+
+	var factors []riskFactor
+	if in.ToolName == "Bash" {
+		factors = append(factors, assessCommand(call)...)
+	}
+	factors = append(factors, assessPaths(call, readOnlyTools[in.ToolName])...)
+	if lines := changedLines(in.ToolName, in.ToolInput); lines > 100 {
+		score := 10
+		if lines > 500 {
+			score = 20
+		}
+		factors = append(factors, riskFactor{score, fmt.Sprintf("large change (%d lines)", lines)})
+	}
+
+	risk := Risk{Score: base}
+	sortRiskFactors(factors)
+	for _, f := range factors {
+		risk.Score += f.score
+		risk.Reasons = append(risk.Reasons, f.reason)
+	}
+	if risk.Score > 100 {
+		risk.Score = 100
+	}
+	risk.Level = riskLevelForScore(risk.Score)
+	return risk
+}
+
+func riskLevelForScore(score int) RiskLevel {
+	switch {
+	case score >= riskCriticalScore:
+		return RiskCritical
+	case score >= riskHighScore:
+		return RiskHigh
+	case score >= riskMediumScore:
+		return RiskMedium
+	default:
+		return RiskLow
+	}
+}
+
+// sortRiskFactors orders factors by descending score, keeping the order of
+// equal ones. The lists are short, so insertion sort is enough.
+func sortRiskFactors(factors []riskFactor) {
+	for i := 1; i < len(factors); i++ {
+		for j := i; j > 0 && factors[j].score > factors[j-1].score; j-- {
+			factors[j], factors[j-1] = factors[j-1], factors[j]
+		}
+	}
+}
+
+// assessCommand scores the segments of a Bash command. Each kind of factor
+// counts once, however many segments raise it.
+func assessCommand(call *policyCall) []riskFactor {
+	if call.command == "" {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var factors []riskFactor
+	add := func(kind string, score int, reason string) {
+		if !seen[kind] {
+			seen[kind] = true
+			factors = append(factors, riskFactor{score, reason})
+		}
+	}
+
+	if call.unsupported != "" {
+		add("unsupported", 15, "command could not be fully analyzed")
+	}
+
+	network := false
+	for _, seg := range call.segments {
+		args := seg.Args
+		for len(args) > 0 && (wrapperCommands[args[0]] || privilegedCommands[args[0]]) {
+			if privilegedCommands[args[0]] {
+				add("privileged", 40, "runs with elevated privileges ("+args[0]+")")
+			}
+			args = skipOptions(args[1:])
+		}
+		if len(args) == 0 {
+			continue
+		}
+		name := filepath.Base(args[0])
+
+		switch {
+		case destructiveCommands[name] || strings.HasPrefix(name, "mkfs."):
+			score := 30
+			if name == "rm" && hasFlag(args[1:], 'r') && hasFlag(args[1:], 'f') {
+				score = 40
+			}
+			add("destructive", score, "destructive command ("+name+")")
+		case name == "git" && len(args) > 1:
+			sub := args[1]
+			if needsForce, ok := gitDestructive[sub]; ok && (!needsForce || gitForced(sub, args[2:])) {
+				add("destructive", 40, "destructive git command (git "+sub+")")
+			}
+			if gitNetwork[sub] {
+				network = true
+				add("network", 15, "network access (git "+sub+")")
+			}
+		case networkCommands[name]:
+			network = true
+			add("network", 20, "network access ("+name+")")
+		case isPackageInstall(name, args[1:]):
+			add("install", 20, "installs packages ("+name+" "+args[1]+")")
+		}
+
+		if network && shellInterpreters[name] && (seg.Operator == "|" || seg.Operator == "|&") {
+			add("remote-code", 40, "pipes downloaded content into "+name)
+		}
+	}
+	return factors
+}
+
+// skipOptions drops leading options of a wrapper command such as "sudo -u root".
+func skipOptions(args []string) []string {
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		args = args[1:]
+	}
+	return args
+}
+
+// hasFlag reports whether a short flag is set, alone or combined ("-rf").
+func hasFlag(args []string, flag rune) bool {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--") {
+			if (flag == 'r' && arg == "--recursive") || (flag == 'f' && arg == "--force") {
+				return true
+			}
+			continue
+		}
+		if strings.HasPrefix(arg, "-") && strings.ContainsRune(arg[1:], flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// gitForced reports whether a git subcommand runs in its destructive form.
+func gitForced(sub string, args []string) bool {
+	for _, arg := range args {
+		switch {
+		case sub == "reset" && arg == "--hard",
+			sub == "push" && (arg == "--force" || arg == "-f" || strings.HasPrefix(arg, "--force-with-lease") || strings.HasPrefix(arg, "+")),
+			sub == "branch" && (arg == "-D" || (arg == "--delete" && hasFlag(args, 'f'))),
+			sub == "checkout" && arg == ".":
+			return true
+		}
+	}
+	return false
+}
+
+func isPackageInstall(name string, args []string) bool {
+	subcommands, ok := packageInstalls[name]
+	if !ok || len(args) == 0 {
+		return false
+	}
+	for _, sub := range subcommands {
+		if args[0] == sub {
+			return true
+		}
+	}
+	return false
+}
+
+// assessPaths scores the paths a call touches. Reading is scored lower than
+// writing, except for secrets.
+func assessPaths(call *policyCall, readOnly bool) []riskFactor {
+	var factors []riskFactor
+	var outside, dotfile, sensitive string
+	for _, p := range call.paths {
+		rel := riskRelativePath(call.root, p)
+		switch {
+		case sensitive == "" && repository.IsSensitiveFile(filepath.Base(p), rel):
+			sensitive = rel
+		case dotfile == "" && isDotfile(rel):
+			dotfile = rel
+		}
+		if outside == "" && call.root != "" && isOutside(call.root, p) {
+			outside = p
+		}
+	}
+
+	scale := func(write, read int) int {
+		if readOnly {
+			return read
+		}
+		return write
+	}
+	if sensitive != "" {
+		factors = append(factors, riskFactor{scale(45, 30), "touches a secrets file (" + sensitive + ")"})
+	}
+	if outside != "" {
+		factors = append(factors, riskFactor{scale(35, 10), "path outside the workspace (" + outside + ")"})
+	}
+	if dotfile != "" {
+		factors = append(factors, riskFactor{scale(15, 5), "touches a dotfile (" + dotfile + ")"})
+	}
+	return factors
+}
+
+// riskRelativePath returns p relative to the workspace root or, failing that,
+// to the home directory, so patterns such as ".aws/credentials" match.
+func riskRelativePath(root, p string) string {
+	bases := []string{root}
+	if home, err := os.UserHomeDir(); err == nil {
+		bases = append(bases, home)
+	}
+	for _, base := range bases {
+		if base == "" || isOutside(base, p) {
+			continue
+		}
+		if rel, err := filepath.Rel(base, p); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(p)
+}
+
+func isOutside(root, p string) bool {
+	rel, err := filepath.Rel(filepath.Clean(root), p)
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// isDotfile reports whether any element of a path is hidden, e.g. ".bashrc"
+// or ".github/workflows/ci.yml".
+func isDotfile(p string) bool {
+	for _, part := range strings.Split(p, "/") {
+		if strings.HasPrefix(part, ".") && part != "." && part != ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// changedLines estimates the size of the change a file tool makes.
+func changedLines(toolName string, toolInput map[string]interface{}) int {
+	count := func(key string, in map[string]interface{}) int {
+		s, _ := in[key].(string)
+		if s == "" {
+			return 0
+		}
+		return strings.Count(s, "\n") + 1
+	}
+
+	switch toolName {
+	case "Write":
+		return count("content", toolInput)
+	case "Edit":
+		return max(count("old_string", toolInput), count("new_string", toolInput))
+	case "MultiEdit":
+		total := 0
+		edits, _ := toolInput["edits"].([]interface{})
+		for _, e := range edits {
+			if edit, ok := e.(map[string]interface{}); ok {
+				total += max(count("old_string", edit), count("new_string", edit))
+			}
+		}
+		return total
+	case "NotebookEdit":
+		return count("new_source", toolInput)
+	}
+	return 0
+}
@@ -0,0 +1,138 @@
+package permission
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultHighRiskApprovals is the number of approvals a high-risk request
+// needs when the routing config does not say otherwise.
+const defaultHighRiskApprovals = 2
+
+// RoutingConfig controls how permission requests are routed across paired
+// devices. Without routing, every request is broadcast and the first answer wins.
+type RoutingConfig struct {
+	Enabled           bool
+	PrimaryDevices    []string      // Devices asked first, by device ID
+	EscalateAfter     time.Duration // Ask every device when the primaries have not answered by then
+	HighRisk          []string      // Patterns such as "Bash(git push:*)" that need several approvals
+	HighRiskApprovals int           // Approvals from distinct devices for high-risk requests (default 2)
+	TimeoutDecision   Decision      // deny (default) or ask when nobody answers in time
+}
+
+// DeviceLister reports the paired devices that are currently connected.
+type DeviceLister interface {
+	ConnectedDevices() []string
+}
+
+// Route is the routing plan of a single permission request.
+type Route struct {
+	Devices           []string      // Devices notified first; empty means every device
+	EscalateAfter     time.Duration // When to notify every device; 0 means never
+	RequiredApprovals int           // Approvals needed before the request is allowed
+}
+
+// Router applies a RoutingConfig to permission requests. A nil Router, or one
+// that is not enabled, broadcasts every request and needs a single approval.
+type Router struct {
+	mu      sync.RWMutex
+	config  RoutingConfig
+	devices DeviceLister
+}
+
+// NewRouter creates a router for the given config.
+func NewRouter(config RoutingConfig) *Router {
+	if config.HighRiskApprovals < 2 {
+		config.HighRiskApprovals = defaultHighRiskApprovals
+	}
+	if config.TimeoutDecision != DecisionAsk {
+		config.TimeoutDecision = DecisionDeny // Never allow a request nobody answered
+	}
+	return &Router{config: config}
+}
+
+// SetDeviceLister sets the source of connected devices. It is set after
+// construction because the WebSocket server is created later than the
+// permission services.
+func (r *Router) SetDeviceLister(devices DeviceLister) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.devices = devices
+}
+
+// Enabled reports whether routing rules apply.
+func (r *Router) Enabled() bool {
+	return r != nil && r.config.Enabled
+}
+
+// ConnectedDevices returns the connected paired devices, sorted.
+func (r *Router) ConnectedDevices() []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	lister := r.devices
+	r.mu.RUnlock()
+	if lister == nil {
+		return nil
+	}
+
+	devices := append([]string(nil), lister.ConnectedDevices()...)
+	sort.Strings(devices)
+	return devices
+}
+
+// Plan returns the routing plan for a tool call. The primary devices that are
+// connected are asked first; if none is connected, every device is asked.
+func (r *Router) Plan(toolName string, toolInput map[string]interface{}) Route {
+	route := Route{RequiredApprovals: 1}
+	if !r.Enabled() {
+		return route
+	}
+
+	if r.IsHighRisk(toolName, toolInput) {
+		route.RequiredApprovals = r.config.HighRiskApprovals
+	}
+
+	connected := make(map[string]bool)
+	for _, device := range r.ConnectedDevices() {
+		connected[device] = true
+	}
+	for _, device := range r.config.PrimaryDevices {
+		if connected[device] {
+			route.Devices = append(route.Devices, device)
+		}
+	}
+	if len(route.Devices) > 0 {
+		route.EscalateAfter = r.config.EscalateAfter
+	}
+	return route
+}
+
+// IsHighRisk reports whether a tool call matches one of the high-risk
+// patterns. Like a deny pattern, a Bash pattern applies if it matches any
+// segment of a compound command.
+func (r *Router) IsHighRisk(toolName string, toolInput map[string]interface{}) bool {
+	if !r.Enabled() {
+		return false
+	}
+	entries := make([]patternDecision, 0, len(r.config.HighRisk))
+	for _, pattern := range r.config.HighRisk {
+		tool, content, err := ParseRulePattern(pattern)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, patternDecision{Pattern: tool + "(" + content + ")", Decision: DecisionDeny})
+	}
+	return resolveDecision(entries, toolName, toolInput) >= 0
+}
+
+// TimeoutDecision returns the decision for a request nobody answered, and
+// whether routing overrides the caller's default.
+func (r *Router) TimeoutDecision() (Decision, bool) {
+	if !r.Enabled() {
+		return "", false
+	}
+	return r.config.TimeoutDecision, true
+}
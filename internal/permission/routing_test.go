@@ -0,0 +1,96 @@
+package permission
+
+import (
+	"testing"
+	"time"
+)
+
+type staticDevices []string
+
+func (d staticDevices) ConnectedDevices() []string { return d }
+
+func TestRouter_Disabled(t *testing.T) {
+	var nilRouter *Router
+	route := nilRouter.Plan("Bash", map[string]interface{}{"command": "git push"})
+	if len(route.Devices) != 0 || route.EscalateAfter != 0 || route.RequiredApprovals != 1 {
+		t.Errorf("nil router route = %+v, want broadcast with one approval", route)
+	}
+	if _, ok := nilRouter.TimeoutDecision(); ok {
+		t.Error("nil router should not override the timeout decision")
+	}
+
+	r := NewRouter(RoutingConfig{HighRisk: []string{"Bash(git push:*)"}})
+	if r.IsHighRisk("Bash", map[string]interface{}{"command": "git push"}) {
+		t.Error("disabled router should not flag high-risk requests")
+	}
+}
+
+func TestRouter_Plan(t *testing.T) {
+	r := NewRouter(RoutingConfig{
+		Enabled:        true,
+		PrimaryDevices: []string{"phone", "tablet"},
+		EscalateAfter:  30 * time.Second,
+		HighRisk:       []string{"Bash(git push:*)", "Write"},
+	})
+
+	t.Run("no devices connected", func(t *testing.T) {
+		route := r.Plan("Bash", map[string]interface{}{"command": "ls"})
+		if len(route.Devices) != 0 || route.EscalateAfter != 0 {
+			t.Errorf("route = %+v, want broadcast without escalation", route)
+		}
+	})
+
+	r.SetDeviceLister(staticDevices{"laptop", "phone"})
+
+	t.Run("connected primary asked first", func(t *testing.T) {
+		route := r.Plan("Bash", map[string]interface{}{"command": "ls"})
+		if len(route.Devices) != 1 || route.Devices[0] != "phone" {
+			t.Errorf("Devices = %v, want [phone]", route.Devices)
+		}
+		if route.EscalateAfter != 30*time.Second {
+			t.Errorf("EscalateAfter = %v, want 30s", route.EscalateAfter)
+		}
+		if route.RequiredApprovals != 1 {
+			t.Errorf("RequiredApprovals = %d, want 1", route.RequiredApprovals)
+		}
+	})
+
+	t.Run("high risk needs quorum", func(t *testing.T) {
+		route := r.Plan("Bash", map[string]interface{}{"command": "git push origin main"})
+		if route.RequiredApprovals != 2 {
+			t.Errorf("RequiredApprovals = %d, want 2", route.RequiredApprovals)
+		}
+		route = r.Plan("Write", map[string]interface{}{"file_path": "/tmp/x"})
+		if route.RequiredApprovals != 2 {
+			t.Errorf("Write RequiredApprovals = %d, want 2", route.RequiredApprovals)
+		}
+	})
+}
+
+func TestRouter_IsHighRisk_CompoundCommand(t *testing.T) {
+	r := NewRouter(RoutingConfig{Enabled: true, HighRisk: []string{"Bash(git push:*)"}})
+	if !r.IsHighRisk("Bash", map[string]interface{}{"command": "git commit -m x && git push"}) {
+		t.Error("git push inside a compound command should be high risk")
+	}
+	if r.IsHighRisk("Bash", map[string]interface{}{"command": "git status"}) {
+		t.Error("git status should not be high risk")
+	}
+}
+
+func TestRouter_TimeoutDecision(t *testing.T) {
+	tests := []struct {
+		configured Decision
+		want       Decision
+	}{
+		{"", DecisionDeny},
+		{DecisionAllow, DecisionDeny},
+		{DecisionAsk, DecisionAsk},
+	}
+	for _, tt := range tests {
+		r := NewRouter(RoutingConfig{Enabled: true, TimeoutDecision: tt.configured})
+		got, ok := r.TimeoutDecision()
+		if !ok || got != tt.want {
+			t.Errorf("TimeoutDecision(%q) = %q, %v; want %q, true", tt.configured, got, ok, tt.want)
+		}
+	}
+}
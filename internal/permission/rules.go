@@ -0,0 +1,243 @@
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const ruleStoreVersion = 1
+
+// Rule is a persistent permission decision scoped to a workspace.
+// It is the durable counterpart of a session-memory pattern: "Edit(src/*.go)"
+// approved with ScopePath applies to every session in the workspace until it
+// is removed or expires.
+type Rule struct {
+	ID          string     `json:"id"`
+	WorkspaceID string     `json:"workspace_id"`
+	Decision    Decision   `json:"decision"`
+	Tool        string     `json:"tool"`
+	Pattern     string     `json:"pattern"` // Pattern content inside Tool(...); "*" matches any input
+	CreatedBy   string     `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// String returns the rule in pattern form, e.g. "Edit(src/*.go)".
+func (r Rule) String() string {
+	return r.Tool + "(" + r.Pattern + ")"
+}
+
+// Expired reports whether the rule has an expiry that has passed.
+func (r Rule) Expired(now time.Time) bool {
+	return r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)
+}
+
+// Matches reports whether the rule applies to a tool invocation.
+func (r Rule) Matches(toolName string, toolInput map[string]interface{}) bool {
+	return MatchPattern(r.String(), toolName, toolInput)
+}
+
+// ParseRulePattern splits a pattern such as "Edit(src/*.go)" into its tool
+// and pattern content. A bare tool name is treated as "Tool(*)".
+func ParseRulePattern(pattern string) (tool, content string, err error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return "", "", fmt.Errorf("pattern is required")
+	}
+
+	open := strings.Index(pattern, "(")
+	if open < 0 {
+		return pattern, "*", nil
+	}
+	if !strings.HasSuffix(pattern, ")") || open == 0 {
+		return "", "", fmt.Errorf("invalid pattern %q: expected Tool(pattern)", pattern)
+	}
+
+	tool = strings.TrimSpace(pattern[:open])
+	content = strings.TrimSpace(pattern[open+1 : len(pattern)-1])
+	if content == "" {
+		content = "*"
+	}
+	return tool, content, nil
+}
+
+// RuleStore persists workspace permission rules to a JSON file.
+// A store with an empty path is kept in memory only.
+type RuleStore struct {
+	mu   sync.Mutex
+	path string
+
+	Version int    `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+// LoadRuleStore loads permission rules from disk or returns an empty store.
+func LoadRuleStore(path string) (*RuleStore, error) {
+	store := &RuleStore{path: path, Version: ruleStoreVersion}
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return store, err
+	}
+
+	var stored RuleStore
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return store, err
+	}
+	if stored.Version != ruleStoreVersion {
+		// Ignore incompatible versions but keep an empty store.
+		return store, nil
+	}
+	store.Rules = stored.Rules
+	return store, nil
+}
+
+// Add validates and stores a rule. A rule for the same workspace, tool and
+// pattern replaces the existing one, so re-approving updates the decision.
+func (s *RuleStore) Add(rule Rule) (Rule, error) {
+	rule.WorkspaceID = strings.TrimSpace(rule.WorkspaceID)
+	rule.Tool = strings.TrimSpace(rule.Tool)
+	rule.Pattern = strings.TrimSpace(rule.Pattern)
+	if rule.Pattern == "" {
+		rule.Pattern = "*"
+	}
+
+	if rule.WorkspaceID == "" {
+		return Rule{}, fmt.Errorf("workspace_id is required")
+	}
+	if rule.Tool == "" {
+		return Rule{}, fmt.Errorf("tool is required")
+	}
+	if rule.Decision != DecisionAllow && rule.Decision != DecisionDeny {
+		return Rule{}, fmt.Errorf("decision must be 'allow' or 'deny'")
+	}
+
+	now := time.Now().UTC()
+	if rule.Expired(now) {
+		return Rule{}, fmt.Errorf("expires_at must be in the future")
+	}
+	rule.ID = uuid.New().String()
+	rule.CreatedAt = now
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(now)
+	replaced := false
+	for i, existing := range s.Rules {
+		if existing.WorkspaceID == rule.WorkspaceID && existing.Tool == rule.Tool && existing.Pattern == rule.Pattern {
+			s.Rules[i] = rule
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		s.Rules = append(s.Rules, rule)
+	}
+
+	if err := s.saveLocked(); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+// Remove deletes a rule by ID within a workspace.
+// Returns false if no such rule exists.
+func (s *RuleStore) Remove(workspaceID, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, rule := range s.Rules {
+		if rule.ID == id && rule.WorkspaceID == workspaceID {
+			s.Rules = append(s.Rules[:i], s.Rules[i+1:]...)
+			return true, s.saveLocked()
+		}
+	}
+	return false, nil
+}
+
+// List returns the unexpired rules of a workspace, or of all workspaces when
+// workspaceID is empty, ordered by creation time.
+func (s *RuleStore) List(workspaceID string) []Rule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	result := make([]Rule, 0, len(s.Rules))
+	for _, rule := range s.Rules {
+		if rule.Expired(now) {
+			continue
+		}
+		if workspaceID != "" && rule.WorkspaceID != workspaceID {
+			continue
+		}
+		result = append(result, rule)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Match returns the rule of a workspace that applies to a tool invocation.
+// Deny rules take precedence over allow rules. Returns nil if none applies.
+func (s *RuleStore) Match(workspaceID, toolName string, toolInput map[string]interface{}) *Rule {
+	if workspaceID == "" {
+		return nil
+	}
+
+	rules := s.List(workspaceID)
+	entries := make([]patternDecision, len(rules))
+	for i, rule := range rules {
+		entries[i] = patternDecision{Pattern: rule.String(), Decision: rule.Decision}
+	}
+	if idx := resolveDecision(entries, toolName, toolInput); idx >= 0 {
+		return &rules[idx]
+	}
+	return nil
+}
+
+// pruneLocked drops expired rules. Must hold s.mu.
+func (s *RuleStore) pruneLocked(now time.Time) {
+	kept := s.Rules[:0]
+	for _, rule := range s.Rules {
+		if !rule.Expired(now) {
+			kept = append(kept, rule)
+		}
+	}
+	s.Rules = kept
+}
+
+func (s *RuleStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.path)
+}
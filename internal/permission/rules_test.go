@@ -0,0 +1,212 @@
+package permission
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestParseRulePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		tool    string
+		content string
+		wantErr bool
+	}{
+		{pattern: "Edit(src/*.go)", tool: "Edit", content: "src/*.go"},
+		{pattern: "Bash(git status:*)", tool: "Bash", content: "git status:*"},
+		{pattern: "Write", tool: "Write", content: "*"},
+		{pattern: "Read()", tool: "Read", content: "*"},
+		{pattern: "", wantErr: true},
+		{pattern: "(foo)", wantErr: true},
+		{pattern: "Edit(foo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			tool, content, err := ParseRulePattern(tt.pattern)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tt.pattern)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tool != tt.tool || content != tt.content {
+				t.Errorf("got (%q, %q), want (%q, %q)", tool, content, tt.tool, tt.content)
+			}
+		})
+	}
+}
+
+func TestRuleStore_PersistsAndReplaces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "permission_rules.json")
+	store, err := LoadRuleStore(path)
+	if err != nil {
+		t.Fatalf("LoadRuleStore: %v", err)
+	}
+
+	first, err := store.Add(Rule{WorkspaceID: "ws-1", Decision: DecisionAllow, Tool: "Bash", Pattern: "git status:*", CreatedBy: "device-1"})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if first.ID == "" || first.CreatedAt.IsZero() {
+		t.Fatalf("expected ID and CreatedAt to be set, got %+v", first)
+	}
+
+	// Same workspace, tool and pattern replaces the decision.
+	if _, err := store.Add(Rule{WorkspaceID: "ws-1", Decision: DecisionDeny, Tool: "Bash", Pattern: "git status:*"}); err != nil {
+		t.Fatalf("Add replacement: %v", err)
+	}
+	if _, err := store.Add(Rule{WorkspaceID: "ws-2", Decision: DecisionAllow, Tool: "Write"}); err != nil {
+		t.Fatalf("Add ws-2: %v", err)
+	}
+
+	reloaded, err := LoadRuleStore(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	rules := reloaded.List("ws-1")
+	if len(rules) != 1 {
+		t.Fatalf("expected 1 rule for ws-1, got %d", len(rules))
+	}
+	if rules[0].Decision != DecisionDeny {
+		t.Errorf("expected replaced decision deny, got %s", rules[0].Decision)
+	}
+	if all := reloaded.List(""); len(all) != 2 {
+		t.Errorf("expected 2 rules in total, got %d", len(all))
+	}
+	if got := reloaded.List("ws-2")[0].Pattern; got != "*" {
+		t.Errorf("expected bare tool rule to match everything, got pattern %q", got)
+	}
+
+	removed, err := reloaded.Remove("ws-1", rules[0].ID)
+	if err != nil || !removed {
+		t.Fatalf("Remove = %v, %v", removed, err)
+	}
+	if removed, _ := reloaded.Remove("ws-1", rules[0].ID); removed {
+		t.Error("expected second remove to report false")
+	}
+}
+
+func TestRuleStore_Validation(t *testing.T) {
+	store := &RuleStore{}
+	past := time.Now().Add(-time.Minute)
+
+	invalid := []Rule{
+		{Decision: DecisionAllow, Tool: "Bash"},
+		{WorkspaceID: "ws-1", Decision: DecisionAllow},
+		{WorkspaceID: "ws-1", Decision: "maybe", Tool: "Bash"},
+		{WorkspaceID: "ws-1", Decision: DecisionAllow, Tool: "Bash", ExpiresAt: &past},
+	}
+	for _, rule := range invalid {
+		if _, err := store.Add(rule); err == nil {
+			t.Errorf("expected error for %+v", rule)
+		}
+	}
+}
+
+func TestRuleStore_ExpiredRulesDoNotMatch(t *testing.T) {
+	store := &RuleStore{}
+	expiresAt := time.Now().Add(50 * time.Millisecond)
+	if _, err := store.Add(Rule{WorkspaceID: "ws-1", Decision: DecisionAllow, Tool: "Bash", ExpiresAt: &expiresAt}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	input := map[string]interface{}{"command": "ls"}
+	if store.Match("ws-1", "Bash", input) == nil {
+		t.Fatal("expected rule to match before expiry")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if store.Match("ws-1", "Bash", input) != nil {
+		t.Error("expected expired rule not to match")
+	}
+	if len(store.List("ws-1")) != 0 {
+		t.Error("expected expired rule to be hidden from List")
+	}
+}
+
+func TestRuleStore_DenyTakesPrecedence(t *testing.T) {
+	store := &RuleStore{}
+	mustAdd := func(rule Rule) {
+		if _, err := store.Add(rule); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	mustAdd(Rule{WorkspaceID: "ws-1", Decision: DecisionAllow, Tool: "Edit", Pattern: "/repo/*"})
+	mustAdd(Rule{WorkspaceID: "ws-1", Decision: DecisionDeny, Tool: "Edit", Pattern: "/repo/secrets/*"})
+
+	rule := store.Match("ws-1", "Edit", map[string]interface{}{"file_path": "/repo/secrets/key.pem"})
+	if rule == nil || rule.Decision != DecisionDeny {
+		t.Fatalf("expected deny rule, got %+v", rule)
+	}
+	rule = store.Match("ws-1", "Edit", map[string]interface{}{"file_path": "/repo/main.go"})
+	if rule == nil || rule.Decision != DecisionAllow {
+		t.Fatalf("expected allow rule, got %+v", rule)
+	}
+	if store.Match("ws-2", "Edit", map[string]interface{}{"file_path": "/repo/main.go"}) != nil {
+		t.Error("expected rules not to apply to other workspaces")
+	}
+}
+
+func TestMemoryManager_CheckMemoryConsultsRules(t *testing.T) {
+	m := NewMemoryManager(SessionMemoryConfig{Enabled: true, TTL: time.Hour, MaxPatterns: 100}, zerolog.Nop())
+
+	if _, err := m.AddRule(Rule{WorkspaceID: "ws-1", Decision: DecisionAllow, Tool: "Bash", Pattern: "go test:*"}); err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	if _, err := m.AddRule(Rule{WorkspaceID: "ws-1", Decision: DecisionDeny, Tool: "Bash", Pattern: "rm:*"}); err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+
+	// Allow rule applies to any session in the workspace.
+	stored := m.CheckMemory("sess-new", "ws-1", "Bash", map[string]interface{}{"command": "go test ./..."})
+	if stored == nil || stored.Decision != DecisionAllow || stored.EffectiveScope() != ScopePath || stored.RuleID == "" {
+		t.Fatalf("expected path-scoped allow from rule, got %+v", stored)
+	}
+
+	// Session decisions win over allow rules...
+	m.StoreDecision("sess-1", "ws-1", "Bash(go:*)", DecisionDeny)
+	stored = m.CheckMemory("sess-1", "ws-1", "Bash", map[string]interface{}{"command": "go test ./..."})
+	if stored == nil || stored.Decision != DecisionDeny || stored.EffectiveScope() != ScopeSession {
+		t.Fatalf("expected session decision, got %+v", stored)
+	}
+
+	// ...but not over deny rules.
+	m.StoreDecision("sess-1", "ws-1", "Bash(rm:*)", DecisionAllow)
+	stored = m.CheckMemory("sess-1", "ws-1", "Bash", map[string]interface{}{"command": "rm -rf build"})
+	if stored == nil || stored.Decision != DecisionDeny || stored.EffectiveScope() != ScopePath {
+		t.Fatalf("expected deny rule to win, got %+v", stored)
+	}
+
+	// Without a workspace only session memory is consulted.
+	if stored := m.CheckMemory("sess-new", "", "Bash", map[string]interface{}{"command": "go test ./..."}); stored != nil {
+		t.Fatalf("expected no decision without workspace, got %+v", stored)
+	}
+}
+
+func TestMemoryManager_RespondToRequestStoresPathRule(t *testing.T) {
+	m := NewMemoryManager(SessionMemoryConfig{Enabled: true, TTL: time.Hour, MaxPatterns: 100}, zerolog.Nop())
+
+	req := &Request{
+		ToolUseID:    "tool-1",
+		SessionID:    "sess-1",
+		WorkspaceID:  "ws-1",
+		ToolName:     "Write",
+		ToolInput:    map[string]interface{}{"file_path": "/repo/main.go"},
+		ResponseChan: make(chan *Response, 1),
+	}
+	m.AddPendingRequest(req)
+	if !m.RespondToRequest("tool-1", &Response{Decision: DecisionAllow, Scope: ScopePath, Pattern: "Write(*.go)", RespondedBy: "iphone"}) {
+		t.Fatal("expected response to be delivered")
+	}
+
+	rules := m.ListRules("ws-1")
+	if len(rules) != 1 || rules[0].String() != "Write(*.go)" || rules[0].CreatedBy != "iphone" {
+		t.Fatalf("expected Write(*.go) rule created by iphone, got %+v", rules)
+	}
+}
@@ -0,0 +1,538 @@
+package permission
+
+import (
+	"strings"
+)
+
+// maxShellNesting bounds recursion into command substitutions and subshells.
+const maxShellNesting = 8
+
+// ShellCommand is a Bash command split into the simple commands it runs.
+//
+// The parser understands quoting, escapes, control operators (&&, ||, ;, |,
+// |&, &, newlines), subshells and brace groups, redirections, here-documents
+// and command/process substitution. It does not evaluate anything: variables
+// and globs are kept as written. Constructs it cannot reason about (loops,
+// case statements, function definitions, unbalanced quotes) set Unsupported;
+// segments are still returned on a best-effort basis.
+type ShellCommand struct {
+	Segments      []ShellSegment
+	Substitutions []string // Raw text of $(...), `...`, <(...) and >(...)
+	Unsupported   string   // Reason the command could not be fully parsed
+}
+
+// ShellSegment is one simple command of a compound command.
+type ShellSegment struct {
+	Operator     string   // Operator joining it to the previous segment: "", "&&", "||", ";", "|", "|&", "&"
+	Args         []string // Words after quote removal, without leading assignments and keywords
+	Redirections []ShellRedirection
+	Subshell     bool // Runs inside ( ... )
+	Substituted  bool // Runs inside a command or process substitution
+}
+
+// ShellRedirection is a redirection attached to a segment, e.g. "2>" "err.log".
+type ShellRedirection struct {
+	Op     string
+	Target string
+}
+
+// Text returns the segment's command line as a single string.
+func (s ShellSegment) Text() string {
+	return strings.Join(s.Args, " ")
+}
+
+// WritesFile reports whether any redirection of the segment writes to a file.
+func (s ShellSegment) WritesFile() bool {
+	for _, r := range s.Redirections {
+		if r.WritesFile() {
+			return true
+		}
+	}
+	return false
+}
+
+// WritesFile reports whether the redirection writes to a file. Duplicating a
+// descriptor (2>&1) and discarding output (>/dev/null) do not count.
+func (r ShellRedirection) WritesFile() bool {
+	if !strings.Contains(r.Op, ">") {
+		return false
+	}
+	if strings.HasSuffix(r.Op, "&") && r.Op != "&>" {
+		return false // >&2, 2>&1, >&-
+	}
+	return r.Target != "/dev/null"
+}
+
+// Compound reports whether the command runs more than one simple command.
+func (c ShellCommand) Compound() bool {
+	return len(c.Segments) > 1
+}
+
+// WritesFiles reports whether any segment redirects output to a file.
+func (c ShellCommand) WritesFiles() bool {
+	for _, seg := range c.Segments {
+		if seg.WritesFile() {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseShellCommand splits a Bash command into its segments.
+func ParseShellCommand(command string) ShellCommand {
+	var cmd ShellCommand
+	p := &shellParser{src: []rune(command), out: &cmd}
+	p.parse(false, 0)
+	return cmd
+}
+
+// shellKeywords are stripped from the start of a segment so that the command
+// they introduce is matched, e.g. "if git diff --quiet" matches "git diff".
+var shellKeywords = map[string]bool{
+	"!": true, "{": true, "}": true, "if": true, "then": true, "elif": true,
+	"else": true, "fi": true, "while": true, "until": true, "do": true,
+	"done": true, "time": true,
+}
+
+// unsupportedKeywords introduce constructs whose words are not commands.
+var unsupportedKeywords = map[string]bool{
+	"for": true, "case": true, "esac": true, "select": true, "function": true,
+	"coproc": true, "[[": true, "]]": true,
+}
+
+type shellParser struct {
+	src []rune
+	pos int
+	out *ShellCommand
+
+	args         []string
+	redirections []ShellRedirection
+	word         strings.Builder
+	inWord       bool
+	pendingRedir string
+	operator     string
+	subshell     int
+	heredocs     []string
+}
+
+func (p *shellParser) unsupported(reason string) {
+	if p.out.Unsupported == "" {
+		p.out.Unsupported = reason
+	}
+}
+
+func (p *shellParser) peek(offset int) rune {
+	if p.pos+offset < len(p.src) {
+		return p.src[p.pos+offset]
+	}
+	return 0
+}
+
+func (p *shellParser) hasPrefix(s string) bool {
+	for i, r := range []rune(s) {
+		if p.peek(i) != r {
+			return false
+		}
+	}
+	return true
+}
+
+// parse consumes the source, appending segments to p.out.
+func (p *shellParser) parse(substituted bool, depth int) {
+	if depth > maxShellNesting {
+		p.unsupported("nesting too deep")
+		return
+	}
+
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		switch {
+		case r == ' ' || r == '\t':
+			p.endWord()
+			p.pos++
+		case r == '\n':
+			p.endSegment(substituted, ";")
+			p.pos++
+			p.skipHeredocBodies()
+		case r == '#' && !p.inWord:
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case r == '\\':
+			if p.peek(1) == '\n' {
+				p.pos += 2
+				continue
+			}
+			p.inWord = true
+			if p.pos+1 < len(p.src) {
+				p.word.WriteRune(p.src[p.pos+1])
+			}
+			p.pos += 2
+		case r == '\'':
+			p.readSingleQuoted()
+		case r == '"':
+			p.readDoubleQuoted(depth)
+		case r == '`':
+			p.word.WriteString(p.readBackticks(depth))
+			p.inWord = true
+		case r == '$' && p.peek(1) == '(':
+			p.word.WriteString(p.readDollarParen(depth))
+			p.inWord = true
+		case r == '$' && p.peek(1) == '{':
+			p.word.WriteString(p.readExpansion('{', '}', depth))
+			p.inWord = true
+		case (r == '<' || r == '>') && p.peek(1) == '(' && !p.inWord:
+			p.word.WriteString(p.readSubstitution(2, depth))
+			p.inWord = true
+		case r == '&' && p.peek(1) == '&':
+			p.endSegment(substituted, "&&")
+			p.pos += 2
+		case r == '|' && p.peek(1) == '|':
+			p.endSegment(substituted, "||")
+			p.pos += 2
+		case r == '|' && p.peek(1) == '&':
+			p.endSegment(substituted, "|&")
+			p.pos += 2
+		case r == '|':
+			p.endSegment(substituted, "|")
+			p.pos++
+		case r == ';':
+			p.endSegment(substituted, ";")
+			for p.pos < len(p.src) && p.src[p.pos] == ';' {
+				p.pos++
+			}
+		case r == '&' && p.peek(1) != '>':
+			p.endSegment(substituted, "&")
+			p.pos++
+		case r == '(':
+			p.endWord()
+			if len(p.args) > 0 || p.inWord {
+				p.unsupported("unexpected '('")
+			}
+			p.subshell++
+			p.pos++
+		case r == ')':
+			p.endSegment(substituted, "")
+			if p.subshell > 0 {
+				p.subshell--
+			} else {
+				p.unsupported("unbalanced parentheses")
+			}
+			p.pos++
+		case r == '<' || r == '>' || (r == '&' && p.peek(1) == '>'):
+			p.readRedirection()
+		default:
+			p.word.WriteRune(r)
+			p.inWord = true
+			p.pos++
+		}
+	}
+	p.endSegment(substituted, "")
+	if p.subshell > 0 {
+		p.unsupported("unbalanced parentheses")
+	}
+}
+
+func (p *shellParser) endWord() {
+	if !p.inWord {
+		return
+	}
+	word := p.word.String()
+	p.word.Reset()
+	p.inWord = false
+
+	if p.pendingRedir != "" {
+		p.redirections = append(p.redirections, ShellRedirection{Op: p.pendingRedir, Target: word})
+		if strings.HasPrefix(p.pendingRedir, "<<") && p.pendingRedir != "<<<" {
+			p.heredocs = append(p.heredocs, word)
+		}
+		p.pendingRedir = ""
+		return
+	}
+	p.args = append(p.args, word)
+}
+
+// endSegment closes the current simple command. next is the operator that
+// ended it, which joins the following segment.
+func (p *shellParser) endSegment(substituted bool, next string) {
+	p.endWord()
+	if p.pendingRedir != "" {
+		p.unsupported("redirection without target")
+		p.pendingRedir = ""
+	}
+
+	args := p.args
+	for len(args) > 0 {
+		if unsupportedKeywords[args[0]] {
+			p.unsupported(args[0] + " statements are not supported")
+			break
+		}
+		if shellKeywords[args[0]] || isShellAssignment(args[0]) {
+			args = args[1:]
+			continue
+		}
+		break
+	}
+	for len(args) > 0 && shellKeywords[args[len(args)-1]] && args[len(args)-1] == "}" {
+		args = args[:len(args)-1]
+	}
+
+	if len(args) > 0 || len(p.redirections) > 0 {
+		p.out.Segments = append(p.out.Segments, ShellSegment{
+			Operator:     p.operator,
+			Args:         args,
+			Redirections: p.redirections,
+			Subshell:     p.subshell > 0,
+			Substituted:  substituted,
+		})
+		p.operator = next
+	} else if next != "" && (next != ";" || p.operator == "") && len(p.out.Segments) > 0 {
+		// An operator after a subshell, or a newline continuing "a &&".
+		p.operator = next
+	}
+	p.args = nil
+	p.redirections = nil
+}
+
+func (p *shellParser) readSingleQuoted() {
+	p.inWord = true
+	p.pos++
+	for p.pos < len(p.src) && p.src[p.pos] != '\'' {
+		p.word.WriteRune(p.src[p.pos])
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		p.unsupported("unterminated quote")
+	}
+	p.pos++
+}
+
+func (p *shellParser) readDoubleQuoted(depth int) {
+	p.inWord = true
+	p.pos++
+	for p.pos < len(p.src) && p.src[p.pos] != '"' {
+		r := p.src[p.pos]
+		switch {
+		case r == '\\' && strings.ContainsRune("\"\\$`\n", p.peek(1)):
+			if p.peek(1) != '\n' {
+				p.word.WriteRune(p.peek(1))
+			}
+			p.pos += 2
+		case r == '`':
+			p.word.WriteString(p.readBackticks(depth))
+		case r == '$' && p.peek(1) == '(':
+			p.word.WriteString(p.readDollarParen(depth))
+		case r == '$' && p.peek(1) == '{':
+			p.word.WriteString(p.readExpansion('{', '}', depth))
+		default:
+			p.word.WriteRune(r)
+			p.pos++
+		}
+	}
+	if p.pos >= len(p.src) {
+		p.unsupported("unterminated quote")
+	}
+	p.pos++
+}
+
+// readDollarParen reads $(...) or arithmetic $((...)) at the current position.
+func (p *shellParser) readDollarParen(depth int) string {
+	if p.peek(2) == '(' {
+		return p.readExpansion('(', ')', depth)
+	}
+	return p.readSubstitution(2, depth)
+}
+
+// readSubstitution reads a parenthesised substitution whose body starts
+// prefixLen runes after the current position, and parses the body.
+func (p *shellParser) readSubstitution(prefixLen, depth int) string {
+	start := p.pos
+	p.pos += prefixLen
+	bodyStart := p.pos
+	level := 1
+	var quote rune
+	for p.pos < len(p.src) && level > 0 {
+		r := p.src[p.pos]
+		switch {
+		case quote != 0:
+			if r == '\\' && quote == '"' {
+				p.pos++
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\\':
+			p.pos++
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			level++
+		case r == ')':
+			level--
+		}
+		p.pos++
+	}
+	if level > 0 {
+		p.unsupported("unterminated substitution")
+		p.subParse(string(p.src[bodyStart:]), depth)
+		return string(p.src[start:])
+	}
+
+	raw := string(p.src[start:p.pos])
+	p.subParse(string(p.src[bodyStart:p.pos-1]), depth)
+	return raw
+}
+
+func (p *shellParser) readBackticks(depth int) string {
+	start := p.pos
+	p.pos++
+	var body strings.Builder
+	for p.pos < len(p.src) && p.src[p.pos] != '`' {
+		if p.src[p.pos] == '\\' && p.pos+1 < len(p.src) {
+			p.pos++
+		}
+		body.WriteRune(p.src[p.pos])
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		p.unsupported("unterminated substitution")
+	} else {
+		p.pos++
+	}
+	p.subParse(body.String(), depth)
+	return string(p.src[start:p.pos])
+}
+
+// readExpansion reads a balanced expansion such as ${VAR:-default} or
+// $((1+2)). The expansion itself is kept as written, but command
+// substitutions inside it run and are parsed like any other.
+func (p *shellParser) readExpansion(open, close rune, depth int) string {
+	start := p.pos
+	level := 0
+	inDouble := false
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		switch {
+		case r == '\\':
+			p.pos += 2
+			continue
+		case r == '\'' && !inDouble:
+			p.pos++
+			for p.pos < len(p.src) && p.src[p.pos] != '\'' {
+				p.pos++
+			}
+		case r == '"':
+			inDouble = !inDouble
+		case r == '`':
+			p.readBackticks(depth)
+			continue
+		case r == '$' && p.peek(1) == '(' && p.peek(2) != '(':
+			p.readSubstitution(2, depth)
+			continue
+		case inDouble:
+		case r == open:
+			level++
+		case r == close:
+			level--
+			if level == 0 {
+				p.pos++
+				return string(p.src[start:p.pos])
+			}
+		}
+		p.pos++
+	}
+	p.unsupported("unterminated expansion")
+	p.pos = len(p.src)
+	return string(p.src[start:])
+}
+
+func (p *shellParser) subParse(body string, depth int) {
+	p.out.Substitutions = append(p.out.Substitutions, strings.TrimSpace(body))
+	sub := &shellParser{src: []rune(body), out: p.out}
+	sub.parse(true, depth+1)
+}
+
+// readRedirection reads a redirection operator, including a numeric file
+// descriptor already collected as the current word (2>, 1>>).
+func (p *shellParser) readRedirection() {
+	fd := ""
+	if p.inWord && isDigits(p.word.String()) {
+		fd = p.word.String()
+		p.word.Reset()
+		p.inWord = false
+	} else {
+		p.endWord()
+	}
+
+	var op string
+	for _, candidate := range []string{"&>>", "&>", "<<<", "<<-", "<<", "<&", "<>", ">>", ">|", ">&", "<", ">"} {
+		if p.hasPrefix(candidate) {
+			op = candidate
+			break
+		}
+	}
+	p.pos += len([]rune(op))
+
+	if op == ">&" || op == "<&" {
+		// Descriptor duplication: the target is a number or "-".
+		start := p.pos
+		for p.pos < len(p.src) && (isDigits(string(p.src[p.pos])) || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		if p.pos > start {
+			p.redirections = append(p.redirections, ShellRedirection{Op: fd + op, Target: string(p.src[start:p.pos])})
+			return
+		}
+		if op == ">&" && fd == "" {
+			op = "&>" // ">& file" is the same as "&> file"
+		}
+	}
+	p.pendingRedir = fd + op
+}
+
+// skipHeredocBodies skips here-document bodies that start after a newline.
+func (p *shellParser) skipHeredocBodies() {
+	for len(p.heredocs) > 0 {
+		delim := p.heredocs[0]
+		p.heredocs = p.heredocs[1:]
+		for p.pos < len(p.src) {
+			end := p.pos
+			for end < len(p.src) && p.src[end] != '\n' {
+				end++
+			}
+			line := strings.TrimLeft(string(p.src[p.pos:end]), "\t")
+			p.pos = end + 1
+			if line == delim {
+				break
+			}
+		}
+	}
+	if p.pos > len(p.src) {
+		p.pos = len(p.src)
+	}
+}
+
+func isShellAssignment(word string) bool {
+	eq := strings.IndexByte(word, '=')
+	if eq <= 0 {
+		return false
+	}
+	for i, r := range word[:eq] {
+		if r == '_' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+package permission
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func segmentTexts(cmd ShellCommand) []string {
+	texts := make([]string, 0, len(cmd.Segments))
+	for _, seg := range cmd.Segments {
+		texts = append(texts, seg.Operator+"|"+seg.Text())
+	}
+	return texts
+}
+
+func TestParseShellCommand_Segments(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+	}{
+		{name: "simple", command: "git status", want: []string{"|git status"}},
+		{name: "and", command: "git status && rm -rf build", want: []string{"|git status", "&&|rm -rf build"}},
+		{name: "or and sequence", command: "make || echo failed; ls", want: []string{"|make", "|||echo failed", ";|ls"}},
+		{name: "pipeline", command: "cat go.mod | grep module |& tee", want: []string{"|cat go.mod", "||grep module", "|&|tee"}},
+		{name: "background", command: "sleep 1 & wait", want: []string{"|sleep 1", "&|wait"}},
+		{name: "newline", command: "go build ./...\ngo test ./...", want: []string{"|go build ./...", ";|go test ./..."}},
+		{name: "quoted operators", command: `echo "a && b" 'c; d' e\;f`, want: []string{"|echo a && b c; d e;f"}},
+		{name: "assignments", command: "FOO=1 BAR=2 go test ./...", want: []string{"|go test ./..."}},
+		{name: "keywords", command: "if git diff --quiet; then echo clean; fi", want: []string{"|git diff --quiet", ";|echo clean"}},
+		{name: "subshell", command: "(cd web && npm test) && ls", want: []string{"|cd web", "&&|npm test", "&&|ls"}},
+		{name: "brace group", command: "{ ls; pwd; }", want: []string{"|ls", ";|pwd"}},
+		{name: "comment", command: "ls # && rm -rf /", want: []string{"|ls"}},
+		{name: "line continuation", command: "go test \\\n  ./...", want: []string{"|go test ./..."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := ParseShellCommand(tt.command)
+			if parsed.Unsupported != "" {
+				t.Fatalf("unexpected unsupported: %s", parsed.Unsupported)
+			}
+			if got := segmentTexts(parsed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("segments = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseShellCommand_Substitutions(t *testing.T) {
+	parsed := ParseShellCommand(`echo "$(rm -rf build)" ` + "`whoami`" + ` <(ls)`)
+	if len(parsed.Substitutions) != 3 {
+		t.Fatalf("substitutions = %q, want 3", parsed.Substitutions)
+	}
+
+	var substituted []string
+	for _, seg := range parsed.Segments {
+		if seg.Substituted {
+			substituted = append(substituted, seg.Text())
+		}
+	}
+	want := []string{"rm -rf build", "whoami", "ls"}
+	if !reflect.DeepEqual(substituted, want) {
+		t.Errorf("substituted segments = %q, want %q", substituted, want)
+	}
+
+	// Arithmetic expansion is not a command.
+	if parsed := ParseShellCommand("echo $((1 + 2))"); len(parsed.Segments) != 1 || len(parsed.Substitutions) != 0 {
+		t.Errorf("arithmetic parsed as %+v", parsed)
+	}
+}
+
+func TestParseShellCommand_SubstitutionInExpansion(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string // Substituted segment
+	}{
+		{command: "git status ${X:-$(rm -rf /)}", want: "rm -rf /"},
+		{command: "git status ${X:-`rm -rf /`}", want: "rm -rf /"},
+		{command: `git status "${X:-$(rm -rf /)}"`, want: "rm -rf /"},
+		{command: "git status ${X:-${Y:-$(rm -rf /)}}", want: "rm -rf /"},
+		{command: "git status ${X:-\"}\"$(rm -rf /)}", want: "rm -rf /"},
+		{command: "echo $(( $(rm -rf /) + 1 ))", want: "rm -rf /"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			parsed := ParseShellCommand(tt.command)
+			var substituted []string
+			for _, seg := range parsed.Segments {
+				if seg.Substituted {
+					substituted = append(substituted, seg.Text())
+				}
+			}
+			if !reflect.DeepEqual(substituted, []string{tt.want}) {
+				t.Errorf("substituted segments = %q, want %q", substituted, tt.want)
+			}
+		})
+	}
+
+	// Plain parameter expansion is still a single word.
+	parsed := ParseShellCommand("echo ${HOME:-/tmp}/x")
+	if got := segmentTexts(parsed); !reflect.DeepEqual(got, []string{"|echo ${HOME:-/tmp}/x"}) || len(parsed.Substitutions) != 0 {
+		t.Errorf("segments = %q, substitutions = %q", got, parsed.Substitutions)
+	}
+}
+
+func TestParseShellCommand_Redirections(t *testing.T) {
+	tests := []struct {
+		command string
+		writes  bool
+	}{
+		{command: "go test ./... > out.txt", writes: true},
+		{command: "go test ./... >> out.txt", writes: true},
+		{command: "go test ./... 2>err.log", writes: true},
+		{command: "go test ./... &> all.log", writes: true},
+		{command: "go test ./... 2>&1", writes: false},
+		{command: "go test ./... >/dev/null 2>&1", writes: false},
+		{command: "echo hi >&2", writes: false},
+		{command: "sort < input.txt", writes: false},
+		{command: "cat <<< hello", writes: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			parsed := ParseShellCommand(tt.command)
+			if parsed.WritesFiles() != tt.writes {
+				t.Errorf("WritesFiles() = %v, want %v (%+v)", parsed.WritesFiles(), tt.writes, parsed.Segments)
+			}
+			if len(parsed.Segments) != 1 {
+				t.Errorf("expected 1 segment, got %q", segmentTexts(parsed))
+			}
+		})
+	}
+}
+
+func TestParseShellCommand_Heredoc(t *testing.T) {
+	parsed := ParseShellCommand("cat <<'EOF' > notes.md\nrm -rf build\nEOF\nls")
+	if got, want := segmentTexts(parsed), []string{"|cat", ";|ls"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("segments = %q, want %q", got, want)
+	}
+	if !parsed.Segments[0].WritesFile() {
+		t.Error("expected heredoc segment to write notes.md")
+	}
+}
+
+func TestParseShellCommand_Unsupported(t *testing.T) {
+	for _, command := range []string{
+		"for f in *.go; do gofmt -l $f; done",
+		`echo "unterminated`,
+		"(ls",
+		"case $x in a) ls;; esac",
+	} {
+		if parsed := ParseShellCommand(command); parsed.Unsupported == "" {
+			t.Errorf("expected %q to be unsupported, got %q", command, segmentTexts(parsed))
+		}
+	}
+}
+
+func TestMatchPattern_CompoundBash(t *testing.T) {
+	tests := []struct {
+		pattern string
+		command string
+		want    bool
+	}{
+		{pattern: "Bash(git status:*)", command: "git status", want: true},
+		{pattern: "Bash(git status:*)", command: "git status --short", want: true},
+		{pattern: "Bash(git status:*)", command: "git status && rm -rf build", want: false},
+		{pattern: "Bash(git status:*)", command: "git status; git status -s", want: true},
+		{pattern: "Bash(git status:*)", command: "git status $(rm -rf build)", want: false},
+		{pattern: "Bash(git status:*)", command: "git status ${X:-$(rm -rf /)}", want: false},
+		{pattern: "Bash(git status:*)", command: "git status ${X:-`rm -rf /`}", want: false},
+		{pattern: "Bash(echo:*)", command: "echo hi > ~/.bashrc", want: false},
+		{pattern: "Bash(go test:*)", command: "go test ./... 2>&1", want: true},
+		{pattern: "Bash(go test:*)", command: "for p in a b; do go test $p; done", want: false},
+		{pattern: "Bash(git status && rm -rf build)", command: "git status && rm -rf build", want: true},
+		{pattern: "Bash(*)", command: "anything | goes", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.command, func(t *testing.T) {
+			got := MatchPattern(tt.pattern, "Bash", map[string]interface{}{"command": tt.command})
+			if got != tt.want {
+				t.Errorf("MatchPattern() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratePattern_CompoundBash(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{command: "git status", want: "Bash(git status:*)"},
+		{command: "git status; git status -s", want: "Bash(git status:*)"},
+		{command: "git status && rm -rf build", want: "Bash(git status && rm -rf build)"},
+		{command: "git status ${X:-$(rm -rf /)}", want: "Bash(git status ${X:-$(rm -rf /)})"},
+		{command: "echo hi > out.txt", want: "Bash(echo hi > out.txt)"},
+		{command: "CGO_ENABLED=0 go build ./...", want: "Bash(go build:*)"},
+		{command: "", want: "Bash(*)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got := GeneratePattern("Bash", map[string]interface{}{"command": tt.command})
+			if got != tt.want {
+				t.Errorf("GeneratePattern() = %q, want %q", got, tt.want)
+			}
+			if tt.command != "" && !MatchPattern(got, "Bash", map[string]interface{}{"command": tt.command}) {
+				t.Errorf("generated pattern %q does not match its own command", got)
+			}
+		})
+	}
+}
+
+func TestResolveDecision_CompoundBash(t *testing.T) {
+	entries := []patternDecision{
+		{Pattern: "Bash(git status:*)", Decision: DecisionAllow},
+		{Pattern: "Bash(head:*)", Decision: DecisionAllow},
+		{Pattern: "Bash(rm:*)", Decision: DecisionDeny},
+	}
+	resolve := func(command string) int {
+		return resolveDecision(entries, "Bash", map[string]interface{}{"command": command})
+	}
+
+	if idx := resolve("git status | head -5"); idx != 0 {
+		t.Errorf("expected segments covered by allow patterns (idx 0), got %d", idx)
+	}
+	if idx := resolve("git status && rm -rf build"); idx != 2 {
+		t.Errorf("expected deny for rm segment (idx 2), got %d", idx)
+	}
+	if idx := resolve("echo $(rm -rf build)"); idx != 2 {
+		t.Errorf("expected deny for substituted rm (idx 2), got %d", idx)
+	}
+	if idx := resolve("git status && curl example.com"); idx != -1 {
+		t.Errorf("expected no decision for uncovered segment, got %d", idx)
+	}
+	if idx := resolve("git status > status.txt"); idx != -1 {
+		t.Errorf("expected no decision for redirected output, got %d", idx)
+	}
+}
+
+func TestExtractPreview_Bash(t *testing.T) {
+	if preview := ExtractPreview("Bash", map[string]interface{}{"command": "git status"}); preview != "" {
+		t.Errorf("expected empty preview for a simple command, got %q", preview)
+	}
+
+	preview := ExtractPreview("Bash", map[string]interface{}{"command": "git status && echo $(date) > out.txt"})
+	for _, want := range []string{"Runs 3 commands:", "git status", "&& echo $(date)", "date  [substitution]", "Writes to: out.txt", "Uses command substitution"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("preview missing %q:\n%s", want, preview)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package permission
+
+import (
+	"sort"
+	"time"
+)
+
+// Sources of a simulated decision.
+const (
+	SimulationSourcePolicy = "policy" // Decided by a permission policy
+	SimulationSourceRule   = "rule"   // Decided by a persistent workspace rule
+	SimulationSourcePrompt = "prompt" // Nothing matched; a device would be asked
+)
+
+// PolicyChecker evaluates permission policies for a tool call.
+type PolicyChecker interface {
+	Evaluate(in PolicyInput) *PolicyDecision
+}
+
+// SimulatedCall is a tool call from session history replayed through the
+// permission engine.
+type SimulatedCall struct {
+	WorkspaceID   string                 `json:"workspace_id"`
+	WorkspacePath string                 `json:"-"`
+	SessionID     string                 `json:"session_id"`
+	AgentType     string                 `json:"agent_type"` // claude or codex
+	ToolUseID     string                 `json:"tool_use_id,omitempty"`
+	ToolName      string                 `json:"tool_name"`
+	ToolInput     map[string]interface{} `json:"tool_input,omitempty"`
+	Cwd           string                 `json:"cwd,omitempty"`
+	Timestamp     time.Time              `json:"timestamp"`
+}
+
+// SimulatedDecision is what the current engine would decide for a call.
+type SimulatedDecision struct {
+	SimulatedCall
+	Decision Decision  `json:"decision"`         // allow, deny or ask
+	Source   string    `json:"source"`           // policy, rule or prompt
+	Rule     string    `json:"rule,omitempty"`   // Policy rule name or workspace rule pattern
+	Reason   string    `json:"reason,omitempty"` // Policy reason
+	Risk     RiskLevel `json:"risk"`
+}
+
+// Simulator replays tool calls through the current policies and workspace
+// rules. Nothing is asked, remembered or audited, and session memory does
+// not apply: the sessions being replayed are over.
+type Simulator struct {
+	policy PolicyChecker
+	rules  *RuleStore
+}
+
+// NewSimulator creates a simulator. Either argument may be nil.
+func NewSimulator(policy PolicyChecker, rules *RuleStore) *Simulator {
+	return &Simulator{policy: policy, rules: rules}
+}
+
+// Decide returns the decision the permission flow would reach for a call
+// before asking a device: policy denies win, then workspace rules, then
+// policy allows. Anything else would prompt.
+func (s *Simulator) Decide(call SimulatedCall) SimulatedDecision {
+	in := PolicyInput{
+		ToolName:      call.ToolName,
+		ToolInput:     call.ToolInput,
+		WorkspaceID:   call.WorkspaceID,
+		WorkspacePath: call.WorkspacePath,
+		Cwd:           call.Cwd,
+		SessionType:   call.AgentType,
+		Time:          call.Timestamp,
+	}
+	out := SimulatedDecision{
+		SimulatedCall: call,
+		Decision:      DecisionAsk,
+		Source:        SimulationSourcePrompt,
+		Risk:          AssessRisk(in).Level,
+	}
+
+	var policy *PolicyDecision
+	if s.policy != nil {
+		policy = s.policy.Evaluate(in)
+	}
+	fromPolicy := func() SimulatedDecision {
+		out.Decision = policy.Decision
+		out.Source = SimulationSourcePolicy
+		out.Rule = policy.Rule
+		out.Reason = policy.Reason
+		return out
+	}
+
+	if policy != nil && policy.Decision == DecisionDeny {
+		return fromPolicy()
+	}
+	if s.rules != nil && (policy == nil || policy.Decision != DecisionAsk) {
+		if rule := s.rules.Match(call.WorkspaceID, call.ToolName, call.ToolInput); rule != nil {
+			out.Decision = rule.Decision
+			out.Source = SimulationSourceRule
+			out.Rule = rule.String()
+			return out
+		}
+	}
+	if policy != nil {
+		return fromPolicy()
+	}
+	return out
+}
+
+// SimulationCounts tallies simulated decisions.
+type SimulationCounts struct {
+	Total    int `json:"total"`
+	Allowed  int `json:"allowed"`
+	Denied   int `json:"denied"`
+	Prompted int `json:"prompted"`
+}
+
+func (c *SimulationCounts) add(decision Decision) {
+	c.Total++
+	switch decision {
+	case DecisionAllow:
+		c.Allowed++
+	case DecisionDeny:
+		c.Denied++
+	default:
+		c.Prompted++
+	}
+}
+
+// WorkspaceSimulation is the simulation result of one workspace.
+type WorkspaceSimulation struct {
+	WorkspaceID string `json:"workspace_id"`
+	Path        string `json:"path"`
+	Sessions    int    `json:"sessions"`
+	SimulationCounts
+	Calls     []SimulatedDecision `json:"calls"`
+	Truncated bool                `json:"truncated,omitempty"` // More calls matched than the limit
+}
+
+// SimulationReport aggregates simulated decisions per workspace.
+type SimulationReport struct {
+	Workspaces []*WorkspaceSimulation `json:"workspaces"`
+	Totals     SimulationCounts       `json:"totals"`
+
+	// IncludeAllowed lists allowed calls too; by default only denied and
+	// prompted calls are listed, all are counted.
+	IncludeAllowed bool `json:"-"`
+	// Limit caps the calls listed per workspace; 0 means no limit.
+	Limit int `json:"-"`
+}
+
+// Workspace returns the result of a workspace, adding it when missing.
+func (r *SimulationReport) Workspace(workspaceID, path string) *WorkspaceSimulation {
+	for _, ws := range r.Workspaces {
+		if ws.WorkspaceID == workspaceID {
+			return ws
+		}
+	}
+	ws := &WorkspaceSimulation{WorkspaceID: workspaceID, Path: path, Calls: []SimulatedDecision{}}
+	r.Workspaces = append(r.Workspaces, ws)
+	return ws
+}
+
+// Add counts a decision and lists it unless filtered out.
+func (r *SimulationReport) Add(d SimulatedDecision) {
+	ws := r.Workspace(d.WorkspaceID, d.WorkspacePath)
+	ws.add(d.Decision)
+	r.Totals.add(d.Decision)
+	if d.Decision == DecisionAllow && !r.IncludeAllowed {
+		return
+	}
+	if r.Limit > 0 && len(ws.Calls) >= r.Limit {
+		ws.Truncated = true
+		return
+	}
+	ws.Calls = append(ws.Calls, d)
+}
+
+// Sort orders the listed calls of every workspace by time.
+func (r *SimulationReport) Sort() {
+	for _, ws := range r.Workspaces {
+		sort.SliceStable(ws.Calls, func(i, j int) bool {
+			return ws.Calls[i].Timestamp.Before(ws.Calls[j].Timestamp)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package permission
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/pathutil"
+)
+
+func TestSimulator_Decide(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	rules := &RuleStore{}
+	for _, rule := range []Rule{
+		{WorkspaceID: "ws-1", Decision: DecisionAllow, Tool: "Bash", Pattern: "npm install:*"},
+		{WorkspaceID: "ws-1", Decision: DecisionAllow, Tool: "Bash", Pattern: "git push:*"},
+		{WorkspaceID: "ws-1", Decision: DecisionAllow, Tool: "Edit", Pattern: "*"},
+	} {
+		if _, err := rules.Add(rule); err != nil {
+			t.Fatalf("Add rule: %v", err)
+		}
+	}
+	sim := NewSimulator(policy, rules)
+
+	tests := []struct {
+		name       string
+		tool       string
+		input      map[string]interface{}
+		agent      string
+		wantDec    Decision
+		wantSource string
+		wantRule   string
+	}{
+		{"policy allow", "Read", map[string]interface{}{"file_path": "/repo/main.go"}, "claude", DecisionAllow, SimulationSourcePolicy, "reads"},
+		{"workspace rule", "Bash", map[string]interface{}{"command": "npm install lodash"}, "claude", DecisionAllow, SimulationSourceRule, "Bash(npm install:*)"},
+		{"policy deny beats rule", "Bash", map[string]interface{}{"command": "git push --force origin"}, "claude", DecisionDeny, SimulationSourcePolicy, "no-force-push"},
+		{"policy ask skips rules", "Edit", map[string]interface{}{"file_path": "/repo/.env"}, "claude", DecisionAsk, SimulationSourcePolicy, "secrets"},
+		{"session type", "Bash", map[string]interface{}{"command": "go test ./..."}, "codex", DecisionAsk, SimulationSourcePrompt, ""},
+		{"nothing matches", "Bash", map[string]interface{}{"command": "make"}, "claude", DecisionAsk, SimulationSourcePrompt, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sim.Decide(SimulatedCall{
+				WorkspaceID:   "ws-1",
+				WorkspacePath: "/repo",
+				AgentType:     tt.agent,
+				ToolName:      tt.tool,
+				ToolInput:     tt.input,
+				Cwd:           "/repo",
+			})
+			if got.Decision != tt.wantDec || got.Source != tt.wantSource || got.Rule != tt.wantRule {
+				t.Errorf("got %s from %s (%q), want %s from %s (%q)", got.Decision, got.Source, got.Rule, tt.wantDec, tt.wantSource, tt.wantRule)
+			}
+			if got.Risk == "" {
+				t.Error("risk tier not set")
+			}
+		})
+	}
+
+	// Nothing configured: every call would prompt
+	if got := NewSimulator(nil, nil).Decide(SimulatedCall{ToolName: "Read"}); got.Decision != DecisionAsk {
+		t.Errorf("empty simulator decision = %s, want ask", got.Decision)
+	}
+}
+
+func TestSimulationReport_AddFiltersAndLimits(t *testing.T) {
+	report := &SimulationReport{Limit: 1}
+	base := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+	for i, dec := range []Decision{DecisionAllow, DecisionAsk, DecisionDeny} {
+		report.Add(SimulatedDecision{
+			SimulatedCall: SimulatedCall{WorkspaceID: "ws-1", Timestamp: base.Add(-time.Duration(i) * time.Minute)},
+			Decision:      dec,
+		})
+	}
+
+	ws := report.Workspace("ws-1", "")
+	if ws.Total != 3 || ws.Allowed != 1 || ws.Denied != 1 || ws.Prompted != 1 {
+		t.Errorf("counts = %+v, want one of each", ws.SimulationCounts)
+	}
+	if report.Totals != ws.SimulationCounts {
+		t.Errorf("totals = %+v, want %+v", report.Totals, ws.SimulationCounts)
+	}
+	if len(ws.Calls) != 1 || ws.Calls[0].Decision != DecisionAsk || !ws.Truncated {
+		t.Errorf("calls = %+v truncated = %v, want only the prompted call", ws.Calls, ws.Truncated)
+	}
+}
+
+func TestSimulator_Replay(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CODEX_HOME", filepath.Join(home, ".codex"))
+
+	repo := filepath.Join(home, "repo")
+	claudeDir := filepath.Join(home, ".claude", "projects", pathutil.EncodePath(repo))
+	codexDir := filepath.Join(home, ".codex", "sessions", "2026", "01", "31")
+	for _, dir := range []string{claudeDir, codexDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+
+	claudeSession := `{"type":"assistant","cwd":"` + repo + `","timestamp":"2026-01-31T11:00:00Z","message":{"content":[{"type":"tool_use","id":"old","name":"Bash","input":{"command":"make"}}]}}
+{"type":"assistant","cwd":"` + repo + `","timestamp":"2026-01-31T13:00:00Z","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"Read","input":{"file_path":"` + repo + `/main.go"}},{"type":"tool_use","id":"toolu_2","name":"Bash","input":{"command":"git push --force"}}]}}
+`
+	codexSession := `{"timestamp":"2026-01-31T13:00:00Z","type":"session_meta","payload":{"id":"codex-1","cwd":"` + repo + `"}}
+{"timestamp":"2026-01-31T13:00:01Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"build it"}]}}
+{"timestamp":"2026-01-31T13:00:02Z","type":"response_item","payload":{"type":"function_call","name":"exec_command","arguments":"{\"cmd\":\"make\"}","call_id":"call_1"}}
+`
+	if err := os.WriteFile(filepath.Join(claudeDir, "11111111-1111-1111-1111-111111111111.jsonl"), []byte(claudeSession), 0644); err != nil {
+		t.Fatalf("write Claude session: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(codexDir, "rollout-codex-1.jsonl"), []byte(codexSession), 0644); err != nil {
+		t.Fatalf("write Codex session: %v", err)
+	}
+
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	sim := NewSimulator(policy, nil)
+	workspaces := []HistoryWorkspace{{ID: "ws-1", Path: repo}, {ID: "ws-empty", Path: filepath.Join(home, "empty")}}
+	since := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	report := sim.Replay(workspaces, HistoryOptions{Since: since})
+	if len(report.Workspaces) != 2 {
+		t.Fatalf("got %d workspaces, want 2", len(report.Workspaces))
+	}
+	ws := report.Workspaces[0]
+	if ws.Sessions != 2 || ws.Total != 3 || ws.Allowed != 1 || ws.Denied != 1 || ws.Prompted != 1 {
+		t.Errorf("ws-1 = %d sessions %+v, want 2 sessions with one call of each decision", ws.Sessions, ws.SimulationCounts)
+	}
+	if len(ws.Calls) != 2 || ws.Calls[0].ToolUseID != "toolu_2" || ws.Calls[1].AgentType != HistoryAgentCodex {
+		t.Errorf("listed calls = %+v, want the denied Claude call then the prompted Codex call", ws.Calls)
+	}
+	if report.Workspaces[1].Total != 0 {
+		t.Errorf("empty workspace = %+v", report.Workspaces[1])
+	}
+
+	report = sim.Replay(workspaces, HistoryOptions{AgentTypes: []string{HistoryAgentCodex}, IncludeAllowed: true})
+	if report.Totals.Total != 1 || report.Workspaces[0].Sessions != 1 {
+		t.Errorf("codex only totals = %+v, want the one Codex call", report.Totals)
+	}
+}
@@ -12,6 +12,7 @@ type Decision string
 const (
 	DecisionAllow Decision = "allow"
 	DecisionDeny  Decision = "deny"
+	DecisionAsk   Decision = "ask" // Policy only: always ask a human, ignoring remembered decisions
 )
 
 // Scope represents the scope of a permission decision.
@@ -20,7 +21,7 @@ type Scope string
 const (
 	ScopeOnce    Scope = "once"    // Allow/deny just this one request
 	ScopeSession Scope = "session" // Allow/deny for the rest of the session
-	ScopePath    Scope = "path"    // Allow for this path pattern (persisted to settings)
+	ScopePath    Scope = "path"    // Allow/deny this pattern in the workspace (persisted as a rule)
 )
 
 // HookInput represents the JSON input from Claude Code's hook system.
@@ -61,6 +62,15 @@ type Request struct {
 	ToolUseID     string                 `json:"tool_use_id"`    // Claude's tool use ID
 	CreatedAt     time.Time              `json:"created_at"`     // When the request was received
 	ResponseChan  chan *Response         `json:"-"`              // Channel to receive response (not serialized)
+	Risk          *Risk                  `json:"risk,omitempty"` // Risk assessment shown with the prompt
+	AgentType     string                 `json:"agent_type"`     // Runtime asking: claude or codex
+	Cwd           string                 `json:"cwd,omitempty"`  // Working directory of the agent
+
+	// Multi-device routing state, updated through MemoryManager while pending
+	NotifiedDevices   []string `json:"notified_devices,omitempty"`   // Devices the request was sent to
+	Escalated         bool     `json:"escalated,omitempty"`          // Sent to every device after the primaries did not answer
+	RequiredApprovals int      `json:"required_approvals,omitempty"` // Approvals needed to allow; 0 means 1
+	Approvals         []string `json:"approvals,omitempty"`          // Devices that approved so far
 }
 
 // Response represents a response to a permission request.
@@ -71,14 +81,29 @@ type Response struct {
 	UpdatedInput map[string]interface{} `json:"updated_input,omitempty"` // Optional modified input
 	Message      string                 `json:"message,omitempty"`       // Optional message
 	Interrupt    bool                   `json:"interrupt,omitempty"`     // If true, interrupt Claude
+	RespondedBy  string                 `json:"responded_by,omitempty"`  // Device or client that answered
+	ApprovedBy   []string               `json:"approved_by,omitempty"`   // Devices that approved a multi-approval request
+	PolicyRule   string                 `json:"policy_rule,omitempty"`   // Policy rule that decided the request
 }
 
-// StoredDecision represents a decision stored in session memory.
+// StoredDecision represents a decision stored in session memory, or a
+// persistent workspace rule that matched a request.
 type StoredDecision struct {
 	Pattern    string    `json:"pattern"`
 	Decision   Decision  `json:"decision"`
 	CreatedAt  time.Time `json:"created_at"`
-	UsageCount int       `json:"usage_count"` // How many times this pattern was matched
+	UsageCount int       `json:"usage_count"`          // How many times this pattern was matched
+	Scope      Scope     `json:"scope,omitempty"`      // session or path; empty means session
+	RuleID     string    `json:"rule_id,omitempty"`    // Set when a workspace rule matched
+	CreatedBy  string    `json:"created_by,omitempty"` // Device that created the matched rule
+}
+
+// EffectiveScope returns the scope the decision was remembered with.
+func (d *StoredDecision) EffectiveScope() Scope {
+	if d.Scope == "" {
+		return ScopeSession
+	}
+	return d.Scope
 }
 
 // SessionMemory holds permission decisions for a single Claude session.
@@ -100,6 +125,7 @@ type PermissionEvent struct {
 	Options     []PermissionOption `json:"options"`     // available choices
 	SessionID   string            `json:"session_id"`
 	WorkspaceID string            `json:"workspace_id"`
+	Risk        *Risk             `json:"risk,omitempty"` // Risk tier, score and reasons
 }
 
 // PermissionOption represents a choice in a permission prompt.
@@ -0,0 +1,2120 @@
+// Code generated by go run ./gen; DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// AgentInputParams are the params of agent/input.
+type AgentInputParams struct {
+	// The keyboard input to send (e.g., '1' to select option 1, or '1\n' with
+	// newline)
+	Input string `json:"input"`
+}
+
+// AgentInputResult is the result of agent/input.
+type AgentInputResult struct {
+	Status string `json:"status"`
+}
+
+// AgentInput calls agent/input: Send input to interactive agent.
+//
+// Sends keyboard input to an agent running in interactive (PTY) mode. Use this
+// to respond to permission prompts.
+func (c *Client) AgentInput(ctx context.Context, params AgentInputParams) (*AgentInputResult, error) {
+	var result AgentInputResult
+	if err := c.invoke(ctx, "agent/input", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AgentRespondParams are the params of agent/respond.
+type AgentRespondParams struct {
+	// The tool use ID from the agent's request
+	ToolUseID string `json:"tool_use_id"`
+	// The response content
+	Response string `json:"response"`
+	// Whether this is an error response
+	IsError bool `json:"is_error,omitempty"`
+}
+
+// AgentRespondResult is the result of agent/respond.
+type AgentRespondResult struct {
+	Status    string `json:"status"`
+	ToolUseID string `json:"tool_use_id"`
+}
+
+// AgentRespond calls agent/respond: Respond to agent tool use request.
+//
+// Sends a response to an agent's tool use request (e.g., permission approval).
+func (c *Client) AgentRespond(ctx context.Context, params AgentRespondParams) (*AgentRespondResult, error) {
+	var result AgentRespondResult
+	if err := c.invoke(ctx, "agent/respond", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AgentRunParams are the params of agent/run.
+type AgentRunParams struct {
+	// The prompt to send to the agent
+	Prompt string `json:"prompt"`
+	// Session mode: 'new' for new session, 'continue' to continue existing. One of
+	// "new", "continue".
+	Mode string `json:"mode,omitempty"`
+	// Session ID to continue (required if mode is 'continue')
+	SessionID string `json:"session_id,omitempty"`
+	// Permission handling mode. Use 'acceptEdits' to auto-accept file edits,
+	// 'bypassPermissions' to skip all, 'interactive' for PTY mode with
+	// terminal-like permission prompts. One of "default", "acceptEdits",
+	// "bypassPermissions", "plan", "interactive".
+	PermissionMode string `json:"permission_mode,omitempty"`
+}
+
+// AgentRun calls agent/run: Start an AI agent with a prompt.
+//
+// Starts the specified AI agent (Claude, Gemini, or Codex) with the given
+// prompt. Can start a new session or continue an existing one.
+func (c *Client) AgentRun(ctx context.Context, params AgentRunParams) (*AgentRunResult, error) {
+	var result AgentRunResult
+	if err := c.invoke(ctx, "agent/run", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AgentStatus calls agent/status: Get agent status.
+//
+// Returns the current status of the AI agent.
+func (c *Client) AgentStatus(ctx context.Context) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "agent/status", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AgentStop calls agent/stop: Stop the running agent.
+//
+// Stops the currently running AI agent gracefully.
+func (c *Client) AgentStop(ctx context.Context) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "agent/stop", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ClientDeliveryParams are the params of client/delivery.
+type ClientDeliveryParams struct {
+	// Replace superseded state events
+	Coalesce bool `json:"coalesce,omitempty"`
+	// Send queued notifications as JSON arrays
+	Batch bool `json:"batch,omitempty"`
+}
+
+// ClientDeliveryResult is the result of client/delivery.
+type ClientDeliveryResult struct {
+	Batch    bool `json:"batch"`
+	Coalesce bool `json:"coalesce"`
+	Lagging  bool `json:"lagging"`
+}
+
+// ClientDelivery calls client/delivery: Set event delivery policy for current
+// client.
+//
+// Controls how events are delivered when the client reads slower than they are
+// produced. With coalesce (default on), a queued state event such as
+// git_status_changed, pty_state, pty_spinner or claude_status is replaced by a
+// newer one of the same workspace and session. With batch (default off),
+// queued small notifications are sent as one JSON-RPC batch array. If the send
+// queue still fills up, queued events are dropped and an event/stream_lagging
+// notification tells the client to resubscribe with since_seq. Permission
+// prompts, errors and session end events are never coalesced or dropped.
+func (c *Client) ClientDelivery(ctx context.Context, params ClientDeliveryParams) (*ClientDeliveryResult, error) {
+	var result ClientDeliveryResult
+	if err := c.invoke(ctx, "client/delivery", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ClientSessionFocusParams are the params of client/session/focus.
+type ClientSessionFocusParams struct {
+	// Workspace ID
+	WorkspaceID string `json:"workspace_id"`
+	// Session ID
+	SessionID string `json:"session_id"`
+}
+
+// ClientSessionFocus calls client/session/focus: Set session focus for current
+// client.
+//
+// Notifies the server which session this client is currently viewing. The
+// server will broadcast session_joined events to other clients viewing the
+// same session.
+func (c *Client) ClientSessionFocus(ctx context.Context, params ClientSessionFocusParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "client/session/focus", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FileGetParams are the params of file/get.
+type FileGetParams struct {
+	// File path relative to repository root
+	Path string `json:"path"`
+}
+
+// FileGet calls file/get: Get file content.
+//
+// Returns the content of a file from the repository.
+func (c *Client) FileGet(ctx context.Context, params FileGetParams) (*FileGetResult, error) {
+	var result FileGetResult
+	if err := c.invoke(ctx, "file/get", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FileListParams are the params of file/list.
+type FileListParams struct {
+	// Relative path from repo root (empty for root directory)
+	Path string `json:"path,omitempty"`
+}
+
+// FileList calls file/list: List directory contents.
+//
+// Returns the contents of a directory in the repository. Supports browsing the
+// file tree.
+func (c *Client) FileList(ctx context.Context, params FileListParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "file/list", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitBranchDeleteParams are the params of git/branch/delete.
+type GitBranchDeleteParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Branch      string `json:"branch"`
+	Force       bool   `json:"force,omitempty"`
+}
+
+// GitBranchDelete calls git/branch/delete: Delete a branch for a workspace.
+//
+// Deletes the specified branch in the workspace.
+func (c *Client) GitBranchDelete(ctx context.Context, params GitBranchDeleteParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/branch/delete", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitBranchesParams are the params of git/branches.
+type GitBranchesParams struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// GitBranches calls git/branches: List branches for a workspace.
+//
+// Returns the list of git branches for the workspace.
+func (c *Client) GitBranches(ctx context.Context, params GitBranchesParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/branches", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitCheckoutParams are the params of git/checkout.
+type GitCheckoutParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Branch      string `json:"branch"`
+	Create      bool   `json:"create,omitempty"`
+}
+
+// GitCheckout calls git/checkout: Checkout a branch for a workspace.
+//
+// Checks out the specified branch in the workspace.
+func (c *Client) GitCheckout(ctx context.Context, params GitCheckoutParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/checkout", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitCommitParams are the params of git/commit.
+type GitCommitParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Message     string `json:"message"`
+	Push        bool   `json:"push,omitempty"`
+}
+
+// GitCommit calls git/commit: Commit staged changes for a workspace.
+//
+// Creates a commit with the staged changes in the workspace.
+func (c *Client) GitCommit(ctx context.Context, params GitCommitParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/commit", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitDiffParams are the params of git/diff.
+type GitDiffParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Path        string `json:"path,omitempty"`
+	Staged      bool   `json:"staged,omitempty"`
+}
+
+// GitDiff calls git/diff: Get git diff for a workspace.
+//
+// Returns the git diff for the specified workspace.
+func (c *Client) GitDiff(ctx context.Context, params GitDiffParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/diff", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitDiscardParams are the params of git/discard.
+type GitDiscardParams struct {
+	WorkspaceID string   `json:"workspace_id"`
+	Paths       []string `json:"paths"`
+}
+
+// GitDiscard calls git/discard: Discard changes for a workspace.
+//
+// Discards uncommitted changes to the specified files in the workspace.
+func (c *Client) GitDiscard(ctx context.Context, params GitDiscardParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/discard", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitFetchParams are the params of git/fetch.
+type GitFetchParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Remote      string `json:"remote,omitempty"`
+	Prune       bool   `json:"prune,omitempty"`
+	// Token to receive $/progress notifications with
+	ProgressToken interface{} `json:"progress_token,omitempty"`
+}
+
+// GitFetch calls git/fetch: Fetch from remote for a workspace.
+//
+// Fetches updates from the remote repository.
+//
+// Set ProgressToken to receive $/progress notifications.
+func (c *Client) GitFetch(ctx context.Context, params GitFetchParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/fetch", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitGetStatusParams are the params of git/get_status.
+type GitGetStatusParams struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// GitGetStatus calls git/get_status: Get comprehensive git status for a
+// workspace.
+//
+// Returns comprehensive git status including state (noGit, gitInit, noRemote,
+// noPush, synced, diverged, conflict), branch info, and file changes.
+func (c *Client) GitGetStatus(ctx context.Context, params GitGetStatusParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/get_status", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitInitParams are the params of git/init.
+type GitInitParams struct {
+	WorkspaceID   string `json:"workspace_id"`
+	InitialBranch string `json:"initial_branch,omitempty"`
+	InitialCommit bool   `json:"initial_commit,omitempty"`
+	CommitMessage string `json:"commit_message,omitempty"`
+}
+
+// GitInit calls git/init: Initialize a git repository for a workspace.
+//
+// Initializes a new git repository in the workspace directory.
+func (c *Client) GitInit(ctx context.Context, params GitInitParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/init", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitLogParams are the params of git/log.
+type GitLogParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Limit       int    `json:"limit,omitempty"`
+	Skip        int    `json:"skip,omitempty"`
+	Branch      string `json:"branch,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Graph       bool   `json:"graph,omitempty"`
+}
+
+// GitLog calls git/log: Get commit log for a workspace.
+//
+// Returns the commit history with optional graph layout for visualization.
+func (c *Client) GitLog(ctx context.Context, params GitLogParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/log", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitMergeParams are the params of git/merge.
+type GitMergeParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Branch      string `json:"branch"`
+	NoFf        bool   `json:"no_ff,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// GitMerge calls git/merge: Merge a branch for a workspace.
+//
+// Merges the specified branch into the current branch.
+func (c *Client) GitMerge(ctx context.Context, params GitMergeParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/merge", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitMergeAbortParams are the params of git/merge/abort.
+type GitMergeAbortParams struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// GitMergeAbort calls git/merge/abort: Abort a merge for a workspace.
+//
+// Aborts an in-progress merge operation.
+func (c *Client) GitMergeAbort(ctx context.Context, params GitMergeAbortParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/merge/abort", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitPullParams are the params of git/pull.
+type GitPullParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Rebase      bool   `json:"rebase,omitempty"`
+	// Token to receive $/progress notifications with
+	ProgressToken interface{} `json:"progress_token,omitempty"`
+}
+
+// GitPull calls git/pull: Pull changes for a workspace.
+//
+// Pulls changes from the remote repository for the workspace.
+//
+// Set ProgressToken to receive $/progress notifications.
+func (c *Client) GitPull(ctx context.Context, params GitPullParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/pull", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitPushParams are the params of git/push.
+type GitPushParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Force       bool   `json:"force,omitempty"`
+	SetUpstream bool   `json:"set_upstream,omitempty"`
+	Remote      string `json:"remote,omitempty"`
+	Branch      string `json:"branch,omitempty"`
+}
+
+// GitPush calls git/push: Push commits for a workspace.
+//
+// Pushes commits to the remote repository for the workspace.
+func (c *Client) GitPush(ctx context.Context, params GitPushParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/push", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitRemoteAddParams are the params of git/remote/add.
+type GitRemoteAddParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Fetch       *bool  `json:"fetch,omitempty"`
+}
+
+// GitRemoteAdd calls git/remote/add: Add a remote to a workspace.
+//
+// Adds a remote repository to the workspace.
+func (c *Client) GitRemoteAdd(ctx context.Context, params GitRemoteAddParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/remote/add", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitRemoteListParams are the params of git/remote/list.
+type GitRemoteListParams struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// GitRemoteList calls git/remote/list: List remotes for a workspace.
+//
+// Returns the list of configured remotes.
+func (c *Client) GitRemoteList(ctx context.Context, params GitRemoteListParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/remote/list", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitRemoteRemoveParams are the params of git/remote/remove.
+type GitRemoteRemoveParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Name        string `json:"name"`
+}
+
+// GitRemoteRemove calls git/remote/remove: Remove a remote from a workspace.
+//
+// Removes a remote repository from the workspace.
+func (c *Client) GitRemoteRemove(ctx context.Context, params GitRemoteRemoveParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/remote/remove", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitStageParams are the params of git/stage.
+type GitStageParams struct {
+	WorkspaceID string   `json:"workspace_id"`
+	Paths       []string `json:"paths"`
+}
+
+// GitStage calls git/stage: Stage files for a workspace.
+//
+// Stages the specified files for commit in the workspace.
+func (c *Client) GitStage(ctx context.Context, params GitStageParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/stage", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitStashParams are the params of git/stash.
+type GitStashParams struct {
+	WorkspaceID      string `json:"workspace_id"`
+	Message          string `json:"message,omitempty"`
+	IncludeUntracked bool   `json:"include_untracked,omitempty"`
+}
+
+// GitStash calls git/stash: Create a stash for a workspace.
+//
+// Stashes changes in the workspace.
+func (c *Client) GitStash(ctx context.Context, params GitStashParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/stash", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitStashApplyParams are the params of git/stash/apply.
+type GitStashApplyParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Index       int    `json:"index,omitempty"`
+}
+
+// GitStashApply calls git/stash/apply: Apply a stash for a workspace.
+//
+// Applies a stash without removing it from the stash list.
+func (c *Client) GitStashApply(ctx context.Context, params GitStashApplyParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/stash/apply", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitStashDropParams are the params of git/stash/drop.
+type GitStashDropParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Index       int    `json:"index,omitempty"`
+}
+
+// GitStashDrop calls git/stash/drop: Drop a stash for a workspace.
+//
+// Removes a stash from the stash list without applying it.
+func (c *Client) GitStashDrop(ctx context.Context, params GitStashDropParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/stash/drop", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitStashListParams are the params of git/stash/list.
+type GitStashListParams struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// GitStashList calls git/stash/list: List stashes for a workspace.
+//
+// Returns the list of stashes in the workspace.
+func (c *Client) GitStashList(ctx context.Context, params GitStashListParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/stash/list", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitStashPopParams are the params of git/stash/pop.
+type GitStashPopParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Index       int    `json:"index,omitempty"`
+}
+
+// GitStashPop calls git/stash/pop: Pop a stash for a workspace.
+//
+// Applies and removes a stash from the stash list.
+func (c *Client) GitStashPop(ctx context.Context, params GitStashPopParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/stash/pop", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitStatusParams are the params of git/status.
+type GitStatusParams struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// GitStatus calls git/status: Get git status for a workspace.
+//
+// Returns the git status for the specified workspace.
+func (c *Client) GitStatus(ctx context.Context, params GitStatusParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/status", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitUnstageParams are the params of git/unstage.
+type GitUnstageParams struct {
+	WorkspaceID string   `json:"workspace_id"`
+	Paths       []string `json:"paths"`
+}
+
+// GitUnstage calls git/unstage: Unstage files for a workspace.
+//
+// Unstages the specified files in the workspace.
+func (c *Client) GitUnstage(ctx context.Context, params GitUnstageParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/unstage", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GitUpstreamSetParams are the params of git/upstream/set.
+type GitUpstreamSetParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Branch      string `json:"branch"`
+	Upstream    string `json:"upstream"`
+}
+
+// GitUpstreamSet calls git/upstream/set: Set upstream for a branch.
+//
+// Sets the upstream tracking branch for the specified branch.
+func (c *Client) GitUpstreamSet(ctx context.Context, params GitUpstreamSetParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "git/upstream/set", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// InitializeParams are the params of initialize.
+type InitializeParams struct {
+	// Protocol version the client supports
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+	// Client information
+	ClientInfo interface{} `json:"clientInfo,omitempty"`
+	// Client capabilities
+	Capabilities map[string]interface{} `json:"capabilities,omitempty"`
+	// Root path of the workspace
+	RootPath string `json:"rootPath,omitempty"`
+}
+
+// Initialize calls initialize: Initialize connection.
+//
+// Initialize the connection and negotiate capabilities. Must be called first
+// before using other methods.
+func (c *Client) Initialize(ctx context.Context, params InitializeParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "initialize", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Initialized calls initialized: Confirm initialization.
+//
+// Confirm that the client has processed the initialize response. This is a
+// notification (no response expected).
+func (c *Client) Initialized(ctx context.Context) error {
+	return c.invoke(ctx, "initialized", nil, nil)
+}
+
+// PermissionAuditParams are the params of permission/audit.
+type PermissionAuditParams struct {
+	// Optional: filter by workspace ID
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	// Optional: filter by session ID
+	SessionID string `json:"session_id,omitempty"`
+	// Optional: filter by tool name
+	Tool string `json:"tool,omitempty"`
+	// One of "allow", "deny", "ask".
+	Decision string `json:"decision,omitempty"`
+	// Optional: policy, rule, memory, device, bypass, timeout, cancelled,
+	// no_clients or unconfigured
+	Source string `json:"source,omitempty"`
+	// One of "low", "medium", "high", "critical".
+	Risk string `json:"risk,omitempty"`
+	// Optional: filter by responding device or client ID
+	Device string `json:"device,omitempty"`
+	// Optional: only approvals with edited input
+	Modified bool   `json:"modified,omitempty"`
+	Since    string `json:"since,omitempty"`
+	Until    string `json:"until,omitempty"`
+	// Page size; exports are unlimited unless set
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+	// One of "json", "jsonl", "csv".
+	Format string `json:"format,omitempty"`
+}
+
+// PermissionAuditResult is the result of permission/audit.
+type PermissionAuditResult struct {
+	// Export document for jsonl/csv formats
+	Content string        `json:"content"`
+	Count   int           `json:"count"`
+	Entries []interface{} `json:"entries"`
+	Total   int           `json:"total"`
+}
+
+// PermissionAudit calls permission/audit: Query the permission decision audit
+// log.
+//
+// Returns recorded permission decisions, newest first: the request, what
+// decided it (policy, rule, memory, device, timeout), whether the input was
+// edited before approval, the risk tier, the responding device, the host and
+// the latency. With format 'jsonl' or 'csv' the matching entries are returned
+// as an export document instead.
+func (c *Client) PermissionAudit(ctx context.Context, params PermissionAuditParams) (*PermissionAuditResult, error) {
+	var result PermissionAuditResult
+	if err := c.invoke(ctx, "permission/audit", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PermissionPendingParams are the params of permission/pending.
+type PermissionPendingParams struct {
+	// Optional: filter by session ID
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// PermissionPendingResult is the result of permission/pending.
+type PermissionPendingResult struct {
+	Groups   []interface{} `json:"groups"`
+	Requests []interface{} `json:"requests"`
+}
+
+// PermissionPending calls permission/pending: Get all pending permission
+// requests.
+//
+// Returns all pending permission requests. Call this on reconnect to catch any
+// missed permissions. Requests are also grouped by workspace and generated
+// pattern; a group can be answered with permission/respond_batch.
+func (c *Client) PermissionPending(ctx context.Context, params PermissionPendingParams) (*PermissionPendingResult, error) {
+	var result PermissionPendingResult
+	if err := c.invoke(ctx, "permission/pending", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PermissionRequestParams are the params of permission/request.
+type PermissionRequestParams struct {
+	// Claude session ID
+	SessionID string `json:"session_id"`
+	// Tool name (Bash, Write, Edit, etc.)
+	ToolName string `json:"tool_name"`
+	// Tool input parameters
+	ToolInput map[string]interface{} `json:"tool_input"`
+	// Claude's tool use ID
+	ToolUseID string `json:"tool_use_id"`
+	// Current working directory
+	Cwd string `json:"cwd,omitempty"`
+}
+
+// PermissionRequest calls permission/request: Request permission decision from
+// mobile app.
+//
+// Called by the hook CLI to request a permission decision. Checks session
+// memory first, then forwards to mobile app if no match.
+func (c *Client) PermissionRequest(ctx context.Context, params PermissionRequestParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "permission/request", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PermissionRespondParams are the params of permission/respond.
+type PermissionRespondParams struct {
+	// Tool use ID from the permission request
+	ToolUseID string `json:"tool_use_id"`
+	// Allow or deny the request. One of "allow", "deny".
+	Decision string `json:"decision"`
+	// Scope of the decision. 'path' persists it as a workspace rule. One of
+	// "once", "session", "path".
+	Scope string `json:"scope,omitempty"`
+	// Pattern to remember for session/path scope, e.g. 'Edit(src/*.go)'. Must
+	// match the request. Defaults to a pattern generated from the request.
+	Pattern string `json:"pattern,omitempty"`
+	// Approve with edited tool input, e.g. a narrower path or an added --dry-run.
+	// Validated against the tool's input schema and re-checked by permission
+	// policies; only valid with decision 'allow', always scoped to this request
+	// and not supported for Codex requests.
+	UpdatedInput map[string]interface{} `json:"updated_input,omitempty"`
+}
+
+// PermissionRespond calls permission/respond: Respond to a permission request.
+//
+// Called by the mobile app to respond to a pending permission request, from
+// Claude or Codex (see agent_type). Requests matching a high-risk routing
+// pattern need approvals from several devices: until then an approval returns
+// status 'awaiting_approvals' and the request stays pending, while a single
+// deny decides it.
+func (c *Client) PermissionRespond(ctx context.Context, params PermissionRespondParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "permission/respond", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PermissionRespondBatchParams are the params of permission/respond_batch.
+type PermissionRespondBatchParams struct {
+	// Group ID from permission/pending. Answers the requests of the group, if it
+	// has not changed.
+	GroupID string `json:"group_id,omitempty"`
+	// Revision of the group from permission/pending, required with group_id
+	Revision string `json:"revision,omitempty"`
+	// Requests to answer, instead of group_id
+	ToolUseIDs []string `json:"tool_use_ids,omitempty"`
+	// One of "allow", "deny".
+	Decision string `json:"decision"`
+	// One of "once", "session", "path".
+	Scope string `json:"scope,omitempty"`
+	// Pattern to remember for session/path scope. Requests it does not match use
+	// their generated pattern.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// PermissionRespondBatch calls permission/respond_batch: Respond to several
+// pending permission requests at once.
+//
+// Applies one decision to a group from permission/pending (requests of a
+// workspace sharing a generated pattern) or to a list of tool use IDs, across
+// sessions. A group is only answered if it still holds the requests that were
+// displayed, as its revision shows. Each request is answered as with
+// permission/respond, so session scope remembers the pattern for every session
+// of the batch and path scope stores one workspace rule.
+func (c *Client) PermissionRespondBatch(ctx context.Context, params PermissionRespondBatchParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "permission/respond_batch", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PermissionRulesAddParams are the params of permission/rules/add.
+type PermissionRulesAddParams struct {
+	// Workspace the rule applies to
+	WorkspaceID string `json:"workspace_id"`
+	// Tool name (Bash, Write, Edit, Read, ...)
+	Tool string `json:"tool"`
+	// One of "allow", "deny".
+	Decision string `json:"decision"`
+	// Pattern content, e.g. 'git status:*' or 'src/*.go'. Relative file patterns
+	// are anchored at the workspace root.
+	Pattern string `json:"pattern,omitempty"`
+	// Optional RFC3339 expiry
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// PermissionRulesAdd calls permission/rules/add: Add a persistent permission
+// rule.
+//
+// Stores an allow or deny rule for a workspace. Rules are checked before
+// session memory and survive restarts; deny rules win over allow rules.
+func (c *Client) PermissionRulesAdd(ctx context.Context, params PermissionRulesAddParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "permission/rules/add", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PermissionRulesListParams are the params of permission/rules/list.
+type PermissionRulesListParams struct {
+	// Optional: filter by workspace ID
+	WorkspaceID string `json:"workspace_id,omitempty"`
+}
+
+// PermissionRulesListResult is the result of permission/rules/list.
+type PermissionRulesListResult struct {
+	Rules []interface{} `json:"rules"`
+}
+
+// PermissionRulesList calls permission/rules/list: List persistent permission
+// rules.
+//
+// Returns the unexpired path-scoped rules of a workspace, or of all workspaces
+// when workspace_id is omitted.
+func (c *Client) PermissionRulesList(ctx context.Context, params PermissionRulesListParams) (*PermissionRulesListResult, error) {
+	var result PermissionRulesListResult
+	if err := c.invoke(ctx, "permission/rules/list", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PermissionRulesRemoveParams are the params of permission/rules/remove.
+type PermissionRulesRemoveParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	RuleID      string `json:"rule_id"`
+}
+
+// PermissionRulesRemove calls permission/rules/remove: Remove a persistent
+// permission rule.
+//
+// Deletes a workspace rule by ID.
+func (c *Client) PermissionRulesRemove(ctx context.Context, params PermissionRulesRemoveParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "permission/rules/remove", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PermissionSimulateParams are the params of permission/simulate.
+type PermissionSimulateParams struct {
+	// Workspaces to replay. Defaults to all configured workspaces.
+	WorkspaceIDs []string `json:"workspace_ids,omitempty"`
+	// Optional: only replay calls made after this time
+	Since string `json:"since,omitempty"`
+	// Optional: session types to replay. Defaults to both.
+	AgentTypes []string `json:"agent_types,omitempty"`
+	// List allowed calls too
+	IncludeAllowed bool `json:"include_allowed,omitempty"`
+	// Calls listed per workspace
+	Limit int `json:"limit,omitempty"`
+}
+
+// PermissionSimulateResult is the result of permission/simulate.
+type PermissionSimulateResult struct {
+	Totals     map[string]interface{} `json:"totals"`
+	Workspaces []interface{}          `json:"workspaces"`
+}
+
+// PermissionSimulate calls permission/simulate: Dry-run permission policies
+// against past sessions.
+//
+// Replays the tool calls of past Claude sessions and Codex rollouts of each
+// workspace through the current policies and workspace rules, and reports
+// which calls would have been allowed, denied or prompted. Nothing is asked,
+// remembered or audited; session memory does not apply. Every call is counted,
+// but only denied and prompted calls are listed unless include_allowed is set.
+func (c *Client) PermissionSimulate(ctx context.Context, params PermissionSimulateParams) (*PermissionSimulateResult, error) {
+	var result PermissionSimulateResult
+	if err := c.invoke(ctx, "permission/simulate", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PermissionStats calls permission/stats: Get permission system statistics.
+//
+// Returns statistics about the permission memory system.
+func (c *Client) PermissionStats(ctx context.Context) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "permission/stats", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RepositoryFilesListParams are the params of repository/files/list.
+type RepositoryFilesListParams struct {
+	// Directory path (empty for root)
+	Directory string `json:"directory,omitempty"`
+	Recursive bool   `json:"recursive,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+	Offset    int    `json:"offset,omitempty"`
+	// One of "name", "size", "modified", "path".
+	SortBy string `json:"sort_by,omitempty"`
+	// One of "asc", "desc".
+	SortOrder  string   `json:"sort_order,omitempty"`
+	Extensions []string `json:"extensions,omitempty"`
+	MinSize    int      `json:"min_size,omitempty"`
+	MaxSize    int      `json:"max_size,omitempty"`
+}
+
+// RepositoryFilesList calls repository/files/list: List files in directory.
+//
+// Returns a paginated list of files in a directory with filtering and sorting
+// options.
+func (c *Client) RepositoryFilesList(ctx context.Context, params RepositoryFilesListParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "repository/files/list", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RepositoryFilesTreeParams are the params of repository/files/tree.
+type RepositoryFilesTreeParams struct {
+	// Root path (empty for repository root)
+	Path string `json:"path,omitempty"`
+	// Maximum depth to traverse
+	Depth int `json:"depth,omitempty"`
+}
+
+// RepositoryFilesTree calls repository/files/tree: Get directory tree.
+//
+// Returns a hierarchical tree structure of the repository with files and
+// directories.
+func (c *Client) RepositoryFilesTree(ctx context.Context, params RepositoryFilesTreeParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "repository/files/tree", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RepositoryIndexRebuildParams are the params of repository/index/rebuild.
+type RepositoryIndexRebuildParams struct {
+	// Token to receive $/progress notifications with
+	ProgressToken interface{} `json:"progress_token,omitempty"`
+}
+
+// RepositoryIndexRebuild calls repository/index/rebuild: Rebuild repository
+// index.
+//
+// Triggers a full re-index of the repository in the background. Returns
+// immediately; with a progress_token, $/progress is reported until the rebuild
+// completes.
+//
+// Set ProgressToken to receive $/progress notifications.
+func (c *Client) RepositoryIndexRebuild(ctx context.Context, params RepositoryIndexRebuildParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "repository/index/rebuild", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RepositoryIndexStatus calls repository/index/status: Get repository index
+// status.
+//
+// Returns the current status of the repository index including file counts,
+// sizes, and last scan time.
+func (c *Client) RepositoryIndexStatus(ctx context.Context) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "repository/index/status", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RepositorySearchParams are the params of repository/search.
+type RepositorySearchParams struct {
+	// Search query string
+	Query string `json:"query"`
+	// One of "fuzzy", "exact", "prefix", "extension".
+	Mode            string   `json:"mode,omitempty"`
+	Limit           int      `json:"limit,omitempty"`
+	Offset          int      `json:"offset,omitempty"`
+	Extensions      []string `json:"extensions,omitempty"`
+	ExcludeBinaries *bool    `json:"exclude_binaries,omitempty"`
+	GitTrackedOnly  bool     `json:"git_tracked_only,omitempty"`
+	// Minimum file size in bytes
+	MinSize int `json:"min_size,omitempty"`
+	// Maximum file size in bytes
+	MaxSize int `json:"max_size,omitempty"`
+}
+
+// RepositorySearch calls repository/search: Search files in repository.
+//
+// Search for files using fuzzy, exact, prefix, or extension matching. Returns
+// paginated results with match scores.
+func (c *Client) RepositorySearch(ctx context.Context, params RepositorySearchParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "repository/search", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RepositoryStats calls repository/stats: Get repository statistics.
+//
+// Returns aggregate statistics about the repository including file counts by
+// extension, largest files, and totals.
+func (c *Client) RepositoryStats(ctx context.Context) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "repository/stats", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionActiveParams are the params of session/active.
+type SessionActiveParams struct {
+	WorkspaceID string `json:"workspace_id,omitempty"`
+}
+
+// SessionActive calls session/active: List active sessions.
+//
+// Returns a list of all active sessions, optionally filtered by workspace.
+func (c *Client) SessionActive(ctx context.Context, params SessionActiveParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/active", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionBatchStartParams are the params of session/batch_start.
+type SessionBatchStartParams struct {
+	// Workspaces to start sessions in. Combined with 'tag' if both are given.
+	WorkspaceIDs []string `json:"workspace_ids,omitempty"`
+	// Select every workspace carrying this tag.
+	Tag    string `json:"tag,omitempty"`
+	Prompt string `json:"prompt"`
+	// Agent runtime type. One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+	// One of "default", "acceptEdits", "bypassPermissions", "plan", "interactive".
+	PermissionMode string `json:"permission_mode,omitempty"`
+	YoloMode       bool   `json:"yolo_mode,omitempty"`
+	MaxConcurrency int    `json:"max_concurrency,omitempty"`
+}
+
+// SessionBatchStart calls session/batch_start: Start sessions with the same
+// prompt in several workspaces.
+//
+// Starts a new session in each selected workspace and sends it the prompt,
+// with bounded concurrency. Returns immediately with a batch ID; progress is
+// reported via session_batch events and session/batch_status.
+func (c *Client) SessionBatchStart(ctx context.Context, params SessionBatchStartParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/batch_start", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionBatchStatusParams are the params of session/batch_status.
+type SessionBatchStatusParams struct {
+	BatchID string `json:"batch_id"`
+}
+
+// SessionBatchStatus calls session/batch_status: Get the progress of a batch
+// session start.
+//
+// Returns aggregate counts and per-workspace state of a session/batch_start
+// run.
+func (c *Client) SessionBatchStatus(ctx context.Context, params SessionBatchStatusParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/batch_status", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionElementsParams are the params of session/elements.
+type SessionElementsParams struct {
+	// Session ID to get elements from
+	SessionID string `json:"session_id"`
+	// Agent type (optional)
+	AgentType string `json:"agent_type,omitempty"`
+	// Maximum elements to return (default 50, max 100)
+	Limit int `json:"limit,omitempty"`
+	// Return elements before this ID (for pagination)
+	Before string `json:"before,omitempty"`
+	// Return elements after this ID (for catch-up)
+	After string `json:"after,omitempty"`
+}
+
+// SessionElements calls session/elements: Get session UI elements.
+//
+// Returns pre-parsed UI elements for a session, ready for rendering in mobile
+// apps.
+func (c *Client) SessionElements(ctx context.Context, params SessionElementsParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/elements", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionGetParams are the params of session/get.
+type SessionGetParams struct {
+	// Session ID to retrieve
+	SessionID string `json:"session_id"`
+	// Agent type (optional, searches all if not specified)
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// SessionGet calls session/get: Get session details.
+//
+// Returns detailed information about a specific session.
+func (c *Client) SessionGet(ctx context.Context, params SessionGetParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/get", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionHandoffParams are the params of session/handoff.
+type SessionHandoffParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	// Session to hand off.
+	SessionID string `json:"session_id"`
+	// Runtime of the source session. One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+	// Runtime to continue on. Defaults to the other runtime. One of "claude",
+	// "codex".
+	TargetAgentType string `json:"target_agent_type,omitempty"`
+	// Instruction for the target session, appended after the handoff context.
+	Prompt string `json:"prompt,omitempty"`
+	// One of "default", "acceptEdits", "bypassPermissions", "plan", "interactive".
+	PermissionMode string `json:"permission_mode,omitempty"`
+	YoloMode       bool   `json:"yolo_mode,omitempty"`
+}
+
+// SessionHandoff calls session/handoff: Continue a session's conversation on
+// another runtime.
+//
+// Condenses the transcript of a Claude or Codex session into a context
+// document, starts a new session on the other runtime seeded with it, and
+// links both sessions in history.
+func (c *Client) SessionHandoff(ctx context.Context, params SessionHandoffParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/handoff", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionHistoryParams are the params of session/history.
+type SessionHistoryParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Limit       int    `json:"limit,omitempty"`
+	// One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// SessionHistory calls session/history: Get historical sessions for a
+// workspace (legacy, use workspace/session/history).
+//
+// Returns historical sessions for the selected runtime in the specified
+// workspace.
+func (c *Client) SessionHistory(ctx context.Context, params SessionHistoryParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/history", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionInfoParams are the params of session/info.
+type SessionInfoParams struct {
+	SessionID string `json:"session_id"`
+}
+
+// SessionInfo calls session/info: Get session info.
+//
+// Returns detailed information about a specific session.
+func (c *Client) SessionInfo(ctx context.Context, params SessionInfoParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/info", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionInputParams are the params of session/input.
+type SessionInputParams struct {
+	SessionID string `json:"session_id"`
+	// Raw text input to send (e.g., '1' for Yes, '2' for Yes all, 'n' for No). A
+	// carriage return is auto-appended for text input.
+	Input string `json:"input,omitempty"`
+	// Special key name to send. Use 'enter' to confirm prompts, arrow keys for
+	// navigation. One of "enter", "escape", "up", "down", "left", "right", "tab",
+	// "backspace", "delete", "home", "end", "pageup", "pagedown", "space".
+	Key string `json:"key,omitempty"`
+	// Agent runtime type. One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// SessionInput calls session/input: Send input to an interactive session.
+//
+// Sends keyboard input to a session running in interactive (PTY) mode. Either
+// 'input' (raw text) or 'key' (special key name) must be provided.
+func (c *Client) SessionInput(ctx context.Context, params SessionInputParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/input", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionListParams are the params of session/list.
+type SessionListParams struct {
+	// Filter by agent type (optional)
+	AgentType string `json:"agent_type,omitempty"`
+	// Filter by workspace (optional)
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	// Filter by project path (optional, for Codex sessions)
+	ProjectPath string `json:"project_path,omitempty"`
+	// Maximum number of sessions to return
+	Limit int `json:"limit,omitempty"`
+}
+
+// SessionList calls session/list: List sessions.
+//
+// Returns a list of available sessions from all configured AI agents. For
+// Codex, sessions include rich metadata (git info, model, first prompt).
+func (c *Client) SessionList(ctx context.Context, params SessionListParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/list", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionMessagesParams are the params of session/messages.
+type SessionMessagesParams struct {
+	// Session ID to get messages from
+	SessionID string `json:"session_id"`
+	// Agent type (optional)
+	AgentType string `json:"agent_type,omitempty"`
+	// Maximum messages to return (default 50, max 500)
+	Limit int `json:"limit,omitempty"`
+	// Offset for pagination
+	Offset int `json:"offset,omitempty"`
+	// Sort order: 'asc' or 'desc' (default 'asc'). One of "asc", "desc".
+	Order string `json:"order,omitempty"`
+}
+
+// SessionMessages calls session/messages: Get session messages.
+//
+// Returns paginated messages for a specific session.
+func (c *Client) SessionMessages(ctx context.Context, params SessionMessagesParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/messages", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionRespondParams are the params of session/respond.
+type SessionRespondParams struct {
+	SessionID string `json:"session_id"`
+	// One of "permission", "question".
+	Type     string `json:"type"`
+	Response string `json:"response"`
+	// Agent runtime type. One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+	// Claude only: approve the pending permission with edited tool input. Claude
+	// is told to retry the call with this input.
+	UpdatedInput map[string]interface{} `json:"updated_input,omitempty"`
+}
+
+// SessionRespond calls session/respond: Respond to a permission or question.
+//
+// Responds to a pending permission request or interactive question for the
+// selected runtime.
+func (c *Client) SessionRespond(ctx context.Context, params SessionRespondParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/respond", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionSendParams are the params of session/send.
+type SessionSendParams struct {
+	// Session ID to send to. If empty, workspace_id must be provided to
+	// auto-create a session.
+	SessionID string `json:"session_id,omitempty"`
+	// Workspace ID. Required when session_id is empty to auto-create a new
+	// session.
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	Prompt      string `json:"prompt"`
+	// Session mode. 'new' starts fresh conversation (default), 'continue' resumes
+	// existing. One of "new", "continue".
+	Mode string `json:"mode,omitempty"`
+	// Permission handling mode. Use 'acceptEdits' to auto-accept file edits,
+	// 'bypassPermissions' to skip all permission checks, 'interactive' to use PTY
+	// mode for true terminal-like permission prompts. When omitted, the session's
+	// launch profile permission_mode applies; an explicit 'default' overrides it.
+	// One of "default", "acceptEdits", "bypassPermissions", "plan", "interactive".
+	PermissionMode string `json:"permission_mode,omitempty"`
+	// Runtime-agnostic bypass intent. Enables runtime-specific dangerous
+	// auto-approval flags when supported.
+	YoloMode bool `json:"yolo_mode,omitempty"`
+	// Agent runtime type. One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// SessionSend calls session/send: Send a prompt to a session.
+//
+// Sends a prompt to the selected runtime. If session_id is provided, sends to
+// that session. If only workspace_id is provided with mode='new', auto-creates
+// a new session and sends the prompt.
+func (c *Client) SessionSend(ctx context.Context, params SessionSendParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/send", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionSnapshotsParams are the params of session/snapshots.
+type SessionSnapshotsParams struct {
+	SessionID string `json:"session_id"`
+	// Workspace ID. Required for sessions not currently managed by cdev.
+	WorkspaceID string `json:"workspace_id,omitempty"`
+}
+
+// SessionSnapshots calls session/snapshots: List working-tree snapshots for a
+// session.
+//
+// Returns the snapshots captured at the start of each prompt turn, newest
+// first. Snapshots are stored as hidden git refs (refs/cdev/snapshots/*) and
+// never touch branches or the index.
+func (c *Client) SessionSnapshots(ctx context.Context, params SessionSnapshotsParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/snapshots", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionSnapshotsDiffParams are the params of session/snapshots/diff.
+type SessionSnapshotsDiffParams struct {
+	SessionID   string `json:"session_id"`
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	// Snapshot ID to diff from.
+	From string `json:"from"`
+	// Snapshot ID to diff to, or 'working_tree'.
+	To string `json:"to,omitempty"`
+}
+
+// SessionSnapshotsDiff calls session/snapshots/diff: Diff two session
+// snapshots.
+//
+// Compares two snapshots of a session. If 'to' is omitted (or 'working_tree'),
+// compares against the current working tree.
+func (c *Client) SessionSnapshotsDiff(ctx context.Context, params SessionSnapshotsDiffParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/snapshots/diff", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionSnapshotsRestoreParams are the params of session/snapshots/restore.
+type SessionSnapshotsRestoreParams struct {
+	SessionID   string `json:"session_id"`
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	SnapshotID  string `json:"snapshot_id"`
+}
+
+// SessionSnapshotsRestore calls session/snapshots/restore: Restore a session
+// snapshot.
+//
+// Rolls the working tree back to a snapshot. The current state is captured as
+// a backup snapshot first, and the index, HEAD and branches are left
+// untouched.
+func (c *Client) SessionSnapshotsRestore(ctx context.Context, params SessionSnapshotsRestoreParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/snapshots/restore", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionStartParams are the params of session/start.
+type SessionStartParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	// Optional session ID to attach to.
+	SessionID string `json:"session_id,omitempty"`
+	// Permission handling mode. Use 'bypassPermissions' to enable runtime-specific
+	// bypass flags when supported. When omitted, the launch profile's
+	// permission_mode applies; an explicit value, including 'default', replaces it
+	// for the session. One of "default", "acceptEdits", "bypassPermissions",
+	// "plan", "interactive".
+	PermissionMode string `json:"permission_mode,omitempty"`
+	// Runtime-agnostic bypass intent. Enables runtime-specific dangerous
+	// auto-approval flags when supported.
+	YoloMode bool `json:"yolo_mode,omitempty"`
+	// Agent runtime type. One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+	// Launch profile defined on the workspace (see initialize
+	// capabilities.launchProfiles). Always starts a new managed Claude session.
+	Profile string `json:"profile,omitempty"`
+}
+
+// SessionStart calls session/start: Start or attach to a session for a
+// workspace.
+//
+// Starts or attaches to a runtime session (Claude or Codex) for the workspace.
+func (c *Client) SessionStart(ctx context.Context, params SessionStartParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/start", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionStateParams are the params of session/state.
+type SessionStateParams struct {
+	SessionID string `json:"session_id"`
+}
+
+// SessionState calls session/state: Get session runtime state for
+// reconnection.
+//
+// Returns the full runtime state of a session including Claude state, pending
+// tool use, waiting status, and the latest resource sample of the agent
+// process tree (Linux). Use this to sync state when reconnecting from a mobile
+// device.
+func (c *Client) SessionState(ctx context.Context, params SessionStateParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/state", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionStopParams are the params of session/stop.
+type SessionStopParams struct {
+	SessionID string `json:"session_id"`
+	// Agent runtime type. One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// SessionStop calls session/stop: Stop a running session.
+//
+// Stops a running Claude or Codex session process.
+func (c *Client) SessionStop(ctx context.Context, params SessionStopParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/stop", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionUnwatchParams are the params of session/unwatch.
+type SessionUnwatchParams struct {
+	// Agent type to unwatch (optional, unwatchs all when omitted)
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// SessionUnwatch calls session/unwatch: Stop watching session.
+//
+// Stops watching the current session.
+func (c *Client) SessionUnwatch(ctx context.Context, params SessionUnwatchParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/unwatch", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionWatchParams are the params of session/watch.
+type SessionWatchParams struct {
+	// Session ID to watch
+	SessionID string `json:"session_id"`
+	// Agent type (optional)
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// SessionWatch calls session/watch: Start watching a session.
+//
+// Starts watching a session for real-time updates. The client will receive
+// notifications when new messages are added.
+func (c *Client) SessionWatch(ctx context.Context, params SessionWatchParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "session/watch", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ShutdownResult is the result of shutdown.
+type ShutdownResult struct {
+	Status string `json:"status"`
+}
+
+// Shutdown calls shutdown: Shutdown server.
+//
+// Request the server to shut down gracefully.
+func (c *Client) Shutdown(ctx context.Context) (*ShutdownResult, error) {
+	var result ShutdownResult
+	if err := c.invoke(ctx, "shutdown", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// StatusGet calls status/get: Get server status.
+//
+// Returns the current status of the cdev server including agent state,
+// connected clients, and configuration.
+func (c *Client) StatusGet(ctx context.Context) (*StatusResult, error) {
+	var result StatusResult
+	if err := c.invoke(ctx, "status/get", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// StatusHealth calls status/health: Health check.
+//
+// Returns a simple health check response for monitoring.
+func (c *Client) StatusHealth(ctx context.Context) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "status/health", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TaskCreateParams are the params of task/create.
+type TaskCreateParams struct {
+	// Workspace ID, name or path
+	WorkspaceID string `json:"workspace_id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Instructions for the agent; defaults to one built from title and description
+	Prompt string `json:"prompt,omitempty"`
+	// One of "fix-issue", "implement-cr", "add-test", "refactor", "auto-fix".
+	TaskType string `json:"task_type,omitempty"`
+	// One of "low", "medium", "high", "critical".
+	Severity string   `json:"severity,omitempty"`
+	Labels   []string `json:"labels,omitempty"`
+	// manual tasks wait for an explicit spawn. One of "manual", "supervised",
+	// "semi-auto", "full-auto-bounded".
+	Autonomy string `json:"autonomy,omitempty"`
+	// One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+	// Who asked for the task, recorded on the task
+	CreatedBy string `json:"created_by,omitempty"`
+}
+
+// TaskCreateResult is the result of task/create.
+type TaskCreateResult struct {
+	AutoSpawned bool   `json:"auto_spawned"`
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+}
+
+// TaskCreate calls task/create: Create an agent task.
+//
+// Creates an agent task in a workspace, like the task webhook. Unless autonomy
+// is manual, the task is spawned right away in its own worktree.
+func (c *Client) TaskCreate(ctx context.Context, params TaskCreateParams) (*TaskCreateResult, error) {
+	var result TaskCreateResult
+	if err := c.invoke(ctx, "task/create", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TaskGetParams are the params of task/get.
+type TaskGetParams struct {
+	TaskID string `json:"task_id"`
+}
+
+// TaskGet calls task/get: Get an agent task.
+//
+// Returns an agent task with its status, timeline and result.
+func (c *Client) TaskGet(ctx context.Context, params TaskGetParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "task/get", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceAddParams are the params of workspace/add.
+type WorkspaceAddParams struct {
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	AutoStart bool   `json:"auto_start,omitempty"`
+	// Create the directory if it doesn't exist
+	CreateIfMissing bool `json:"create_if_missing,omitempty"`
+}
+
+// WorkspaceAdd calls workspace/add: Add a new workspace.
+//
+// Registers a new workspace configuration. Can be any folder, not just git
+// repositories. Set create_if_missing to true to create the directory if it
+// doesn't exist.
+func (c *Client) WorkspaceAdd(ctx context.Context, params WorkspaceAddParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/add", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceCacheInvalidate calls workspace/cache/invalidate: Invalidate
+// discovery cache.
+//
+// Clears the cached repository discovery results. The next workspace/discover
+// call will perform a fresh scan.
+func (c *Client) WorkspaceCacheInvalidate(ctx context.Context) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/cache/invalidate", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceDiscoverParams are the params of workspace/discover.
+type WorkspaceDiscoverParams struct {
+	Paths []string `json:"paths,omitempty"`
+	Fresh bool     `json:"fresh,omitempty"`
+	// Token to receive $/progress notifications with
+	ProgressToken interface{} `json:"progress_token,omitempty"`
+}
+
+// WorkspaceDiscover calls workspace/discover: Discover git repositories.
+//
+// Scans directories to find git repositories that can be added as workspaces.
+//
+// Set ProgressToken to receive $/progress notifications.
+func (c *Client) WorkspaceDiscover(ctx context.Context, params WorkspaceDiscoverParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/discover", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceFileGetParams are the params of workspace/file/get.
+type WorkspaceFileGetParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Path        string `json:"path"`
+	MaxSizeKB   int    `json:"max_size_kb,omitempty"`
+}
+
+// WorkspaceFileGet calls workspace/file/get: Get file content from a
+// workspace.
+//
+// Returns the content of a file from a workspace. Use this for multi-workspace
+// mode instead of file/get.
+func (c *Client) WorkspaceFileGet(ctx context.Context, params WorkspaceFileGetParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/file/get", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceFilesListParams are the params of workspace/files/list.
+type WorkspaceFilesListParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Directory   string `json:"directory,omitempty"`
+	Limit       int    `json:"limit,omitempty"`
+	Offset      int    `json:"offset,omitempty"`
+}
+
+// WorkspaceFilesList calls workspace/files/list: List files in a workspace
+// directory.
+//
+// Returns a paginated list of files and directories in a workspace. Matches
+// the format of /api/repository/files/list.
+func (c *Client) WorkspaceFilesList(ctx context.Context, params WorkspaceFilesListParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/files/list", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceGetParams are the params of workspace/get.
+type WorkspaceGetParams struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// WorkspaceGet calls workspace/get: Get workspace details.
+//
+// Returns detailed information about a specific workspace.
+func (c *Client) WorkspaceGet(ctx context.Context, params WorkspaceGetParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/get", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceListParams are the params of workspace/list.
+type WorkspaceListParams struct {
+	// Include git status for each workspace (default: false)
+	IncludeGit bool `json:"include_git,omitempty"`
+	// Limit git status fetching to first N workspaces (0 = all)
+	GitLimit int `json:"git_limit,omitempty"`
+}
+
+// WorkspaceList calls workspace/list: List all workspaces.
+//
+// Returns a list of all configured workspaces. Optionally includes git status
+// for each workspace.
+func (c *Client) WorkspaceList(ctx context.Context, params WorkspaceListParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/list", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceRemoveParams are the params of workspace/remove.
+type WorkspaceRemoveParams struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// WorkspaceRemove calls workspace/remove: Remove a workspace.
+//
+// Unregisters a workspace configuration.
+func (c *Client) WorkspaceRemove(ctx context.Context, params WorkspaceRemoveParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/remove", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceSessionActivateParams are the params of workspace/session/activate.
+type WorkspaceSessionActivateParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	SessionID   string `json:"session_id"`
+}
+
+// WorkspaceSessionActivate calls workspace/session/activate: Set the active
+// session for a workspace.
+//
+// Sets the active session for a workspace. This allows iOS clients to switch
+// which session they are viewing/interacting with. The active session ID will
+// be included in workspace/list responses.
+func (c *Client) WorkspaceSessionActivate(ctx context.Context, params WorkspaceSessionActivateParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/session/activate", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceSessionDeleteParams are the params of workspace/session/delete.
+type WorkspaceSessionDeleteParams struct {
+	// Workspace ID
+	WorkspaceID string `json:"workspace_id"`
+	// Session ID (UUID) to delete
+	SessionID string `json:"session_id"`
+	// One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// WorkspaceSessionDelete calls workspace/session/delete: Delete a historical
+// session file.
+//
+// Deletes a runtime session file from local history storage for the specified
+// workspace.
+func (c *Client) WorkspaceSessionDelete(ctx context.Context, params WorkspaceSessionDeleteParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/session/delete", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceSessionHistoryParams are the params of workspace/session/history.
+type WorkspaceSessionHistoryParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	Limit       int    `json:"limit,omitempty"`
+	// One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// WorkspaceSessionHistory calls workspace/session/history: Get historical
+// sessions for a workspace.
+//
+// Returns historical sessions for the selected runtime in the specified
+// workspace.
+func (c *Client) WorkspaceSessionHistory(ctx context.Context, params WorkspaceSessionHistoryParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/session/history", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceSessionMessagesParams are the params of workspace/session/messages.
+type WorkspaceSessionMessagesParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	SessionID   string `json:"session_id"`
+	Limit       int    `json:"limit,omitempty"`
+	Offset      int    `json:"offset,omitempty"`
+	// One of "asc", "desc".
+	Order string `json:"order,omitempty"`
+	// One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// WorkspaceSessionMessages calls workspace/session/messages: Get messages from
+// a historical session.
+//
+// Returns paginated messages from a runtime session file for the specified
+// workspace.
+func (c *Client) WorkspaceSessionMessages(ctx context.Context, params WorkspaceSessionMessagesParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/session/messages", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceSessionUnwatchParams are the params of workspace/session/unwatch.
+type WorkspaceSessionUnwatchParams struct {
+	// One of "claude", "codex".
+	AgentType string `json:"agent_type"`
+	// Optional session ID to unwatch.
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// WorkspaceSessionUnwatch calls workspace/session/unwatch: Stop watching a
+// session.
+//
+// Stops watching a session for the selected runtime. If session_id is omitted,
+// legacy behavior removes one watched session deterministically.
+func (c *Client) WorkspaceSessionUnwatch(ctx context.Context, params WorkspaceSessionUnwatchParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/session/unwatch", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceSessionWatchParams are the params of workspace/session/watch.
+type WorkspaceSessionWatchParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	SessionID   string `json:"session_id"`
+	// One of "claude", "codex".
+	AgentType string `json:"agent_type,omitempty"`
+}
+
+// WorkspaceSessionWatch calls workspace/session/watch: Start watching a
+// session for real-time updates.
+//
+// Starts watching a session file for new messages for the selected runtime. A
+// client may watch multiple sessions concurrently.
+func (c *Client) WorkspaceSessionWatch(ctx context.Context, params WorkspaceSessionWatchParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/session/watch", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceStatusParams are the params of workspace/status.
+type WorkspaceStatusParams struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// WorkspaceStatus calls workspace/status: Get workspace status.
+//
+// Returns detailed status for a specific workspace including git tracker
+// state, active sessions, and watch status.
+func (c *Client) WorkspaceStatus(ctx context.Context, params WorkspaceStatusParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/status", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceSubscribeParams are the params of workspace/subscribe.
+type WorkspaceSubscribeParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	// Replay journaled events of the workspace after this sequence
+	SinceSeq int `json:"since_seq,omitempty"`
+	// Only receive these event types, e.g. ["claude_message", "pty_permission"]
+	EventTypes []string `json:"event_types,omitempty"`
+	// Never receive these event types, e.g. ["claude_log", "file_changed"]
+	ExcludeEventTypes []string `json:"exclude_event_types,omitempty"`
+	// Only receive session events of these sessions; events without a session
+	// still pass
+	SessionIDs []string `json:"session_ids,omitempty"`
+	// Payload fields per event type: {"git_diff": {"omit": ["diff"]}} or
+	// {"file_changed": {"fields": ["path", "change"]}}
+	Projections map[string]interface{} `json:"projections,omitempty"`
+}
+
+// WorkspaceSubscribe calls workspace/subscribe: Subscribe to workspace events.
+//
+// Subscribe to receive events for a specific workspace. By default, clients
+// receive all events. After calling this method, only events for subscribed
+// workspaces (and global events) will be forwarded. Workspace events carry a
+// per-workspace seq; pass the last seq seen as since_seq after a reconnect to
+// replay the missed events before live delivery resumes. If the journal no
+// longer holds them, a stream_gap event is sent instead and the client should
+// refetch state. event_types, exclude_event_types, session_ids and projections
+// narrow the events of the client and trim their payloads; each list given
+// replaces the previous one and applies to every workspace of the client.
+func (c *Client) WorkspaceSubscribe(ctx context.Context, params WorkspaceSubscribeParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/subscribe", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceSubscribeAll calls workspace/subscribeAll: Subscribe to all
+// workspace events.
+//
+// Clear workspace, event type and session filters and projections, and receive
+// events from all workspaces (default behavior).
+func (c *Client) WorkspaceSubscribeAll(ctx context.Context) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/subscribeAll", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceSubscriptions calls workspace/subscriptions: List workspace
+// subscriptions.
+//
+// Returns the list of workspaces this client is subscribed to and its event
+// filter. Empty list means all events are received.
+func (c *Client) WorkspaceSubscriptions(ctx context.Context) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/subscriptions", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceUnsubscribeParams are the params of workspace/unsubscribe.
+type WorkspaceUnsubscribeParams struct {
+	WorkspaceID string `json:"workspace_id"`
+}
+
+// WorkspaceUnsubscribe calls workspace/unsubscribe: Unsubscribe from workspace
+// events.
+//
+// Stop receiving events for a specific workspace.
+func (c *Client) WorkspaceUnsubscribe(ctx context.Context, params WorkspaceUnsubscribeParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/unsubscribe", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WorkspaceUpdateParams are the params of workspace/update.
+type WorkspaceUpdateParams struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	AutoStart bool   `json:"auto_start,omitempty"`
+	// Replaces the workspace tags (used by session/batch_start).
+	Tags []string `json:"tags,omitempty"`
+}
+
+// WorkspaceUpdate calls workspace/update: Update workspace settings.
+//
+// Updates workspace configuration settings.
+func (c *Client) WorkspaceUpdate(ctx context.Context, params WorkspaceUpdateParams) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.invoke(ctx, "workspace/update", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AgentRunResult is a result schema of the API.
+type AgentRunResult struct {
+	AgentType string `json:"agent_type"`
+	SessionID string `json:"session_id"`
+	Status    string `json:"status"`
+}
+
+// FileGetResult is a result schema of the API.
+type FileGetResult struct {
+	Content   string `json:"content"`
+	Path      string `json:"path"`
+	Size      int    `json:"size"`
+	Truncated bool   `json:"truncated"`
+}
+
+// StatusResult is a result schema of the API.
+type StatusResult struct {
+	AgentSessionID   string `json:"agent_session_id"`
+	AgentState       string `json:"agent_state"`
+	ConnectedClients int    `json:"connected_clients"`
+	RepoName         string `json:"repo_name"`
+	RepoPath         string `json:"repo_path"`
+	SessionID        string `json:"session_id"`
+	UptimeSeconds    int    `json:"uptime_seconds"`
+	Version          string `json:"version"`
+}
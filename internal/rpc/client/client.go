@@ -1,8 +1,19 @@
+// Package client is a JSON-RPC 2.0 client for the cdev WebSocket API.
+//
+// The typed methods of Client in api_gen.go are generated from the OpenRPC
+// metadata of the server's method registry; run go generate after changing
+// the metadata of a method.
 package client
 
+//go:generate go run ./gen -o api_gen.go
+
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"sync"
 	"time"
 
@@ -11,41 +22,101 @@ import (
 	"github.com/brianly1003/cdev/internal/rpc/message"
 )
 
+// ErrConnectionLost is returned by calls whose connection dropped before
+// the response arrived. They are not retried, as they may have run.
+var ErrConnectionLost = errors.New("connection lost")
+
+// ErrClosed is returned by calls on a closed client.
+var ErrClosed = errors.New("client closed")
+
+// Options configures a Client.
+type Options struct {
+	// Header is sent with the WebSocket handshake, e.g. an Authorization header.
+	Header http.Header
+
+	// Reconnect redials with backoff when the connection drops, then
+	// repeats initialize and resumes the workspace subscriptions from the
+	// last event seq received, so missed events are replayed.
+	Reconnect bool
+
+	// MaxBackoff caps the delay between redials. Defaults to 30s.
+	MaxBackoff time.Duration
+
+	// OnReconnect is called after a reconnect, once subscriptions are resumed.
+	OnReconnect func()
+}
+
 // Client is a JSON-RPC 2.0 client for WebSocket communication.
 type Client struct {
-	conn      *websocket.Conn
-	mu        sync.Mutex
-	nextID    int64
+	url  string
+	opts Options
+
+	mu     sync.Mutex // guards conn and writes to it
+	conn   *websocket.Conn
+	nextID int64
+
 	pending   map[int64]chan *message.Response
 	pendingMu sync.RWMutex
+
+	handlersMu sync.RWMutex
+	handlers   map[string]map[int]notificationHandler
+	nextHandle int
+	inbox      chan *message.Notification
+
+	resume resumeState
+
+	closeOnce sync.Once
 	closeCh   chan struct{}
 }
 
 // NewClient creates a new JSON-RPC client connected to the given WebSocket URL.
 func NewClient(url string) (*Client, error) {
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-	}
+	return Dial(url, Options{})
+}
 
-	conn, _, err := dialer.Dial(url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to %s: %w", url, err)
+// Dial connects a client to the given WebSocket URL.
+func Dial(url string, opts Options) (*Client, error) {
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 30 * time.Second
 	}
-
 	c := &Client{
-		conn:    conn,
-		nextID:  1,
-		pending: make(map[int64]chan *message.Response),
-		closeCh: make(chan struct{}),
+		url:      url,
+		opts:     opts,
+		nextID:   1,
+		pending:  make(map[int64]chan *message.Response),
+		handlers: make(map[string]map[int]notificationHandler),
+		inbox:    make(chan *message.Notification, 256),
+		resume:   newResumeState(),
+		closeCh:  make(chan struct{}),
 	}
 
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+
 	// Start reading responses in background
-	go c.readLoop()
+	go c.readLoop(conn)
+	go c.deliverLoop()
 
 	return c, nil
 }
 
+func (c *Client) dial() (*websocket.Conn, error) {
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+	}
+
+	conn, _, err := dialer.Dial(c.url, c.opts.Header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", c.url, err)
+	}
+	return conn, nil
+}
+
 // Call makes a JSON-RPC call and waits for the response.
+// If ctx is done first, the server is asked to cancel the request.
 func (c *Client) Call(ctx context.Context, method string, params interface{}) (*message.Response, error) {
 	// Generate request ID
 	c.mu.Lock()
@@ -66,10 +137,7 @@ func (c *Client) Call(ctx context.Context, method string, params interface{}) (*
 	c.pendingMu.Unlock()
 
 	// Send request
-	c.mu.Lock()
-	err = c.conn.WriteJSON(req)
-	c.mu.Unlock()
-	if err != nil {
+	if err := c.write(req); err != nil {
 		c.pendingMu.Lock()
 		delete(c.pending, id)
 		c.pendingMu.Unlock()
@@ -78,54 +146,182 @@ func (c *Client) Call(ctx context.Context, method string, params interface{}) (*
 
 	// Wait for response
 	select {
-	case resp := <-respCh:
+	case resp, ok := <-respCh:
+		if !ok {
+			return nil, ErrConnectionLost
+		}
+		if resp.Error == nil {
+			c.resume.record(method, req.Params)
+		}
 		return resp, nil
 	case <-ctx.Done():
 		c.pendingMu.Lock()
 		delete(c.pending, id)
 		c.pendingMu.Unlock()
+		_ = c.Notify(context.Background(), "$/cancelRequest", map[string]int64{"id": id})
 		return nil, ctx.Err()
 	case <-c.closeCh:
-		return nil, fmt.Errorf("connection closed")
+		return nil, ErrClosed
+	}
+}
+
+// Notify sends a JSON-RPC notification, which gets no response.
+func (c *Client) Notify(ctx context.Context, method string, params interface{}) error {
+	req, err := message.NewRequest(nil, method, params)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.write(req)
+}
+
+// invoke calls a method and decodes its result into result, if not nil.
+// Error responses are returned as *message.Error.
+func (c *Client) invoke(ctx context.Context, method string, params, result interface{}) error {
+	resp, err := c.Call(ctx, method, params)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", method, err)
+	}
+	return nil
+}
+
+func (c *Client) write(v interface{}) error {
+	select {
+	case <-c.closeCh:
+		return ErrClosed
+	default:
 	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(v)
 }
 
-// readLoop reads messages from the WebSocket connection.
-func (c *Client) readLoop() {
-	defer close(c.closeCh)
+// incoming is a message from the server: a response or a notification.
+type incoming struct {
+	ID     *message.ID     `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *message.Error  `json:"error"`
+}
 
+// readLoop reads messages from the WebSocket connection until it drops,
+// then reconnects if the options ask for it.
+func (c *Client) readLoop(conn *websocket.Conn) {
 	for {
-		var resp message.Response
-		err := c.conn.ReadJSON(&resp)
+		_, data, err := conn.ReadMessage()
 		if err != nil {
-			// Connection closed or error
-			c.pendingMu.Lock()
-			for _, ch := range c.pending {
-				close(ch)
+			break
+		}
+
+		// Notifications may arrive batched in an array
+		var msgs []incoming
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			if err := json.Unmarshal(trimmed, &msgs); err != nil {
+				continue
+			}
+		} else {
+			var msg incoming
+			if err := json.Unmarshal(data, &msg); err != nil {
+				continue
 			}
-			c.pending = make(map[int64]chan *message.Response)
-			c.pendingMu.Unlock()
+			msgs = append(msgs, msg)
+		}
+		for _, msg := range msgs {
+			c.route(msg)
+		}
+	}
+
+	// Connection closed or error: fail the calls waiting on it
+	c.pendingMu.Lock()
+	for _, ch := range c.pending {
+		close(ch)
+	}
+	c.pending = make(map[int64]chan *message.Response)
+	c.pendingMu.Unlock()
+
+	select {
+	case <-c.closeCh:
+		return
+	default:
+	}
+	if !c.opts.Reconnect {
+		c.shutdown()
+		return
+	}
+	c.reconnect()
+}
+
+// route hands a response to its caller and queues a notification for the
+// handlers.
+func (c *Client) route(msg incoming) {
+	if msg.Method != "" {
+		c.resume.observe(msg.Method, msg.Params)
+		select {
+		case c.inbox <- &message.Notification{JSONRPC: message.Version, Method: msg.Method, Params: msg.Params}:
+		case <-c.closeCh:
+		}
+		return
+	}
+
+	// Route response to waiting caller
+	// Extract int64 from ID (we always use NumberID)
+	idInt := idToInt64(msg.ID)
+	if idInt < 0 {
+		return
+	}
+	c.pendingMu.Lock()
+	ch, ok := c.pending[idInt]
+	delete(c.pending, idInt)
+	c.pendingMu.Unlock()
+	if ok {
+		ch <- &message.Response{JSONRPC: message.Version, ID: msg.ID, Result: msg.Result, Error: msg.Error}
+	}
+}
+
+// reconnect redials with exponential backoff until it succeeds or the
+// client is closed, then resumes the session on the new connection.
+func (c *Client) reconnect() {
+	backoff := 250 * time.Millisecond
+	for {
+		select {
+		case <-c.closeCh:
 			return
+		case <-time.After(backoff):
 		}
 
-		// Route response to waiting caller
-		if resp.ID != nil {
-			// Extract int64 from ID (we always use NumberID)
-			idInt := idToInt64(resp.ID)
-			if idInt >= 0 {
-				c.pendingMu.RLock()
-				ch, ok := c.pending[idInt]
-				c.pendingMu.RUnlock()
-
-				if ok {
-					ch <- &resp
-					c.pendingMu.Lock()
-					delete(c.pending, idInt)
-					c.pendingMu.Unlock()
-				}
+		conn, err := c.dial()
+		if err != nil {
+			backoff *= 2
+			if backoff > c.opts.MaxBackoff {
+				backoff = c.opts.MaxBackoff
 			}
+			continue
 		}
-		// Ignore notifications (no ID)
+
+		c.mu.Lock()
+		c.conn = conn
+		c.mu.Unlock()
+		go c.readLoop(conn)
+		go func() {
+			c.resume.replay(c)
+			if c.opts.OnReconnect != nil {
+				c.opts.OnReconnect()
+			}
+		}()
+		return
 	}
 }
 
@@ -144,8 +340,20 @@ func idToInt64(id *message.ID) int64 {
 	return n
 }
 
+// shutdown marks the client closed for good.
+func (c *Client) shutdown() {
+	c.closeOnce.Do(func() { close(c.closeCh) })
+}
+
+// Done returns a channel that is closed when the client is closed, or when
+// its connection drops and it does not reconnect.
+func (c *Client) Done() <-chan struct{} {
+	return c.closeCh
+}
+
 // Close closes the WebSocket connection.
 func (c *Client) Close() error {
+	c.shutdown()
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	return c.conn.Close()
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+)
+
+// testServer serves a registry over WebSocket like the unified server does.
+type testServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	conns []*testConn
+}
+
+type testConn struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (c *testConn) send(data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func newTestServer(t *testing.T, registry *handler.Registry) *testServer {
+	t.Helper()
+	s := &testServer{}
+	dispatcher := handler.NewDispatcher(registry)
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		c := &testConn{conn: conn}
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.mu.Unlock()
+
+		executor := handler.NewExecutor(dispatcher, 4)
+		defer executor.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			executor.Handle(context.Background(), data, c.send)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) url() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+// notify sends a notification on the latest connection.
+func (s *testServer) notify(t *testing.T, method string, params interface{}) {
+	t.Helper()
+	n, err := message.NewNotification(method, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(n)
+	s.mu.Lock()
+	c := s.conns[len(s.conns)-1]
+	s.mu.Unlock()
+	c.send(data)
+}
+
+// drop closes every connection of the server.
+func (s *testServer) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		c.conn.Close()
+	}
+}
+
+func TestClient_TypedCalls(t *testing.T) {
+	registry := handler.NewRegistry()
+	registry.Register("agent/respond", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		var p AgentRespondParams
+		_ = json.Unmarshal(params, &p)
+		return map[string]string{"status": "sent", "tool_use_id": p.ToolUseID}, nil
+	})
+	registry.Register("permission/rules/remove", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		return nil, message.ErrInvalidParams("rule_id is required")
+	})
+	s := newTestServer(t, registry)
+
+	c, err := NewClient(s.url())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx := context.Background()
+
+	result, err := c.AgentRespond(ctx, AgentRespondParams{ToolUseID: "toolu_1", Response: "yes"})
+	if err != nil {
+		t.Fatalf("AgentRespond: %v", err)
+	}
+	if result.Status != "sent" || result.ToolUseID != "toolu_1" {
+		t.Errorf("result = %+v", result)
+	}
+
+	_, err = c.PermissionRulesRemove(ctx, PermissionRulesRemoveParams{WorkspaceID: "ws-1"})
+	var rpcErr *message.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != message.InvalidParams {
+		t.Errorf("error = %v, want an InvalidParams *message.Error", err)
+	}
+}
+
+func TestClient_CancelsOnContextDone(t *testing.T) {
+	registry := handler.NewRegistry()
+	cancelled := make(chan struct{})
+	registry.RegisterWithMeta("session/state", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, message.ErrRequestCancelled()
+	}, handler.MethodMeta{Concurrency: handler.ConcurrencyParallel})
+	s := newTestServer(t, registry)
+
+	c, err := NewClient(s.url())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.SessionState(ctx, SessionStateParams{SessionID: "s-1"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want the context error", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server request was not cancelled")
+	}
+}
+
+func TestClient_ReconnectResumesSubscriptions(t *testing.T) {
+	registry := handler.NewRegistry()
+	subscribes := make(chan json.RawMessage, 4)
+	registry.Register("workspace/subscribe", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		subscribes <- params
+		return map[string]bool{"subscribed": true}, nil
+	})
+	s := newTestServer(t, registry)
+
+	reconnected := make(chan struct{}, 1)
+	c, err := Dial(s.url(), Options{Reconnect: true, OnReconnect: func() { reconnected <- struct{}{} }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	received := make(chan Event, 4)
+	c.OnEvent(events.EventTypeGitStatusChanged, func(e Event) { received <- e })
+
+	if _, err := c.WorkspaceSubscribe(context.Background(), WorkspaceSubscribeParams{WorkspaceID: "ws-1"}); err != nil {
+		t.Fatal(err)
+	}
+	<-subscribes
+	s.notify(t, "event/git_status_changed", map[string]interface{}{"workspace_id": "ws-1", "seq": 41, "branch": "main"})
+
+	var event Event
+	select {
+	case event = <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not delivered")
+	}
+	var payload struct{ Branch string }
+	if err := event.Decode(&payload); err != nil || payload.Branch != "main" || event.Seq != 41 || event.Type != events.EventTypeGitStatusChanged {
+		t.Errorf("event = %+v, payload %+v", event, payload)
+	}
+
+	s.drop()
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not reconnect")
+	}
+	select {
+	case params := <-subscribes:
+		var p WorkspaceSubscribeParams
+		_ = json.Unmarshal(params, &p)
+		if p.WorkspaceID != "ws-1" || p.SinceSeq != 41 {
+			t.Errorf("resubscribe params = %s, want ws-1 since_seq 41", params)
+		}
+	default:
+		t.Fatal("subscription not resumed")
+	}
+}
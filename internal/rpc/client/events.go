@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+)
+
+const eventPrefix = "event/"
+
+// notificationHandler receives the method and params of a notification.
+type notificationHandler func(method string, params json.RawMessage)
+
+// Event is a server event, received as an event/<type> notification.
+type Event struct {
+	Type        events.EventType `json:"-"`
+	WorkspaceID string           `json:"workspace_id,omitempty"`
+	SessionID   string           `json:"session_id,omitempty"`
+	AgentType   string           `json:"agent_type,omitempty"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Seq         uint64           `json:"seq,omitempty"`
+
+	// Params holds the notification params: the payload fields of the
+	// event next to the fields above.
+	Params json.RawMessage `json:"-"`
+}
+
+// Decode decodes the event payload into v.
+func (e Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Params, v)
+}
+
+// OnNotification calls fn with the params of every notification of method,
+// e.g. "$/progress". Handlers run one at a time, in the order the
+// notifications arrive, and may call the client. The returned function
+// removes the handler.
+func (c *Client) OnNotification(method string, fn func(params json.RawMessage)) (remove func()) {
+	return c.handle(method, func(_ string, params json.RawMessage) { fn(params) })
+}
+
+// handle registers fn for the notifications of method; "event/*" matches
+// every event.
+func (c *Client) handle(method string, fn notificationHandler) (remove func()) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	c.nextHandle++
+	handle := c.nextHandle
+	if c.handlers[method] == nil {
+		c.handlers[method] = make(map[int]notificationHandler)
+	}
+	c.handlers[method][handle] = fn
+
+	return func() {
+		c.handlersMu.Lock()
+		defer c.handlersMu.Unlock()
+		delete(c.handlers[method], handle)
+	}
+}
+
+// OnEvent calls fn with every event of the given type, or with every event
+// when eventType is empty. Which events the server sends is set with
+// WorkspaceSubscribe and the other workspace/subscribe* methods.
+func (c *Client) OnEvent(eventType events.EventType, fn func(Event)) (remove func()) {
+	method := eventPrefix + string(eventType)
+	if eventType == "" {
+		method = eventPrefix + "*"
+	}
+	return c.handle(method, func(method string, params json.RawMessage) {
+		var event Event
+		_ = json.Unmarshal(params, &event)
+		event.Type = events.EventType(strings.TrimPrefix(method, eventPrefix))
+		event.Params = params
+		fn(event)
+	})
+}
+
+// deliverLoop runs the notification handlers until the client is closed.
+func (c *Client) deliverLoop() {
+	for {
+		select {
+		case n := <-c.inbox:
+			fns := c.handlersFor(n.Method)
+			for _, fn := range fns {
+				fn(n.Method, n.Params)
+			}
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+// handlersFor returns the handlers of a notification method, in the order
+// they were registered.
+func (c *Client) handlersFor(method string) []notificationHandler {
+	c.handlersMu.RLock()
+	defer c.handlersMu.RUnlock()
+
+	handles := make([]int, 0)
+	byHandle := make(map[int]notificationHandler)
+	for _, key := range []string{method, eventPrefix + "*"} {
+		if key != method && !strings.HasPrefix(method, eventPrefix) {
+			continue
+		}
+		for handle, fn := range c.handlers[key] {
+			handles = append(handles, handle)
+			byHandle[handle] = fn
+		}
+	}
+	sort.Ints(handles)
+
+	fns := make([]notificationHandler, len(handles))
+	for i, handle := range handles {
+		fns[i] = byHandle[handle]
+	}
+	return fns
+}
+
+// resumeState tracks the per-connection state a reconnect restores: the
+// initialize params, the delivery policy and the workspace subscriptions,
+// with the last event seq received for each workspace.
+type resumeState struct {
+	mu            sync.Mutex
+	initialize    json.RawMessage
+	delivery      json.RawMessage
+	subscriptions map[string]json.RawMessage // workspace ID -> workspace/subscribe params
+	seqs          map[string]uint64
+}
+
+func newResumeState() resumeState {
+	return resumeState{
+		subscriptions: make(map[string]json.RawMessage),
+		seqs:          make(map[string]uint64),
+	}
+}
+
+// record notes a successful call that changes the state of the connection.
+func (s *resumeState) record(method string, params json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch method {
+	case "initialize":
+		s.initialize = params
+	case "client/delivery":
+		s.delivery = params
+	case "workspace/subscribe":
+		if id := workspaceID(params); id != "" {
+			s.subscriptions[id] = params
+		}
+	case "workspace/unsubscribe":
+		delete(s.subscriptions, workspaceID(params))
+	case "workspace/subscribeAll":
+		s.subscriptions = make(map[string]json.RawMessage)
+	}
+}
+
+// observe notes the seq of a workspace event.
+func (s *resumeState) observe(method string, params json.RawMessage) {
+	if !strings.HasPrefix(method, eventPrefix) {
+		return
+	}
+	var event struct {
+		WorkspaceID string `json:"workspace_id"`
+		Seq         uint64 `json:"seq"`
+	}
+	if json.Unmarshal(params, &event) != nil || event.WorkspaceID == "" || event.Seq == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if event.Seq > s.seqs[event.WorkspaceID] {
+		s.seqs[event.WorkspaceID] = event.Seq
+	}
+}
+
+// replay restores the recorded state on a new connection. Subscriptions
+// ask for the events after the last seq received, which the server replays
+// from its journal, or answers with a stream_gap event when it no longer
+// holds them.
+func (s *resumeState) replay(c *Client) {
+	s.mu.Lock()
+	initialize, delivery := s.initialize, s.delivery
+	ids := make([]string, 0, len(s.subscriptions))
+	for id := range s.subscriptions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	subscriptions := make([]json.RawMessage, 0, len(ids))
+	for _, id := range ids {
+		subscriptions = append(subscriptions, withSinceSeq(s.subscriptions[id], s.seqs[id]))
+	}
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if initialize != nil {
+		_, _ = c.Call(ctx, "initialize", initialize)
+	}
+	if delivery != nil {
+		_, _ = c.Call(ctx, "client/delivery", delivery)
+	}
+	for _, params := range subscriptions {
+		_, _ = c.Call(ctx, "workspace/subscribe", params)
+	}
+}
+
+// withSinceSeq returns subscribe params that resume after seq.
+func withSinceSeq(params json.RawMessage, seq uint64) json.RawMessage {
+	if seq == 0 {
+		return params
+	}
+	var p map[string]interface{}
+	if json.Unmarshal(params, &p) != nil {
+		return params
+	}
+	p["since_seq"] = seq
+	data, err := json.Marshal(p)
+	if err != nil {
+		return params
+	}
+	return data
+}
+
+func workspaceID(params json.RawMessage) string {
+	var p struct {
+		WorkspaceID string `json:"workspace_id"`
+	}
+	_ = json.Unmarshal(params, &p)
+	return p.WorkspaceID
+}
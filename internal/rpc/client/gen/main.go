@@ -0,0 +1,349 @@
+// Command gen generates the typed methods of the JSON-RPC client from the
+// OpenRPC metadata of the server's method registry.
+//
+// Usage, from internal/rpc/client:
+//
+//	go generate
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/handler/methods"
+)
+
+func main() {
+	out := flag.String("o", "api_gen.go", "output file")
+	flag.Parse()
+
+	src, err := generate(serverSpec())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+}
+
+// serverSpec returns the OpenRPC document of the methods the server serves.
+func serverSpec() *handler.OpenRPCSpec {
+	registry := handler.NewRegistry()
+	methods.SchemaServices().Register(registry)
+	return registry.GenerateOpenRPC(handler.OpenRPCInfo{}, "")
+}
+
+// generator writes the client source for one OpenRPC document.
+type generator struct {
+	spec  *handler.OpenRPCSpec
+	buf   bytes.Buffer
+	names map[string]string // Go type name -> what declared it
+	types []string          // component types referenced by results, declared last
+}
+
+// generate returns the formatted Go source of the typed client methods.
+func generate(spec *handler.OpenRPCSpec) ([]byte, error) {
+	g := &generator{spec: spec, names: make(map[string]string)}
+
+	list := append([]handler.OpenRPCMethod(nil), spec.Methods...)
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	for _, method := range list {
+		if err := g.method(method); err != nil {
+			return nil, err
+		}
+	}
+	for i := 0; i < len(g.types); i++ {
+		name := g.types[i]
+		g.printf("\n// %s is a result schema of the API.\n", name)
+		g.structType(name, g.spec.Components.Schemas[name].(map[string]interface{}))
+	}
+
+	var file bytes.Buffer
+	file.WriteString("// Code generated by go run ./gen; DO NOT EDIT.\n\n")
+	file.WriteString("package client\n\n")
+	if bytes.Contains(g.buf.Bytes(), []byte("json.RawMessage")) {
+		file.WriteString("import (\n\t\"context\"\n\t\"encoding/json\"\n)\n")
+	} else {
+		file.WriteString("import \"context\"\n")
+	}
+	file.Write(g.buf.Bytes())
+
+	src, err := format.Source(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w", err)
+	}
+	return src, nil
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// declare reserves a Go type name, failing when two methods map to it.
+func (g *generator) declare(name, by string) error {
+	if prev, ok := g.names[name]; ok && prev != by {
+		return fmt.Errorf("%s and %s both generate type %s", prev, by, name)
+	}
+	g.names[name] = by
+	return nil
+}
+
+// method writes the params and result types and the client method of an
+// OpenRPC method.
+func (g *generator) method(m handler.OpenRPCMethod) error {
+	name := goName(m.Name)
+
+	paramsType := ""
+	if len(m.Params) > 0 {
+		paramsType = name + "Params"
+		if err := g.declare(paramsType, m.Name); err != nil {
+			return err
+		}
+		g.printf("\n// %s are the params of %s.\n", paramsType, m.Name)
+		g.printf("type %s struct {\n", paramsType)
+		for _, param := range m.Params {
+			g.field(param.Name, param.Schema, param.Description, param.Required, true)
+		}
+		g.printf("}\n")
+	}
+
+	resultType, err := g.resultType(name, m)
+	if err != nil {
+		return err
+	}
+
+	g.printf("\n")
+	g.comment("", name+" calls "+m.Name+sentence(m.Summary, ": "))
+	if m.Description != "" {
+		g.printf("//\n")
+		g.comment("", m.Description)
+	}
+	if m.Progress {
+		g.printf("//\n// Set ProgressToken to receive $/progress notifications.\n")
+	}
+
+	args := "ctx context.Context"
+	params := "nil"
+	if paramsType != "" {
+		args += ", params " + paramsType
+		params = "params"
+	}
+	switch {
+	case m.Result == nil:
+		g.printf("func (c *Client) %s(%s) error {\n", name, args)
+		g.printf("\treturn c.invoke(ctx, %q, %s, nil)\n}\n", m.Name, params)
+	case resultType == "json.RawMessage":
+		g.printf("func (c *Client) %s(%s) (json.RawMessage, error) {\n", name, args)
+		g.printf("\tvar result json.RawMessage\n")
+		g.printf("\tif err := c.invoke(ctx, %q, %s, &result); err != nil {\n\t\treturn nil, err\n\t}\n", m.Name, params)
+		g.printf("\treturn result, nil\n}\n")
+	default:
+		g.printf("func (c *Client) %s(%s) (*%s, error) {\n", name, args, resultType)
+		g.printf("\tvar result %s\n", resultType)
+		g.printf("\tif err := c.invoke(ctx, %q, %s, &result); err != nil {\n\t\treturn nil, err\n\t}\n", m.Name, params)
+		g.printf("\treturn &result, nil\n}\n")
+	}
+	return nil
+}
+
+// resultType returns the Go type of a method result: a struct when the
+// schema lists its properties, inline or as a component, and raw JSON
+// otherwise.
+func (g *generator) resultType(name string, m handler.OpenRPCMethod) (string, error) {
+	if m.Result == nil {
+		return "", nil
+	}
+	schema := m.Result.Schema
+	if ref, ok := schema["$ref"].(string); ok {
+		component := strings.TrimPrefix(ref, "#/components/schemas/")
+		resolved, ok := g.spec.Components.Schemas[component].(map[string]interface{})
+		if !ok || resolved["properties"] == nil {
+			return "json.RawMessage", nil
+		}
+		if _, declared := g.names[component]; !declared {
+			g.types = append(g.types, component)
+		}
+		return component, g.declare(component, ref)
+	}
+	if schema["properties"] == nil {
+		return "json.RawMessage", nil
+	}
+
+	resultType := name + "Result"
+	if err := g.declare(resultType, m.Name); err != nil {
+		return "", err
+	}
+	g.printf("\n// %s is the result of %s.\n", resultType, m.Name)
+	g.structType(resultType, schema)
+	return resultType, nil
+}
+
+// structType writes a struct type for an object schema.
+func (g *generator) structType(name string, schema map[string]interface{}) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	if list, ok := schema["required"].([]string); ok {
+		for _, r := range list {
+			required[r] = true
+		}
+	}
+
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	g.printf("type %s struct {\n", name)
+	for _, k := range keys {
+		property, _ := properties[k].(map[string]interface{})
+		description, _ := property["description"].(string)
+		g.field(k, property, description, required[k], false)
+	}
+	g.printf("}\n")
+}
+
+// field writes a struct field for a param or property. Optional params
+// are omitted when zero, except booleans that default to true, which are
+// pointers so that false can be sent.
+func (g *generator) field(name string, schema map[string]interface{}, description string, required, isParam bool) {
+	if description == "" {
+		description, _ = schema["description"].(string)
+	}
+	if enum := enumValues(schema["enum"]); enum != "" {
+		description = sentence(description, "") + " One of " + enum + "."
+	}
+	if description != "" {
+		g.comment("\t", strings.TrimSpace(description))
+	}
+
+	typ := goType(schema)
+	tag := name
+	if isParam && !required {
+		tag += ",omitempty"
+		if typ == "bool" && schema["default"] == true {
+			typ = "*bool"
+		}
+	}
+	g.printf("\t%s %s `json:%q`\n", goName(name), typ, tag)
+}
+
+// comment writes text as a comment wrapped at 76 columns.
+func (g *generator) comment(indent, text string) {
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > 76 {
+			g.printf("%s// %s\n", indent, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		g.printf("%s// %s\n", indent, line)
+	}
+}
+
+// goType returns the Go type of a JSON Schema.
+func goType(schema map[string]interface{}) string {
+	t, _ := schema["type"].(string)
+	switch t {
+	case "string":
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			return "[]" + goType(items)
+		}
+		return "[]interface{}"
+	case "object":
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+// enumValues lists the values of an enum keyword as Go string literals.
+func enumValues(enum interface{}) string {
+	values, ok := enum.([]string)
+	if !ok || len(values) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sentence returns text with a final period, after prefix, or "" for no text.
+func sentence(text, prefix string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	if !strings.HasSuffix(text, ".") {
+		text += "."
+	}
+	return prefix + text
+}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"api": true, "cpu": true, "fts": true, "http": true, "id": true, "ids": true,
+	"ip": true, "json": true, "kb": true, "mcp": true, "pid": true, "pty": true,
+	"sse": true, "ttl": true, "ui": true, "uri": true, "url": true, "uuid": true,
+}
+
+// goName converts a method or param name such as "git/stash_list" or
+// "clientInfo" to an exported Go name.
+func goName(name string) string {
+	var words []string
+	word := []rune{}
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+	for i, r := range name {
+		switch {
+		case r == '/' || r == '_' || r == '-' || r == '.' || r == '$':
+			flush()
+		case unicode.IsUpper(r) && i > 0:
+			flush()
+			word = append(word, unicode.ToLower(r))
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		switch {
+		case w == "ids":
+			b.WriteString("IDs")
+		case initialisms[w]:
+			b.WriteString(strings.ToUpper(w))
+		default:
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+)
+
+// TestGeneratedClientUpToDate fails when the method metadata of the server
+// and the generated client drift apart.
+func TestGeneratedClientUpToDate(t *testing.T) {
+	want, err := generate(serverSpec())
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	got, err := os.ReadFile("../api_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("internal/rpc/client/api_gen.go is out of date with the server's method metadata; run go generate ./internal/rpc/client")
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"git/status":               "GitStatus",
+		"permission/respond_batch": "PermissionRespondBatch",
+		"workspace/subscribeAll":   "WorkspaceSubscribeAll",
+		"protocolVersion":          "ProtocolVersion",
+		"tool_use_id":              "ToolUseID",
+		"session_ids":              "SessionIDs",
+		"max_size_kb":              "MaxSizeKB",
+	}
+	for in, want := range tests {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerate_DuplicateTypeName(t *testing.T) {
+	spec := &handler.OpenRPCSpec{Methods: []handler.OpenRPCMethod{
+		{Name: "git/stash_list", Params: []handler.OpenRPCParam{{Name: "a", Schema: map[string]interface{}{"type": "string"}}}},
+		{Name: "git/stash/list", Params: []handler.OpenRPCParam{{Name: "a", Schema: map[string]interface{}{"type": "string"}}}},
+	}}
+	if _, err := generate(spec); err == nil {
+		t.Error("methods mapping to the same Go name were generated")
+	}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// Concurrency says how a method may run next to the other requests of a
+// connection. Set it in MethodMeta.
+type Concurrency int
+
+const (
+	// ConcurrencyOrdered runs a request after the ordered requests its
+	// connection sent before it. It is the default, so methods with side
+	// effects, such as session/send, keep the order the client sent them in.
+	ConcurrencyOrdered Concurrency = iota
+
+	// ConcurrencyParallel runs a request as soon as a worker is free, for
+	// methods that only read state or that block for long, like
+	// permission/request.
+	ConcurrencyParallel
+
+	// ConcurrencyWorkspace runs one request at a time per workspace_id,
+	// across all connections. A connection's requests to a workspace keep
+	// their order but do not wait for its other requests. For slow writes
+	// to a repository, such as git/fetch or git/commit.
+	ConcurrencyWorkspace
+)
+
+// String returns the name used in the OpenRPC spec.
+func (c Concurrency) String() string {
+	switch c {
+	case ConcurrencyParallel:
+		return "parallel"
+	case ConcurrencyWorkspace:
+		return "workspace"
+	default:
+		return "ordered"
+	}
+}
+
+// Concurrency returns the concurrency hint of a method.
+func (r *Registry) Concurrency(method string) Concurrency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.meta[method].Concurrency
+}
+
+// workspaceKey returns the workspace_id param of a request. Requests
+// without one share the empty key.
+func workspaceKey(params json.RawMessage) string {
+	var p struct {
+		WorkspaceID string `json:"workspace_id"`
+	}
+	_ = json.Unmarshal(params, &p)
+	return p.WorkspaceID
+}
+
+// keyedLocks is a set of mutexes created on demand, one per key. Waiting for
+// a lock can be cancelled.
+type keyedLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	held chan struct{}
+	refs int
+}
+
+// lock locks key, or returns ctx's error if ctx is done first.
+func (k *keyedLocks) lock(ctx context.Context, key string) (unlock func(), err error) {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyedLock)
+	}
+	l := k.locks[key]
+	if l == nil {
+		l = &keyedLock{held: make(chan struct{}, 1)}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	select {
+	case l.held <- struct{}{}:
+		return func() {
+			<-l.held
+			k.release(key, l)
+		}, nil
+	case <-ctx.Done():
+		k.release(key, l)
+		return nil, ctx.Err()
+	}
+}
+
+func (k *keyedLocks) release(key string, l *keyedLock) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	l.refs--
+	if l.refs == 0 {
+		delete(k.locks, key)
+	}
+}
@@ -10,7 +10,8 @@ import (
 
 // Dispatcher routes JSON-RPC requests to registered handlers.
 type Dispatcher struct {
-	registry *Registry
+	registry       *Registry
+	workspaceLocks keyedLocks // Serialise ConcurrencyWorkspace methods
 }
 
 // NewDispatcher creates a new dispatcher with the given registry.
@@ -39,6 +40,21 @@ func (d *Dispatcher) Dispatch(ctx context.Context, req *message.Request) *messag
 		return message.NewErrorResponse(req.ID, message.ErrMethodNotFound(req.Method))
 	}
 
+	meta := d.registry.GetMeta(req.Method)
+	if meta.Progress {
+		ctx = withProgress(ctx, req.Params)
+	}
+
+	// Methods that write to a repository run one at a time per workspace,
+	// whichever connection sent them
+	if meta.Concurrency == ConcurrencyWorkspace {
+		unlock, err := d.workspaceLocks.lock(ctx, workspaceKey(req.Params))
+		if err != nil {
+			return cancelledResponse(req)
+		}
+		defer unlock()
+	}
+
 	// Execute handler
 	result, rpcErr := handler(ctx, req.Params)
 
@@ -106,18 +122,17 @@ func (d *Dispatcher) DispatchBytes(ctx context.Context, data []byte) ([]byte, er
 	return json.Marshal(resp)
 }
 
-// BatchDispatch handles a batch of JSON-RPC requests.
-// Returns a slice of responses (some may be nil for notifications).
+// BatchDispatch handles a batch of JSON-RPC requests concurrently, following
+// the Concurrency hints of the methods. Returns the responses in request
+// order, without entries for notifications.
 func (d *Dispatcher) BatchDispatch(ctx context.Context, requests []*message.Request) []*message.Response {
-	responses := make([]*message.Response, 0, len(requests))
-
-	for _, req := range requests {
-		resp := d.Dispatch(ctx, req)
-		if resp != nil {
-			responses = append(responses, resp)
-		}
-	}
-
+	e := NewExecutor(d, DefaultWorkers)
+	defer e.Close()
+	var responses []*message.Response
+	e.scheduleAll(ctx, requests, make([]*message.Response, len(requests)), func(out []*message.Response) {
+		responses = out
+	})
+	e.Wait()
 	return responses
 }
 
@@ -132,42 +147,17 @@ func (d *Dispatcher) HandleMessage(ctx context.Context, data []byte) ([]byte, er
 	return d.DispatchBytes(ctx, data)
 }
 
-// handleBatch handles a batch request.
+// handleBatch handles a batch request. Its entries run concurrently like
+// those of a connection; see Executor.
 func (d *Dispatcher) handleBatch(ctx context.Context, data []byte) ([]byte, error) {
-	var rawRequests []json.RawMessage
-	if err := json.Unmarshal(data, &rawRequests); err != nil {
-		resp := message.NewErrorResponse(nil, message.ErrParseError("Invalid batch request"))
-		return json.Marshal(resp)
-	}
-
-	if len(rawRequests) == 0 {
-		resp := message.NewErrorResponse(nil, message.ErrInvalidRequest("Empty batch"))
-		return json.Marshal(resp)
-	}
-
-	// Parse and dispatch each request
-	responses := make([]*message.Response, 0, len(rawRequests))
-
-	for _, rawReq := range rawRequests {
-		req, err := message.ParseRequest(rawReq)
-		if err != nil {
-			// Invalid request in batch - add error response
-			responses = append(responses, message.NewErrorResponse(nil, message.ErrParseError(err.Error())))
-			continue
-		}
-
-		resp := d.Dispatch(ctx, req)
-		if resp != nil {
-			responses = append(responses, resp)
-		}
-	}
-
-	// If all requests were notifications, return nothing
-	if len(responses) == 0 {
-		return nil, nil
-	}
-
-	return json.Marshal(responses)
+	e := NewExecutor(d, DefaultWorkers)
+	defer e.Close()
+	var response []byte
+	e.Handle(ctx, data, func(data []byte) {
+		response = data
+	})
+	e.Wait()
+	return response, nil
 }
 
 // Registry returns the underlying registry.
@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/rs/zerolog/log"
+)
+
+// CancelRequestMethod is the notification a client sends to cancel one of
+// its requests: {"method":"$/cancelRequest","params":{"id":<request id>}}.
+const CancelRequestMethod = "$/cancelRequest"
+
+// DefaultWorkers is how many requests of a connection run at once.
+const DefaultWorkers = 8
+
+// Executor runs the requests of one connection concurrently on a bounded
+// number of workers, following the Concurrency hint of each method:
+// ordered requests run one after the other in the order they arrived, and
+// so do the requests to the same workspace; parallel ones run as soon as a
+// worker is free. It handles $/cancelRequest by cancelling the context of
+// the request.
+type Executor struct {
+	dispatcher *Dispatcher
+	slots      chan struct{}
+
+	ctx       context.Context // Cancelled by Close
+	cancelAll context.CancelFunc
+
+	mu       sync.Mutex
+	chains   map[string]chan struct{} // Closed when the last request of a chain is done
+	inflight map[string]*inflightRequest
+
+	wg sync.WaitGroup
+}
+
+// inflightRequest is a request that can still be cancelled.
+type inflightRequest struct {
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// NewExecutor creates an executor for one connection.
+func NewExecutor(dispatcher *Dispatcher, workers int) *Executor {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Executor{
+		dispatcher: dispatcher,
+		slots:      make(chan struct{}, workers),
+		ctx:        ctx,
+		cancelAll:  cancel,
+		chains:     make(map[string]chan struct{}),
+		inflight:   make(map[string]*inflightRequest),
+	}
+}
+
+// Handle handles a single message or a batch without waiting for it to
+// run. reply is called with the response, from another goroutine, unless
+// the message needs none. Responses of a batch come in one reply, in the
+// order of the batch.
+func (e *Executor) Handle(ctx context.Context, data []byte, reply func([]byte)) {
+	// Handlers can report progress on the connection while they run
+	ctx = withNotifier(ctx, reply)
+
+	send := func(v interface{}) {
+		out, err := json.Marshal(v)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to marshal response")
+			return
+		}
+		reply(out)
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var rawRequests []json.RawMessage
+		if err := json.Unmarshal(trimmed, &rawRequests); err != nil {
+			send(message.NewErrorResponse(nil, message.ErrParseError("Invalid batch request")))
+			return
+		}
+		if len(rawRequests) == 0 {
+			send(message.NewErrorResponse(nil, message.ErrInvalidRequest("Empty batch")))
+			return
+		}
+		e.handleBatch(ctx, rawRequests, func(responses []*message.Response) {
+			if len(responses) > 0 {
+				send(responses)
+			}
+		})
+		return
+	}
+
+	req, err := message.ParseRequest(data)
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to parse request")
+		send(message.NewErrorResponse(nil, message.ErrParseError(err.Error())))
+		return
+	}
+	if req.Method == CancelRequestMethod {
+		e.cancelRequest(req.Params)
+		return
+	}
+	e.schedule(ctx, req, func(resp *message.Response) {
+		if resp != nil {
+			send(resp)
+		}
+	})
+}
+
+// handleBatch schedules the entries of a batch in order, so that ordered
+// entries run in batch order, and calls done with the responses once all
+// entries are done.
+func (e *Executor) handleBatch(ctx context.Context, rawRequests []json.RawMessage, done func([]*message.Response)) {
+	requests := make([]*message.Request, len(rawRequests))
+	responses := make([]*message.Response, len(rawRequests))
+	for i, raw := range rawRequests {
+		req, err := message.ParseRequest(raw)
+		if err != nil {
+			// Invalid request in batch - add error response
+			responses[i] = message.NewErrorResponse(nil, message.ErrParseError(err.Error()))
+			continue
+		}
+		if req.Method == CancelRequestMethod {
+			e.cancelRequest(req.Params)
+			continue
+		}
+		requests[i] = req
+	}
+	e.scheduleAll(ctx, requests, responses, done)
+}
+
+// scheduleAll schedules the non-nil requests and calls done with responses
+// filled in and stripped of nil entries once they are all done.
+func (e *Executor) scheduleAll(ctx context.Context, requests []*message.Request, responses []*message.Response, done func([]*message.Response)) {
+	var mu sync.Mutex
+	remaining := 1 // Held until every request is scheduled
+	finish := func() {
+		mu.Lock()
+		remaining--
+		last := remaining == 0
+		mu.Unlock()
+		if !last {
+			return
+		}
+		out := make([]*message.Response, 0, len(responses))
+		for _, resp := range responses {
+			if resp != nil {
+				out = append(out, resp)
+			}
+		}
+		done(out)
+	}
+
+	for i, req := range requests {
+		if req == nil {
+			continue
+		}
+		mu.Lock()
+		remaining++
+		mu.Unlock()
+		e.schedule(ctx, req, func(resp *message.Response) {
+			mu.Lock()
+			responses[i] = resp
+			mu.Unlock()
+			finish()
+		})
+	}
+	finish()
+}
+
+// schedule runs a request in its own goroutine and calls done with its
+// response, nil for notifications.
+func (e *Executor) schedule(ctx context.Context, req *message.Request, done func(*message.Response)) {
+	reqCtx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(e.ctx, cancel)
+
+	// Ordered requests chain up in arrival order, and so do the requests
+	// to each workspace
+	var chain string
+	var prev <-chan struct{}
+	var finished chan struct{}
+	switch e.dispatcher.registry.Concurrency(req.Method) {
+	case ConcurrencyOrdered:
+		chain = "ordered"
+	case ConcurrencyWorkspace:
+		chain = "workspace:" + workspaceKey(req.Params)
+	}
+	if chain != "" {
+		finished = make(chan struct{})
+		e.mu.Lock()
+		prev = e.chains[chain]
+		e.chains[chain] = finished
+		e.mu.Unlock()
+	}
+
+	var key string
+	entry := &inflightRequest{cancel: cancel}
+	if !req.IsNotification() {
+		key = requestKey(req.ID)
+		e.mu.Lock()
+		e.inflight[key] = entry
+		e.mu.Unlock()
+	}
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		defer stop()
+		defer cancel()
+
+		resp := e.run(reqCtx, req, prev)
+
+		cancelled := false
+		if key != "" {
+			e.mu.Lock()
+			if e.inflight[key] == entry {
+				delete(e.inflight, key)
+			}
+			cancelled = entry.cancelled
+			e.mu.Unlock()
+		}
+		// A handler that gave up because of the cancellation fails with
+		// whatever error it saw; report the cancellation instead
+		if cancelled && resp != nil && resp.Error != nil {
+			resp = message.NewErrorResponse(req.ID, message.ErrRequestCancelled())
+		}
+		done(resp)
+
+		if finished != nil {
+			// A request cancelled while waiting must not let the next
+			// ordered request overtake the one it was waiting for
+			if prev != nil {
+				<-prev
+			}
+			close(finished)
+			e.mu.Lock()
+			if e.chains[chain] == finished {
+				delete(e.chains, chain)
+			}
+			e.mu.Unlock()
+		}
+	}()
+}
+
+// run waits for the turn of a request and a free worker, then dispatches
+// it. A request cancelled before it starts is not run.
+func (e *Executor) run(ctx context.Context, req *message.Request, prev <-chan struct{}) *message.Response {
+	if prev != nil {
+		select {
+		case <-prev:
+		case <-ctx.Done():
+			return cancelledResponse(req)
+		}
+	}
+	select {
+	case e.slots <- struct{}{}:
+	case <-ctx.Done():
+		return cancelledResponse(req)
+	}
+	defer func() { <-e.slots }()
+
+	if ctx.Err() != nil {
+		return cancelledResponse(req)
+	}
+	return e.dispatcher.Dispatch(ctx, req)
+}
+
+// cancelRequest cancels the request whose ID is in the params of a
+// $/cancelRequest notification. Unknown or finished requests are ignored.
+func (e *Executor) cancelRequest(params json.RawMessage) {
+	var p struct {
+		ID *message.ID `json:"id"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil || p.ID == nil {
+		log.Debug().Str("params", string(params)).Msg("invalid $/cancelRequest params")
+		return
+	}
+
+	key := requestKey(p.ID)
+	e.mu.Lock()
+	entry := e.inflight[key]
+	if entry != nil {
+		entry.cancelled = true
+	}
+	e.mu.Unlock()
+
+	if entry != nil {
+		log.Debug().Str("id", p.ID.String()).Msg("request cancelled")
+		entry.cancel()
+	}
+}
+
+// Wait blocks until every request handled so far is done and answered.
+func (e *Executor) Wait() {
+	e.wg.Wait()
+}
+
+// Close cancels the requests still waiting or running, e.g. when the
+// client disconnects. It does not wait for them.
+func (e *Executor) Close() {
+	e.cancelAll()
+}
+
+// requestKey identifies a request ID, telling 1 and "1" apart.
+func requestKey(id *message.ID) string {
+	data, _ := json.Marshal(id)
+	return string(data)
+}
+
+// cancelledResponse is the response to a request cancelled before it ran.
+func cancelledResponse(req *message.Request) *message.Response {
+	if req.IsNotification() {
+		return nil
+	}
+	return message.NewErrorResponse(req.ID, message.ErrRequestCancelled())
+}
@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/rpc/message"
+)
+
+// replies collects the responses an executor sends.
+type replies struct {
+	mu  sync.Mutex
+	out []string
+	got chan struct{}
+}
+
+func newReplies() *replies {
+	return &replies{got: make(chan struct{}, 100)}
+}
+
+func (r *replies) reply(data []byte) {
+	r.mu.Lock()
+	r.out = append(r.out, string(data))
+	r.mu.Unlock()
+	r.got <- struct{}{}
+}
+
+func (r *replies) wait(t *testing.T, n int) []string {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.got:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got %d responses, want %d", i, n)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.out...)
+}
+
+func responseID(t *testing.T, data string) string {
+	t.Helper()
+	var resp message.Response
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatalf("invalid response %s: %v", data, err)
+	}
+	return resp.ID.String()
+}
+
+// blockingMethod registers a method that blocks until release is closed.
+func blockingMethod(r *Registry, name string, concurrency Concurrency, started chan<- struct{}, release <-chan struct{}) {
+	r.RegisterWithMeta(name, func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		if started != nil {
+			started <- struct{}{}
+		}
+		select {
+		case <-release:
+			return "done", nil
+		case <-ctx.Done():
+			return nil, message.ErrInternalError(ctx.Err().Error())
+		}
+	}, MethodMeta{Concurrency: concurrency})
+}
+
+func TestExecutor_ParallelNotBlockedBySlowRequest(t *testing.T) {
+	registry := NewRegistry()
+	release := make(chan struct{})
+	defer close(release)
+	blockingMethod(registry, "test/slow", ConcurrencyOrdered, nil, release)
+	registry.RegisterWithMeta("test/fast", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		return "fast", nil
+	}, MethodMeta{Concurrency: ConcurrencyParallel})
+
+	e := NewExecutor(NewDispatcher(registry), 2)
+	defer e.Close()
+	r := newReplies()
+	e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"test/slow"}`), r.reply)
+	e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"test/fast"}`), r.reply)
+
+	out := r.wait(t, 1)
+	if id := responseID(t, out[0]); id != "2" {
+		t.Errorf("first response id = %s, want 2", id)
+	}
+}
+
+func TestExecutor_OrderedKeepsArrivalOrder(t *testing.T) {
+	registry := NewRegistry()
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	registry.Register("test/record", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		var p struct{ N string }
+		_ = json.Unmarshal(params, &p)
+		if p.N == "1" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		mu.Lock()
+		order = append(order, p.N)
+		mu.Unlock()
+		return p.N, nil
+	})
+
+	e := NewExecutor(NewDispatcher(registry), 4)
+	r := newReplies()
+	for _, n := range []string{"1", "2", "3"} {
+		e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":`+n+`,"method":"test/record","params":{"n":"`+n+`"}}`), r.reply)
+	}
+	e.Wait()
+
+	if len(order) != 3 || order[0] != "1" || order[1] != "2" || order[2] != "3" {
+		t.Errorf("order = %v, want [1 2 3]", order)
+	}
+}
+
+func TestExecutor_CancelRequest(t *testing.T) {
+	registry := NewRegistry()
+	started := make(chan struct{}, 1)
+	blockingMethod(registry, "test/slow", ConcurrencyParallel, started, nil)
+
+	e := NewExecutor(NewDispatcher(registry), 2)
+	defer e.Close()
+	r := newReplies()
+	e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":"a","method":"test/slow"}`), r.reply)
+	<-started
+	e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":"a"}}`), r.reply)
+
+	out := r.wait(t, 1)
+	var resp message.Response
+	if err := json.Unmarshal([]byte(out[0]), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error == nil || resp.Error.Code != message.RequestCancelled || resp.ID.String() != "a" {
+		t.Errorf("response = %s, want a RequestCancelled error for id a", out[0])
+	}
+}
+
+func TestExecutor_CancelWaitingRequest(t *testing.T) {
+	registry := NewRegistry()
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	blockingMethod(registry, "test/slow", ConcurrencyOrdered, started, release)
+
+	e := NewExecutor(NewDispatcher(registry), 2)
+	r := newReplies()
+	e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"test/slow"}`), r.reply)
+	<-started
+	e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"test/slow"}`), r.reply)
+	e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","method":"$/cancelRequest","params":{"id":2}}`), r.reply)
+
+	out := r.wait(t, 1)
+	if id := responseID(t, out[0]); id != "2" {
+		t.Errorf("first response id = %s, want the cancelled request 2", id)
+	}
+	close(release)
+	e.Wait()
+	if len(started) != 0 {
+		t.Error("cancelled request was run")
+	}
+}
+
+func TestExecutor_BatchKeepsOrder(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterWithMeta("test/sleep", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		var p struct{ Ms int }
+		_ = json.Unmarshal(params, &p)
+		time.Sleep(time.Duration(p.Ms) * time.Millisecond)
+		return p.Ms, nil
+	}, MethodMeta{Concurrency: ConcurrencyParallel})
+
+	e := NewExecutor(NewDispatcher(registry), 4)
+	r := newReplies()
+	e.Handle(context.Background(), []byte(`[
+		{"jsonrpc":"2.0","id":1,"method":"test/sleep","params":{"ms":30}},
+		{"jsonrpc":"2.0","method":"test/sleep","params":{"ms":0}},
+		{"jsonrpc":"2.0","id":2,"method":"test/sleep","params":{"ms":0}}
+	]`), r.reply)
+
+	out := r.wait(t, 1)
+	var responses []message.Response
+	if err := json.Unmarshal([]byte(out[0]), &responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 2 || responses[0].ID.String() != "1" || responses[1].ID.String() != "2" {
+		t.Errorf("batch response = %s, want ids 1 and 2 in order", out[0])
+	}
+}
+
+func TestDispatcher_WorkspaceMethodsSerialised(t *testing.T) {
+	registry := NewRegistry()
+	var (
+		mu      sync.Mutex
+		running = map[string]int{}
+		overlap bool
+	)
+	registry.RegisterWithMeta("test/write", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		ws := workspaceKey(params)
+		mu.Lock()
+		running[ws]++
+		if running[ws] > 1 {
+			overlap = true
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running[ws]--
+		mu.Unlock()
+		return nil, nil
+	}, MethodMeta{Concurrency: ConcurrencyWorkspace})
+
+	dispatcher := NewDispatcher(registry)
+	// Two connections writing to the same workspace
+	var wg sync.WaitGroup
+	for c := 0; c < 2; c++ {
+		e := NewExecutor(dispatcher, 4)
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"test/write","params":{"workspace_id":"ws-1"}}`), func([]byte) {
+				wg.Done()
+			})
+		}
+	}
+	wg.Wait()
+
+	if overlap {
+		t.Error("requests to the same workspace ran at the same time")
+	}
+}
+
+func TestExecutor_CloseCancelsRunningRequests(t *testing.T) {
+	registry := NewRegistry()
+	started := make(chan struct{}, 1)
+	blockingMethod(registry, "test/slow", ConcurrencyParallel, started, nil)
+
+	e := NewExecutor(NewDispatcher(registry), 1)
+	r := newReplies()
+	e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"test/slow"}`), r.reply)
+	<-started
+	e.Close()
+
+	done := make(chan struct{})
+	go func() {
+		e.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not cancel the running request")
+	}
+}
@@ -123,6 +123,7 @@ func (s *AgentService) RegisterMethods(r *handler.Registry) {
 		Description: "Returns the current status of the AI agent.",
 		Params:      []handler.OpenRPCParam{},
 		Result:      &handler.OpenRPCResult{Name: "AgentStatusResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/AgentStatusResult"}},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	// agent/input
@@ -15,9 +15,17 @@ type ClientFocusProvider interface {
 	SetSessionFocus(clientID, workspaceID, sessionID string) (interface{}, error)
 }
 
+// ClientDeliveryProvider changes how events are delivered to a client.
+type ClientDeliveryProvider interface {
+	// SetDeliveryPolicy updates the delivery policy of a client; nil options
+	// are left unchanged. Returns the resulting policy.
+	SetDeliveryPolicy(clientID string, coalesce, batch *bool) (interface{}, error)
+}
+
 // ClientService provides client-related RPC methods.
 type ClientService struct {
-	provider ClientFocusProvider
+	provider         ClientFocusProvider
+	deliveryProvider ClientDeliveryProvider
 }
 
 // NewClientService creates a new client service.
@@ -33,6 +41,11 @@ func (s *ClientService) SetProvider(provider ClientFocusProvider) {
 	s.provider = provider
 }
 
+// SetDeliveryProvider sets the delivery policy provider for the service.
+func (s *ClientService) SetDeliveryProvider(provider ClientDeliveryProvider) {
+	s.deliveryProvider = provider
+}
+
 // RegisterMethods registers all client methods with the registry.
 func (s *ClientService) RegisterMethods(r *handler.Registry) {
 	r.RegisterWithMeta("client/session/focus", s.SessionFocus, handler.MethodMeta{
@@ -45,6 +58,24 @@ func (s *ClientService) RegisterMethods(r *handler.Registry) {
 		Result: &handler.OpenRPCResult{Name: "FocusChangeResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/FocusChangeResult"}},
 		Errors: []string{},
 	})
+
+	r.RegisterWithMeta("client/delivery", s.Delivery, handler.MethodMeta{
+		Summary:     "Set event delivery policy for current client",
+		Description: "Controls how events are delivered when the client reads slower than they are produced. With coalesce (default on), a queued state event such as git_status_changed, pty_state, pty_spinner or claude_status is replaced by a newer one of the same workspace and session. With batch (default off), queued small notifications are sent as one JSON-RPC batch array. If the send queue still fills up, queued events are dropped and an event/stream_lagging notification tells the client to resubscribe with since_seq. Permission prompts, errors and session end events are never coalesced or dropped.",
+		Params: []handler.OpenRPCParam{
+			{Name: "coalesce", Description: "Replace superseded state events", Required: false, Schema: map[string]interface{}{"type": "boolean"}},
+			{Name: "batch", Description: "Send queued notifications as JSON arrays", Required: false, Schema: map[string]interface{}{"type": "boolean"}},
+		},
+		Result: &handler.OpenRPCResult{Name: "DeliveryPolicyResult", Schema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"coalesce": map[string]interface{}{"type": "boolean"},
+				"batch":    map[string]interface{}{"type": "boolean"},
+				"lagging":  map[string]interface{}{"type": "boolean"},
+			},
+		}},
+		Errors: []string{},
+	})
 }
 
 // SessionFocusParams for client/session/focus method.
@@ -92,3 +123,38 @@ func (s *ClientService) SessionFocus(ctx context.Context, params json.RawMessage
 
 	return result, nil
 }
+
+// DeliveryParams for client/delivery method.
+type DeliveryParams struct {
+	// Coalesce replaces superseded state events. Nil leaves it unchanged.
+	Coalesce *bool `json:"coalesce"`
+
+	// Batch sends queued notifications as JSON arrays. Nil leaves it unchanged.
+	Batch *bool `json:"batch"`
+}
+
+// Delivery updates the event delivery policy of the current client.
+func (s *ClientService) Delivery(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	if s.deliveryProvider == nil {
+		return nil, message.ErrInternalError("delivery provider not available")
+	}
+
+	var p DeliveryParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, message.ErrInvalidParams("invalid params: " + err.Error())
+		}
+	}
+
+	clientID, ok := ctx.Value(handler.ClientIDKey).(string)
+	if !ok || clientID == "" {
+		return nil, message.ErrInternalError("client ID not found in context")
+	}
+
+	result, err := s.deliveryProvider.SetDeliveryPolicy(clientID, p.Coalesce, p.Batch)
+	if err != nil {
+		return nil, message.ErrInternalError(err.Error())
+	}
+
+	return result, nil
+}
@@ -59,8 +59,9 @@ func (s *FileService) RegisterMethods(r *handler.Registry) {
 		Params: []handler.OpenRPCParam{
 			{Name: "path", Description: "File path relative to repository root", Required: true, Schema: map[string]interface{}{"type": "string"}},
 		},
-		Result: &handler.OpenRPCResult{Name: "FileGetResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/FileGetResult"}},
-		Errors: []string{"FileNotFound"},
+		Result:      &handler.OpenRPCResult{Name: "FileGetResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/FileGetResult"}},
+		Errors:      []string{"FileNotFound"},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	r.RegisterWithMeta("file/list", s.ListDirectory, handler.MethodMeta{
@@ -69,8 +70,9 @@ func (s *FileService) RegisterMethods(r *handler.Registry) {
 		Params: []handler.OpenRPCParam{
 			{Name: "path", Description: "Relative path from repo root (empty for root directory)", Required: false, Schema: map[string]interface{}{"type": "string"}},
 		},
-		Result: &handler.OpenRPCResult{Name: "FileListResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/FileListResult"}},
-		Errors: []string{"FileNotFound"},
+		Result:      &handler.OpenRPCResult{Name: "FileListResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/FileListResult"}},
+		Errors:      []string{"FileNotFound"},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 }
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	"github.com/brianly1003/cdev/internal/adapters/git"
+	"github.com/brianly1003/cdev/internal/domain/ports"
 	"github.com/brianly1003/cdev/internal/rpc/handler"
 	"github.com/brianly1003/cdev/internal/rpc/message"
 )
@@ -92,6 +93,7 @@ func (s *GitService) RegisterMethods(r *handler.Registry) {
 		Params:      []handler.OpenRPCParam{},
 		Result:      &handler.OpenRPCResult{Name: "GitStatusResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/GitStatusResult"}},
 		Errors:      []string{"GitError"},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	r.RegisterWithMeta("git/diff", s.Diff, handler.MethodMeta{
@@ -100,8 +102,9 @@ func (s *GitService) RegisterMethods(r *handler.Registry) {
 		Params: []handler.OpenRPCParam{
 			{Name: "path", Description: "File path relative to repository root (optional, omit for all files)", Required: false, Schema: map[string]interface{}{"type": "string"}},
 		},
-		Result: &handler.OpenRPCResult{Name: "GitDiffResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/GitDiffResult"}},
-		Errors: []string{"FileNotFound", "GitError"},
+		Result:      &handler.OpenRPCResult{Name: "GitDiffResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/GitDiffResult"}},
+		Errors:      []string{"FileNotFound", "GitError"},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	r.RegisterWithMeta("git/stage", s.Stage, handler.MethodMeta{
@@ -110,8 +113,9 @@ func (s *GitService) RegisterMethods(r *handler.Registry) {
 		Params: []handler.OpenRPCParam{
 			{Name: "paths", Description: "Array of file paths to stage", Required: true, Schema: pathsSchema},
 		},
-		Result: &handler.OpenRPCResult{Name: "OperationResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/OperationResult"}},
-		Errors: []string{"GitError"},
+		Result:      &handler.OpenRPCResult{Name: "OperationResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/OperationResult"}},
+		Errors:      []string{"GitError"},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	r.RegisterWithMeta("git/unstage", s.Unstage, handler.MethodMeta{
@@ -120,8 +124,9 @@ func (s *GitService) RegisterMethods(r *handler.Registry) {
 		Params: []handler.OpenRPCParam{
 			{Name: "paths", Description: "Array of file paths to unstage", Required: true, Schema: pathsSchema},
 		},
-		Result: &handler.OpenRPCResult{Name: "OperationResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/OperationResult"}},
-		Errors: []string{"GitError"},
+		Result:      &handler.OpenRPCResult{Name: "OperationResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/OperationResult"}},
+		Errors:      []string{"GitError"},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	r.RegisterWithMeta("git/discard", s.Discard, handler.MethodMeta{
@@ -130,8 +135,9 @@ func (s *GitService) RegisterMethods(r *handler.Registry) {
 		Params: []handler.OpenRPCParam{
 			{Name: "paths", Description: "Array of file paths to discard", Required: true, Schema: pathsSchema},
 		},
-		Result: &handler.OpenRPCResult{Name: "OperationResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/OperationResult"}},
-		Errors: []string{"GitError"},
+		Result:      &handler.OpenRPCResult{Name: "OperationResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/OperationResult"}},
+		Errors:      []string{"GitError"},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	r.RegisterWithMeta("git/commit", s.Commit, handler.MethodMeta{
@@ -141,8 +147,9 @@ func (s *GitService) RegisterMethods(r *handler.Registry) {
 			{Name: "message", Description: "Commit message", Required: true, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "push", Description: "Push after commit", Required: false, Schema: map[string]interface{}{"type": "boolean", "default": false}},
 		},
-		Result: &handler.OpenRPCResult{Name: "CommitResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/CommitResult"}},
-		Errors: []string{"GitError"},
+		Result:      &handler.OpenRPCResult{Name: "CommitResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/CommitResult"}},
+		Errors:      []string{"GitError"},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	r.RegisterWithMeta("git/push", s.Push, handler.MethodMeta{
@@ -151,6 +158,7 @@ func (s *GitService) RegisterMethods(r *handler.Registry) {
 		Params:      []handler.OpenRPCParam{},
 		Result:      &handler.OpenRPCResult{Name: "OperationResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/OperationResult"}},
 		Errors:      []string{"GitError"},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	r.RegisterWithMeta("git/pull", s.Pull, handler.MethodMeta{
@@ -159,6 +167,8 @@ func (s *GitService) RegisterMethods(r *handler.Registry) {
 		Params:      []handler.OpenRPCParam{},
 		Result:      &handler.OpenRPCResult{Name: "OperationResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/OperationResult"}},
 		Errors:      []string{"GitError"},
+		Concurrency: handler.ConcurrencyWorkspace,
+		Progress:    true,
 	})
 
 	r.RegisterWithMeta("git/branches", s.Branches, handler.MethodMeta{
@@ -167,6 +177,7 @@ func (s *GitService) RegisterMethods(r *handler.Registry) {
 		Params:      []handler.OpenRPCParam{},
 		Result:      &handler.OpenRPCResult{Name: "BranchesResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/BranchesResult"}},
 		Errors:      []string{"GitError"},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	r.RegisterWithMeta("git/checkout", s.Checkout, handler.MethodMeta{
@@ -175,8 +186,9 @@ func (s *GitService) RegisterMethods(r *handler.Registry) {
 		Params: []handler.OpenRPCParam{
 			{Name: "branch", Description: "Branch name to checkout", Required: true, Schema: map[string]interface{}{"type": "string"}},
 		},
-		Result: &handler.OpenRPCResult{Name: "CheckoutResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/CheckoutResult"}},
-		Errors: []string{"GitError"},
+		Result:      &handler.OpenRPCResult{Name: "CheckoutResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/CheckoutResult"}},
+		Errors:      []string{"GitError"},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 }
 
@@ -420,10 +432,18 @@ func (s *GitService) Pull(ctx context.Context, params json.RawMessage) (interfac
 		return nil, message.ErrNotAGitRepo()
 	}
 
-	result, err := s.provider.Pull(ctx)
+	progress := handler.ProgressFrom(ctx)
+	progress.Begin("Pulling")
+	result, err := s.provider.Pull(ports.WithProgress(ctx, progress.ReportFunc()))
 	if err != nil {
+		progress.End("Pull failed")
 		return nil, message.ErrGitOperationFailed("pull", err.Error())
 	}
+	if result.Success {
+		progress.End("Pull completed")
+	} else {
+		progress.End("Pull failed")
+	}
 
 	return result, nil
 }
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/brianly1003/cdev/internal/config"
 	"github.com/brianly1003/cdev/internal/rpc/handler"
 	"github.com/brianly1003/cdev/internal/rpc/message"
 )
@@ -33,6 +34,26 @@ type ServerCapabilities struct {
 
 	// RuntimeRegistry describes per-runtime behavior so clients can route without hardcoded branches.
 	RuntimeRegistry *RuntimeCapabilityRegistry `json:"runtimeRegistry,omitempty"`
+
+	// LaunchProfiles lists the launch profiles selectable in session/start, keyed by workspace ID.
+	LaunchProfiles map[string][]LaunchProfileInfo `json:"launchProfiles,omitempty"`
+}
+
+// LaunchProfileInfo describes a workspace launch profile.
+// Env values are never exposed, only the variable names a profile sets.
+type LaunchProfileInfo struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description,omitempty"`
+	Model          string   `json:"model,omitempty"`
+	PermissionMode string   `json:"permissionMode,omitempty"`
+	AllowedTools   []string `json:"allowedTools,omitempty"`
+	WorkDir        string   `json:"workDir,omitempty"`
+	EnvKeys        []string `json:"envKeys,omitempty"`
+}
+
+// LaunchProfileProvider lists workspace launch profiles for initialize.
+type LaunchProfileProvider interface {
+	LaunchProfiles() map[string][]config.LaunchProfile
 }
 
 // AgentCapabilities describes AI agent-related capabilities.
@@ -128,10 +149,11 @@ type RuntimeMethods struct {
 
 // LifecycleService handles initialization and shutdown.
 type LifecycleService struct {
-	version      string
-	capabilities ServerCapabilities
-	initialized  bool
-	shutdownCh   chan struct{}
+	version         string
+	capabilities    ServerCapabilities
+	profileProvider LaunchProfileProvider
+	initialized     bool
+	shutdownCh      chan struct{}
 }
 
 // NewLifecycleService creates a new lifecycle service.
@@ -147,6 +169,12 @@ func NewLifecycleService(version string, caps ServerCapabilities) *LifecycleServ
 	}
 }
 
+// SetLaunchProfileProvider sets the source of launch profiles reported by initialize.
+// Profiles are read on every initialize so workspace edits show up on reconnect.
+func (s *LifecycleService) SetLaunchProfileProvider(provider LaunchProfileProvider) {
+	s.profileProvider = provider
+}
+
 // RegisterMethods registers lifecycle methods with the registry.
 func (s *LifecycleService) RegisterMethods(r *handler.Registry) {
 	r.RegisterWithMeta("initialize", s.Initialize, handler.MethodMeta{
@@ -156,6 +184,7 @@ func (s *LifecycleService) RegisterMethods(r *handler.Registry) {
 			{Name: "protocolVersion", Description: "Protocol version the client supports", Required: false, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "clientInfo", Description: "Client information", Required: false, Schema: map[string]interface{}{"$ref": "#/components/schemas/ClientInfo"}},
 			{Name: "capabilities", Description: "Client capabilities", Required: false, Schema: map[string]interface{}{"type": "object"}},
+			{Name: "rootPath", Description: "Root path of the workspace", Required: false, Schema: map[string]interface{}{"type": "string"}},
 		},
 		Result: &handler.OpenRPCResult{Name: "InitializeResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/InitializeResult"}},
 	})
@@ -249,17 +278,47 @@ func (s *LifecycleService) Initialize(ctx context.Context, params json.RawMessag
 	// Get client ID from context (assigned by WebSocket handler on connection)
 	clientID, _ := ctx.Value(handler.ClientIDKey).(string)
 
+	caps := s.capabilities
+	if s.profileProvider != nil {
+		caps.LaunchProfiles = launchProfileInfos(s.profileProvider.LaunchProfiles())
+	}
+
 	return InitializeResult{
 		ProtocolVersion: "1.0",
 		ServerInfo: ServerInfo{
 			Name:    "cdev",
 			Version: s.version,
 		},
-		Capabilities: s.capabilities,
+		Capabilities: caps,
 		ClientID:     clientID,
 	}, nil
 }
 
+func launchProfileInfos(profiles map[string][]config.LaunchProfile) map[string][]LaunchProfileInfo {
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	result := make(map[string][]LaunchProfileInfo, len(profiles))
+	for workspaceID, list := range profiles {
+		infos := make([]LaunchProfileInfo, 0, len(list))
+		for i := range list {
+			p := &list[i]
+			infos = append(infos, LaunchProfileInfo{
+				Name:           p.Name,
+				Description:    p.Description,
+				Model:          p.Model,
+				PermissionMode: p.PermissionMode,
+				AllowedTools:   p.AllowedTools,
+				WorkDir:        p.WorkDir,
+				EnvKeys:        p.EnvKeys(),
+			})
+		}
+		result[workspaceID] = infos
+	}
+	return result
+}
+
 // Initialized is a notification from the client that initialization is complete.
 // After this, the client can start sending other requests.
 func (s *LifecycleService) Initialized(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
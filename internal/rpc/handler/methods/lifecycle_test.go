@@ -3,9 +3,11 @@ package methods
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/brianly1003/cdev/internal/config"
 	"github.com/brianly1003/cdev/internal/rpc/handler"
 )
 
@@ -119,3 +121,42 @@ func TestLifecycleServiceInitializeIncludesRuntimeRegistry(t *testing.T) {
 		t.Fatalf("runtime count = %d, supportedAgents count = %d", len(registry.Runtimes), len(initResult.Capabilities.SupportedAgents))
 	}
 }
+
+type stubLaunchProfileProvider map[string][]config.LaunchProfile
+
+func (p stubLaunchProfileProvider) LaunchProfiles() map[string][]config.LaunchProfile {
+	return p
+}
+
+func TestLifecycleServiceInitializeListsLaunchProfiles(t *testing.T) {
+	service := NewLifecycleService("test-version", ServerCapabilities{})
+	service.SetLaunchProfileProvider(stubLaunchProfileProvider{
+		"ws-1": {{
+			Name:           "review",
+			Model:          "sonnet",
+			PermissionMode: "plan",
+			Env:            []string{"API_KEY=${HOST_KEY}"},
+		}},
+	})
+
+	result, rpcErr := service.Initialize(context.Background(), json.RawMessage(`{}`))
+	if rpcErr != nil {
+		t.Fatalf("Initialize() error = %v", rpcErr)
+	}
+
+	profiles := result.(InitializeResult).Capabilities.LaunchProfiles["ws-1"]
+	if len(profiles) != 1 || profiles[0].Name != "review" || profiles[0].Model != "sonnet" {
+		t.Fatalf("launchProfiles[ws-1] = %+v", profiles)
+	}
+	if len(profiles[0].EnvKeys) != 1 || profiles[0].EnvKeys[0] != "API_KEY" {
+		t.Fatalf("envKeys = %v, want [API_KEY]", profiles[0].EnvKeys)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "HOST_KEY") {
+		t.Fatalf("initialize result leaks env values: %s", data)
+	}
+}
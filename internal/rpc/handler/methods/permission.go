@@ -4,8 +4,11 @@ package methods
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/brianly1003/cdev/internal/domain/events"
@@ -17,8 +20,8 @@ import (
 
 // PermissionManager defines the interface for managing permission requests.
 type PermissionManager interface {
-	// CheckMemory checks session memory for a matching pattern.
-	CheckMemory(sessionID, toolName string, toolInput map[string]interface{}) *permission.StoredDecision
+	// CheckMemory checks workspace rules and session memory for a matching pattern.
+	CheckMemory(sessionID, workspaceID, toolName string, toolInput map[string]interface{}) *permission.StoredDecision
 
 	// StoreDecision stores a permission decision in session memory.
 	StoreDecision(sessionID, workspaceID, pattern string, decision permission.Decision)
@@ -35,14 +38,32 @@ type PermissionManager interface {
 	// RespondToRequest sends a response to a pending request.
 	RespondToRequest(toolUseID string, response *permission.Response) bool
 
+	// Remember stores a session or path scoped response to a request.
+	Remember(req *permission.Request, response *permission.Response)
+
 	// GetAndRemovePendingRequest atomically gets and removes a pending request.
 	GetAndRemovePendingRequest(toolUseID string) *permission.Request
 
+	// MarkNotified records the devices a pending request was sent to.
+	MarkNotified(toolUseID string, devices []string, escalated bool) bool
+
+	// AddApproval records a device's approval of a request that needs several.
+	AddApproval(toolUseID, device string) ([]string, int, error)
+
 	// ListPendingRequests returns all pending permission requests.
 	ListPendingRequests() []*permission.Request
 
 	// GetSessionStats returns statistics about session memory.
 	GetSessionStats() map[string]interface{}
+
+	// AddRule stores a persistent workspace rule.
+	AddRule(rule permission.Rule) (permission.Rule, error)
+
+	// RemoveRule deletes a persistent workspace rule.
+	RemoveRule(workspaceID, ruleID string) (bool, error)
+
+	// ListRules returns persistent rules of a workspace, or all when empty.
+	ListRules(workspaceID string) []permission.Rule
 }
 
 // EventPublisher defines the interface for publishing events.
@@ -57,11 +78,26 @@ type WorkspaceResolver interface {
 	ResolveWorkspaceID(path string) (string, error)
 }
 
+// PolicyEvaluator evaluates declarative permission policies for a tool call.
+type PolicyEvaluator interface {
+	Evaluate(in permission.PolicyInput) *permission.PolicyDecision
+}
+
+// PermissionAuditLog records permission decisions and answers audit queries.
+type PermissionAuditLog interface {
+	Record(entry permission.AuditEntry) error
+	Query(filter permission.AuditFilter) ([]permission.AuditEntry, int, error)
+}
+
 // PermissionService provides permission-related RPC methods.
 type PermissionService struct {
 	manager           PermissionManager
 	publisher         EventPublisher
 	workspaceResolver WorkspaceResolver
+	workspacePaths    WorkspacePathResolver
+	policy            PolicyEvaluator
+	audit             PermissionAuditLog
+	router            *permission.Router
 	timeout           time.Duration
 }
 
@@ -80,6 +116,29 @@ func (s *PermissionService) SetTimeout(timeout time.Duration) {
 	s.timeout = timeout
 }
 
+// SetWorkspacePathResolver sets the resolver used to anchor relative path
+// patterns of workspace rules at the workspace root.
+func (s *PermissionService) SetWorkspacePathResolver(resolver WorkspacePathResolver) {
+	s.workspacePaths = resolver
+}
+
+// SetPolicyEvaluator sets the policy engine consulted before memory and mobile.
+func (s *PermissionService) SetPolicyEvaluator(policy PolicyEvaluator) {
+	s.policy = policy
+}
+
+// SetAuditLog sets the audit log that records every permission decision and
+// backs permission/audit.
+func (s *PermissionService) SetAuditLog(audit PermissionAuditLog) {
+	s.audit = audit
+}
+
+// SetRouter sets the multi-device routing rules: primary devices, escalation,
+// multiple approvals for high-risk tools and the decision on timeout.
+func (s *PermissionService) SetRouter(router *permission.Router) {
+	s.router = router
+}
+
 // RegisterMethods registers all permission methods with the registry.
 func (s *PermissionService) RegisterMethods(r *handler.Registry) {
 	r.RegisterWithMeta("permission/request", s.Request, handler.MethodMeta{
@@ -96,20 +155,24 @@ func (s *PermissionService) RegisterMethods(r *handler.Registry) {
 			Name:   "PermissionResponse",
 			Schema: map[string]interface{}{"$ref": "#/components/schemas/PermissionResponse"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	r.RegisterWithMeta("permission/respond", s.Respond, handler.MethodMeta{
 		Summary:     "Respond to a permission request",
-		Description: "Called by the mobile app to respond to a pending permission request.",
+		Description: "Called by the mobile app to respond to a pending permission request, from Claude or Codex (see agent_type). Requests matching a high-risk routing pattern need approvals from several devices: until then an approval returns status 'awaiting_approvals' and the request stays pending, while a single deny decides it.",
 		Params: []handler.OpenRPCParam{
 			{Name: "tool_use_id", Required: true, Schema: map[string]interface{}{"type": "string", "description": "Tool use ID from the permission request"}},
 			{Name: "decision", Required: true, Schema: map[string]interface{}{"type": "string", "enum": []string{"allow", "deny"}, "description": "Allow or deny the request"}},
-			{Name: "scope", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"once", "session"}, "default": "once", "description": "Scope of the decision"}},
+			{Name: "scope", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"once", "session", "path"}, "default": "once", "description": "Scope of the decision. 'path' persists it as a workspace rule."}},
+			{Name: "pattern", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Pattern to remember for session/path scope, e.g. 'Edit(src/*.go)'. Must match the request. Defaults to a pattern generated from the request."}},
+			{Name: "updated_input", Required: false, Schema: map[string]interface{}{"type": "object", "description": "Approve with edited tool input, e.g. a narrower path or an added --dry-run. Validated against the tool's input schema and re-checked by permission policies; only valid with decision 'allow', always scoped to this request and not supported for Codex requests."}},
 		},
 		Result: &handler.OpenRPCResult{
 			Name:   "RespondResult",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		UnknownParams: handler.UnknownParamsReject,
 	})
 
 	r.RegisterWithMeta("permission/stats", s.Stats, handler.MethodMeta{
@@ -120,18 +183,25 @@ func (s *PermissionService) RegisterMethods(r *handler.Registry) {
 			Name:   "StatsResult",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
+	s.registerRuleMethods(r)
+	s.registerAuditMethods(r)
+	s.registerBatchMethods(r)
+	s.registerSimulateMethods(r)
+
 	r.RegisterWithMeta("permission/pending", s.Pending, handler.MethodMeta{
 		Summary:     "Get all pending permission requests",
-		Description: "Returns all pending permission requests. Call this on reconnect to catch any missed permissions.",
+		Description: "Returns all pending permission requests. Call this on reconnect to catch any missed permissions. Requests are also grouped by workspace and generated pattern; a group can be answered with permission/respond_batch.",
 		Params: []handler.OpenRPCParam{
 			{Name: "session_id", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Optional: filter by session ID"}},
 		},
 		Result: &handler.OpenRPCResult{
 			Name:   "PendingResult",
-			Schema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"requests": map[string]interface{}{"type": "array"}}},
+			Schema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"requests": map[string]interface{}{"type": "array"}, "groups": map[string]interface{}{"type": "array"}}},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 }
 
@@ -169,16 +239,6 @@ func (s *PermissionService) Request(ctx context.Context, params json.RawMessage)
 		return nil, message.ErrInvalidParams("tool_use_id is required")
 	}
 
-	// Check session memory for matching pattern
-	if stored := s.manager.CheckMemory(p.SessionID, p.ToolName, p.ToolInput); stored != nil {
-		// Found a matching pattern in session memory
-		return &permission.Response{
-			Decision: stored.Decision,
-			Scope:    permission.ScopeSession,
-			Pattern:  stored.Pattern,
-		}, nil
-	}
-
 	// Resolve workspace ID from cwd
 	workspaceID := ""
 	if s.workspaceResolver != nil && p.Cwd != "" {
@@ -187,16 +247,81 @@ func (s *PermissionService) Request(ctx context.Context, params json.RawMessage)
 		}
 	}
 
+	return s.decide(ctx, permissionCall{
+		RequestParams: p,
+		workspaceID:   workspaceID,
+		agentType:     sessionManagerAgentClaude, // The hook CLI is Claude-specific
+		channel:       "rpc",
+	}), nil
+}
+
+// permissionCall is a tool call waiting for a permission decision, asked by
+// the Claude hook CLI or normalised from a Codex approval prompt.
+type permissionCall struct {
+	RequestParams
+	workspaceID string
+	agentType   string // claude or codex
+	channel     string // Audit channel: rpc, codex or mcp
+	mustAsk     bool   // Input is not exact, so only a device may allow it
+}
+
+// decide runs a tool call through policies, workspace rules, session
+// memory and finally the mobile app. It blocks until a device answers, the
+// request times out or ctx is cancelled.
+func (s *PermissionService) decide(ctx context.Context, p permissionCall) *permission.Response {
+	workspaceID := p.workspaceID
+	audit := permission.AuditEntry{
+		ToolUseID:   p.ToolUseID,
+		SessionID:   p.SessionID,
+		WorkspaceID: workspaceID,
+		ToolName:    p.ToolName,
+		ToolInput:   p.ToolInput,
+		Channel:     p.channel,
+		RequestedAt: time.Now(),
+	}
+	pre := permission.RunPrecheck(permission.PrecheckInput{
+		PolicyInput: permission.PolicyInput{
+			ToolName:      p.ToolName,
+			ToolInput:     p.ToolInput,
+			WorkspaceID:   workspaceID,
+			WorkspacePath: s.workspaceRoot(workspaceID),
+			Cwd:           p.Cwd,
+			SessionType:   p.agentType,
+		},
+		SessionID: p.SessionID,
+		MustAsk:   p.mustAsk,
+	}, s.policy, s.manager, s.router)
+	risk := pre.Risk
+	audit.Risk = risk.Level
+
+	switch {
+	case pre.Stored != nil:
+		s.recordAudit(audit.WithStored(pre.Stored))
+		return &permission.Response{
+			Decision: pre.Stored.Decision,
+			Scope:    pre.Stored.EffectiveScope(),
+			Pattern:  pre.Stored.Pattern,
+		}
+	case pre.Decided():
+		s.recordAudit(audit.WithPolicy(pre.Policy))
+		return policyResponse(pre.Policy)
+	}
+
 	// Create pending request with buffered channel
+	route := pre.Route
 	req := &permission.Request{
-		ID:           p.ToolUseID,
-		SessionID:    p.SessionID,
-		WorkspaceID:  workspaceID,
-		ToolName:     p.ToolName,
-		ToolInput:    p.ToolInput,
-		ToolUseID:    p.ToolUseID,
-		CreatedAt:    time.Now(),
-		ResponseChan: make(chan *permission.Response, 1),
+		ID:                p.ToolUseID,
+		SessionID:         p.SessionID,
+		WorkspaceID:       workspaceID,
+		ToolName:          p.ToolName,
+		ToolInput:         p.ToolInput,
+		ToolUseID:         p.ToolUseID,
+		CreatedAt:         time.Now(),
+		ResponseChan:      make(chan *permission.Response, 1),
+		RequiredApprovals: route.RequiredApprovals,
+		Risk:              &risk,
+		AgentType:         p.agentType,
+		Cwd:               p.Cwd,
 	}
 
 	// Add to pending requests
@@ -205,66 +330,119 @@ func (s *PermissionService) Request(ctx context.Context, params json.RawMessage)
 	// Publish event to mobile app
 	if s.publisher != nil {
 		// Check if any mobile clients are connected
-		// Subscriber count should be > 1 (the hook command itself is a subscriber)
+		// Subscriber count should be > 1 (the hook command itself is a subscriber).
+		// A Codex prompt blocks nothing but Codex, so it stays pending for
+		// devices that connect later.
 		subscriberCount := s.publisher.SubscriberCount()
-		if subscriberCount <= 1 {
+		if p.agentType != sessionManagerAgentCodex && subscriberCount <= 1 {
 			// No mobile clients connected - return deny (user cannot approve)
 			s.manager.RemovePendingRequest(p.ToolUseID)
 			log.Warn().
 				Int("subscriber_count", subscriberCount).
 				Msg("No mobile clients connected - returning deny")
-			return &permission.Response{
+			response := &permission.Response{
 				Decision: permission.DecisionDeny,
 				Message:  "no_clients",
-			}, nil
+			}
+			s.recordAudit(audit.WithResponse(response, permission.AuditSourceNoClients))
+			return response
 		}
 
-		event := s.createPermissionEvent(req)
 		log.Info().
 			Str("tool_use_id", req.ToolUseID).
 			Str("tool_name", req.ToolName).
 			Str("session_id", req.SessionID).
 			Str("workspace_id", req.WorkspaceID).
-			Str("event_type", string(event.Type())).
+			Strs("target_devices", route.Devices).
+			Int("required_approvals", route.RequiredApprovals).
 			Int("subscriber_count", subscriberCount).
 			Msg("Publishing pty_permission event to mobile app")
-		s.publisher.Publish(event)
+		s.publishPermission(req, events.PermissionRouting{TargetDevices: route.Devices})
 	} else {
 		log.Warn().Msg("Publisher is nil - cannot send pty_permission event")
 	}
 
+	// Escalate to every device if the primaries do not answer in time
+	var escalate <-chan time.Time
+	if route.EscalateAfter > 0 && route.EscalateAfter < s.timeout {
+		escalateTimer := time.NewTimer(route.EscalateAfter)
+		defer escalateTimer.Stop()
+		escalate = escalateTimer.C
+	}
+	timeout := time.NewTimer(s.timeout)
+	defer timeout.Stop()
+
 	// Wait for response with timeout
 	// Note: We don't use defer RemovePendingRequest here because
 	// RespondToRequest already removes the request atomically
-	select {
-	case response := <-req.ResponseChan:
-		return response, nil
-	case <-time.After(s.timeout):
-		// Timeout - return deny (user did not respond in time)
-		s.manager.RemovePendingRequest(p.ToolUseID)
-		log.Warn().
-			Str("tool_use_id", p.ToolUseID).
-			Dur("timeout", s.timeout).
-			Msg("Permission request timed out - returning deny")
-		return &permission.Response{
-			Decision: permission.DecisionDeny,
-			Message:  "timeout",
-		}, nil
-	case <-ctx.Done():
-		// Context cancelled - return deny
-		s.manager.RemovePendingRequest(p.ToolUseID)
-		log.Warn().
-			Str("tool_use_id", p.ToolUseID).
-			Msg("Permission request cancelled - returning deny")
-		return &permission.Response{
-			Decision: permission.DecisionDeny,
-			Message:  "cancelled",
-		}, nil
+	for {
+		select {
+		case <-escalate:
+			escalate = nil
+			log.Info().
+				Str("tool_use_id", req.ToolUseID).
+				Dur("escalate_after", route.EscalateAfter).
+				Msg("Primary devices did not answer - escalating permission request to every device")
+			s.publishPermission(req, events.PermissionRouting{Escalated: true})
+		case response := <-req.ResponseChan:
+			s.recordAudit(audit.WithResponse(response, permission.AuditSourceDevice))
+			return response
+		case <-timeout.C:
+			// Timeout - return deny (user did not respond in time), or ask if
+			// the routing rules say so
+			s.manager.RemovePendingRequest(p.ToolUseID)
+			decision := permission.DecisionDeny
+			if routed, ok := s.router.TimeoutDecision(); ok {
+				decision = routed
+			}
+			log.Warn().
+				Str("tool_use_id", p.ToolUseID).
+				Dur("timeout", s.timeout).
+				Str("decision", string(decision)).
+				Msg("Permission request timed out")
+			response := &permission.Response{
+				Decision: decision,
+				Message:  "timeout",
+			}
+			s.recordAudit(audit.WithResponse(response, permission.AuditSourceTimeout))
+			return response
+		case <-ctx.Done():
+			// Context cancelled - return deny
+			s.manager.RemovePendingRequest(p.ToolUseID)
+			log.Warn().
+				Str("tool_use_id", p.ToolUseID).
+				Msg("Permission request cancelled - returning deny")
+			response := &permission.Response{
+				Decision: permission.DecisionDeny,
+				Message:  "cancelled",
+			}
+			s.recordAudit(audit.WithResponse(response, permission.AuditSourceCancelled))
+			return response
+		}
 	}
 }
 
+// publishPermission sends the pty_permission prompt of a pending request to
+// the routed devices (every device when none are set) and records which
+// devices were notified.
+func (s *PermissionService) publishPermission(req *permission.Request, routing events.PermissionRouting) {
+	if s.publisher == nil {
+		return
+	}
+	notified := routing.TargetDevices
+	if len(notified) == 0 {
+		notified = s.router.ConnectedDevices()
+	}
+	s.manager.MarkNotified(req.ToolUseID, notified, routing.Escalated)
+
+	if req.RequiredApprovals > 1 {
+		routing.RequiredApprovals = req.RequiredApprovals
+	}
+	s.publisher.Publish(s.createPermissionEvent(req, routing))
+}
+
 // createPermissionEvent creates a pty_permission event for the mobile app.
-func (s *PermissionService) createPermissionEvent(req *permission.Request) *events.BaseEvent {
+func (s *PermissionService) createPermissionEvent(req *permission.Request, routing events.PermissionRouting) *events.BaseEvent {
 	// Generate human-readable description
 	description := permission.GenerateReadableDescription(req.ToolName, req.ToolInput)
 	target := permission.ExtractTarget(req.ToolName, req.ToolInput)
@@ -287,6 +465,9 @@ func (s *PermissionService) createPermissionEvent(req *permission.Request) *even
 		Options:     options,
 		SessionID:   req.SessionID,
 		WorkspaceID: req.WorkspaceID,
+		Risk:        req.Risk.ToEvent(),
+
+		PermissionRouting: routing,
 	}
 
 	// Create event with context.
@@ -297,16 +478,35 @@ func (s *PermissionService) createPermissionEvent(req *permission.Request) *even
 	event := events.NewEvent(events.EventTypePTYPermission, payload)
 	event.SessionID = req.SessionID
 	event.WorkspaceID = req.WorkspaceID
-	event.SetAgentType("claude") // Permission hooks are Claude-specific
+	event.SetAgentType(req.AgentType)
 
 	return event
 }
 
+// policyResponse converts a policy allow/deny into a hook response.
+func policyResponse(policy *permission.PolicyDecision) *permission.Response {
+	log.Info().
+		Str("policy_source", policy.Source).
+		Str("policy_rule", policy.Rule).
+		Str("decision", string(policy.Decision)).
+		Msg("permission request decided by policy")
+	return &permission.Response{
+		Decision:   policy.Decision,
+		Scope:      permission.ScopeOnce,
+		Message:    policy.Reason,
+		PolicyRule: policy.Rule,
+	}
+}
+
 // RespondParams for permission/respond method.
 type RespondParams struct {
 	ToolUseID string `json:"tool_use_id"`
-	Decision  string `json:"decision"` // "allow" or "deny"
-	Scope     string `json:"scope"`    // "once" or "session"
+	Decision  string `json:"decision"`          // "allow" or "deny"
+	Scope     string `json:"scope"`             // "once", "session" or "path"
+	Pattern   string `json:"pattern,omitempty"` // Optional pattern for session/path scope
+
+	// UpdatedInput approves the request with edited tool input.
+	UpdatedInput map[string]interface{} `json:"updated_input,omitempty"`
 }
 
 // Respond handles a response from the mobile app.
@@ -319,7 +519,11 @@ func (s *PermissionService) Respond(ctx context.Context, params json.RawMessage)
 	if err := json.Unmarshal(params, &p); err != nil {
 		return nil, message.ErrInvalidParams(fmt.Sprintf("Invalid params: %v", err))
 	}
+	return s.respond(ctx, p)
+}
 
+// respond applies a device's answer to one pending request.
+func (s *PermissionService) respond(ctx context.Context, p RespondParams) (map[string]interface{}, *message.Error) {
 	// Validate required fields
 	if p.ToolUseID == "" {
 		return nil, message.ErrInvalidParams("tool_use_id is required")
@@ -330,11 +534,78 @@ func (s *PermissionService) Respond(ctx context.Context, params json.RawMessage)
 	if p.Decision != "allow" && p.Decision != "deny" {
 		return nil, message.ErrInvalidParams("decision must be 'allow' or 'deny'")
 	}
+	if p.UpdatedInput != nil && p.Decision != "allow" {
+		return nil, message.ErrInvalidParams("updated_input is only valid with decision 'allow'")
+	}
 
 	// Default scope to once
 	scope := permission.ScopeOnce
-	if p.Scope == "session" {
+	switch p.Scope {
+	case "session":
 		scope = permission.ScopeSession
+	case "path":
+		scope = permission.ScopePath
+	}
+
+	device := requestingDevice(ctx)
+
+	// High-risk requests need approvals from several devices; any deny wins.
+	// Until enough devices approved, the request stays pending.
+	var approvedBy []string
+	if p.Decision == "allow" {
+		if pending := s.manager.GetPendingRequest(p.ToolUseID); pending != nil && pending.RequiredApprovals > 1 {
+			if p.UpdatedInput != nil {
+				return nil, message.ErrInvalidParams("updated_input is not supported for requests that need several approvals")
+			}
+			approvals, required, err := s.manager.AddApproval(p.ToolUseID, device)
+			if errors.Is(err, permission.ErrAlreadyApproved) {
+				return nil, message.ErrInvalidParams("This device already approved the request - another device must approve it")
+			}
+			if err != nil {
+				return nil, message.ErrInternalError("Request not found or already responded")
+			}
+			if len(approvals) < required {
+				s.requestMoreApprovals(pending, approvals)
+				return map[string]interface{}{
+					"success":            true,
+					"status":             "awaiting_approvals",
+					"approvals":          approvals,
+					"required_approvals": required,
+				}, nil
+			}
+			approvedBy = approvals
+			if scope != permission.ScopeOnce {
+				log.Warn().
+					Str("tool_use_id", p.ToolUseID).
+					Str("scope", string(scope)).
+					Msg("Approval of a high-risk request is not remembered - using scope once")
+				scope = permission.ScopeOnce
+			}
+		}
+	}
+
+	// Edited input is validated before the request is taken, so a rejected
+	// edit leaves the request pending for another answer.
+	var updatedInput map[string]interface{}
+	if p.UpdatedInput != nil {
+		pending := s.manager.GetPendingRequest(p.ToolUseID)
+		if pending == nil {
+			return nil, message.ErrInternalError("Request not found or already responded")
+		}
+		if pending.AgentType == sessionManagerAgentCodex {
+			return nil, message.ErrInvalidParams("updated_input is not supported for Codex requests - Codex can only be answered yes or no")
+		}
+		var rpcErr *message.Error
+		if updatedInput, rpcErr = s.checkUpdatedInput(pending, p.UpdatedInput); rpcErr != nil {
+			return nil, rpcErr
+		}
+		if updatedInput != nil && scope != permission.ScopeOnce {
+			log.Warn().
+				Str("tool_use_id", p.ToolUseID).
+				Str("scope", string(scope)).
+				Msg("Approval with edited input is not remembered - using scope once")
+			scope = permission.ScopeOnce
+		}
 	}
 
 	// Atomically get and remove the pending request
@@ -345,22 +616,30 @@ func (s *PermissionService) Respond(ctx context.Context, params json.RawMessage)
 		return nil, message.ErrInternalError("Request not found or already responded")
 	}
 
-	// Generate pattern for session scope
+	// A path decision is stored per workspace; without one, remember it for the session.
+	if scope == permission.ScopePath && req.WorkspaceID == "" {
+		log.Warn().
+			Str("tool_use_id", req.ToolUseID).
+			Msg("Path-scoped decision without workspace - remembering for session only")
+		scope = permission.ScopeSession
+	}
+
+	// Generate pattern for session/path scope
 	pattern := ""
-	if scope == permission.ScopeSession {
-		pattern = permission.GeneratePattern(req.ToolName, req.ToolInput)
+	if scope != permission.ScopeOnce {
+		pattern = s.rememberedPattern(req, p.Pattern)
 	}
 
 	response := &permission.Response{
-		Decision: permission.Decision(p.Decision),
-		Scope:    scope,
-		Pattern:  pattern,
+		Decision:     permission.Decision(p.Decision),
+		Scope:        scope,
+		Pattern:      pattern,
+		UpdatedInput: updatedInput,
+		RespondedBy:  device,
+		ApprovedBy:   approvedBy,
 	}
 
-	// Store decision in session memory if scope is session
-	if scope == permission.ScopeSession && pattern != "" {
-		s.manager.StoreDecision(req.SessionID, req.WorkspaceID, pattern, response.Decision)
-	}
+	s.manager.Remember(req, response)
 
 	// Send response through the channel (non-blocking)
 	select {
@@ -376,15 +655,122 @@ func (s *PermissionService) Respond(ctx context.Context, params json.RawMessage)
 		resolved := events.NewPTYPermissionResolvedEvent(
 			req.SessionID,
 			req.WorkspaceID,
-			"", // clientID - not tracked currently
+			device,
 			string(response.Decision),
 		)
+		resolved.SetAgentType(req.AgentType)
 		s.publisher.Publish(resolved)
 	}
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"success": true,
-	}, nil
+		"scope":   string(scope),
+	}
+	if pattern != "" {
+		result["pattern"] = pattern
+	}
+	if updatedInput != nil {
+		result["modified"] = true
+	}
+	if approvedBy != nil {
+		result["approved_by"] = approvedBy
+	}
+	return result, nil
+}
+
+// requestMoreApprovals re-sends a partially approved request to the devices
+// that have not approved it yet.
+func (s *PermissionService) requestMoreApprovals(req *permission.Request, approvals []string) {
+	var others []string
+	for _, device := range s.router.ConnectedDevices() {
+		if !slices.Contains(approvals, device) {
+			others = append(others, device)
+		}
+	}
+	log.Info().
+		Str("tool_use_id", req.ToolUseID).
+		Strs("approvals", approvals).
+		Strs("ask_devices", others).
+		Msg("Permission request needs more approvals")
+	if len(others) == 0 {
+		return // Devices connecting later see it through permission/pending
+	}
+	s.publishPermission(req, events.PermissionRouting{
+		TargetDevices: others,
+		Escalated:     true,
+		Approvals:     approvals,
+	})
+}
+
+// checkUpdatedInput validates edited tool input for a pending request. It
+// returns nil when the edit is identical to the original input, so the
+// approval is recorded as a plain allow.
+//
+// The approval was given for the original call, so the edited call must
+// pass the checks a new request would: policy and remembered denies reject
+// it, and so does an edit that makes it high-risk or raises its risk tier.
+func (s *PermissionService) checkUpdatedInput(req *permission.Request, updated map[string]interface{}) (map[string]interface{}, *message.Error) {
+	if err := permission.ValidateUpdatedInput(req.ToolName, req.ToolInput, updated); err != nil {
+		return nil, message.ErrInvalidParams("Invalid updated_input: " + err.Error())
+	}
+	if !permission.InputModified(req.ToolInput, updated) {
+		return nil, nil
+	}
+
+	if s.policy != nil {
+		policy := s.policy.Evaluate(permission.PolicyInput{
+			ToolName:    req.ToolName,
+			ToolInput:   updated,
+			WorkspaceID: req.WorkspaceID,
+			Cwd:         req.Cwd,
+			SessionType: req.AgentType,
+		})
+		if policy != nil && policy.Decision == permission.DecisionDeny {
+			reason := policy.Reason
+			if reason == "" {
+				reason = policy.Rule
+			}
+			return nil, message.ErrInvalidParams("updated_input is denied by policy: " + reason)
+		}
+	}
+	if stored := s.manager.CheckMemory(req.SessionID, req.WorkspaceID, req.ToolName, updated); stored != nil && stored.Decision == permission.DecisionDeny {
+		return nil, message.ErrInvalidParams("updated_input is denied by rule " + stored.Pattern)
+	}
+	if req.RequiredApprovals <= 1 && s.router.IsHighRisk(req.ToolName, updated) {
+		return nil, message.ErrInvalidParams("updated_input makes the request high-risk, which needs approvals from several devices - deny it instead")
+	}
+	risk := permission.AssessRisk(permission.PolicyInput{
+		ToolName:      req.ToolName,
+		ToolInput:     updated,
+		WorkspaceID:   req.WorkspaceID,
+		WorkspacePath: s.workspaceRoot(req.WorkspaceID),
+		Cwd:           req.Cwd,
+	})
+	if req.Risk != nil && risk.Level != req.Risk.Level && risk.Level.AtLeast(req.Risk.Level) {
+		return nil, message.ErrInvalidParams(fmt.Sprintf("updated_input raises the risk from %s to %s - deny it instead", req.Risk.Level, risk.Level))
+	}
+	return updated, nil
+}
+
+// rememberedPattern returns the pattern a session/path decision is stored
+// under. A client-supplied pattern is used only if it parses and still covers
+// the request it answers; otherwise one is generated from the request.
+func (s *PermissionService) rememberedPattern(req *permission.Request, requested string) string {
+	if strings.TrimSpace(requested) != "" {
+		tool, content, err := permission.ParseRulePattern(requested)
+		if err == nil {
+			content = s.anchorPattern(req.WorkspaceID, tool, content)
+			candidate := tool + "(" + content + ")"
+			if permission.MatchPattern(candidate, req.ToolName, req.ToolInput) {
+				return candidate
+			}
+		}
+		log.Warn().
+			Str("tool_use_id", req.ToolUseID).
+			Str("pattern", requested).
+			Msg("Requested pattern does not match the request - using generated pattern")
+	}
+	return permission.GeneratePattern(req.ToolName, req.ToolInput)
 }
 
 // Stats returns permission system statistics.
@@ -409,6 +795,16 @@ type PendingRequestInfo struct {
 	ToolName    string                 `json:"tool_name"`
 	ToolInput   map[string]interface{} `json:"tool_input"`
 	CreatedAt   string                 `json:"created_at"`
+	Risk        *permission.Risk       `json:"risk,omitempty"`
+	AgentType   string                 `json:"agent_type"`
+	Pattern     string                 `json:"pattern"`  // Generated pattern, shared by the group
+	GroupID     string                 `json:"group_id"` // Group for permission/respond_batch
+
+	// Multi-device routing state
+	NotifiedDevices   []string `json:"notified_devices,omitempty"`
+	Escalated         bool     `json:"escalated,omitempty"`
+	RequiredApprovals int      `json:"required_approvals,omitempty"`
+	Approvals         []string `json:"approvals,omitempty"`
 }
 
 // Pending returns all pending permission requests.
@@ -430,12 +826,15 @@ func (s *PermissionService) Pending(ctx context.Context, params json.RawMessage)
 
 	// Convert to response format, optionally filtering by session_id
 	result := make([]PendingRequestInfo, 0, len(requests))
+	listed := make([]*permission.Request, 0, len(requests))
 	for _, req := range requests {
 		// Filter by session_id if provided
 		if p.SessionID != "" && req.SessionID != p.SessionID {
 			continue
 		}
 
+		pattern, groupID := pendingGroupKey(req)
+		listed = append(listed, req)
 		result = append(result, PendingRequestInfo{
 			ToolUseID:   req.ToolUseID,
 			SessionID:   req.SessionID,
@@ -443,6 +842,15 @@ func (s *PermissionService) Pending(ctx context.Context, params json.RawMessage)
 			ToolName:    req.ToolName,
 			ToolInput:   req.ToolInput,
 			CreatedAt:   req.CreatedAt.Format(time.RFC3339),
+			Risk:        req.Risk,
+			AgentType:   req.AgentType,
+			Pattern:     pattern,
+			GroupID:     groupID,
+
+			NotifiedDevices:   req.NotifiedDevices,
+			Escalated:         req.Escalated,
+			RequiredApprovals: req.RequiredApprovals,
+			Approvals:         req.Approvals,
 		})
 	}
 
@@ -454,6 +862,7 @@ func (s *PermissionService) Pending(ctx context.Context, params json.RawMessage)
 	return map[string]interface{}{
 		"requests": result,
 		"count":    len(result),
+		"groups":   groupPendingRequests(listed),
 	}, nil
 }
 
@@ -0,0 +1,163 @@
+package methods
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultAuditLimit is the page size of permission/audit when no limit is given.
+const defaultAuditLimit = 100
+
+// registerAuditMethods registers the permission audit query method.
+func (s *PermissionService) registerAuditMethods(r *handler.Registry) {
+	r.RegisterWithMeta("permission/audit", s.Audit, handler.MethodMeta{
+		Summary:     "Query the permission decision audit log",
+		Description: "Returns recorded permission decisions, newest first: the request, what decided it (policy, rule, memory, device, timeout), whether the input was edited before approval, the risk tier, the responding device, the host and the latency. With format 'jsonl' or 'csv' the matching entries are returned as an export document instead.",
+		Params: []handler.OpenRPCParam{
+			{Name: "workspace_id", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Optional: filter by workspace ID"}},
+			{Name: "session_id", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Optional: filter by session ID"}},
+			{Name: "tool", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Optional: filter by tool name"}},
+			{Name: "decision", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"allow", "deny", "ask"}}},
+			{Name: "source", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Optional: policy, rule, memory, device, bypass, timeout, cancelled, no_clients or unconfigured"}},
+			{Name: "risk", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"low", "medium", "high", "critical"}}},
+			{Name: "device", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Optional: filter by responding device or client ID"}},
+			{Name: "modified", Required: false, Schema: map[string]interface{}{"type": "boolean", "description": "Optional: only approvals with edited input"}},
+			{Name: "since", Required: false, Schema: map[string]interface{}{"type": "string", "format": "date-time"}},
+			{Name: "until", Required: false, Schema: map[string]interface{}{"type": "string", "format": "date-time"}},
+			{Name: "limit", Required: false, Schema: map[string]interface{}{"type": "integer", "default": defaultAuditLimit, "description": "Page size; exports are unlimited unless set"}},
+			{Name: "offset", Required: false, Schema: map[string]interface{}{"type": "integer", "default": 0}},
+			{Name: "format", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"json", "jsonl", "csv"}, "default": "json"}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name: "AuditResult",
+			Schema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"entries": map[string]interface{}{"type": "array"},
+					"count":   map[string]interface{}{"type": "integer"},
+					"total":   map[string]interface{}{"type": "integer"},
+					"content": map[string]interface{}{"type": "string", "description": "Export document for jsonl/csv formats"},
+				},
+			},
+		},
+		Concurrency: handler.ConcurrencyParallel,
+	})
+}
+
+// AuditParams for permission/audit method.
+type AuditParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	SessionID   string `json:"session_id"`
+	Tool        string `json:"tool"`
+	Decision    string `json:"decision"`
+	Source      string `json:"source"`
+	Risk        string `json:"risk"`
+	Device      string `json:"device"`
+	Modified    bool   `json:"modified"`
+	Since       string `json:"since"`
+	Until       string `json:"until"`
+	Limit       int    `json:"limit"`
+	Offset      int    `json:"offset"`
+	Format      string `json:"format"`
+}
+
+// Audit queries or exports the permission audit log.
+func (s *PermissionService) Audit(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	if s.audit == nil {
+		return nil, message.ErrInternalError("Permission audit log not available")
+	}
+
+	var p AuditParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, message.ErrInvalidParams(fmt.Sprintf("Invalid params: %v", err))
+		}
+	}
+
+	format := p.Format
+	if format == "" {
+		format = permission.AuditFormatJSON
+	}
+	if !permission.ValidAuditFormat(format) {
+		return nil, message.ErrInvalidParams("format must be 'json', 'jsonl' or 'csv'")
+	}
+	if p.Limit < 0 || p.Offset < 0 {
+		return nil, message.ErrInvalidParams("limit and offset must not be negative")
+	}
+
+	filter := permission.AuditFilter{
+		WorkspaceID: p.WorkspaceID,
+		SessionID:   p.SessionID,
+		ToolName:    p.Tool,
+		Decision:    p.Decision,
+		Source:      p.Source,
+		Risk:        p.Risk,
+		RespondedBy: p.Device,
+		Modified:    p.Modified,
+		Limit:       p.Limit,
+		Offset:      p.Offset,
+	}
+	if format == permission.AuditFormatJSON && filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	for _, bound := range []struct {
+		name  string
+		value string
+		dst   *time.Time
+	}{
+		{"since", p.Since, &filter.Since},
+		{"until", p.Until, &filter.Until},
+	} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			return nil, message.ErrInvalidParams(bound.name + " must be an RFC3339 timestamp")
+		}
+		*bound.dst = t
+	}
+
+	entries, total, err := s.audit.Query(filter)
+	if err != nil {
+		return nil, message.ErrInternalError(fmt.Sprintf("Failed to query audit log: %v", err))
+	}
+
+	if format == permission.AuditFormatJSON {
+		return map[string]interface{}{
+			"entries": entries,
+			"count":   len(entries),
+			"total":   total,
+		}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := permission.WriteAuditExport(&buf, entries, format); err != nil {
+		return nil, message.ErrInternalError(fmt.Sprintf("Failed to export audit log: %v", err))
+	}
+	return map[string]interface{}{
+		"format":  format,
+		"content": buf.String(),
+		"count":   len(entries),
+		"total":   total,
+	}, nil
+}
+
+// recordAudit stores a decided permission request in the audit log, if configured.
+// Audit failures are logged but never change the decision.
+func (s *PermissionService) recordAudit(entry permission.AuditEntry) {
+	if s.audit == nil {
+		return
+	}
+	if err := s.audit.Record(entry); err != nil {
+		log.Warn().Err(err).Str("tool_use_id", entry.ToolUseID).Msg("Failed to record permission audit entry")
+	}
+}
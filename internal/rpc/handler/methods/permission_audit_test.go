@@ -0,0 +1,158 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/security"
+)
+
+func TestPermissionService_AuditRecordsDeviceResponse(t *testing.T) {
+	audit, err := permission.OpenAuditLog(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("OpenAuditLog: %v", err)
+	}
+	defer func() { _ = audit.Close() }()
+
+	manager := newMockPermissionManager()
+	publisher := newMockEventPublisher()
+	publisher.SetSubscriberCount(2)
+	service := NewPermissionService(manager, publisher, &mockWorkspaceResolver{workspaceID: "ws-1"})
+	service.SetAuditLog(audit)
+	service.SetTimeout(5 * time.Second)
+
+	params, _ := json.Marshal(map[string]interface{}{
+		"session_id":  "sess-1",
+		"tool_name":   "Bash",
+		"tool_input":  map[string]interface{}{"command": "make deploy"},
+		"tool_use_id": "tool-audit",
+		"cwd":         "/repo",
+	})
+	done := make(chan *permission.Response, 1)
+	go func() {
+		result, _ := service.Request(context.Background(), params)
+		done <- result.(*permission.Response)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for manager.GetPendingRequest("tool-audit") == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("request never became pending")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ctx := context.WithValue(context.Background(), handler.AuthPayloadKey, &security.TokenPayload{DeviceID: "iphone-7"})
+	respondParams, _ := json.Marshal(map[string]interface{}{"tool_use_id": "tool-audit", "decision": "allow"})
+	if _, rpcErr := service.Respond(ctx, respondParams); rpcErr != nil {
+		t.Fatalf("Respond: %v", rpcErr)
+	}
+	if resp := <-done; resp.RespondedBy != "iphone-7" {
+		t.Fatalf("response should carry the responding device, got %+v", resp)
+	}
+
+	queryParams, _ := json.Marshal(map[string]interface{}{"workspace_id": "ws-1", "tool": "Bash"})
+	result, rpcErr := service.Audit(context.Background(), queryParams)
+	if rpcErr != nil {
+		t.Fatalf("Audit: %v", rpcErr)
+	}
+	entries := result.(map[string]interface{})["entries"].([]permission.AuditEntry)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Source != permission.AuditSourceDevice || entry.RespondedBy != "iphone-7" ||
+		entry.Decision != permission.DecisionAllow || entry.Channel != "rpc" || entry.Target != "make deploy" {
+		t.Errorf("unexpected audit entry: %+v", entry)
+	}
+
+	exportParams, _ := json.Marshal(map[string]interface{}{"format": "csv", "decision": "allow"})
+	result, rpcErr = service.Audit(context.Background(), exportParams)
+	if rpcErr != nil {
+		t.Fatalf("Audit export: %v", rpcErr)
+	}
+	content := result.(map[string]interface{})["content"].(string)
+	if !strings.HasPrefix(content, "id,decided_at") || !strings.Contains(content, "iphone-7") {
+		t.Errorf("unexpected csv export:\n%s", content)
+	}
+}
+
+func TestPermissionService_AuditRecordsMemoryAndPolicy(t *testing.T) {
+	audit, err := permission.OpenAuditLog(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("OpenAuditLog: %v", err)
+	}
+	defer func() { _ = audit.Close() }()
+
+	manager := newMockPermissionManager()
+	manager.memoryDecisions["sess-1:Bash"] = &permission.StoredDecision{
+		Pattern: "Bash(go test:*)", Decision: permission.DecisionAllow, Scope: permission.ScopePath, RuleID: "rule-1", CreatedBy: "ipad",
+	}
+	policy := &mockPolicyEvaluator{}
+	service := NewPermissionService(manager, newMockEventPublisher(), &mockWorkspaceResolver{workspaceID: "ws-1"})
+	service.SetPolicyEvaluator(policy)
+	service.SetAuditLog(audit)
+
+	request := func(toolUseID string) {
+		t.Helper()
+		params, _ := json.Marshal(map[string]interface{}{
+			"session_id":  "sess-1",
+			"tool_name":   "Bash",
+			"tool_input":  map[string]interface{}{"command": "go test ./..."},
+			"tool_use_id": toolUseID,
+			"cwd":         "/repo",
+		})
+		if _, rpcErr := service.Request(context.Background(), params); rpcErr != nil {
+			t.Fatalf("Request: %v", rpcErr)
+		}
+	}
+
+	request("tool-rule")
+	policy.decision = &permission.PolicyDecision{Decision: permission.DecisionDeny, Rule: "freeze", Reason: "release freeze"}
+	request("tool-policy")
+
+	entries, _, err := audit.Query(permission.AuditFilter{})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d (%v)", len(entries), err)
+	}
+	byID := map[string]permission.AuditEntry{}
+	for _, e := range entries {
+		byID[e.ToolUseID] = e
+	}
+	if e := byID["tool-rule"]; e.Source != permission.AuditSourceRule || e.RuleID != "rule-1" || e.RespondedBy != "ipad" {
+		t.Errorf("unexpected rule entry: %+v", e)
+	}
+	if e := byID["tool-policy"]; e.Source != permission.AuditSourcePolicy || e.PolicyRule != "freeze" || e.Decision != permission.DecisionDeny {
+		t.Errorf("unexpected policy entry: %+v", e)
+	}
+}
+
+func TestPermissionService_AuditValidation(t *testing.T) {
+	service := NewPermissionService(newMockPermissionManager(), newMockEventPublisher(), nil)
+	if _, rpcErr := service.Audit(context.Background(), nil); rpcErr == nil {
+		t.Fatal("expected error without an audit log")
+	}
+
+	audit, err := permission.OpenAuditLog(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("OpenAuditLog: %v", err)
+	}
+	defer func() { _ = audit.Close() }()
+	service.SetAuditLog(audit)
+
+	for _, params := range []string{
+		`{"format":"xml"}`,
+		`{"since":"yesterday"}`,
+		`{"limit":-1}`,
+	} {
+		if _, rpcErr := service.Audit(context.Background(), json.RawMessage(params)); rpcErr == nil {
+			t.Errorf("expected invalid params error for %s", params)
+		}
+	}
+}
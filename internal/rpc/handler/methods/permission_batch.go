@@ -0,0 +1,224 @@
+package methods
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/rs/zerolog/log"
+)
+
+// registerBatchMethods registers permission/respond_batch.
+func (s *PermissionService) registerBatchMethods(r *handler.Registry) {
+	r.RegisterWithMeta("permission/respond_batch", s.RespondBatch, handler.MethodMeta{
+		Summary:     "Respond to several pending permission requests at once",
+		Description: "Applies one decision to a group from permission/pending (requests of a workspace sharing a generated pattern) or to a list of tool use IDs, across sessions. A group is only answered if it still holds the requests that were displayed, as its revision shows. Each request is answered as with permission/respond, so session scope remembers the pattern for every session of the batch and path scope stores one workspace rule.",
+		Params: []handler.OpenRPCParam{
+			{Name: "group_id", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Group ID from permission/pending. Answers the requests of the group, if it has not changed."}},
+			{Name: "revision", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Revision of the group from permission/pending, required with group_id"}},
+			{Name: "tool_use_ids", Required: false, Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Requests to answer, instead of group_id"}},
+			{Name: "decision", Required: true, Schema: map[string]interface{}{"type": "string", "enum": []string{"allow", "deny"}}},
+			{Name: "scope", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"once", "session", "path"}, "default": "once"}},
+			{Name: "pattern", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Pattern to remember for session/path scope. Requests it does not match use their generated pattern."}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "RespondBatchResult",
+			Schema: map[string]interface{}{"type": "object"},
+		},
+	})
+}
+
+// PendingGroup is a set of pending requests of one workspace that share a
+// generated pattern and can be answered together.
+type PendingGroup struct {
+	GroupID     string               `json:"group_id"`
+	WorkspaceID string               `json:"workspace_id"`
+	Pattern     string               `json:"pattern"`
+	ToolName    string               `json:"tool_name"`
+	Count       int                  `json:"count"`
+	Revision    string               `json:"revision"` // Changes when a request joins or leaves the group
+	ToolUseIDs  []string             `json:"tool_use_ids"`
+	SessionIDs  []string             `json:"session_ids"`
+	Risk        permission.RiskLevel `json:"risk,omitempty"` // Highest risk level in the group
+	OldestAt    string               `json:"oldest_at"`
+
+	oldest time.Time
+}
+
+// pendingGroupKey returns the generated pattern of a request and the ID of
+// its group.
+func pendingGroupKey(req *permission.Request) (pattern, groupID string) {
+	pattern = permission.GeneratePattern(req.ToolName, req.ToolInput)
+	sum := sha256.Sum256([]byte(req.WorkspaceID + "\x00" + pattern))
+	return pattern, hex.EncodeToString(sum[:8])
+}
+
+// groupPendingRequests groups requests by workspace and generated pattern,
+// oldest group first.
+func groupPendingRequests(requests []*permission.Request) []PendingGroup {
+	index := make(map[string]int)
+	groups := make([]PendingGroup, 0)
+	for _, req := range requests {
+		pattern, groupID := pendingGroupKey(req)
+		i, ok := index[groupID]
+		if !ok {
+			i = len(groups)
+			index[groupID] = i
+			groups = append(groups, PendingGroup{
+				GroupID:     groupID,
+				WorkspaceID: req.WorkspaceID,
+				Pattern:     pattern,
+				ToolName:    req.ToolName,
+				oldest:      req.CreatedAt,
+			})
+		}
+		g := &groups[i]
+		g.Count++
+		g.ToolUseIDs = append(g.ToolUseIDs, req.ToolUseID)
+		if !slices.Contains(g.SessionIDs, req.SessionID) {
+			g.SessionIDs = append(g.SessionIDs, req.SessionID)
+		}
+		if req.Risk != nil && (g.Risk == "" || req.Risk.Level.AtLeast(g.Risk)) {
+			g.Risk = req.Risk.Level
+		}
+		if req.CreatedAt.Before(g.oldest) {
+			g.oldest = req.CreatedAt
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].oldest.Before(groups[j].oldest)
+	})
+	for i := range groups {
+		groups[i].OldestAt = groups[i].oldest.Format(time.RFC3339)
+		groups[i].Revision = groupRevision(groups[i].ToolUseIDs)
+	}
+	return groups
+}
+
+// groupRevision identifies the set of requests in a group, so a batch
+// answers only the requests a device displayed.
+func groupRevision(toolUseIDs []string) string {
+	ids := slices.Clone(toolUseIDs)
+	sort.Strings(ids)
+	sum := sha256.Sum256([]byte(strings.Join(ids, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// RespondBatchParams for permission/respond_batch method.
+type RespondBatchParams struct {
+	GroupID    string   `json:"group_id,omitempty"`
+	Revision   string   `json:"revision,omitempty"` // Group revision the device displayed
+	ToolUseIDs []string `json:"tool_use_ids,omitempty"`
+	Decision   string   `json:"decision"`          // "allow" or "deny"
+	Scope      string   `json:"scope"`             // "once", "session" or "path"
+	Pattern    string   `json:"pattern,omitempty"` // Optional pattern for session/path scope
+}
+
+// RespondBatchItem is the outcome for one request of a batch.
+type RespondBatchItem struct {
+	ToolUseID string `json:"tool_use_id"`
+	Success   bool   `json:"success"`
+	Status    string `json:"status,omitempty"` // "awaiting_approvals" when more devices must approve
+	Pattern   string `json:"pattern,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// RespondBatch applies one decision to several pending requests.
+func (s *PermissionService) RespondBatch(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	if s.manager == nil {
+		return nil, message.ErrInternalError("Permission manager not available")
+	}
+
+	var p RespondBatchParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.ErrInvalidParams(fmt.Sprintf("Invalid params: %v", err))
+	}
+	if p.Decision != "allow" && p.Decision != "deny" {
+		return nil, message.ErrInvalidParams("decision must be 'allow' or 'deny'")
+	}
+	if (p.GroupID == "") == (len(p.ToolUseIDs) == 0) {
+		return nil, message.ErrInvalidParams("exactly one of group_id or tool_use_ids is required")
+	}
+
+	if p.GroupID != "" && p.Revision == "" {
+		return nil, message.ErrInvalidParams("revision is required with group_id")
+	}
+
+	ids := p.ToolUseIDs
+	if p.GroupID != "" {
+		ids = nil
+		for _, req := range s.manager.ListPendingRequests() {
+			if _, groupID := pendingGroupKey(req); groupID == p.GroupID {
+				ids = append(ids, req.ToolUseID)
+			}
+		}
+		if len(ids) == 0 {
+			return nil, message.ErrInvalidParams("Group not found or already responded")
+		}
+		// Requests that joined or left the group since it was displayed
+		// must not be answered blindly.
+		if revision := groupRevision(ids); revision != p.Revision {
+			return nil, message.NewErrorWithData(message.InvalidParams, "Group changed since it was displayed - reload permission/pending", map[string]interface{}{
+				"group_id":     p.GroupID,
+				"revision":     revision,
+				"tool_use_ids": ids,
+			})
+		}
+	}
+
+	items := make([]RespondBatchItem, 0, len(ids))
+	responded := 0
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		item := RespondBatchItem{ToolUseID: id}
+		result, rpcErr := s.respond(ctx, RespondParams{
+			ToolUseID: id,
+			Decision:  p.Decision,
+			Scope:     p.Scope,
+			Pattern:   p.Pattern,
+		})
+		if rpcErr != nil {
+			item.Error = rpcErr.Message
+		} else {
+			item.Success = true
+			item.Status, _ = result["status"].(string)
+			item.Pattern, _ = result["pattern"].(string)
+			responded++
+		}
+		items = append(items, item)
+	}
+
+	log.Info().
+		Str("group_id", p.GroupID).
+		Str("decision", p.Decision).
+		Str("scope", p.Scope).
+		Int("requested", len(items)).
+		Int("responded", responded).
+		Msg("Batch permission response")
+
+	result := map[string]interface{}{
+		"success":   responded == len(items),
+		"responded": responded,
+		"failed":    len(items) - responded,
+		"results":   items,
+	}
+	if p.GroupID != "" {
+		result["group_id"] = p.GroupID
+	}
+	return result, nil
+}
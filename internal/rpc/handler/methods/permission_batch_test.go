@@ -0,0 +1,162 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+)
+
+func addPendingBash(manager *mockPermissionManager, toolUseID, sessionID, workspaceID, command string, age time.Duration) *permission.Request {
+	req := &permission.Request{
+		ID:           toolUseID,
+		SessionID:    sessionID,
+		WorkspaceID:  workspaceID,
+		ToolName:     "Bash",
+		ToolInput:    map[string]interface{}{"command": command},
+		ToolUseID:    toolUseID,
+		CreatedAt:    time.Now().Add(-age),
+		ResponseChan: make(chan *permission.Response, 1),
+	}
+	manager.AddPendingRequest(req)
+	return req
+}
+
+func TestPermissionService_Pending_GroupsByPatternAndWorkspace(t *testing.T) {
+	manager := newMockPermissionManager()
+	addPendingBash(manager, "tool-1", "session-1", "ws-1", "npm install lodash", time.Minute)
+	addPendingBash(manager, "tool-2", "session-2", "ws-1", "npm install react", 2*time.Minute)
+	addPendingBash(manager, "tool-3", "session-3", "ws-2", "npm install react", 3*time.Minute)
+	addPendingBash(manager, "tool-4", "session-1", "ws-1", "go test ./...", 0)
+
+	service := NewPermissionService(manager, newMockEventPublisher(), nil)
+	result, err := service.Pending(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Pending failed: %v", err)
+	}
+
+	groups := result.(map[string]interface{})["groups"].([]PendingGroup)
+	if len(groups) != 3 {
+		t.Fatalf("got %d groups, want 3: %+v", len(groups), groups)
+	}
+	// Oldest group first
+	if groups[0].WorkspaceID != "ws-2" || groups[1].WorkspaceID != "ws-1" {
+		t.Errorf("group order = %s, %s, want ws-2 then ws-1", groups[0].WorkspaceID, groups[1].WorkspaceID)
+	}
+	npm := groups[1]
+	if npm.Pattern != "Bash(npm install:*)" || npm.Count != 2 || len(npm.SessionIDs) != 2 {
+		t.Errorf("npm group = %+v, want 2 requests of 2 sessions for Bash(npm install:*)", npm)
+	}
+	if groups[0].GroupID == npm.GroupID {
+		t.Error("same pattern in different workspaces must not share a group")
+	}
+
+	requests := result.(map[string]interface{})["requests"].([]PendingRequestInfo)
+	for _, req := range requests {
+		if req.ToolUseID == "tool-1" && req.GroupID != npm.GroupID {
+			t.Errorf("tool-1 group_id = %q, want %q", req.GroupID, npm.GroupID)
+		}
+	}
+}
+
+func TestPermissionService_RespondBatch_Group(t *testing.T) {
+	manager := newMockPermissionManager()
+	first := addPendingBash(manager, "tool-1", "session-1", "ws-1", "npm install lodash", time.Minute)
+	second := addPendingBash(manager, "tool-2", "session-2", "ws-1", "npm install react", time.Minute)
+	other := addPendingBash(manager, "tool-3", "session-1", "ws-1", "go test ./...", time.Minute)
+
+	service := NewPermissionService(manager, newMockEventPublisher(), nil)
+	_, groupID := pendingGroupKey(first)
+	displayed := groupRevision([]string{"tool-1", "tool-2"})
+
+	// A request that joined the group after it was displayed is not
+	// answered blindly.
+	late := addPendingBash(manager, "tool-4", "session-3", "ws-1", "npm install left-pad", 0)
+	params, _ := json.Marshal(map[string]interface{}{
+		"group_id": groupID,
+		"revision": displayed,
+		"decision": "allow",
+		"scope":    "session",
+	})
+	if _, err := service.RespondBatch(context.Background(), params); err == nil || err.Code != message.InvalidParams {
+		t.Fatalf("changed group: error = %v, want InvalidParams", err)
+	}
+	if manager.GetPendingRequest(first.ToolUseID) == nil {
+		t.Fatal("a changed group must not be answered")
+	}
+	manager.RemovePendingRequest(late.ToolUseID)
+
+	result, err := service.RespondBatch(context.Background(), params)
+	if err != nil {
+		t.Fatalf("RespondBatch failed: %v", err)
+	}
+	batch := result.(map[string]interface{})
+	if batch["responded"] != 2 || batch["failed"] != 0 {
+		t.Errorf("batch result = %+v, want 2 responded", batch)
+	}
+
+	// Every waiting request of the group is unblocked and remembered
+	for _, req := range []*permission.Request{first, second} {
+		select {
+		case response := <-req.ResponseChan:
+			if response.Decision != permission.DecisionAllow || response.Pattern != "Bash(npm install:*)" {
+				t.Errorf("%s response = %+v", req.ToolUseID, response)
+			}
+		default:
+			t.Errorf("%s was not answered", req.ToolUseID)
+		}
+		if manager.memoryDecisions[req.SessionID+":Bash(npm install:*)"] == nil {
+			t.Errorf("decision not remembered for %s", req.SessionID)
+		}
+	}
+	if manager.GetPendingRequest(other.ToolUseID) == nil {
+		t.Error("request outside the group must stay pending")
+	}
+
+	// The group is gone once answered
+	if _, err := service.RespondBatch(context.Background(), params); err == nil {
+		t.Error("answering an answered group should fail")
+	}
+}
+
+func TestPermissionService_RespondBatch_ToolUseIDs(t *testing.T) {
+	manager := newMockPermissionManager()
+	req := addPendingBash(manager, "tool-1", "session-1", "ws-1", "rm -rf build", time.Minute)
+
+	service := NewPermissionService(manager, newMockEventPublisher(), nil)
+
+	for name, params := range map[string]map[string]interface{}{
+		"no selection":   {"decision": "deny"},
+		"both selectors": {"decision": "deny", "group_id": "abc", "tool_use_ids": []string{"tool-1"}},
+		"no revision":    {"decision": "deny", "group_id": "abc"},
+		"bad decision":   {"decision": "maybe", "tool_use_ids": []string{"tool-1"}},
+	} {
+		raw, _ := json.Marshal(params)
+		if _, err := service.RespondBatch(context.Background(), raw); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	raw, _ := json.Marshal(map[string]interface{}{
+		"tool_use_ids": []string{"tool-1", "tool-1", "missing"},
+		"decision":     "deny",
+	})
+	result, err := service.RespondBatch(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("RespondBatch failed: %v", err)
+	}
+	batch := result.(map[string]interface{})
+	items := batch["results"].([]RespondBatchItem)
+	if len(items) != 2 || !items[0].Success || items[1].Success || items[1].Error == "" {
+		t.Errorf("results = %+v, want tool-1 answered and missing failed", items)
+	}
+	if batch["success"] != false {
+		t.Errorf("success = %v, want false when a request failed", batch["success"])
+	}
+	if response := <-req.ResponseChan; response.Decision != permission.DecisionDeny {
+		t.Errorf("Decision = %s, want deny", response.Decision)
+	}
+}
@@ -0,0 +1,50 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/brianly1003/cdev/internal/adapters/codex"
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/rs/zerolog/log"
+)
+
+// CodexApproval is an approval prompt shown by a Codex session running in a
+// cdev-managed PTY.
+type CodexApproval struct {
+	SessionID   string
+	WorkspaceID string
+	Cwd         string
+	ToolUseID   string // Synthesized; Codex does not expose its call ID in the TUI
+	Prompt      *codex.ApprovalPrompt
+}
+
+// DecideCodexApproval runs a Codex approval prompt through the same
+// policies, workspace rules, session memory and pty_permission prompt as a
+// Claude hook request, so clients answer both with permission/respond.
+// It blocks until the prompt is decided, times out or ctx is cancelled.
+//
+// A multi-line command may have been wrapped by the TUI, so policies and
+// remembered decisions may deny it but only a device may allow it.
+func (s *PermissionService) DecideCodexApproval(ctx context.Context, approval CodexApproval) *permission.Response {
+	toolName, toolInput := approval.Prompt.ToolCall()
+	log.Info().
+		Str("session_id", approval.SessionID).
+		Str("tool_use_id", approval.ToolUseID).
+		Str("kind", string(approval.Prompt.Kind)).
+		Str("tool_name", toolName).
+		Msg("Codex approval prompt detected")
+
+	return s.decide(ctx, permissionCall{
+		RequestParams: RequestParams{
+			SessionID: approval.SessionID,
+			ToolName:  toolName,
+			ToolInput: toolInput,
+			ToolUseID: approval.ToolUseID,
+			Cwd:       approval.Cwd,
+		},
+		workspaceID: approval.WorkspaceID,
+		agentType:   sessionManagerAgentCodex,
+		channel:     sessionManagerAgentCodex,
+		mustAsk:     approval.Prompt.Multiline,
+	})
+}
@@ -0,0 +1,57 @@
+package methods
+
+import (
+	"context"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/google/uuid"
+)
+
+// AuthorizeToolCall decides whether an MCP tool call may run. Tool names are
+// mcp__cdev__<tool>, so mcp rules of the permission policy match them.
+//
+// Read-only calls no rule matches are allowed: MCP clients are agents of
+// the local user, and those tools only read what cdev exposes. Other calls
+// no rule matches go through remembered decisions to the mobile app, like
+// a hook request. Policy allows and denies are recorded in the audit log,
+// and an ask rule prompts the mobile app. It returns nil when the call may
+// run without a decision.
+func (s *PermissionService) AuthorizeToolCall(ctx context.Context, channel, workspaceID, toolName string, toolInput map[string]interface{}, readOnly bool) *permission.Response {
+	var policy *permission.PolicyDecision
+	if s.policy != nil {
+		policy = s.policy.Evaluate(permission.PolicyInput{
+			ToolName:    toolName,
+			ToolInput:   toolInput,
+			WorkspaceID: workspaceID,
+		})
+	}
+	if policy == nil && readOnly {
+		return nil
+	}
+
+	if policy != nil && policy.Decision != permission.DecisionAsk {
+		now := time.Now()
+		s.recordAudit(permission.AuditEntry{
+			ToolUseID:   uuid.New().String(),
+			SessionID:   channel,
+			WorkspaceID: workspaceID,
+			ToolName:    toolName,
+			ToolInput:   toolInput,
+			Channel:     "mcp",
+			RequestedAt: now,
+		}.WithPolicy(policy))
+		return policyResponse(policy)
+	}
+
+	return s.decide(ctx, permissionCall{
+		RequestParams: RequestParams{
+			SessionID: channel,
+			ToolName:  toolName,
+			ToolInput: toolInput,
+			ToolUseID: uuid.New().String(),
+		},
+		workspaceID: workspaceID,
+		channel:     "mcp",
+	})
+}
@@ -0,0 +1,57 @@
+package methods
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/permission"
+)
+
+func TestPermissionService_AuthorizeToolCall(t *testing.T) {
+	policy := &mockPolicyEvaluator{}
+	service := NewPermissionService(newMockPermissionManager(), newMockEventPublisher(), &mockWorkspaceResolver{workspaceID: "test-workspace"})
+	service.SetTimeout(50 * time.Millisecond)
+
+	authorize := func() *permission.Response {
+		return service.AuthorizeToolCall(context.Background(), "cdev", "ws-1", "mcp__cdev__read_file", map[string]interface{}{"path": ".env"}, true)
+	}
+	createTask := func() *permission.Response {
+		return service.AuthorizeToolCall(context.Background(), "cdev", "ws-1", "mcp__cdev__create_task", map[string]interface{}{"prompt": "fix it"}, false)
+	}
+
+	// Without a policy engine read-only calls run, and others are asked,
+	// which nobody answers here.
+	if resp := authorize(); resp != nil {
+		t.Fatalf("expected no decision without a policy, got %+v", resp)
+	}
+	if resp := createTask(); resp == nil || resp.Decision == permission.DecisionAllow {
+		t.Fatalf("expected create_task to be asked without a policy, got %+v", resp)
+	}
+
+	service.SetPolicyEvaluator(policy)
+
+	// Read-only calls no rule matches run.
+	if resp := authorize(); resp != nil {
+		t.Fatalf("expected no decision without a matching rule, got %+v", resp)
+	}
+	if resp := createTask(); resp == nil || resp.Decision == permission.DecisionAllow {
+		t.Fatalf("expected create_task to be asked without a matching rule, got %+v", resp)
+	}
+
+	policy.decision = &permission.PolicyDecision{Decision: permission.DecisionDeny, Reason: "no secrets"}
+	if resp := authorize(); resp == nil || resp.Decision != permission.DecisionDeny || resp.Message != "no secrets" {
+		t.Fatalf("expected policy deny, got %+v", resp)
+	}
+
+	policy.decision = &permission.PolicyDecision{Decision: permission.DecisionAllow, Rule: "tasks"}
+	if resp := createTask(); resp == nil || resp.Decision != permission.DecisionAllow || resp.PolicyRule != "tasks" {
+		t.Fatalf("expected policy allow, got %+v", resp)
+	}
+
+	// Ask goes to mobile, which does not answer here.
+	policy.decision = &permission.PolicyDecision{Decision: permission.DecisionAsk}
+	if resp := authorize(); resp == nil || resp.Decision == permission.DecisionAllow {
+		t.Fatalf("expected ask to end in a refusal, got %+v", resp)
+	}
+}
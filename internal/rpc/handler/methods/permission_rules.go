@@ -0,0 +1,214 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/brianly1003/cdev/internal/security"
+)
+
+// registerRuleMethods registers the persistent workspace rule methods.
+func (s *PermissionService) registerRuleMethods(r *handler.Registry) {
+	r.RegisterWithMeta("permission/rules/list", s.RulesList, handler.MethodMeta{
+		Summary:     "List persistent permission rules",
+		Description: "Returns the unexpired path-scoped rules of a workspace, or of all workspaces when workspace_id is omitted.",
+		Params: []handler.OpenRPCParam{
+			{Name: "workspace_id", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Optional: filter by workspace ID"}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "RulesListResult",
+			Schema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"rules": map[string]interface{}{"type": "array"}}},
+		},
+		Concurrency: handler.ConcurrencyParallel,
+	})
+
+	r.RegisterWithMeta("permission/rules/add", s.RulesAdd, handler.MethodMeta{
+		Summary:     "Add a persistent permission rule",
+		Description: "Stores an allow or deny rule for a workspace. Rules are checked before session memory and survive restarts; deny rules win over allow rules.",
+		Params: []handler.OpenRPCParam{
+			{Name: "workspace_id", Required: true, Schema: map[string]interface{}{"type": "string", "description": "Workspace the rule applies to"}},
+			{Name: "tool", Required: true, Schema: map[string]interface{}{"type": "string", "description": "Tool name (Bash, Write, Edit, Read, ...)"}},
+			{Name: "decision", Required: true, Schema: map[string]interface{}{"type": "string", "enum": []string{"allow", "deny"}}},
+			{Name: "pattern", Required: false, Schema: map[string]interface{}{"type": "string", "default": "*", "description": "Pattern content, e.g. 'git status:*' or 'src/*.go'. Relative file patterns are anchored at the workspace root."}},
+			{Name: "expires_at", Required: false, Schema: map[string]interface{}{"type": "string", "format": "date-time", "description": "Optional RFC3339 expiry"}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "Rule",
+			Schema: map[string]interface{}{"type": "object"},
+		},
+		UnknownParams: handler.UnknownParamsReject,
+	})
+
+	r.RegisterWithMeta("permission/rules/remove", s.RulesRemove, handler.MethodMeta{
+		Summary:     "Remove a persistent permission rule",
+		Description: "Deletes a workspace rule by ID.",
+		Params: []handler.OpenRPCParam{
+			{Name: "workspace_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "rule_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "RulesRemoveResult",
+			Schema: map[string]interface{}{"type": "object"},
+		},
+	})
+}
+
+// RulesList returns persistent permission rules.
+func (s *PermissionService) RulesList(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	if s.manager == nil {
+		return nil, message.ErrInternalError("Permission manager not available")
+	}
+
+	var p struct {
+		WorkspaceID string `json:"workspace_id"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, message.ErrInvalidParams(fmt.Sprintf("Invalid params: %v", err))
+		}
+	}
+
+	rules := s.manager.ListRules(strings.TrimSpace(p.WorkspaceID))
+	return map[string]interface{}{
+		"rules": rules,
+		"count": len(rules),
+	}, nil
+}
+
+// RulesAdd stores a persistent permission rule.
+func (s *PermissionService) RulesAdd(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	if s.manager == nil {
+		return nil, message.ErrInternalError("Permission manager not available")
+	}
+
+	var p struct {
+		WorkspaceID string `json:"workspace_id"`
+		Tool        string `json:"tool"`
+		Decision    string `json:"decision"`
+		Pattern     string `json:"pattern"`
+		ExpiresAt   string `json:"expires_at"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.ErrInvalidParams(fmt.Sprintf("Invalid params: %v", err))
+	}
+
+	if strings.TrimSpace(p.WorkspaceID) == "" {
+		return nil, message.ErrInvalidParams("workspace_id is required")
+	}
+	if strings.TrimSpace(p.Tool) == "" {
+		return nil, message.ErrInvalidParams("tool is required")
+	}
+	if p.Decision != "allow" && p.Decision != "deny" {
+		return nil, message.ErrInvalidParams("decision must be 'allow' or 'deny'")
+	}
+
+	rule := permission.Rule{
+		WorkspaceID: strings.TrimSpace(p.WorkspaceID),
+		Decision:    permission.Decision(p.Decision),
+		Tool:        strings.TrimSpace(p.Tool),
+		Pattern:     strings.TrimSpace(p.Pattern),
+		CreatedBy:   requestingDevice(ctx),
+	}
+	if rule.Pattern != "" {
+		rule.Pattern = s.anchorPattern(rule.WorkspaceID, rule.Tool, rule.Pattern)
+	}
+	if p.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, p.ExpiresAt)
+		if err != nil {
+			return nil, message.ErrInvalidParams("expires_at must be an RFC3339 timestamp")
+		}
+		expiresAt = expiresAt.UTC()
+		rule.ExpiresAt = &expiresAt
+	}
+
+	stored, err := s.manager.AddRule(rule)
+	if err != nil {
+		return nil, message.ErrInvalidParams(err.Error())
+	}
+	return stored, nil
+}
+
+// RulesRemove deletes a persistent permission rule.
+func (s *PermissionService) RulesRemove(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	if s.manager == nil {
+		return nil, message.ErrInternalError("Permission manager not available")
+	}
+
+	var p struct {
+		WorkspaceID string `json:"workspace_id"`
+		RuleID      string `json:"rule_id"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.ErrInvalidParams(fmt.Sprintf("Invalid params: %v", err))
+	}
+	if p.WorkspaceID == "" {
+		return nil, message.ErrInvalidParams("workspace_id is required")
+	}
+	if p.RuleID == "" {
+		return nil, message.ErrInvalidParams("rule_id is required")
+	}
+
+	removed, err := s.manager.RemoveRule(p.WorkspaceID, p.RuleID)
+	if err != nil {
+		return nil, message.ErrInternalError(err.Error())
+	}
+	if !removed {
+		return nil, message.ErrInvalidParams("rule not found: " + p.RuleID)
+	}
+	return map[string]interface{}{
+		"success": true,
+		"rule_id": p.RuleID,
+	}, nil
+}
+
+// anchorPattern makes a relative file pattern absolute under the workspace
+// root, since file tools are matched against absolute file_path values.
+// Extension patterns such as "*.go" and non-file tools are left unchanged.
+func (s *PermissionService) anchorPattern(workspaceID, tool, content string) string {
+	switch tool {
+	case "Write", "Edit", "Read":
+	default:
+		return content
+	}
+	if content == "" || strings.HasPrefix(content, "*") || filepath.IsAbs(content) {
+		return content
+	}
+	root := s.workspaceRoot(workspaceID)
+	if root == "" {
+		return content
+	}
+	anchored := filepath.Join(root, content)
+	if strings.HasSuffix(content, "/*") && !strings.HasSuffix(anchored, "/*") {
+		anchored += "/*"
+	}
+	return anchored
+}
+
+// workspaceRoot returns the root directory of a workspace, or "" if unknown.
+func (s *PermissionService) workspaceRoot(workspaceID string) string {
+	if s.workspacePaths == nil || workspaceID == "" {
+		return ""
+	}
+	root, err := s.workspacePaths.GetWorkspacePath(workspaceID)
+	if err != nil {
+		return ""
+	}
+	return root
+}
+
+// requestingDevice identifies who made an RPC call: the paired device when
+// the connection is authenticated, otherwise the client ID.
+func requestingDevice(ctx context.Context) string {
+	if payload, _ := ctx.Value(handler.AuthPayloadKey).(*security.TokenPayload); payload != nil && payload.DeviceID != "" {
+		return payload.DeviceID
+	}
+	clientID, _ := ctx.Value(handler.ClientIDKey).(string)
+	return clientID
+}
@@ -0,0 +1,383 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/brianly1003/cdev/internal/security"
+)
+
+type staticWorkspacePaths map[string]string
+
+func (p staticWorkspacePaths) GetWorkspacePath(workspaceID string) (string, error) {
+	path, ok := p[workspaceID]
+	if !ok {
+		return "", fmt.Errorf("workspace not found: %s", workspaceID)
+	}
+	return path, nil
+}
+
+func newRulesTestService() (*PermissionService, *mockPermissionManager) {
+	manager := newMockPermissionManager()
+	service := NewPermissionService(manager, newMockEventPublisher(), &mockWorkspaceResolver{workspaceID: "ws-1"})
+	service.SetWorkspacePathResolver(staticWorkspacePaths{"ws-1": "/repo"})
+	return service, manager
+}
+
+func respondWith(t *testing.T, service *PermissionService, manager *mockPermissionManager, ctx context.Context, req *permission.Request, params map[string]interface{}) (map[string]interface{}, *permission.Response) {
+	t.Helper()
+	req.ResponseChan = make(chan *permission.Response, 1)
+	manager.AddPendingRequest(req)
+
+	params["tool_use_id"] = req.ToolUseID
+	raw, _ := json.Marshal(params)
+	result, err := service.Respond(ctx, raw)
+	if err != nil {
+		t.Fatalf("Respond: %v", err)
+	}
+	return result.(map[string]interface{}), <-req.ResponseChan
+}
+
+func TestPermissionService_Respond_PathScopeStoresRule(t *testing.T) {
+	service, manager := newRulesTestService()
+	payload := &security.TokenPayload{DeviceID: "iphone-1"}
+	ctx := context.WithValue(context.Background(), handler.AuthPayloadKey, payload)
+
+	result, response := respondWith(t, service, manager, ctx, &permission.Request{
+		ToolUseID:   "tool-1",
+		SessionID:   "sess-1",
+		WorkspaceID: "ws-1",
+		ToolName:    "Edit",
+		ToolInput:   map[string]interface{}{"file_path": "/repo/src/main.go"},
+	}, map[string]interface{}{"decision": "allow", "scope": "path", "pattern": "Edit(src/*.go)"})
+
+	if response.Scope != permission.ScopePath || response.Pattern != "Edit(/repo/src/*.go)" {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	if result["scope"] != "path" {
+		t.Errorf("result scope = %v, want path", result["scope"])
+	}
+
+	rules := manager.ListRules("ws-1")
+	if len(rules) != 1 {
+		t.Fatalf("expected 1 rule, got %d", len(rules))
+	}
+	if rules[0].Pattern != "/repo/src/*.go" || rules[0].CreatedBy != "iphone-1" || rules[0].Decision != permission.DecisionAllow {
+		t.Errorf("unexpected rule %+v", rules[0])
+	}
+}
+
+func TestPermissionService_Respond_PatternMustMatchRequest(t *testing.T) {
+	service, manager := newRulesTestService()
+	ctx := context.WithValue(context.Background(), handler.ClientIDKey, "client-1")
+
+	_, response := respondWith(t, service, manager, ctx, &permission.Request{
+		ToolUseID:   "tool-1",
+		SessionID:   "sess-1",
+		WorkspaceID: "ws-1",
+		ToolName:    "Bash",
+		ToolInput:   map[string]interface{}{"command": "git status"},
+	}, map[string]interface{}{"decision": "allow", "scope": "path", "pattern": "Bash(npm:*)"})
+
+	want := permission.GeneratePattern("Bash", map[string]interface{}{"command": "git status"})
+	if response.Pattern != want {
+		t.Errorf("pattern = %q, want generated %q", response.Pattern, want)
+	}
+	rules := manager.ListRules("ws-1")
+	if len(rules) != 1 || rules[0].String() != want || rules[0].CreatedBy != "client-1" {
+		t.Errorf("unexpected rules %+v", rules)
+	}
+}
+
+func TestPermissionService_Respond_PathScopeWithoutWorkspace(t *testing.T) {
+	service, manager := newRulesTestService()
+
+	result, response := respondWith(t, service, manager, context.Background(), &permission.Request{
+		ToolUseID: "tool-1",
+		SessionID: "sess-1",
+		ToolName:  "Bash",
+		ToolInput: map[string]interface{}{"command": "go test ./..."},
+	}, map[string]interface{}{"decision": "allow", "scope": "path"})
+
+	if response.Scope != permission.ScopeSession || result["scope"] != "session" {
+		t.Errorf("expected downgrade to session scope, got %+v / %v", response, result["scope"])
+	}
+	if rules := manager.ListRules(""); len(rules) != 0 {
+		t.Errorf("expected no rules, got %+v", rules)
+	}
+}
+
+func TestPermissionService_RulesAddListRemove(t *testing.T) {
+	service, _ := newRulesTestService()
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	raw, _ := json.Marshal(map[string]interface{}{
+		"workspace_id": "ws-1",
+		"tool":         "Write",
+		"decision":     "deny",
+		"pattern":      "secrets/*",
+		"expires_at":   expiresAt,
+	})
+	added, err := service.RulesAdd(ctx, raw)
+	if err != nil {
+		t.Fatalf("RulesAdd: %v", err)
+	}
+	rule := added.(permission.Rule)
+	if rule.Pattern != "/repo/secrets/*" || rule.ExpiresAt == nil {
+		t.Fatalf("unexpected rule %+v", rule)
+	}
+
+	listed, err := service.RulesList(ctx, json.RawMessage(`{"workspace_id":"ws-1"}`))
+	if err != nil {
+		t.Fatalf("RulesList: %v", err)
+	}
+	if count := listed.(map[string]interface{})["count"]; count != 1 {
+		t.Fatalf("count = %v, want 1", count)
+	}
+
+	raw, _ = json.Marshal(map[string]interface{}{"workspace_id": "ws-1", "rule_id": rule.ID})
+	if _, err := service.RulesRemove(ctx, raw); err != nil {
+		t.Fatalf("RulesRemove: %v", err)
+	}
+	if _, err := service.RulesRemove(ctx, raw); err == nil || err.Code != message.InvalidParams {
+		t.Errorf("expected InvalidParams for unknown rule, got %v", err)
+	}
+}
+
+func TestPermissionService_RulesValidation(t *testing.T) {
+	service, _ := newRulesTestService()
+
+	tests := []struct {
+		name   string
+		call   func(context.Context, json.RawMessage) (interface{}, *message.Error)
+		params string
+	}{
+		{name: "add missing workspace", call: service.RulesAdd, params: `{"tool":"Bash","decision":"allow"}`},
+		{name: "add missing tool", call: service.RulesAdd, params: `{"workspace_id":"ws-1","decision":"allow"}`},
+		{name: "add invalid decision", call: service.RulesAdd, params: `{"workspace_id":"ws-1","tool":"Bash","decision":"ask"}`},
+		{name: "add invalid expiry", call: service.RulesAdd, params: `{"workspace_id":"ws-1","tool":"Bash","decision":"allow","expires_at":"tomorrow"}`},
+		{name: "add past expiry", call: service.RulesAdd, params: `{"workspace_id":"ws-1","tool":"Bash","decision":"allow","expires_at":"2000-01-01T00:00:00Z"}`},
+		{name: "remove missing workspace", call: service.RulesRemove, params: `{"rule_id":"r1"}`},
+		{name: "remove missing rule", call: service.RulesRemove, params: `{"workspace_id":"ws-1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.call(context.Background(), json.RawMessage(tt.params))
+			if err == nil || err.Code != message.InvalidParams {
+				t.Fatalf("expected InvalidParams, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPermissionService_Respond_UpdatedInput(t *testing.T) {
+	service, manager := newRulesTestService()
+
+	result, response := respondWith(t, service, manager, context.Background(), &permission.Request{
+		ToolUseID:   "tool-1",
+		SessionID:   "sess-1",
+		WorkspaceID: "ws-1",
+		ToolName:    "Bash",
+		ToolInput:   map[string]interface{}{"command": "make deploy"},
+	}, map[string]interface{}{
+		"decision":      "allow",
+		"scope":         "session",
+		"updated_input": map[string]interface{}{"command": "make deploy --dry-run"},
+	})
+
+	if response.Decision != permission.DecisionAllow || response.UpdatedInput["command"] != "make deploy --dry-run" {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	if response.Scope != permission.ScopeOnce || result["scope"] != "once" || result["modified"] != true {
+		t.Errorf("edited approval should be scoped once and marked modified, got %+v / %v", response, result)
+	}
+	if len(manager.memoryDecisions) != 0 {
+		t.Errorf("edited approval must not be remembered, got %v", manager.memoryDecisions)
+	}
+}
+
+func TestPermissionService_Respond_UpdatedInputRejected(t *testing.T) {
+	service, manager := newRulesTestService()
+	policy := &mockPolicyEvaluator{}
+	service.SetPolicyEvaluator(policy)
+
+	req := &permission.Request{
+		ToolUseID:    "tool-1",
+		SessionID:    "sess-1",
+		WorkspaceID:  "ws-1",
+		ToolName:     "Bash",
+		ToolInput:    map[string]interface{}{"command": "make deploy"},
+		ResponseChan: make(chan *permission.Response, 1),
+	}
+	manager.AddPendingRequest(req)
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		policy *permission.PolicyDecision
+	}{
+		{
+			name:   "deny with edit",
+			params: map[string]interface{}{"decision": "deny", "updated_input": map[string]interface{}{"command": "ls"}},
+		},
+		{
+			name:   "invalid schema",
+			params: map[string]interface{}{"decision": "allow", "updated_input": map[string]interface{}{"cmd": "ls"}},
+		},
+		{
+			name:   "denied by policy",
+			params: map[string]interface{}{"decision": "allow", "updated_input": map[string]interface{}{"command": "rm -rf /"}},
+			policy: &permission.PolicyDecision{Decision: permission.DecisionDeny, Rule: "no-rm", Reason: "destructive"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy.decision = tt.policy
+			tt.params["tool_use_id"] = "tool-1"
+			raw, _ := json.Marshal(tt.params)
+			_, err := service.Respond(context.Background(), raw)
+			if err == nil || err.Code != message.InvalidParams {
+				t.Fatalf("expected InvalidParams, got %v", err)
+			}
+			if manager.GetPendingRequest("tool-1") == nil {
+				t.Fatal("rejected edit should leave the request pending")
+			}
+		})
+	}
+}
+
+func TestPermissionService_Respond_UpdatedInputRechecked(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*PermissionService, *mockPermissionManager)
+		edit  string
+		want  string
+	}{
+		{
+			name: "made high-risk",
+			setup: func(service *PermissionService, _ *mockPermissionManager) {
+				service.SetRouter(permission.NewRouter(permission.RoutingConfig{Enabled: true, HighRisk: []string{"Bash(git push:*)"}}))
+			},
+			edit: "git push --force",
+			want: "high-risk",
+		},
+		{
+			name: "remembered deny",
+			setup: func(_ *PermissionService, manager *mockPermissionManager) {
+				manager.SetMemoryDecision("sess-1", "Bash", &permission.StoredDecision{Pattern: "Bash(git push:*)", Decision: permission.DecisionDeny})
+			},
+			edit: "git push",
+			want: "Bash(git push:*)",
+		},
+		{
+			name: "risk raised",
+			edit: "sudo rm -rf /etc",
+			want: "raises the risk",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, manager := newRulesTestService()
+			if tt.setup != nil {
+				tt.setup(service, manager)
+			}
+			manager.AddPendingRequest(&permission.Request{
+				ToolUseID:    "tool-1",
+				SessionID:    "sess-1",
+				WorkspaceID:  "ws-1",
+				ToolName:     "Bash",
+				ToolInput:    map[string]interface{}{"command": "git status"},
+				Risk:         &permission.Risk{Level: permission.RiskLow},
+				AgentType:    "claude",
+				Cwd:          "/repo",
+				ResponseChan: make(chan *permission.Response, 1),
+			})
+
+			raw, _ := json.Marshal(map[string]interface{}{
+				"tool_use_id":   "tool-1",
+				"decision":      "allow",
+				"updated_input": map[string]interface{}{"command": tt.edit},
+			})
+			_, err := service.Respond(context.Background(), raw)
+			if err == nil || err.Code != message.InvalidParams || !strings.Contains(err.Message, tt.want) {
+				t.Fatalf("error = %v, want InvalidParams mentioning %q", err, tt.want)
+			}
+			if manager.GetPendingRequest("tool-1") == nil {
+				t.Fatal("rejected edit should leave the request pending")
+			}
+		})
+	}
+}
+
+func TestPermissionService_Respond_MultiDeviceApproval(t *testing.T) {
+	service, manager := newRulesTestService()
+	deviceCtx := func(device string) context.Context {
+		return context.WithValue(context.Background(), handler.AuthPayloadKey, &security.TokenPayload{DeviceID: device})
+	}
+	respond := func(device, decision string) (interface{}, *message.Error) {
+		raw, _ := json.Marshal(map[string]interface{}{"tool_use_id": "tool-1", "decision": decision, "scope": "session"})
+		return service.Respond(deviceCtx(device), raw)
+	}
+
+	req := &permission.Request{
+		ToolUseID:         "tool-1",
+		SessionID:         "sess-1",
+		ToolName:          "Bash",
+		ToolInput:         map[string]interface{}{"command": "git push"},
+		RequiredApprovals: 2,
+		ResponseChan:      make(chan *permission.Response, 1),
+	}
+	manager.AddPendingRequest(req)
+
+	result, rpcErr := respond("phone", "allow")
+	if rpcErr != nil {
+		t.Fatalf("first approval: %v", rpcErr)
+	}
+	if status := result.(map[string]interface{})["status"]; status != "awaiting_approvals" {
+		t.Fatalf("status = %v, want awaiting_approvals", status)
+	}
+	if manager.GetPendingRequest("tool-1") == nil {
+		t.Fatal("request should stay pending until enough devices approved")
+	}
+
+	if _, rpcErr := respond("phone", "allow"); rpcErr == nil || rpcErr.Code != message.InvalidParams {
+		t.Fatalf("repeat approval error = %v, want invalid params", rpcErr)
+	}
+
+	if _, rpcErr := respond("laptop", "allow"); rpcErr != nil {
+		t.Fatalf("second approval: %v", rpcErr)
+	}
+	response := <-req.ResponseChan
+	if response.Decision != permission.DecisionAllow || response.Scope != permission.ScopeOnce {
+		t.Errorf("response = %+v, want allow once", response)
+	}
+	if len(response.ApprovedBy) != 2 || response.ApprovedBy[0] != "phone" || response.ApprovedBy[1] != "laptop" {
+		t.Errorf("ApprovedBy = %v, want [phone laptop]", response.ApprovedBy)
+	}
+}
+
+func TestPermissionService_Respond_MultiDeviceDenyVetoes(t *testing.T) {
+	service, manager := newRulesTestService()
+	req := &permission.Request{
+		ToolUseID:         "tool-1",
+		SessionID:         "sess-1",
+		ToolName:          "Bash",
+		ToolInput:         map[string]interface{}{"command": "git push"},
+		RequiredApprovals: 2,
+		Approvals:         []string{"phone"},
+	}
+
+	_, response := respondWith(t, service, manager, context.Background(), req, map[string]interface{}{"decision": "deny"})
+	if response.Decision != permission.DecisionDeny {
+		t.Errorf("decision = %s, want deny", response.Decision)
+	}
+}
@@ -0,0 +1,134 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultSimulateLimit caps the calls listed per workspace by permission/simulate.
+const defaultSimulateLimit = 100
+
+// WorkspaceLister lists configured workspace IDs. A WorkspacePathResolver
+// that implements it lets permission/simulate default to every workspace.
+type WorkspaceLister interface {
+	ListWorkspaceIDs() []string
+}
+
+// registerSimulateMethods registers the permission policy simulation method.
+func (s *PermissionService) registerSimulateMethods(r *handler.Registry) {
+	r.RegisterWithMeta("permission/simulate", s.Simulate, handler.MethodMeta{
+		Summary:     "Dry-run permission policies against past sessions",
+		Description: "Replays the tool calls of past Claude sessions and Codex rollouts of each workspace through the current policies and workspace rules, and reports which calls would have been allowed, denied or prompted. Nothing is asked, remembered or audited; session memory does not apply. Every call is counted, but only denied and prompted calls are listed unless include_allowed is set.",
+		Params: []handler.OpenRPCParam{
+			{Name: "workspace_ids", Required: false, Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Workspaces to replay. Defaults to all configured workspaces."}},
+			{Name: "since", Required: false, Schema: map[string]interface{}{"type": "string", "format": "date-time", "description": "Optional: only replay calls made after this time"}},
+			{Name: "agent_types", Required: false, Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "enum": []string{"claude", "codex"}}, "description": "Optional: session types to replay. Defaults to both."}},
+			{Name: "include_allowed", Required: false, Schema: map[string]interface{}{"type": "boolean", "default": false, "description": "List allowed calls too"}},
+			{Name: "limit", Required: false, Schema: map[string]interface{}{"type": "integer", "default": defaultSimulateLimit, "description": "Calls listed per workspace"}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name: "SimulateResult",
+			Schema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"workspaces": map[string]interface{}{"type": "array"},
+					"totals":     map[string]interface{}{"type": "object"},
+				},
+			},
+		},
+		Concurrency: handler.ConcurrencyParallel,
+	})
+}
+
+// SimulateParams for permission/simulate method.
+type SimulateParams struct {
+	WorkspaceIDs   []string `json:"workspace_ids"`
+	Since          string   `json:"since"`
+	AgentTypes     []string `json:"agent_types"`
+	IncludeAllowed bool     `json:"include_allowed"`
+	Limit          int      `json:"limit"`
+}
+
+// Simulate replays session history through the current permission engine.
+func (s *PermissionService) Simulate(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	if s.workspacePaths == nil {
+		return nil, message.ErrInternalError("Workspace resolver not available")
+	}
+
+	var p SimulateParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, message.ErrInvalidParams(fmt.Sprintf("Invalid params: %v", err))
+		}
+	}
+
+	opts := permission.HistoryOptions{
+		AgentTypes:     p.AgentTypes,
+		IncludeAllowed: p.IncludeAllowed,
+		Limit:          p.Limit,
+	}
+	for _, agentType := range p.AgentTypes {
+		if agentType != permission.HistoryAgentClaude && agentType != permission.HistoryAgentCodex {
+			return nil, message.ErrInvalidParams("agent_types entries must be 'claude' or 'codex'")
+		}
+	}
+	if p.Limit < 0 {
+		return nil, message.ErrInvalidParams("limit must not be negative")
+	}
+	if opts.Limit == 0 {
+		opts.Limit = defaultSimulateLimit
+	}
+	if p.Since != "" {
+		since, err := time.Parse(time.RFC3339, p.Since)
+		if err != nil {
+			return nil, message.ErrInvalidParams("since must be an RFC3339 timestamp")
+		}
+		opts.Since = since
+	}
+
+	ids := p.WorkspaceIDs
+	if len(ids) == 0 {
+		lister, ok := s.workspacePaths.(WorkspaceLister)
+		if !ok {
+			return nil, message.ErrInvalidParams("workspace_ids is required")
+		}
+		ids = lister.ListWorkspaceIDs()
+	}
+	workspaces := make([]permission.HistoryWorkspace, 0, len(ids))
+	for _, id := range ids {
+		path, err := s.workspacePaths.GetWorkspacePath(id)
+		if err != nil || path == "" {
+			return nil, message.ErrInvalidParams(fmt.Sprintf("Workspace not found: %s", id))
+		}
+		workspaces = append(workspaces, permission.HistoryWorkspace{ID: id, Path: path})
+	}
+
+	var rules *permission.RuleStore
+	if s.manager != nil {
+		rules = &permission.RuleStore{Rules: s.manager.ListRules("")}
+	}
+	var policy permission.PolicyChecker
+	if s.policy != nil {
+		policy = s.policy
+	}
+
+	started := time.Now()
+	report := permission.NewSimulator(policy, rules).Replay(workspaces, opts)
+
+	log.Info().
+		Int("workspaces", len(workspaces)).
+		Int("calls", report.Totals.Total).
+		Int("denied", report.Totals.Denied).
+		Int("prompted", report.Totals.Prompted).
+		Dur("took", time.Since(started)).
+		Msg("Simulated permission policies against session history")
+
+	return report, nil
+}
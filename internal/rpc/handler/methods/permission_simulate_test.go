@@ -0,0 +1,81 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/pathutil"
+	"github.com/brianly1003/cdev/internal/permission"
+)
+
+type listedWorkspacePaths struct {
+	staticWorkspacePaths
+}
+
+func (p listedWorkspacePaths) ListWorkspaceIDs() []string {
+	ids := make([]string, 0, len(p.staticWorkspacePaths))
+	for id := range p.staticWorkspacePaths {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestPermissionService_Simulate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CODEX_HOME", filepath.Join(home, ".codex"))
+
+	repo := filepath.Join(home, "repo")
+	sessionsDir := filepath.Join(home, ".claude", "projects", pathutil.EncodePath(repo))
+	if err := os.MkdirAll(sessionsDir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	session := `{"type":"assistant","timestamp":"2026-01-31T12:00:00Z","message":{"content":[{"type":"tool_use","id":"toolu_1","name":"Bash","input":{"command":"make build"}},{"type":"tool_use","id":"toolu_2","name":"Bash","input":{"command":"rm -rf dist"}}]}}
+`
+	if err := os.WriteFile(filepath.Join(sessionsDir, "11111111-1111-1111-1111-111111111111.jsonl"), []byte(session), 0644); err != nil {
+		t.Fatalf("write session: %v", err)
+	}
+
+	manager := newMockPermissionManager()
+	if _, err := manager.AddRule(permission.Rule{WorkspaceID: "ws-1", Decision: permission.DecisionAllow, Tool: "Bash", Pattern: "make:*"}); err != nil {
+		t.Fatalf("AddRule: %v", err)
+	}
+	service := NewPermissionService(manager, newMockEventPublisher(), nil)
+	service.SetWorkspacePathResolver(listedWorkspacePaths{staticWorkspacePaths{"ws-1": repo}})
+
+	result, err := service.Simulate(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	report := result.(*permission.SimulationReport)
+	if len(report.Workspaces) != 1 || report.Totals.Total != 2 || report.Totals.Allowed != 1 || report.Totals.Prompted != 1 {
+		t.Fatalf("report = %+v totals = %+v, want one allowed and one prompted call", report.Workspaces, report.Totals)
+	}
+	calls := report.Workspaces[0].Calls
+	if len(calls) != 1 || calls[0].ToolUseID != "toolu_2" || calls[0].Source != permission.SimulationSourcePrompt {
+		t.Errorf("calls = %+v, want only the prompted call listed", calls)
+	}
+	if len(manager.ListPendingRequests()) != 0 {
+		t.Error("simulation must not create pending requests")
+	}
+
+	for name, params := range map[string]map[string]interface{}{
+		"unknown workspace": {"workspace_ids": []string{"missing"}},
+		"bad agent type":    {"agent_types": []string{"gemini"}},
+		"bad since":         {"since": "yesterday"},
+	} {
+		raw, _ := json.Marshal(params)
+		if _, err := service.Simulate(context.Background(), raw); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	// Without a workspace lister the workspaces must be named
+	service.SetWorkspacePathResolver(staticWorkspacePaths{"ws-1": repo})
+	if _, err := service.Simulate(context.Background(), nil); err == nil {
+		t.Error("expected error without workspace_ids")
+	}
+}
@@ -18,6 +18,7 @@ type mockPermissionManager struct {
 	memoryDecisions map[string]*permission.StoredDecision
 	pendingRequests map[string]*permission.Request
 	stats           map[string]interface{}
+	rules           *permission.RuleStore
 }
 
 func newMockPermissionManager() *mockPermissionManager {
@@ -25,10 +26,11 @@ func newMockPermissionManager() *mockPermissionManager {
 		memoryDecisions: make(map[string]*permission.StoredDecision),
 		pendingRequests: make(map[string]*permission.Request),
 		stats:           make(map[string]interface{}),
+		rules:           &permission.RuleStore{},
 	}
 }
 
-func (m *mockPermissionManager) CheckMemory(sessionID, toolName string, toolInput map[string]interface{}) *permission.StoredDecision {
+func (m *mockPermissionManager) CheckMemory(sessionID, workspaceID, toolName string, toolInput map[string]interface{}) *permission.StoredDecision {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	key := sessionID + ":" + toolName
@@ -46,6 +48,22 @@ func (m *mockPermissionManager) StoreDecision(sessionID, workspaceID, pattern st
 	}
 }
 
+func (m *mockPermissionManager) Remember(req *permission.Request, response *permission.Response) {
+	switch response.Scope {
+	case permission.ScopeSession:
+		m.StoreDecision(req.SessionID, req.WorkspaceID, response.Pattern, response.Decision)
+	case permission.ScopePath:
+		tool, content, _ := permission.ParseRulePattern(response.Pattern)
+		_, _ = m.AddRule(permission.Rule{
+			WorkspaceID: req.WorkspaceID,
+			Decision:    response.Decision,
+			Tool:        tool,
+			Pattern:     content,
+			CreatedBy:   response.RespondedBy,
+		})
+	}
+}
+
 func (m *mockPermissionManager) AddPendingRequest(req *permission.Request) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -89,12 +107,52 @@ func (m *mockPermissionManager) GetAndRemovePendingRequest(toolUseID string) *pe
 	return req
 }
 
+func (m *mockPermissionManager) MarkNotified(toolUseID string, devices []string, escalated bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	req, ok := m.pendingRequests[toolUseID]
+	if !ok {
+		return false
+	}
+	req.NotifiedDevices = append(req.NotifiedDevices, devices...)
+	req.Escalated = req.Escalated || escalated
+	return true
+}
+
+func (m *mockPermissionManager) AddApproval(toolUseID, device string) ([]string, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	req, ok := m.pendingRequests[toolUseID]
+	if !ok {
+		return nil, 0, permission.ErrRequestNotPending
+	}
+	for _, approved := range req.Approvals {
+		if approved == device {
+			return req.Approvals, req.RequiredApprovals, permission.ErrAlreadyApproved
+		}
+	}
+	req.Approvals = append(req.Approvals, device)
+	return append([]string(nil), req.Approvals...), req.RequiredApprovals, nil
+}
+
 func (m *mockPermissionManager) GetSessionStats() map[string]interface{} {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	return m.stats
 }
 
+func (m *mockPermissionManager) AddRule(rule permission.Rule) (permission.Rule, error) {
+	return m.rules.Add(rule)
+}
+
+func (m *mockPermissionManager) RemoveRule(workspaceID, ruleID string) (bool, error) {
+	return m.rules.Remove(workspaceID, ruleID)
+}
+
+func (m *mockPermissionManager) ListRules(workspaceID string) []permission.Rule {
+	return m.rules.List(workspaceID)
+}
+
 func (m *mockPermissionManager) ListPendingRequests() []*permission.Request {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -257,6 +315,50 @@ func TestPermissionService_Request_MemoryHit(t *testing.T) {
 	}
 }
 
+func TestPermissionService_Request_RememberedAllowKeepsHighRiskApprovals(t *testing.T) {
+	manager := newMockPermissionManager()
+	manager.SetMemoryDecision("session-1", "Bash", &permission.StoredDecision{
+		Pattern:  "Bash(git push:*)",
+		Decision: permission.DecisionAllow,
+	})
+
+	service := NewPermissionService(manager, newMockEventPublisher(), &mockWorkspaceResolver{workspaceID: "test-workspace"})
+	service.SetRouter(permission.NewRouter(permission.RoutingConfig{Enabled: true, HighRisk: []string{"Bash(git push:*)"}, HighRiskApprovals: 3}))
+	service.SetTimeout(5 * time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	params, _ := json.Marshal(map[string]interface{}{
+		"session_id":  "session-1",
+		"tool_name":   "Bash",
+		"tool_input":  map[string]interface{}{"command": "git push origin main"},
+		"tool_use_id": "tool-push",
+	})
+	done := make(chan interface{}, 1)
+	go func() {
+		result, _ := service.Request(ctx, params)
+		done <- result
+	}()
+
+	var pending *permission.Request
+	for deadline := time.Now().Add(2 * time.Second); pending == nil && time.Now().Before(deadline); {
+		select {
+		case result := <-done:
+			t.Fatalf("request decided without devices: %+v", result)
+		case <-time.After(10 * time.Millisecond):
+		}
+		pending = manager.GetPendingRequest("tool-push")
+	}
+	cancel()
+	<-done
+
+	if pending == nil {
+		t.Fatal("request was not sent to the devices")
+	}
+	if pending.RequiredApprovals != 3 {
+		t.Errorf("RequiredApprovals = %d, want 3", pending.RequiredApprovals)
+	}
+}
+
 func TestPermissionService_Request_Timeout(t *testing.T) {
 	manager := newMockPermissionManager()
 	publisher := newMockEventPublisher()
@@ -326,6 +428,23 @@ func TestPermissionService_Request_PublishesEvent(t *testing.T) {
 	if event.Type() != "pty_permission" {
 		t.Errorf("Event type = %v, want pty_permission", event.Type())
 	}
+
+	// Writing outside the working directory is flagged as high risk
+	data, _ := event.ToJSON()
+	var decoded struct {
+		Payload struct {
+			Risk *struct {
+				Level   string   `json:"level"`
+				Reasons []string `json:"reasons"`
+			} `json:"risk"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if decoded.Payload.Risk == nil || decoded.Payload.Risk.Level != "high" {
+		t.Errorf("Event risk = %+v, want level high", decoded.Payload.Risk)
+	}
 }
 
 func TestPermissionService_Respond_MissingParams(t *testing.T) {
@@ -637,3 +756,55 @@ func TestPermissionService_Respond_DenyDecision(t *testing.T) {
 		t.Errorf("Decision = %v, want deny", response.Decision)
 	}
 }
+
+type mockPolicyEvaluator struct {
+	decision *permission.PolicyDecision
+}
+
+func (m *mockPolicyEvaluator) Evaluate(in permission.PolicyInput) *permission.PolicyDecision {
+	return m.decision
+}
+
+func TestPermissionService_Request_PolicyDecisions(t *testing.T) {
+	manager := newMockPermissionManager()
+	manager.memoryDecisions["session-1:Bash"] = &permission.StoredDecision{Pattern: "Bash(git push:*)", Decision: permission.DecisionAllow}
+	policy := &mockPolicyEvaluator{}
+
+	service := NewPermissionService(manager, newMockEventPublisher(), &mockWorkspaceResolver{workspaceID: "test-workspace"})
+	service.SetPolicyEvaluator(policy)
+	service.SetTimeout(50 * time.Millisecond)
+
+	request := func() *permission.Response {
+		t.Helper()
+		params, _ := json.Marshal(map[string]interface{}{
+			"session_id":  "session-1",
+			"tool_name":   "Bash",
+			"tool_input":  map[string]interface{}{"command": "git push --force"},
+			"tool_use_id": "tool-policy",
+			"cwd":         "/repo",
+		})
+		result, err := service.Request(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Request: %v", err)
+		}
+		return result.(*permission.Response)
+	}
+
+	// Deny wins over the remembered allow.
+	policy.decision = &permission.PolicyDecision{Decision: permission.DecisionDeny, Reason: "no force push"}
+	if resp := request(); resp.Decision != permission.DecisionDeny || resp.Message != "no force push" || resp.Scope != permission.ScopeOnce {
+		t.Fatalf("expected policy deny, got %+v", resp)
+	}
+
+	// Ask skips the remembered allow and goes to mobile (timing out here).
+	policy.decision = &permission.PolicyDecision{Decision: permission.DecisionAsk}
+	if resp := request(); resp.Decision == permission.DecisionAllow {
+		t.Fatalf("expected ask to bypass remembered allow, got %+v", resp)
+	}
+
+	// Without a policy decision the remembered allow applies.
+	policy.decision = nil
+	if resp := request(); resp.Decision != permission.DecisionAllow || resp.Pattern != "Bash(git push:*)" {
+		t.Fatalf("expected remembered allow, got %+v", resp)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/brianly1003/cdev/internal/adapters/repository"
+	"github.com/brianly1003/cdev/internal/domain/ports"
 	"github.com/brianly1003/cdev/internal/rpc/handler"
 	"github.com/brianly1003/cdev/internal/rpc/message"
 )
@@ -43,6 +44,7 @@ func (s *RepositoryService) RegisterMethods(registry *handler.Registry) {
 			Name:   "status",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("repository/search", s.Search, handler.MethodMeta{
@@ -63,6 +65,7 @@ func (s *RepositoryService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("repository/files/list", s.ListFiles, handler.MethodMeta{
@@ -83,6 +86,7 @@ func (s *RepositoryService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("repository/files/tree", s.GetTree, handler.MethodMeta{
@@ -96,6 +100,7 @@ func (s *RepositoryService) RegisterMethods(registry *handler.Registry) {
 			Name:   "tree",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("repository/stats", s.GetStats, handler.MethodMeta{
@@ -106,16 +111,18 @@ func (s *RepositoryService) RegisterMethods(registry *handler.Registry) {
 			Name:   "stats",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("repository/index/rebuild", s.Rebuild, handler.MethodMeta{
 		Summary:     "Rebuild repository index",
-		Description: "Triggers a full re-index of the repository in the background. Returns immediately.",
+		Description: "Triggers a full re-index of the repository in the background. Returns immediately; with a progress_token, $/progress is reported until the rebuild completes.",
 		Params:      []handler.OpenRPCParam{},
 		Result: &handler.OpenRPCResult{
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Progress: true,
 	})
 }
 
@@ -280,10 +287,19 @@ func (s *RepositoryService) Rebuild(ctx context.Context, params json.RawMessage)
 		return nil, message.NewError(message.InternalError, "Repository indexer not available")
 	}
 
+	// Progress is reported until the rebuild completes, after the response
+	progress := handler.ProgressFrom(ctx)
+	progress.Begin("Rebuilding repository index")
+
 	// Start rebuild in background
 	go func() {
 		// Use background context since the request context will be cancelled
-		_ = s.indexer.FullScan(context.Background())
+		scanCtx := ports.WithProgress(context.Background(), progress.ReportFunc())
+		if err := s.indexer.FullScan(scanCtx); err != nil {
+			progress.End("Index rebuild failed: " + err.Error())
+			return
+		}
+		progress.End("Index rebuilt")
 	}()
 
 	return map[string]interface{}{
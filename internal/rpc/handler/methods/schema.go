@@ -0,0 +1,89 @@
+package methods
+
+import "github.com/brianly1003/cdev/internal/rpc/handler"
+
+// Services are the method services the app serves. The app and the
+// OpenRPC driven client generator both register them with Register, so the
+// generated client cannot drift from the server. A nil service is not
+// served.
+type Services struct {
+	Status          *StatusService
+	Agent           *AgentService
+	Git             *GitService
+	File            *FileService
+	Session         *SessionService
+	WorkspaceConfig *WorkspaceConfigService
+	SessionManager  *SessionManagerService
+	Repository      *RepositoryService
+	Lifecycle       *LifecycleService
+	Subscription    *SubscriptionService
+	Client          *ClientService
+	Task            *TaskService
+	Permission      *PermissionService
+}
+
+// Register registers the methods of every service. Services are registered
+// in field order, so a method served by two services resolves the same way
+// wherever they are registered.
+func (s Services) Register(r *handler.Registry) {
+	if s.Status != nil {
+		s.Status.RegisterMethods(r)
+	}
+	if s.Agent != nil {
+		s.Agent.RegisterMethods(r)
+	}
+	if s.Git != nil {
+		s.Git.RegisterMethods(r)
+	}
+	if s.File != nil {
+		s.File.RegisterMethods(r)
+	}
+	if s.Session != nil {
+		s.Session.RegisterMethods(r)
+	}
+	if s.WorkspaceConfig != nil {
+		s.WorkspaceConfig.RegisterMethods(r)
+	}
+	if s.SessionManager != nil {
+		s.SessionManager.RegisterMethods(r)
+	}
+	if s.Repository != nil {
+		s.Repository.RegisterMethods(r)
+	}
+	if s.Lifecycle != nil {
+		s.Lifecycle.RegisterMethods(r)
+	}
+	if s.Subscription != nil {
+		s.Subscription.RegisterMethods(r)
+	}
+	if s.Client != nil {
+		s.Client.RegisterMethods(r)
+	}
+	if s.Task != nil {
+		s.Task.RegisterMethods(r)
+	}
+	if s.Permission != nil {
+		s.Permission.RegisterMethods(r)
+	}
+}
+
+// SchemaServices returns every service with nil dependencies, for tools
+// that only need the metadata, such as the client generator. The
+// registered handlers must not be called.
+func SchemaServices() Services {
+	return Services{
+		Status:          NewStatusService(nil),
+		Agent:           NewAgentService(nil),
+		Git:             NewGitService(nil, 0),
+		File:            NewFileService(nil, 0),
+		Session:         NewSessionService(nil),
+		WorkspaceConfig: NewWorkspaceConfigService(nil, nil, nil, nil),
+		SessionManager:  NewSessionManagerService(nil),
+		Repository:      NewRepositoryService(nil),
+		Lifecycle:       NewLifecycleService("", DefaultCapabilities()),
+		Subscription:    NewSubscriptionService(),
+		Client:          NewClientService(nil),
+		Task:            NewTaskService(nil, nil),
+		Permission:      NewPermissionService(nil, nil, nil),
+	}
+}
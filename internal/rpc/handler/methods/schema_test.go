@@ -0,0 +1,31 @@
+package methods
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+)
+
+// TestSchemaServices_Complete fails when a service is added to Services but
+// not to SchemaServices, which would leave it out of the generated client.
+func TestSchemaServices_Complete(t *testing.T) {
+	v := reflect.ValueOf(SchemaServices())
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsNil() {
+			t.Errorf("SchemaServices().%s is nil", v.Type().Field(i).Name)
+		}
+	}
+}
+
+func TestServices_RegisterSkipsNil(t *testing.T) {
+	r := handler.NewRegistry()
+	Services{Subscription: NewSubscriptionService()}.Register(r)
+
+	if !r.Has("workspace/subscribe") {
+		t.Error("workspace/subscribe not registered")
+	}
+	if r.Has("git/status") || r.Has("permission/respond") {
+		t.Error("methods of nil services were registered")
+	}
+}
@@ -239,10 +239,12 @@ func (s *SessionService) RegisterMethods(r *handler.Registry) {
 		Description: "Returns a list of available sessions from all configured AI agents. For Codex, sessions include rich metadata (git info, model, first prompt).",
 		Params: []handler.OpenRPCParam{
 			{Name: "agent_type", Description: "Filter by agent type (optional)", Required: false, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "workspace_id", Description: "Filter by workspace (optional)", Required: false, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "project_path", Description: "Filter by project path (optional, for Codex sessions)", Required: false, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "limit", Description: "Maximum number of sessions to return", Required: false, Schema: map[string]interface{}{"type": "integer", "default": 50}},
 		},
-		Result: &handler.OpenRPCResult{Name: "SessionListResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/SessionListResult"}},
+		Result:      &handler.OpenRPCResult{Name: "SessionListResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/SessionListResult"}},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	r.RegisterWithMeta("session/get", s.GetSession, handler.MethodMeta{
@@ -252,8 +254,9 @@ func (s *SessionService) RegisterMethods(r *handler.Registry) {
 			{Name: "session_id", Description: "Session ID to retrieve", Required: true, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "agent_type", Description: "Agent type (optional, searches all if not specified)", Required: false, Schema: map[string]interface{}{"type": "string"}},
 		},
-		Result: &handler.OpenRPCResult{Name: "SessionInfo", Schema: map[string]interface{}{"$ref": "#/components/schemas/SessionInfo"}},
-		Errors: []string{"SessionNotFound"},
+		Result:      &handler.OpenRPCResult{Name: "SessionInfo", Schema: map[string]interface{}{"$ref": "#/components/schemas/SessionInfo"}},
+		Errors:      []string{"SessionNotFound"},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	r.RegisterWithMeta("session/messages", s.GetSessionMessages, handler.MethodMeta{
@@ -266,8 +269,9 @@ func (s *SessionService) RegisterMethods(r *handler.Registry) {
 			{Name: "offset", Description: "Offset for pagination", Required: false, Schema: map[string]interface{}{"type": "integer", "default": 0}},
 			{Name: "order", Description: "Sort order: 'asc' or 'desc' (default 'asc')", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"asc", "desc"}, "default": "asc"}},
 		},
-		Result: &handler.OpenRPCResult{Name: "SessionMessagesResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/SessionMessagesResult"}},
-		Errors: []string{"SessionNotFound"},
+		Result:      &handler.OpenRPCResult{Name: "SessionMessagesResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/SessionMessagesResult"}},
+		Errors:      []string{"SessionNotFound"},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	r.RegisterWithMeta("session/elements", s.GetSessionElements, handler.MethodMeta{
@@ -280,8 +284,9 @@ func (s *SessionService) RegisterMethods(r *handler.Registry) {
 			{Name: "before", Description: "Return elements before this ID (for pagination)", Required: false, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "after", Description: "Return elements after this ID (for catch-up)", Required: false, Schema: map[string]interface{}{"type": "string"}},
 		},
-		Result: &handler.OpenRPCResult{Name: "SessionElementsResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/SessionElementsResult"}},
-		Errors: []string{"SessionNotFound"},
+		Result:      &handler.OpenRPCResult{Name: "SessionElementsResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/SessionElementsResult"}},
+		Errors:      []string{"SessionNotFound"},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	r.RegisterWithMeta("session/watch", s.WatchSession, handler.MethodMeta{
@@ -43,6 +43,7 @@ type SessionManagerService struct {
 	focusProvider  SessionFocusProvider
 	sessionService *SessionService
 	permissions    pendingPermissionProvider
+	codexApprovals CodexApprovalDecider
 
 	codexMu              sync.Mutex
 	codexSessions        map[string]*codexPTYSession
@@ -50,6 +51,10 @@ type SessionManagerService struct {
 	codexLastPTYLogLine  map[string]string
 	codexSessionWatchers map[string]context.CancelFunc
 	runtimeDispatch      map[string]sessionRuntimeDispatch
+
+	batchMu    sync.Mutex
+	batches    map[string]*sessionBatch
+	batchOrder []string
 }
 
 const (
@@ -87,6 +92,14 @@ type codexPTYSession struct {
 	workspace   string
 	cmd         *exec.Cmd
 	ptmx        *os.File
+
+	// Approval prompts detected in the output, decided through the shared
+	// permission flow. approvalCancel withdraws the pending request when the
+	// prompt is answered another way.
+	approvalMu     sync.Mutex
+	approvals      *codex.ApprovalDetector
+	approvalCancel context.CancelFunc
+	approvalSeq    int
 }
 
 func (c *codexPTYSession) SessionID() string {
@@ -132,6 +145,13 @@ func (s *SessionManagerService) SetPermissionManager(provider pendingPermissionP
 	s.permissions = provider
 }
 
+// SetCodexApprovalDecider installs the permission flow that decides Codex
+// approval prompts. Without one, Codex prompts are only answered through
+// session/respond and session/input.
+func (s *SessionManagerService) SetCodexApprovalDecider(decider CodexApprovalDecider) {
+	s.codexApprovals = decider
+}
+
 // RegisterMethods registers all session management methods with the handler.
 func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 	// Session lifecycle methods
@@ -141,9 +161,10 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 		Params: []handler.OpenRPCParam{
 			{Name: "workspace_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "session_id", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Optional session ID to attach to."}},
-			{Name: "permission_mode", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"default", "acceptEdits", "bypassPermissions", "plan", "interactive"}, "default": "default", "description": "Permission handling mode. Use 'bypassPermissions' to enable runtime-specific bypass flags when supported."}},
+			{Name: "permission_mode", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"default", "acceptEdits", "bypassPermissions", "plan", "interactive"}, "default": "default", "description": "Permission handling mode. Use 'bypassPermissions' to enable runtime-specific bypass flags when supported. When omitted, the launch profile's permission_mode applies; an explicit value, including 'default', replaces it for the session."}},
 			{Name: "yolo_mode", Required: false, Schema: map[string]interface{}{"type": "boolean", "description": "Runtime-agnostic bypass intent. Enables runtime-specific dangerous auto-approval flags when supported."}},
 			{Name: "agent_type", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"claude", "codex"}, "default": "claude", "description": "Agent runtime type."}},
+			{Name: "profile", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Launch profile defined on the workspace (see initialize capabilities.launchProfiles). Always starts a new managed Claude session."}},
 		},
 		Result: &handler.OpenRPCResult{
 			Name:   "session",
@@ -172,7 +193,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			{Name: "workspace_id", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Workspace ID. Required when session_id is empty to auto-create a new session."}},
 			{Name: "prompt", Required: true, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "mode", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"new", "continue"}, "default": "new", "description": "Session mode. 'new' starts fresh conversation (default), 'continue' resumes existing."}},
-			{Name: "permission_mode", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"default", "acceptEdits", "bypassPermissions", "plan", "interactive"}, "default": "default", "description": "Permission handling mode. Use 'acceptEdits' to auto-accept file edits, 'bypassPermissions' to skip all permission checks, 'interactive' to use PTY mode for true terminal-like permission prompts."}},
+			{Name: "permission_mode", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"default", "acceptEdits", "bypassPermissions", "plan", "interactive"}, "default": "default", "description": "Permission handling mode. Use 'acceptEdits' to auto-accept file edits, 'bypassPermissions' to skip all permission checks, 'interactive' to use PTY mode for true terminal-like permission prompts. When omitted, the session's launch profile permission_mode applies; an explicit 'default' overrides it."}},
 			{Name: "yolo_mode", Required: false, Schema: map[string]interface{}{"type": "boolean", "description": "Runtime-agnostic bypass intent. Enables runtime-specific dangerous auto-approval flags when supported."}},
 			{Name: "agent_type", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"claude", "codex"}, "default": "claude", "description": "Agent runtime type."}},
 		},
@@ -205,6 +226,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			{Name: "type", Required: true, Schema: map[string]interface{}{"type": "string", "enum": []string{"permission", "question"}}},
 			{Name: "response", Required: true, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "agent_type", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"claude", "codex"}, "default": "claude", "description": "Agent runtime type."}},
+			{Name: "updated_input", Required: false, Schema: map[string]interface{}{"type": "object", "description": "Claude only: approve the pending permission with edited tool input. Claude is told to retry the call with this input."}},
 		},
 		Result: &handler.OpenRPCResult{
 			Name:   "result",
@@ -222,6 +244,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "sessions",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("session/info", s.Info, handler.MethodMeta{
@@ -234,11 +257,12 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "session",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("session/state", s.State, handler.MethodMeta{
 		Summary:     "Get session runtime state for reconnection",
-		Description: "Returns the full runtime state of a session including Claude state, pending tool use, and waiting status. Use this to sync state when reconnecting from a mobile device.",
+		Description: "Returns the full runtime state of a session including Claude state, pending tool use, waiting status, and the latest resource sample of the agent process tree (Linux). Use this to sync state when reconnecting from a mobile device.",
 		Params: []handler.OpenRPCParam{
 			{Name: "session_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
 		},
@@ -246,6 +270,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "state",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("session/history", s.History, handler.MethodMeta{
@@ -260,6 +285,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "history",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("workspace/session/history", s.History, handler.MethodMeta{
@@ -274,6 +300,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "history",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("workspace/session/messages", s.GetSessionMessages, handler.MethodMeta{
@@ -291,6 +318,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "messages",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("workspace/session/watch", s.WatchSession, handler.MethodMeta{
@@ -358,6 +386,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "status",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("git/diff", s.GitDiff, handler.MethodMeta{
@@ -365,12 +394,14 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 		Description: "Returns the git diff for the specified workspace.",
 		Params: []handler.OpenRPCParam{
 			{Name: "workspace_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "path", Required: false, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "staged", Required: false, Schema: map[string]interface{}{"type": "boolean"}},
 		},
 		Result: &handler.OpenRPCResult{
 			Name:   "diff",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("git/stage", s.GitStage, handler.MethodMeta{
@@ -384,6 +415,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/unstage", s.GitUnstage, handler.MethodMeta{
@@ -397,6 +429,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/discard", s.GitDiscard, handler.MethodMeta{
@@ -410,6 +443,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/commit", s.GitCommit, handler.MethodMeta{
@@ -424,6 +458,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/push", s.GitPush, handler.MethodMeta{
@@ -440,6 +475,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/pull", s.GitPull, handler.MethodMeta{
@@ -453,6 +489,8 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
+		Progress:    true,
 	})
 
 	registry.RegisterWithMeta("git/branches", s.GitBranches, handler.MethodMeta{
@@ -465,6 +503,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("git/checkout", s.GitCheckout, handler.MethodMeta{
@@ -479,6 +518,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/branch/delete", s.GitDeleteBranch, handler.MethodMeta{
@@ -493,6 +533,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/fetch", s.GitFetch, handler.MethodMeta{
@@ -507,6 +548,8 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
+		Progress:    true,
 	})
 
 	registry.RegisterWithMeta("git/log", s.GitLog, handler.MethodMeta{
@@ -524,6 +567,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	// Stash methods
@@ -539,6 +583,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/stash/list", s.GitStashList, handler.MethodMeta{
@@ -551,6 +596,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("git/stash/apply", s.GitStashApply, handler.MethodMeta{
@@ -564,6 +610,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/stash/pop", s.GitStashPop, handler.MethodMeta{
@@ -577,6 +624,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/stash/drop", s.GitStashDrop, handler.MethodMeta{
@@ -590,6 +638,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	// Merge methods
@@ -606,6 +655,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/merge/abort", s.GitMergeAbort, handler.MethodMeta{
@@ -618,6 +668,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	// Repository setup methods
@@ -634,6 +685,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/remote/add", s.GitRemoteAdd, handler.MethodMeta{
@@ -649,6 +701,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/remote/list", s.GitRemoteList, handler.MethodMeta{
@@ -661,6 +714,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("git/remote/remove", s.GitRemoteRemove, handler.MethodMeta{
@@ -674,6 +728,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/upstream/set", s.GitSetUpstream, handler.MethodMeta{
@@ -688,6 +743,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyWorkspace,
 	})
 
 	registry.RegisterWithMeta("git/get_status", s.GitGetStatus, handler.MethodMeta{
@@ -700,6 +756,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "result",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	// File methods
@@ -716,6 +773,7 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "files_list",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("workspace/file/get", s.FileGet, handler.MethodMeta{
@@ -730,7 +788,12 @@ func (s *SessionManagerService) RegisterMethods(registry *handler.Registry) {
 			Name:   "file_content",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
+
+	s.registerSnapshotMethods(registry)
+	s.registerBatchMethods(registry)
+	s.registerHandoffMethods(registry)
 }
 
 // Start starts or attaches to a session for a workspace.
@@ -742,6 +805,7 @@ func (s *SessionManagerService) Start(ctx context.Context, params json.RawMessag
 		PermissionMode string `json:"permission_mode"`
 		YoloMode       bool   `json:"yolo_mode"`
 		AgentType      string `json:"agent_type"`
+		Profile        string `json:"profile"`
 	}
 	if err := json.Unmarshal(params, &p); err != nil {
 		return nil, message.NewError(message.InvalidParams, "failed to parse params: "+err.Error())
@@ -758,7 +822,7 @@ func (s *SessionManagerService) Start(ctx context.Context, params json.RawMessag
 	if dispatchErr != nil {
 		return nil, dispatchErr
 	}
-	return runtimeDispatch.start(ctx, p.WorkspaceID, p.SessionID, p.PermissionMode, p.YoloMode)
+	return runtimeDispatch.start(ctx, p.WorkspaceID, p.SessionID, p.PermissionMode, p.Profile, p.YoloMode)
 }
 
 // Stop stops a running session.
@@ -817,7 +881,17 @@ func (s *SessionManagerService) Send(ctx context.Context, params json.RawMessage
 		p.Mode = "new"
 	}
 
-	return runtimeDispatch.send(ctx, p.WorkspaceID, p.SessionID, p.Prompt, p.Mode, p.PermissionMode, p.YoloMode)
+	// Snapshot the working tree before the agent acts on this turn so it can be
+	// rolled back with session/snapshots/restore.
+	if p.SessionID != "" {
+		s.captureTurnSnapshot(p.WorkspaceID, p.SessionID, p.Prompt)
+		return runtimeDispatch.send(ctx, p.WorkspaceID, p.SessionID, p.Prompt, p.Mode, p.PermissionMode, p.YoloMode)
+	}
+
+	pendingSnapshot := s.captureStartSnapshot(p.WorkspaceID, p.Prompt)
+	result, rpcErr := runtimeDispatch.send(ctx, p.WorkspaceID, p.SessionID, p.Prompt, p.Mode, p.PermissionMode, p.YoloMode)
+	s.adoptStartSnapshot(p.WorkspaceID, pendingSnapshot, result)
+	return result, rpcErr
 }
 
 // Input sends keyboard input to an interactive (PTY) session.
@@ -853,6 +927,8 @@ func (s *SessionManagerService) Respond(ctx context.Context, params json.RawMess
 		Type      string `json:"type"`     // "permission" or "question"
 		Response  string `json:"response"` // "yes"/"no" for permission, or text for question
 		AgentType string `json:"agent_type"`
+
+		UpdatedInput map[string]interface{} `json:"updated_input"` // Approve a permission with edited input
 	}
 	if err := json.Unmarshal(params, &p); err != nil {
 		return nil, message.NewError(message.InvalidParams, "failed to parse params: "+err.Error())
@@ -868,13 +944,43 @@ func (s *SessionManagerService) Respond(ctx context.Context, params json.RawMess
 		return nil, message.NewError(message.InvalidParams, "response is required")
 	}
 
-	_, runtimeDispatch, dispatchErr := s.resolveRuntimeDispatch(p.AgentType)
+	agentType, runtimeDispatch, dispatchErr := s.resolveRuntimeDispatch(p.AgentType)
 	if dispatchErr != nil {
 		return nil, dispatchErr
 	}
+	if p.UpdatedInput != nil {
+		return s.respondWithEditedInput(ctx, agentType, p.SessionID, p.Type, p.UpdatedInput)
+	}
 	return runtimeDispatch.respond(ctx, p.SessionID, p.Type, p.Response)
 }
 
+// respondWithEditedInput approves a pending Claude permission with edited tool input.
+func (s *SessionManagerService) respondWithEditedInput(ctx context.Context, agentType, sessionID, responseType string, updatedInput map[string]interface{}) (interface{}, *message.Error) {
+	if responseType != "permission" {
+		return nil, message.NewError(message.InvalidParams, "updated_input is only valid for type 'permission'")
+	}
+	if agentType != sessionManagerAgentClaude {
+		return nil, message.NewError(message.InvalidParams, "updated_input is not supported for agent_type "+agentType)
+	}
+	if s.manager == nil {
+		return nil, message.NewError(message.AgentNotConfigured, "claude session manager is not configured")
+	}
+
+	if err := s.manager.RespondToPermissionWithEdit(sessionID, updatedInput); err != nil {
+		return nil, message.NewError(message.InternalError, err.Error())
+	}
+
+	// Let other devices dismiss their permission popups.
+	clientID, _ := ctx.Value(handler.ClientIDKey).(string)
+	s.manager.EmitPermissionResolved(sessionID, clientID, "modified")
+
+	return map[string]interface{}{
+		"status":     "responded",
+		"agent_type": sessionManagerAgentClaude,
+		"modified":   true,
+	}, nil
+}
+
 // Active returns a list of active sessions.
 func (s *SessionManagerService) Active(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
 	var p struct {
@@ -1022,6 +1128,7 @@ func (s *SessionManagerService) History(ctx context.Context, params json.RawMess
 	if err != nil {
 		return nil, message.NewError(message.InternalError, err.Error())
 	}
+	s.attachHandoffLinks(history)
 
 	return map[string]interface{}{
 		"sessions": history,
@@ -1419,10 +1526,18 @@ func (s *SessionManagerService) GitPull(ctx context.Context, params json.RawMess
 		return nil, message.NewError(message.InvalidParams, "workspace_id is required")
 	}
 
-	result, err := s.manager.GitPull(p.WorkspaceID, p.Rebase)
+	progress := handler.ProgressFrom(ctx)
+	progress.Begin("Pulling")
+	result, err := s.manager.GitPull(ctx, p.WorkspaceID, p.Rebase, progress.ReportFunc())
 	if err != nil {
+		progress.End("Pull failed")
 		return nil, message.NewError(message.InternalError, err.Error())
 	}
+	if result.Success {
+		progress.End("Pull completed")
+	} else {
+		progress.End("Pull failed")
+	}
 
 	// Return JSON-RPC error if git operation failed
 	if !result.Success {
@@ -1533,10 +1648,18 @@ func (s *SessionManagerService) GitFetch(ctx context.Context, params json.RawMes
 		p.Remote = "origin"
 	}
 
-	result, err := s.manager.GitFetch(p.WorkspaceID, p.Remote, p.Prune)
+	progress := handler.ProgressFrom(ctx)
+	progress.Begin("Fetching " + p.Remote)
+	result, err := s.manager.GitFetch(ctx, p.WorkspaceID, p.Remote, p.Prune, progress.ReportFunc())
 	if err != nil {
+		progress.End("Fetch failed")
 		return nil, message.NewError(message.InternalError, err.Error())
 	}
+	if result.Success {
+		progress.End("Fetch completed")
+	} else {
+		progress.End("Fetch failed")
+	}
 
 	// Return JSON-RPC error if git operation failed
 	if !result.Success {
@@ -2664,7 +2787,7 @@ func enableRuntimeBypass(permissionMode string, yoloMode bool) bool {
 	}
 }
 
-func (s *SessionManagerService) startCodexSession(ctx context.Context, workspaceID, sessionID, permissionMode string, yoloMode bool) (interface{}, *message.Error) {
+func (s *SessionManagerService) startCodexSession(ctx context.Context, workspaceID, sessionID, permissionMode, profile string, yoloMode bool) (interface{}, *message.Error) {
 	if s.manager == nil {
 		return nil, message.NewErrorWithData(
 			message.AgentNotConfigured,
@@ -2675,6 +2798,10 @@ func (s *SessionManagerService) startCodexSession(ctx context.Context, workspace
 			},
 		)
 	}
+	if profile != "" {
+		// Codex is re-spawned per prompt, so session-scoped launch settings would not persist.
+		return nil, message.NewError(message.InvalidParams, "launch profiles are only supported for agent_type claude")
+	}
 
 	ws, err := s.manager.GetWorkspace(workspaceID)
 	if err != nil {
@@ -3038,7 +3165,8 @@ func (s *SessionManagerService) startCodexProcess(ctx context.Context, mapSessio
 		args = []string{}
 	}
 
-	cmd := exec.CommandContext(ctx, "codex", args...)
+	// The process outlives the request that started it
+	cmd := exec.CommandContext(context.WithoutCancel(ctx), "codex", args...)
 	cmd.Dir = workspacePath
 
 	ptmx, err := pty.Start(cmd)
@@ -3053,6 +3181,7 @@ func (s *SessionManagerService) startCodexProcess(ctx context.Context, mapSessio
 		workspace:   workspacePath,
 		cmd:         cmd,
 		ptmx:        ptmx,
+		approvals:   codex.NewApprovalDetector(),
 	}
 
 	s.codexMu.Lock()
@@ -3101,6 +3230,7 @@ func (s *SessionManagerService) streamCodexOutput(codexSession *codexPTYSession)
 		if strings.TrimSpace(trimmedLine) == "" {
 			return
 		}
+		s.detectCodexApproval(codexSession, trimmedLine)
 
 		// Keep very large lines as immediate standalone events.
 		if len(trimmedLine) >= codexPTYOutputMaxBytes {
@@ -3194,6 +3324,7 @@ func (s *SessionManagerService) waitCodexProcess(codexSession *codexPTYSession)
 	delete(s.codexLastPTYLogLine, currentSessionID)
 	s.codexMu.Unlock()
 	s.stopCodexSessionIDWatcher(currentSessionID)
+	codexSession.clearApproval()
 
 	if waitErr != nil {
 		s.publishCodexPTYOutput(currentSessionID, waitErr.Error(), ptyStateError)
@@ -3522,7 +3653,8 @@ func (s *SessionManagerService) startCodexSessionIDWatcher(ctx context.Context,
 		s.codexMu.Unlock()
 		return
 	}
-	watchCtx, cancel := context.WithCancel(ctx)
+	// The watcher outlives the request that started the session
+	watchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
 	s.codexSessionWatchers[temporaryID] = cancel
 	s.codexMu.Unlock()
 
@@ -3592,6 +3724,9 @@ func (s *SessionManagerService) remapCodexSessionID(oldSessionID, newSessionID s
 	if oldSessionID == "" || newSessionID == "" || oldSessionID == newSessionID {
 		return
 	}
+	if s.manager != nil {
+		s.manager.RemapHandoffSession(oldSessionID, newSessionID)
+	}
 
 	s.codexMu.Lock()
 	defer s.codexMu.Unlock()
@@ -3619,6 +3754,29 @@ func (s *SessionManagerService) getCodexSession(sessionID string) *codexPTYSessi
 	return s.codexSessions[sessionID]
 }
 
+// AgentProcesses returns the running Codex PTY processes so the session
+// manager's resource monitor samples them alongside Claude sessions.
+func (s *SessionManagerService) AgentProcesses() []session.AgentProcess {
+	s.codexMu.Lock()
+	defer s.codexMu.Unlock()
+
+	processes := make([]session.AgentProcess, 0, len(s.codexSessions))
+	seen := make(map[*codexPTYSession]bool, len(s.codexSessions))
+	for key, codexSession := range s.codexSessions {
+		if seen[codexSession] || codexSession.cmd == nil || codexSession.cmd.Process == nil {
+			continue
+		}
+		seen[codexSession] = true
+		processes = append(processes, session.AgentProcess{
+			WorkspaceID: codexSession.workspaceID,
+			SessionID:   codexSession.SessionID(),
+			PID:         codexSession.cmd.Process.Pid,
+			Stop:        func() error { return s.stopCodexSession(key) },
+		})
+	}
+	return processes
+}
+
 func (s *SessionManagerService) getCodexSessionForWorkspace(workspaceID string) *codexPTYSession {
 	s.codexMu.Lock()
 	defer s.codexMu.Unlock()
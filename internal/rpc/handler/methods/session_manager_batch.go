@@ -0,0 +1,320 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/google/uuid"
+)
+
+const (
+	batchDefaultConcurrency = 4
+	batchMaxConcurrency     = 16
+	batchMaxWorkspaces      = 100
+	batchMaxRetained        = 50
+
+	batchItemPending  = "pending"
+	batchItemStarting = "starting"
+	batchItemStarted  = "started"
+	batchItemFailed   = "failed"
+)
+
+// sessionBatch tracks one session/batch_start run.
+type sessionBatch struct {
+	mu          sync.Mutex
+	id          string
+	agentType   string
+	createdAt   time.Time
+	completedAt time.Time
+	items       []events.SessionBatchItem
+}
+
+// registerBatchMethods registers multi-workspace batch session methods.
+func (s *SessionManagerService) registerBatchMethods(registry *handler.Registry) {
+	registry.RegisterWithMeta("session/batch_start", s.BatchStart, handler.MethodMeta{
+		Summary:     "Start sessions with the same prompt in several workspaces",
+		Description: "Starts a new session in each selected workspace and sends it the prompt, with bounded concurrency. Returns immediately with a batch ID; progress is reported via session_batch events and session/batch_status.",
+		Params: []handler.OpenRPCParam{
+			{Name: "workspace_ids", Required: false, Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Workspaces to start sessions in. Combined with 'tag' if both are given."}},
+			{Name: "tag", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Select every workspace carrying this tag."}},
+			{Name: "prompt", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "agent_type", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"claude", "codex"}, "default": "claude", "description": "Agent runtime type."}},
+			{Name: "permission_mode", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"default", "acceptEdits", "bypassPermissions", "plan", "interactive"}, "default": "default"}},
+			{Name: "yolo_mode", Required: false, Schema: map[string]interface{}{"type": "boolean"}},
+			{Name: "max_concurrency", Required: false, Schema: map[string]interface{}{"type": "integer", "default": batchDefaultConcurrency, "minimum": 1, "maximum": batchMaxConcurrency}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "batch",
+			Schema: map[string]interface{}{"type": "object"},
+		},
+	})
+
+	registry.RegisterWithMeta("session/batch_status", s.BatchStatus, handler.MethodMeta{
+		Summary:     "Get the progress of a batch session start",
+		Description: "Returns aggregate counts and per-workspace state of a session/batch_start run.",
+		Params: []handler.OpenRPCParam{
+			{Name: "batch_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "batch",
+			Schema: map[string]interface{}{"type": "object"},
+		},
+		Concurrency: handler.ConcurrencyParallel,
+	})
+}
+
+// BatchStart starts sessions in several workspaces with the same prompt.
+func (s *SessionManagerService) BatchStart(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	var p struct {
+		WorkspaceIDs   []string `json:"workspace_ids"`
+		Tag            string   `json:"tag"`
+		Prompt         string   `json:"prompt"`
+		AgentType      string   `json:"agent_type"`
+		PermissionMode string   `json:"permission_mode"`
+		YoloMode       bool     `json:"yolo_mode"`
+		MaxConcurrency int      `json:"max_concurrency"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.NewError(message.InvalidParams, "failed to parse params: "+err.Error())
+	}
+
+	if p.Prompt == "" {
+		return nil, message.NewError(message.InvalidParams, "prompt is required")
+	}
+	if len(p.WorkspaceIDs) == 0 && strings.TrimSpace(p.Tag) == "" {
+		return nil, message.NewError(message.InvalidParams, "workspace_ids or tag is required")
+	}
+	if err := validatePermissionMode(p.PermissionMode); err != nil {
+		return nil, err
+	}
+	if p.MaxConcurrency <= 0 {
+		p.MaxConcurrency = batchDefaultConcurrency
+	}
+	if p.MaxConcurrency > batchMaxConcurrency {
+		p.MaxConcurrency = batchMaxConcurrency
+	}
+
+	agentType, runtimeDispatch, dispatchErr := s.resolveRuntimeDispatch(p.AgentType)
+	if dispatchErr != nil {
+		return nil, dispatchErr
+	}
+	if err := s.ensureSessionManagerConfigured("session/batch_start"); err != nil {
+		return nil, err
+	}
+
+	workspaceIDs, rpcErr := s.resolveBatchWorkspaces(p.WorkspaceIDs, p.Tag)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	batch := &sessionBatch{
+		id:        uuid.New().String(),
+		agentType: agentType,
+		createdAt: time.Now().UTC(),
+		items:     make([]events.SessionBatchItem, len(workspaceIDs)),
+	}
+	for i, id := range workspaceIDs {
+		batch.items[i] = events.SessionBatchItem{WorkspaceID: id, Status: batchItemPending}
+	}
+	s.storeBatch(batch)
+
+	// The batch outlives this request; sessions must not be tied to its context.
+	runCtx := context.WithoutCancel(ctx)
+	go func() {
+		sem := make(chan struct{}, p.MaxConcurrency)
+		var wg sync.WaitGroup
+		for i := range batch.items {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(index int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				workspaceID := batch.items[index].WorkspaceID
+				s.updateBatchItem(batch, index, batchItemStarting, "", "")
+
+				pendingSnapshot := s.captureStartSnapshot(workspaceID, p.Prompt)
+				result, err := runtimeDispatch.send(runCtx, workspaceID, "", p.Prompt, "new", p.PermissionMode, p.YoloMode)
+				sessionID := s.adoptStartSnapshot(workspaceID, pendingSnapshot, result)
+				if err != nil {
+					s.updateBatchItem(batch, index, batchItemFailed, "", err.Message)
+					return
+				}
+				s.updateBatchItem(batch, index, batchItemStarted, sessionID, "")
+			}(i)
+		}
+		wg.Wait()
+
+		batch.mu.Lock()
+		batch.completedAt = time.Now().UTC()
+		payload := batch.payloadLocked(nil)
+		batch.mu.Unlock()
+		s.manager.PublishEvent(events.NewSessionBatchEvent("", "", payload))
+	}()
+
+	return map[string]interface{}{
+		"batch_id":        batch.id,
+		"agent_type":      agentType,
+		"workspace_ids":   workspaceIDs,
+		"total":           len(workspaceIDs),
+		"max_concurrency": p.MaxConcurrency,
+	}, nil
+}
+
+// BatchStatus returns the progress of a batch session start.
+func (s *SessionManagerService) BatchStatus(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	var p struct {
+		BatchID string `json:"batch_id"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.NewError(message.InvalidParams, "failed to parse params: "+err.Error())
+	}
+	if p.BatchID == "" {
+		return nil, message.NewError(message.InvalidParams, "batch_id is required")
+	}
+
+	s.batchMu.Lock()
+	batch := s.batches[p.BatchID]
+	s.batchMu.Unlock()
+	if batch == nil {
+		return nil, message.NewError(message.InvalidParams, "batch not found: "+p.BatchID)
+	}
+
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+
+	payload := batch.payloadLocked(nil)
+	status := "running"
+	if payload.Done {
+		status = "completed"
+	}
+	result := map[string]interface{}{
+		"batch_id":   batch.id,
+		"agent_type": batch.agentType,
+		"status":     status,
+		"created_at": batch.createdAt,
+		"total":      payload.Total,
+		"pending":    payload.Pending,
+		"started":    payload.Started,
+		"failed":     payload.Failed,
+		"items":      append([]events.SessionBatchItem(nil), batch.items...),
+	}
+	if !batch.completedAt.IsZero() {
+		result["completed_at"] = batch.completedAt
+	}
+	return result, nil
+}
+
+// resolveBatchWorkspaces merges explicit workspace IDs with the workspaces
+// carrying tag, preserving order and dropping duplicates.
+func (s *SessionManagerService) resolveBatchWorkspaces(workspaceIDs []string, tag string) ([]string, *message.Error) {
+	seen := make(map[string]bool)
+	var result []string
+	for _, id := range workspaceIDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		if _, err := s.manager.GetWorkspace(id); err != nil {
+			return nil, message.NewError(message.InvalidParams, "workspace not found: "+id)
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+
+	if tag = strings.TrimSpace(tag); tag != "" {
+		var tagged []string
+		for _, ws := range s.manager.ListWorkspaces() {
+			if ws.Definition.HasTag(tag) && !seen[ws.Definition.ID] {
+				seen[ws.Definition.ID] = true
+				tagged = append(tagged, ws.Definition.ID)
+			}
+		}
+		sort.Strings(tagged)
+		result = append(result, tagged...)
+	}
+
+	if len(result) == 0 {
+		return nil, message.NewError(message.InvalidParams, "no workspaces matched")
+	}
+	if len(result) > batchMaxWorkspaces {
+		return nil, message.NewError(message.InvalidParams, "too many workspaces in one batch")
+	}
+	return result, nil
+}
+
+// storeBatch records a new batch, evicting the oldest completed batches once
+// more than batchMaxRetained are held.
+func (s *SessionManagerService) storeBatch(batch *sessionBatch) {
+	s.batchMu.Lock()
+	defer s.batchMu.Unlock()
+
+	if s.batches == nil {
+		s.batches = make(map[string]*sessionBatch)
+	}
+	s.batches[batch.id] = batch
+	s.batchOrder = append(s.batchOrder, batch.id)
+
+	// Running batches are kept however old they are, so a long one cannot
+	// pin finished batches behind it
+	excess := len(s.batchOrder) - batchMaxRetained
+	kept := s.batchOrder[:0]
+	for _, id := range s.batchOrder {
+		if excess > 0 {
+			b := s.batches[id]
+			b.mu.Lock()
+			running := b.completedAt.IsZero()
+			b.mu.Unlock()
+			if !running {
+				delete(s.batches, id)
+				excess--
+				continue
+			}
+		}
+		kept = append(kept, id)
+	}
+	s.batchOrder = kept
+}
+
+// updateBatchItem changes the state of one batch item and publishes progress.
+func (s *SessionManagerService) updateBatchItem(batch *sessionBatch, index int, status, sessionID, errMsg string) {
+	batch.mu.Lock()
+	item := &batch.items[index]
+	item.Status = status
+	item.SessionID = sessionID
+	item.Error = errMsg
+	changed := *item
+	payload := batch.payloadLocked(&changed)
+	batch.mu.Unlock()
+
+	s.manager.PublishEvent(events.NewSessionBatchEvent(changed.WorkspaceID, sessionID, payload))
+}
+
+// payloadLocked builds a progress payload. Must hold batch.mu.
+func (b *sessionBatch) payloadLocked(item *events.SessionBatchItem) events.SessionBatchPayload {
+	payload := events.SessionBatchPayload{
+		BatchID:   b.id,
+		AgentType: b.agentType,
+		Total:     len(b.items),
+		Item:      item,
+	}
+	for _, it := range b.items {
+		switch it.Status {
+		case batchItemStarted:
+			payload.Started++
+		case batchItemFailed:
+			payload.Failed++
+		default:
+			payload.Pending++
+		}
+	}
+	payload.Done = !b.completedAt.IsZero()
+	return payload
+}
@@ -0,0 +1,123 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+)
+
+func TestSessionManagerBatch_MethodMetadata(t *testing.T) {
+	service := NewSessionManagerService(nil)
+	registry := handler.NewRegistry()
+	service.RegisterMethods(registry)
+
+	startMeta := registry.GetMeta("session/batch_start")
+	assertParamContract(t, startMeta, "prompt", true)
+	assertParamContract(t, startMeta, "workspace_ids", false)
+	assertParamContract(t, startMeta, "tag", false)
+	concurrency := assertParamContract(t, startMeta, "max_concurrency", false)
+	if got := concurrency.Schema["default"]; got != batchDefaultConcurrency {
+		t.Fatalf("max_concurrency default = %v, want %d", got, batchDefaultConcurrency)
+	}
+
+	statusMeta := registry.GetMeta("session/batch_status")
+	assertParamContract(t, statusMeta, "batch_id", true)
+}
+
+func TestSessionManagerBatch_ValidatesParams(t *testing.T) {
+	service := NewSessionManagerService(nil)
+
+	tests := []struct {
+		name   string
+		call   func(context.Context, json.RawMessage) (interface{}, *message.Error)
+		params string
+		code   int
+	}{
+		{"missing prompt", service.BatchStart, `{"tag":"web"}`, message.InvalidParams},
+		{"missing selection", service.BatchStart, `{"prompt":"hi"}`, message.InvalidParams},
+		{"bad permission mode", service.BatchStart, `{"prompt":"hi","tag":"web","permission_mode":"nope"}`, message.InvalidParams},
+		{"without manager", service.BatchStart, `{"prompt":"hi","tag":"web"}`, message.AgentNotConfigured},
+		{"status missing id", service.BatchStatus, `{}`, message.InvalidParams},
+		{"status unknown id", service.BatchStatus, `{"batch_id":"missing"}`, message.InvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.call(context.Background(), json.RawMessage(tt.params))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if err.Code != tt.code {
+				t.Fatalf("error code = %d, want %d (%s)", err.Code, tt.code, err.Message)
+			}
+		})
+	}
+}
+
+func TestSessionBatch_PayloadCounts(t *testing.T) {
+	batch := &sessionBatch{
+		id:        "b1",
+		agentType: "claude",
+		items: []events.SessionBatchItem{
+			{WorkspaceID: "a", Status: batchItemStarted},
+			{WorkspaceID: "b", Status: batchItemFailed},
+			{WorkspaceID: "c", Status: batchItemStarting},
+			{WorkspaceID: "d", Status: batchItemPending},
+		},
+	}
+
+	payload := batch.payloadLocked(nil)
+	if payload.Total != 4 || payload.Started != 1 || payload.Failed != 1 || payload.Pending != 2 {
+		t.Fatalf("unexpected counts: %+v", payload)
+	}
+	if payload.Done {
+		t.Fatal("batch without completion time should not be done")
+	}
+}
+
+func TestSessionManagerBatch_StatusReportsProgress(t *testing.T) {
+	service := NewSessionManagerService(nil)
+	service.storeBatch(&sessionBatch{
+		id:        "b1",
+		agentType: "codex",
+		items: []events.SessionBatchItem{
+			{WorkspaceID: "a", Status: batchItemStarted, SessionID: "s1"},
+			{WorkspaceID: "b", Status: batchItemPending},
+		},
+	})
+
+	result, err := service.BatchStatus(context.Background(), json.RawMessage(`{"batch_id":"b1"}`))
+	if err != nil {
+		t.Fatalf("BatchStatus() error = %v", err.Message)
+	}
+	status := result.(map[string]interface{})
+	if status["status"] != "running" || status["started"] != 1 || status["pending"] != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestSessionManagerBatch_EvictionSkipsRunningBatches(t *testing.T) {
+	service := NewSessionManagerService(nil)
+	service.storeBatch(&sessionBatch{id: "running"})
+	for i := 0; i < batchMaxRetained; i++ {
+		service.storeBatch(&sessionBatch{id: fmt.Sprintf("done-%d", i), completedAt: time.Now()})
+	}
+
+	if len(service.batches) != batchMaxRetained {
+		t.Fatalf("retained %d batches, want %d", len(service.batches), batchMaxRetained)
+	}
+	if _, ok := service.batches["running"]; !ok {
+		t.Fatal("running batch should not be evicted")
+	}
+	if _, ok := service.batches["done-0"]; ok {
+		t.Fatal("oldest finished batch should be evicted")
+	}
+	if len(service.batchOrder) != len(service.batches) || service.batchOrder[0] != "running" {
+		t.Fatalf("unexpected batch order: %v", service.batchOrder)
+	}
+}
@@ -0,0 +1,116 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/rs/zerolog/log"
+)
+
+// CodexApprovalDecider decides Codex approval prompts through the permission
+// flow shared with Claude hooks.
+type CodexApprovalDecider interface {
+	DecideCodexApproval(ctx context.Context, approval CodexApproval) *permission.Response
+}
+
+// detectCodexApproval feeds a line of PTY output to the approval detector
+// and hands a complete prompt to the permission flow.
+func (s *SessionManagerService) detectCodexApproval(codexSession *codexPTYSession, line string) {
+	if s.codexApprovals == nil || codexSession.approvals == nil {
+		return
+	}
+
+	codexSession.approvalMu.Lock()
+	defer codexSession.approvalMu.Unlock()
+
+	var approval CodexApproval
+	for _, clean := range strings.Split(sanitizeCodexPTYOutputText(line), "\n") {
+		if prompt := codexSession.approvals.Feed(clean); prompt != nil {
+			approval.Prompt = prompt
+		}
+	}
+	if approval.Prompt == nil {
+		return
+	}
+
+	codexSession.approvalSeq++
+	sessionID := codexSession.SessionID()
+	approval.SessionID = sessionID
+	approval.WorkspaceID = codexSession.workspaceID
+	approval.Cwd = codexSession.workspace
+	approval.ToolUseID = fmt.Sprintf("codex-%s-%d", sessionID, codexSession.approvalSeq)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	codexSession.approvalCancel = cancel
+	go s.answerCodexApproval(ctx, codexSession, approval)
+}
+
+// answerCodexApproval waits for the decision on a Codex prompt and types it
+// into the PTY. Ask decisions leave the prompt open for session/respond.
+// A cancelled ctx means the prompt was answered through session/respond or
+// session/input, or Codex exited, so nothing is typed.
+func (s *SessionManagerService) answerCodexApproval(ctx context.Context, codexSession *codexPTYSession, approval CodexApproval) {
+	response := s.codexApprovals.DecideCodexApproval(ctx, approval)
+	if !codexSession.finishApproval(ctx) {
+		return
+	}
+	if response == nil || (response.Decision != permission.DecisionAllow && response.Decision != permission.DecisionDeny) {
+		return
+	}
+
+	// The detector ignores redraws of the prompt until the answer is typed
+	sessionID := codexSession.SessionID()
+	err := s.respondCodexSession(sessionID, "permission", string(response.Decision))
+	codexSession.clearApproval()
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("session_id", sessionID).
+			Str("tool_use_id", approval.ToolUseID).
+			Msg("failed to answer Codex approval prompt")
+		return
+	}
+	log.Info().
+		Str("session_id", sessionID).
+		Str("tool_use_id", approval.ToolUseID).
+		Str("decision", string(response.Decision)).
+		Str("message", response.Message).
+		Msg("answered Codex approval prompt")
+}
+
+// clearCodexApproval withdraws the pending approval of a Codex session that
+// was answered through session/respond or session/input.
+func (s *SessionManagerService) clearCodexApproval(sessionID string) {
+	if codexSession := s.getCodexSession(sessionID); codexSession != nil {
+		codexSession.clearApproval()
+	}
+}
+
+// finishApproval ends the pending approval started with ctx. It reports
+// false when the approval was already cleared by another answer.
+func (c *codexPTYSession) finishApproval(ctx context.Context) bool {
+	c.approvalMu.Lock()
+	defer c.approvalMu.Unlock()
+	if ctx.Err() != nil {
+		return false
+	}
+	c.approvalCancel()
+	c.approvalCancel = nil
+	return true
+}
+
+// clearApproval withdraws a pending approval and re-arms the detector, once
+// the prompt was answered or Codex exited.
+func (c *codexPTYSession) clearApproval() {
+	c.approvalMu.Lock()
+	defer c.approvalMu.Unlock()
+	if c.approvalCancel != nil {
+		c.approvalCancel()
+		c.approvalCancel = nil
+	}
+	if c.approvals != nil {
+		c.approvals.Reset()
+	}
+}
@@ -1,9 +1,24 @@
 package methods
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/adapters/codex"
+	"github.com/brianly1003/cdev/internal/config"
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/brianly1003/cdev/internal/session"
+	"github.com/brianly1003/cdev/internal/testutil"
 )
 
 func TestRemapCodexSessionID_UpdatesSessionAndMovesDedupeState(t *testing.T) {
@@ -163,3 +178,145 @@ func TestExtractCodexContextWindowErrorText_NoMatch(t *testing.T) {
 		t.Fatalf("got %q, want empty", got)
 	}
 }
+
+type stubCodexApprovalDecider struct {
+	approvals chan CodexApproval
+	decide    func(ctx context.Context) *permission.Response
+}
+
+func (d *stubCodexApprovalDecider) DecideCodexApproval(ctx context.Context, approval CodexApproval) *permission.Response {
+	d.approvals <- approval
+	return d.decide(ctx)
+}
+
+func newCodexApprovalTestSession(t *testing.T, decider CodexApprovalDecider) (*SessionManagerService, *codexPTYSession, *os.File) {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = reader.Close()
+		_ = writer.Close()
+	})
+
+	service := &SessionManagerService{codexSessions: make(map[string]*codexPTYSession)}
+	service.SetCodexApprovalDecider(decider)
+	session := &codexPTYSession{
+		sessionID:   "codex-1",
+		workspaceID: "ws-1",
+		workspace:   "/repo",
+		ptmx:        writer,
+		approvals:   codex.NewApprovalDetector(),
+	}
+	service.codexSessions["codex-1"] = session
+	return service, session, reader
+}
+
+func TestDetectCodexApproval_TypesDecision(t *testing.T) {
+	decider := &stubCodexApprovalDecider{
+		approvals: make(chan CodexApproval, 1),
+		decide: func(context.Context) *permission.Response {
+			return &permission.Response{Decision: permission.DecisionAllow}
+		},
+	}
+	service, session, reader := newCodexApprovalTestSession(t, decider)
+
+	for _, line := range []string{
+		"\x1b[1mWould you like to run the following command?\x1b[0m",
+		"$ go test ./...",
+		"› 1. Yes, proceed (y)",
+	} {
+		service.detectCodexApproval(session, line)
+	}
+
+	approval := <-decider.approvals
+	if approval.SessionID != "codex-1" || approval.WorkspaceID != "ws-1" || approval.Cwd != "/repo" {
+		t.Errorf("approval context = %+v", approval)
+	}
+	if approval.ToolUseID != "codex-codex-1-1" || approval.Prompt.Command != "go test ./..." {
+		t.Errorf("approval = %+v, prompt = %+v", approval, approval.Prompt)
+	}
+
+	buf := make([]byte, 8)
+	n, err := reader.Read(buf)
+	if err != nil {
+		t.Fatalf("read PTY input: %v", err)
+	}
+	if got := string(buf[:n]); got != "y\r" {
+		t.Errorf("typed %q, want %q", got, "y\r")
+	}
+}
+
+func TestDetectCodexApproval_AnsweredElsewhereTypesNothing(t *testing.T) {
+	cancelled := make(chan struct{})
+	decider := &stubCodexApprovalDecider{
+		approvals: make(chan CodexApproval, 1),
+		decide: func(ctx context.Context) *permission.Response {
+			<-ctx.Done()
+			defer close(cancelled)
+			return &permission.Response{Decision: permission.DecisionDeny, Message: "cancelled"}
+		},
+	}
+	service, session, reader := newCodexApprovalTestSession(t, decider)
+
+	service.detectCodexApproval(session, "Allow command?  $ make  Yes (y)  No (n)")
+	<-decider.approvals
+
+	// Answered through session/respond: the pending request is withdrawn
+	service.clearCodexApproval("codex-1")
+	<-cancelled
+	time.Sleep(20 * time.Millisecond)
+
+	_ = session.ptmx.Close()
+	typed, _ := io.ReadAll(reader)
+	if len(typed) != 0 {
+		t.Errorf("typed %q after the prompt was answered elsewhere, want nothing", typed)
+	}
+}
+
+// TestStartCodexProcess_OutlivesRequest fails when a Codex process started
+// by a request is tied to the request context, which the executor cancels
+// as soon as the handler returns.
+func TestStartCodexProcess_OutlivesRequest(t *testing.T) {
+	bin := t.TempDir()
+	script := "#!/bin/sh\nsleep 30\n"
+	if err := os.WriteFile(filepath.Join(bin, "codex"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	manager := session.NewManager(testutil.NewMockEventHub(), &config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	service := NewSessionManagerService(manager)
+	t.Cleanup(func() {
+		if codexSession := service.getCodexSession("codex-1"); codexSession != nil {
+			_ = codexSession.cmd.Process.Kill()
+		}
+	})
+
+	registry := handler.NewRegistry()
+	registry.Register("test/start", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		if err := service.startCodexProcess(ctx, "codex-1", "ws-1", t.TempDir(), nil); err != nil {
+			return nil, message.ErrInternalError(err.Error())
+		}
+		return map[string]bool{"started": true}, nil
+	})
+	executor := handler.NewExecutor(handler.NewDispatcher(registry), 1)
+	defer executor.Close()
+
+	responded := make(chan []byte, 1)
+	executor.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"test/start"}`), func(data []byte) { responded <- data })
+	select {
+	case data := <-responded:
+		if strings.Contains(string(data), `"error"`) {
+			t.Fatalf("test/start failed: %s", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no response")
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if service.getCodexSession("codex-1") == nil {
+		t.Fatal("codex process exited once the request returned")
+	}
+}
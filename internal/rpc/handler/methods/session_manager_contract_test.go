@@ -30,6 +30,7 @@ func TestSessionManagerProtocolContract_MethodMetadata(t *testing.T) {
 	startAgent := assertParamContract(t, startMeta, "agent_type", false)
 	assertSchemaDefault(t, startAgent, "claude")
 	assertSchemaEnumContains(t, startAgent, "claude", "codex")
+	assertParamContract(t, startMeta, "profile", false)
 
 	sendMeta := registry.GetMeta("session/send")
 	if sendMeta.Summary == "" {
@@ -347,6 +348,37 @@ func TestSessionManagerProtocolContract_SessionState(t *testing.T) {
 	})
 }
 
+func TestSessionManagerProtocolContract_ParamsValidated(t *testing.T) {
+	service := NewSessionManagerService(nil)
+	registry := handler.NewRegistry()
+	service.RegisterMethods(registry)
+	start := registry.Get("session/start")
+
+	tests := []struct {
+		name    string
+		params  string
+		pointer string
+	}{
+		{"missing workspace_id", `{}`, "/workspace_id"},
+		{"unknown permission_mode", `{"workspace_id":"ws-1","permission_mode":"yolo"}`, "/permission_mode"},
+		{"yolo_mode not boolean", `{"workspace_id":"ws-1","yolo_mode":"true"}`, "/yolo_mode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, rpcErr := start(context.Background(), []byte(tt.params))
+			if rpcErr == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if rpcErr.Code != message.InvalidParams {
+				t.Fatalf("error code = %d, want %d", rpcErr.Code, message.InvalidParams)
+			}
+			if got := decodeErrorData(t, rpcErr)["pointer"]; got != tt.pointer {
+				t.Fatalf("pointer = %q, want %q", got, tt.pointer)
+			}
+		})
+	}
+}
+
 func assertParamContract(t *testing.T, meta handler.MethodMeta, name string, required bool) handler.OpenRPCParam {
 	t.Helper()
 	param, ok := findParam(meta, name)
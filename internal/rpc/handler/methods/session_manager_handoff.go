@@ -0,0 +1,169 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/brianly1003/cdev/internal/adapters/codex"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/brianly1003/cdev/internal/session"
+)
+
+// registerHandoffMethods registers cross-runtime handoff methods.
+func (s *SessionManagerService) registerHandoffMethods(registry *handler.Registry) {
+	registry.RegisterWithMeta("session/handoff", s.Handoff, handler.MethodMeta{
+		Summary:     "Continue a session's conversation on another runtime",
+		Description: "Condenses the transcript of a Claude or Codex session into a context document, starts a new session on the other runtime seeded with it, and links both sessions in history.",
+		Params: []handler.OpenRPCParam{
+			{Name: "workspace_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "session_id", Required: true, Schema: map[string]interface{}{"type": "string", "description": "Session to hand off."}},
+			{Name: "agent_type", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"claude", "codex"}, "default": "claude", "description": "Runtime of the source session."}},
+			{Name: "target_agent_type", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"claude", "codex"}, "description": "Runtime to continue on. Defaults to the other runtime."}},
+			{Name: "prompt", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Instruction for the target session, appended after the handoff context."}},
+			{Name: "permission_mode", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"default", "acceptEdits", "bypassPermissions", "plan", "interactive"}, "default": "default"}},
+			{Name: "yolo_mode", Required: false, Schema: map[string]interface{}{"type": "boolean"}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "handoff",
+			Schema: map[string]interface{}{"type": "object"},
+		},
+	})
+}
+
+// Handoff continues a session's conversation in a new session on another runtime.
+func (s *SessionManagerService) Handoff(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	var p struct {
+		WorkspaceID     string `json:"workspace_id"`
+		SessionID       string `json:"session_id"`
+		AgentType       string `json:"agent_type"`
+		TargetAgentType string `json:"target_agent_type"`
+		Prompt          string `json:"prompt"`
+		PermissionMode  string `json:"permission_mode"`
+		YoloMode        bool   `json:"yolo_mode"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.NewError(message.InvalidParams, "failed to parse params: "+err.Error())
+	}
+
+	if p.WorkspaceID == "" {
+		return nil, message.NewError(message.InvalidParams, "workspace_id is required")
+	}
+	if p.SessionID == "" {
+		return nil, message.NewError(message.InvalidParams, "session_id is required")
+	}
+	if err := validatePermissionMode(p.PermissionMode); err != nil {
+		return nil, err
+	}
+
+	sourceAgent, _, dispatchErr := s.resolveRuntimeDispatch(p.AgentType)
+	if dispatchErr != nil {
+		return nil, dispatchErr
+	}
+	targetRaw := strings.TrimSpace(p.TargetAgentType)
+	if targetRaw == "" {
+		targetRaw = handoffCounterpart(sourceAgent)
+	}
+	targetAgent, targetDispatch, dispatchErr := s.resolveRuntimeDispatch(targetRaw)
+	if dispatchErr != nil {
+		return nil, dispatchErr
+	}
+	if targetAgent == sourceAgent {
+		return nil, message.NewError(message.InvalidParams, "target_agent_type must differ from the source runtime")
+	}
+	if err := s.ensureSessionManagerConfigured("session/handoff"); err != nil {
+		return nil, err
+	}
+
+	ws, err := s.manager.GetWorkspace(p.WorkspaceID)
+	if err != nil {
+		return nil, message.NewError(message.InvalidParams, "workspace not found: "+p.WorkspaceID)
+	}
+
+	transcript, rpcErr := s.handoffTranscript(p.WorkspaceID, p.SessionID, sourceAgent)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if len(transcript.Entries) == 0 {
+		return nil, message.NewError(message.InvalidParams, "session has no conversation to hand off")
+	}
+
+	document := session.BuildHandoffDocument(transcript, session.HandoffSource{
+		AgentType:   sourceAgent,
+		SessionID:   p.SessionID,
+		ProjectPath: ws.Definition.Path,
+	}, p.Prompt, session.DefaultHandoffMaxChars)
+
+	pendingSnapshot := s.captureStartSnapshot(p.WorkspaceID, document)
+	result, rpcErr := targetDispatch.send(ctx, p.WorkspaceID, "", document, "new", p.PermissionMode, p.YoloMode)
+	targetSessionID := s.adoptStartSnapshot(p.WorkspaceID, pendingSnapshot, result)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if targetSessionID == "" {
+		return nil, message.NewError(message.InternalError, "target session did not report a session ID")
+	}
+
+	link, err := s.manager.RecordHandoff(session.HandoffLink{
+		WorkspaceID:     p.WorkspaceID,
+		SourceSessionID: p.SessionID,
+		SourceAgent:     sourceAgent,
+		TargetSessionID: targetSessionID,
+		TargetAgent:     targetAgent,
+	})
+	if err != nil {
+		return nil, message.NewError(message.InternalError, err.Error())
+	}
+
+	return map[string]interface{}{
+		"handoff_id":        link.ID,
+		"workspace_id":      p.WorkspaceID,
+		"source_session_id": link.SourceSessionID,
+		"source_agent":      sourceAgent,
+		"target_session_id": targetSessionID,
+		"target_agent":      targetAgent,
+		"context_chars":     len(document),
+		"files":             transcript.Files,
+	}, nil
+}
+
+// handoffTranscript reads and condenses the source session of a handoff.
+func (s *SessionManagerService) handoffTranscript(workspaceID, sessionID, agentType string) (session.HandoffTranscript, *message.Error) {
+	switch agentType {
+	case sessionManagerAgentCodex:
+		entry, err := s.resolveCodexSessionForWorkspace(workspaceID, sessionID)
+		if err != nil || entry.FullPath == "" {
+			return session.HandoffTranscript{}, message.ErrSessionNotFound(sessionID)
+		}
+		items, err := codex.ReadConversationItems(entry.FullPath)
+		if err != nil {
+			return session.HandoffTranscript{}, message.NewError(message.InternalError, "failed to read codex session: "+err.Error())
+		}
+		return session.HandoffTranscriptFromCodex(items), nil
+	default:
+		transcript, err := s.manager.ClaudeHandoffTranscript(workspaceID, sessionID)
+		if err != nil {
+			if strings.Contains(err.Error(), "session not found") {
+				return session.HandoffTranscript{}, message.ErrSessionNotFound(sessionID)
+			}
+			return session.HandoffTranscript{}, message.NewError(message.InternalError, err.Error())
+		}
+		return transcript, nil
+	}
+}
+
+// attachHandoffLinks annotates history entries with their handoff links.
+func (s *SessionManagerService) attachHandoffLinks(history []session.HistoryInfo) {
+	for i := range history {
+		history[i].Handoffs = s.manager.HandoffLinks(history[i].SessionID)
+	}
+}
+
+// handoffCounterpart returns the default target runtime for a handoff.
+func handoffCounterpart(agentType string) string {
+	if agentType == sessionManagerAgentCodex {
+		return sessionManagerAgentClaude
+	}
+	return sessionManagerAgentCodex
+}
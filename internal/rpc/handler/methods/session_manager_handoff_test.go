@@ -0,0 +1,60 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+)
+
+func TestSessionManagerHandoff_MethodMetadata(t *testing.T) {
+	service := NewSessionManagerService(nil)
+	registry := handler.NewRegistry()
+	service.RegisterMethods(registry)
+
+	meta := registry.GetMeta("session/handoff")
+	assertParamContract(t, meta, "workspace_id", true)
+	assertParamContract(t, meta, "session_id", true)
+	assertParamContract(t, meta, "target_agent_type", false)
+	assertParamContract(t, meta, "prompt", false)
+	agentType := assertParamContract(t, meta, "agent_type", false)
+	assertSchemaDefault(t, agentType, "claude")
+}
+
+func TestSessionManagerHandoff_ValidatesParams(t *testing.T) {
+	service := NewSessionManagerService(nil)
+
+	tests := []struct {
+		name   string
+		params string
+		code   int
+	}{
+		{"missing workspace", `{"session_id":"s1"}`, message.InvalidParams},
+		{"missing session", `{"workspace_id":"ws"}`, message.InvalidParams},
+		{"same runtime", `{"workspace_id":"ws","session_id":"s1","agent_type":"codex","target_agent_type":"codex"}`, message.InvalidParams},
+		{"unknown target", `{"workspace_id":"ws","session_id":"s1","target_agent_type":"gemini"}`, message.InvalidParams},
+		{"without manager", `{"workspace_id":"ws","session_id":"s1"}`, message.AgentNotConfigured},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Handoff(context.Background(), json.RawMessage(tt.params))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if err.Code != tt.code {
+				t.Fatalf("error code = %d, want %d (%s)", err.Code, tt.code, err.Message)
+			}
+		})
+	}
+}
+
+func TestHandoffCounterpart(t *testing.T) {
+	if got := handoffCounterpart("claude"); got != "codex" {
+		t.Fatalf("handoffCounterpart(claude) = %q", got)
+	}
+	if got := handoffCounterpart("codex"); got != "claude" {
+		t.Fatalf("handoffCounterpart(codex) = %q", got)
+	}
+}
@@ -2,7 +2,6 @@ package methods
 
 import (
 	"context"
-	"fmt"
 	"sort"
 	"strings"
 
@@ -10,10 +9,11 @@ import (
 	"github.com/brianly1003/cdev/internal/rpc/handler"
 	"github.com/brianly1003/cdev/internal/rpc/message"
 	"github.com/brianly1003/cdev/internal/session"
+	"github.com/rs/zerolog/log"
 )
 
 type sessionRuntimeDispatch struct {
-	start   func(ctx context.Context, workspaceID, sessionID, permissionMode string, yoloMode bool) (interface{}, *message.Error)
+	start   func(ctx context.Context, workspaceID, sessionID, permissionMode, profile string, yoloMode bool) (interface{}, *message.Error)
 	stop    func(ctx context.Context, sessionID string) (interface{}, *message.Error)
 	send    func(ctx context.Context, workspaceID, sessionID, prompt, mode, permissionMode string, yoloMode bool) (interface{}, *message.Error)
 	input   func(ctx context.Context, sessionID, input, key string) (interface{}, *message.Error)
@@ -78,11 +78,20 @@ func (s *SessionManagerService) resolveRuntimeDispatchForWorkspaceSession(rawAge
 	return s.resolveRuntimeDispatch(rawAgentType)
 }
 
-func (s *SessionManagerService) startClaudeSession(ctx context.Context, workspaceID, sessionID, permissionMode string, yoloMode bool) (interface{}, *message.Error) {
+func (s *SessionManagerService) startClaudeSession(ctx context.Context, workspaceID, sessionID, permissionMode, profile string, yoloMode bool) (interface{}, *message.Error) {
 	if s.manager == nil {
 		return nil, message.NewError(message.AgentNotConfigured, "claude session manager is not configured")
 	}
 
+	// A launch profile only applies when cdev spawns Claude, so it always
+	// starts a new managed session instead of attaching to an existing one.
+	if profile != "" {
+		if sessionID != "" {
+			return nil, message.NewError(message.InvalidParams, "profile cannot be combined with session_id")
+		}
+		return s.startManagedClaudeSession(ctx, workspaceID, permissionMode, profile, yoloMode)
+	}
+
 	// If session_id is provided, validate against .claude/projects
 	if sessionID != "" {
 		exists, err := s.manager.SessionFileExists(workspaceID, sessionID)
@@ -188,30 +197,42 @@ func (s *SessionManagerService) startClaudeSession(ctx context.Context, workspac
 	}
 
 	// No sessions found in .claude/projects - start a new managed session.
-	// This starts Claude in interactive mode (PTY) waiting for user input.
-	newSession, err := s.manager.StartSession(workspaceID)
+	return s.startManagedClaudeSession(ctx, workspaceID, permissionMode, "", yoloMode)
+}
+
+// startManagedClaudeSession starts a new cdev-managed Claude session in
+// interactive mode (PTY) waiting for user input, optionally with a launch profile.
+func (s *SessionManagerService) startManagedClaudeSession(ctx context.Context, workspaceID, permissionMode, profile string, yoloMode bool) (interface{}, *message.Error) {
+	newSession, err := s.manager.StartSessionWithProfile(workspaceID, profile)
 	if err != nil {
+		if profile != "" {
+			return nil, message.NewError(message.InvalidParams, "failed to start session with profile: "+err.Error())
+		}
 		return nil, message.NewError(message.InternalError, "failed to start session: "+err.Error())
 	}
 
-	// Get workspace for path
-	ws, err := s.manager.GetWorkspace(workspaceID)
-	if err != nil {
-		return nil, message.NewError(message.InternalError, "failed to get workspace: "+err.Error())
-	}
+	// The agent and its watcher outlive this request.
+	runCtx := context.WithoutCancel(ctx)
 
 	// Always watch for new session file creation for PTY sessions.
 	// Claude creates a NEW session file with a NEW UUID every time it starts.
 	// The temporary ID generated by cdev is internal only - we need to detect
 	// the real session ID from Claude and emit session_id_resolved event
 	// so iOS can switch to watching the real session file.
-	go s.manager.WatchForNewSessionFile(ctx, workspaceID, newSession.ID, ws.Definition.Path)
+	// The project path is the profile's work_dir when one is configured.
+	go s.manager.WatchForNewSessionFile(runCtx, workspaceID, newSession.ID, newSession.ProjectPath)
 
 	// Start Claude in interactive PTY mode (no initial prompt).
 	claudeManager := newSession.ClaudeManager()
 	if claudeManager != nil {
 		enableBypass := enableRuntimeBypass(permissionMode, yoloMode)
 
+		// An explicit permission mode replaces the profile's for this
+		// session, so "default" turns a profile's bypass off.
+		if profile != "" && permissionMode != "" && permissionMode != "interactive" {
+			claudeManager.SetLaunchPermissionMode(permissionMode)
+		}
+
 		// Set up callback to detect when Claude exits without creating a session.
 		// This handles the case where user declines trust folder (clicks "No").
 		temporaryID := newSession.ID
@@ -231,22 +252,26 @@ func (s *SessionManagerService) startClaudeSession(ctx context.Context, workspac
 
 		go func() {
 			// Start Claude with empty prompt for interactive mode.
-			if err := claudeManager.StartWithPTY(ctx, "", "new", newSession.ID, enableBypass); err != nil {
+			if err := claudeManager.StartWithPTY(runCtx, "", "new", newSession.ID, enableBypass); err != nil {
 				// Log error but don't fail - session is still created.
 				// The user can send a prompt via session/send.
-				fmt.Printf("Warning: failed to start Claude in interactive mode: %v\n", err)
+				log.Warn().Err(err).Str("session_id", newSession.ID).Msg("failed to start Claude in interactive mode")
 			}
 		}()
 	}
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"session_id":   newSession.ID,
 		"workspace_id": workspaceID,
 		"source":       "managed",
 		"status":       "started",
 		"agent_type":   sessionManagerAgentClaude,
 		"message":      "New Claude session started in interactive mode",
-	}, nil
+	}
+	if profile != "" {
+		result["profile"] = profile
+	}
+	return result, nil
 }
 
 func (s *SessionManagerService) stopClaudeSession(ctx context.Context, sessionID string) (interface{}, *message.Error) {
@@ -290,9 +315,12 @@ func (s *SessionManagerService) sendClaudePrompt(ctx context.Context, workspaceI
 			return nil, message.NewError(message.InternalError, "failed to get workspace: "+err.Error())
 		}
 
+		// The agent and its watcher outlive this request.
+		runCtx := context.WithoutCancel(ctx)
+
 		// Always watch for new session file creation for PTY sessions.
 		// Claude creates a NEW session file with a NEW UUID every time.
-		go s.manager.WatchForNewSessionFile(ctx, workspaceID, newSession.ID, ws.Definition.Path)
+		go s.manager.WatchForNewSessionFile(runCtx, workspaceID, newSession.ID, ws.Definition.Path)
 
 		// Start Claude with the prompt immediately (don't wait for user input).
 		claudeManager := newSession.ClaudeManager()
@@ -313,9 +341,9 @@ func (s *SessionManagerService) sendClaudePrompt(ctx context.Context, workspaceI
 
 			// Start Claude with PTY and the prompt.
 			go func() {
-				if err := claudeManager.StartWithPTY(ctx, prompt, "new", newSession.ID, yoloMode); err != nil {
+				if err := claudeManager.StartWithPTY(runCtx, prompt, "new", newSession.ID, yoloMode); err != nil {
 					// Log error but session was created.
-					fmt.Printf("Warning: failed to start Claude with prompt: %v\n", err)
+					log.Warn().Err(err).Str("session_id", newSession.ID).Msg("failed to start Claude with prompt")
 				}
 			}()
 		}
@@ -437,6 +465,7 @@ func (s *SessionManagerService) inputCodexSession(ctx context.Context, sessionID
 		if err := s.sendCodexInput(sessionID, key); err != nil {
 			return nil, message.NewError(message.InternalError, err.Error())
 		}
+		s.clearCodexApproval(sessionID)
 		s.emitCodexPermissionResolved(ctx, sessionID, key)
 		return map[string]interface{}{
 			"status":     "sent",
@@ -449,6 +478,7 @@ func (s *SessionManagerService) inputCodexSession(ctx context.Context, sessionID
 		if err := s.sendCodexInput(sessionID, input); err != nil {
 			return nil, message.NewError(message.InternalError, err.Error())
 		}
+		s.clearCodexApproval(sessionID)
 		s.emitCodexPermissionResolved(ctx, sessionID, input)
 		return map[string]interface{}{
 			"status":     "sent",
@@ -463,6 +493,7 @@ func (s *SessionManagerService) respondCodexSessionRPC(ctx context.Context, sess
 	if err := s.respondCodexSession(sessionID, responseType, response); err != nil {
 		return nil, message.NewError(message.InternalError, err.Error())
 	}
+	s.clearCodexApproval(sessionID)
 	s.emitCodexPermissionResolved(ctx, sessionID, response)
 	return map[string]interface{}{
 		"status":     "responded",
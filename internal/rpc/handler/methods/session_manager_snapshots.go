@@ -0,0 +1,222 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/brianly1003/cdev/internal/domain"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/rs/zerolog/log"
+)
+
+// registerSnapshotMethods registers per-turn working-tree snapshot methods.
+func (s *SessionManagerService) registerSnapshotMethods(registry *handler.Registry) {
+	registry.RegisterWithMeta("session/snapshots", s.ListSnapshots, handler.MethodMeta{
+		Summary:     "List working-tree snapshots for a session",
+		Description: "Returns the snapshots captured at the start of each prompt turn, newest first. Snapshots are stored as hidden git refs (refs/cdev/snapshots/*) and never touch branches or the index.",
+		Params: []handler.OpenRPCParam{
+			{Name: "session_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "workspace_id", Required: false, Schema: map[string]interface{}{"type": "string", "description": "Workspace ID. Required for sessions not currently managed by cdev."}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "snapshots",
+			Schema: map[string]interface{}{"type": "object"},
+		},
+		Concurrency: handler.ConcurrencyParallel,
+	})
+
+	registry.RegisterWithMeta("session/snapshots/diff", s.DiffSnapshots, handler.MethodMeta{
+		Summary:     "Diff two session snapshots",
+		Description: "Compares two snapshots of a session. If 'to' is omitted (or 'working_tree'), compares against the current working tree.",
+		Params: []handler.OpenRPCParam{
+			{Name: "session_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "workspace_id", Required: false, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "from", Required: true, Schema: map[string]interface{}{"type": "string", "description": "Snapshot ID to diff from."}},
+			{Name: "to", Required: false, Schema: map[string]interface{}{"type": "string", "default": "working_tree", "description": "Snapshot ID to diff to, or 'working_tree'."}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "diff",
+			Schema: map[string]interface{}{"type": "object"},
+		},
+		Concurrency: handler.ConcurrencyParallel,
+	})
+
+	registry.RegisterWithMeta("session/snapshots/restore", s.RestoreSnapshot, handler.MethodMeta{
+		Summary:     "Restore a session snapshot",
+		Description: "Rolls the working tree back to a snapshot. The current state is captured as a backup snapshot first, and the index, HEAD and branches are left untouched.",
+		Params: []handler.OpenRPCParam{
+			{Name: "session_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "workspace_id", Required: false, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "snapshot_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "result",
+			Schema: map[string]interface{}{"type": "object"},
+		},
+		Concurrency: handler.ConcurrencyWorkspace,
+	})
+}
+
+// ListSnapshots returns the working-tree snapshots recorded for a session.
+func (s *SessionManagerService) ListSnapshots(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	var p struct {
+		SessionID   string `json:"session_id"`
+		WorkspaceID string `json:"workspace_id"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.NewError(message.InvalidParams, "failed to parse params: "+err.Error())
+	}
+	if p.SessionID == "" {
+		return nil, message.NewError(message.InvalidParams, "session_id is required")
+	}
+	if err := s.ensureSessionManagerConfigured("session/snapshots"); err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.manager.ListSnapshots(p.WorkspaceID, p.SessionID)
+	if err != nil {
+		return nil, snapshotError(p.SessionID, err)
+	}
+
+	return map[string]interface{}{
+		"session_id": s.manager.ResolveSessionID(p.SessionID),
+		"snapshots":  snapshots,
+		"count":      len(snapshots),
+	}, nil
+}
+
+// DiffSnapshots compares two snapshots of a session.
+func (s *SessionManagerService) DiffSnapshots(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	var p struct {
+		SessionID   string `json:"session_id"`
+		WorkspaceID string `json:"workspace_id"`
+		From        string `json:"from"`
+		To          string `json:"to"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.NewError(message.InvalidParams, "failed to parse params: "+err.Error())
+	}
+	if p.SessionID == "" {
+		return nil, message.NewError(message.InvalidParams, "session_id is required")
+	}
+	if p.From == "" {
+		return nil, message.NewError(message.InvalidParams, "from is required")
+	}
+	if err := s.ensureSessionManagerConfigured("session/snapshots/diff"); err != nil {
+		return nil, err
+	}
+
+	result, err := s.manager.DiffSnapshots(p.WorkspaceID, p.SessionID, p.From, p.To)
+	if err != nil {
+		return nil, snapshotError(p.SessionID, err)
+	}
+	return result, nil
+}
+
+// RestoreSnapshot rolls a session's working tree back to a snapshot.
+func (s *SessionManagerService) RestoreSnapshot(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	var p struct {
+		SessionID   string `json:"session_id"`
+		WorkspaceID string `json:"workspace_id"`
+		SnapshotID  string `json:"snapshot_id"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.NewError(message.InvalidParams, "failed to parse params: "+err.Error())
+	}
+	if p.SessionID == "" {
+		return nil, message.NewError(message.InvalidParams, "session_id is required")
+	}
+	if p.SnapshotID == "" {
+		return nil, message.NewError(message.InvalidParams, "snapshot_id is required")
+	}
+	if err := s.ensureSessionManagerConfigured("session/snapshots/restore"); err != nil {
+		return nil, err
+	}
+
+	result, err := s.manager.RestoreSnapshot(p.WorkspaceID, p.SessionID, p.SnapshotID)
+	if err != nil {
+		return nil, snapshotError(p.SessionID, err)
+	}
+	return result, nil
+}
+
+// captureTurnSnapshot snapshots the working tree before a prompt turn.
+// Failures are logged and never block the prompt.
+func (s *SessionManagerService) captureTurnSnapshot(workspaceID, sessionID, prompt string) {
+	if s.manager == nil || sessionID == "" {
+		return
+	}
+	if _, err := s.manager.CaptureTurnSnapshot(workspaceID, sessionID, snapshotLabel(prompt)); err != nil {
+		log.Debug().
+			Err(err).
+			Str("session_id", sessionID).
+			Str("workspace_id", workspaceID).
+			Msg("failed to capture turn snapshot")
+	}
+}
+
+// captureStartSnapshot snapshots the working tree before a runtime send
+// auto-creates a session, so the snapshot predates anything the agent does.
+// The session has no ID yet; the returned pending ID is handed to
+// adoptStartSnapshot. Failures are logged and never block the prompt.
+func (s *SessionManagerService) captureStartSnapshot(workspaceID, prompt string) string {
+	if s.manager == nil || workspaceID == "" {
+		return ""
+	}
+	pendingID, err := s.manager.CaptureStartSnapshot(workspaceID, snapshotLabel(prompt))
+	if err != nil {
+		log.Debug().
+			Err(err).
+			Str("workspace_id", workspaceID).
+			Msg("failed to capture start snapshot")
+	}
+	return pendingID
+}
+
+// adoptStartSnapshot files a start snapshot under the session a runtime send
+// auto-created, and returns the new session ID from the send result. The
+// snapshot is discarded when the send reported no session.
+func (s *SessionManagerService) adoptStartSnapshot(workspaceID, pendingID string, result interface{}) string {
+	res, _ := result.(map[string]interface{})
+	sessionID, _ := res["session_id"].(string)
+	if s.manager == nil || pendingID == "" {
+		return sessionID
+	}
+	if err := s.manager.AdoptStartSnapshot(workspaceID, pendingID, sessionID); err != nil {
+		log.Debug().
+			Err(err).
+			Str("session_id", sessionID).
+			Str("workspace_id", workspaceID).
+			Msg("failed to adopt start snapshot")
+	}
+	return sessionID
+}
+
+// snapshotLabel derives a short one-line label from the prompt that started a turn.
+func snapshotLabel(prompt string) string {
+	line := strings.TrimSpace(prompt)
+	if idx := strings.IndexAny(line, "\r\n"); idx >= 0 {
+		line = strings.TrimSpace(line[:idx])
+	}
+	if runes := []rune(line); len(runes) > 72 {
+		line = string(runes[:69]) + "..."
+	}
+	return "before: " + line
+}
+
+func snapshotError(sessionID string, err error) *message.Error {
+	if errors.Is(err, domain.ErrNotGitRepo) {
+		return message.ErrNotAGitRepo()
+	}
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "session not found"):
+		return message.ErrSessionNotFound(sessionID)
+	case strings.Contains(msg, "snapshot not found"), strings.Contains(msg, "invalid "), strings.Contains(msg, "cannot restore"):
+		return message.NewError(message.InvalidParams, msg)
+	}
+	return message.NewError(message.InternalError, msg)
+}
@@ -0,0 +1,67 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+)
+
+func TestSessionManagerSnapshots_MethodMetadata(t *testing.T) {
+	service := NewSessionManagerService(nil)
+	registry := handler.NewRegistry()
+	service.RegisterMethods(registry)
+
+	listMeta := registry.GetMeta("session/snapshots")
+	assertParamContract(t, listMeta, "session_id", true)
+	assertParamContract(t, listMeta, "workspace_id", false)
+
+	diffMeta := registry.GetMeta("session/snapshots/diff")
+	assertParamContract(t, diffMeta, "from", true)
+	to := assertParamContract(t, diffMeta, "to", false)
+	assertSchemaDefault(t, to, "working_tree")
+
+	restoreMeta := registry.GetMeta("session/snapshots/restore")
+	assertParamContract(t, restoreMeta, "session_id", true)
+	assertParamContract(t, restoreMeta, "snapshot_id", true)
+}
+
+func TestSessionManagerSnapshots_ValidatesParams(t *testing.T) {
+	service := NewSessionManagerService(nil)
+
+	tests := []struct {
+		name   string
+		call   func(context.Context, json.RawMessage) (interface{}, *message.Error)
+		params string
+		code   int
+	}{
+		{"list missing session", service.ListSnapshots, `{}`, message.InvalidParams},
+		{"diff missing from", service.DiffSnapshots, `{"session_id":"s1"}`, message.InvalidParams},
+		{"restore missing snapshot", service.RestoreSnapshot, `{"session_id":"s1"}`, message.InvalidParams},
+		{"list without manager", service.ListSnapshots, `{"session_id":"s1"}`, message.AgentNotConfigured},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.call(context.Background(), json.RawMessage(tt.params))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if err.Code != tt.code {
+				t.Fatalf("error code = %d, want %d (%s)", err.Code, tt.code, err.Message)
+			}
+		})
+	}
+}
+
+func TestSnapshotLabel(t *testing.T) {
+	if got := snapshotLabel("  fix the tests\nand more detail"); got != "before: fix the tests" {
+		t.Fatalf("snapshotLabel() = %q", got)
+	}
+	long := snapshotLabel(strings.Repeat("a", 200))
+	if n := len([]rune(strings.TrimPrefix(long, "before: "))); n != 72 {
+		t.Fatalf("label length = %d, want 72", n)
+	}
+}
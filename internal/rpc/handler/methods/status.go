@@ -55,6 +55,7 @@ func (s *StatusService) RegisterMethods(r *handler.Registry) {
 		Description: "Returns the current status of the cdev server including agent state, connected clients, and configuration.",
 		Params:      []handler.OpenRPCParam{},
 		Result:      &handler.OpenRPCResult{Name: "StatusResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/StatusResult"}},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	r.RegisterWithMeta("status/health", s.Health, handler.MethodMeta{
@@ -62,6 +63,7 @@ func (s *StatusService) RegisterMethods(r *handler.Registry) {
 		Description: "Returns a simple health check response for monitoring.",
 		Params:      []handler.OpenRPCParam{},
 		Result:      &handler.OpenRPCResult{Name: "HealthResult", Schema: map[string]interface{}{"$ref": "#/components/schemas/HealthResult"}},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 }
 
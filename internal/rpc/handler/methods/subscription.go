@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/brianly1003/cdev/internal/domain/ports"
 	"github.com/brianly1003/cdev/internal/hub"
 	"github.com/brianly1003/cdev/internal/rpc/handler"
 	"github.com/brianly1003/cdev/internal/rpc/message"
@@ -21,10 +22,17 @@ type GitWatcherManager interface {
 	StopGitWatcher(workspaceID string)
 }
 
+// EventReplayer replays journaled events to a reconnecting subscriber.
+type EventReplayer interface {
+	Resume(sub ports.Subscriber, workspaceID string, sinceSeq uint64, attach func()) (hub.ResumeResult, error)
+	CurrentSeq(workspaceID string) uint64
+}
+
 // SubscriptionService handles workspace subscription RPC methods.
 type SubscriptionService struct {
 	provider          FilteredSubscriberProvider
 	gitWatcherManager GitWatcherManager
+	replayer          EventReplayer
 }
 
 // NewSubscriptionService creates a new subscription service.
@@ -44,13 +52,23 @@ func (s *SubscriptionService) SetGitWatcherManager(manager GitWatcherManager) {
 	s.gitWatcherManager = manager
 }
 
+// SetEventReplayer sets the event hub used to replay missed events.
+func (s *SubscriptionService) SetEventReplayer(replayer EventReplayer) {
+	s.replayer = replayer
+}
+
 // RegisterMethods registers all subscription methods with the handler.
 func (s *SubscriptionService) RegisterMethods(registry *handler.Registry) {
 	registry.RegisterWithMeta("workspace/subscribe", s.Subscribe, handler.MethodMeta{
 		Summary:     "Subscribe to workspace events",
-		Description: "Subscribe to receive events for a specific workspace. By default, clients receive all events. After calling this method, only events for subscribed workspaces (and global events) will be forwarded.",
+		Description: "Subscribe to receive events for a specific workspace. By default, clients receive all events. After calling this method, only events for subscribed workspaces (and global events) will be forwarded. Workspace events carry a per-workspace seq; pass the last seq seen as since_seq after a reconnect to replay the missed events before live delivery resumes. If the journal no longer holds them, a stream_gap event is sent instead and the client should refetch state. event_types, exclude_event_types, session_ids and projections narrow the events of the client and trim their payloads; each list given replaces the previous one and applies to every workspace of the client.",
 		Params: []handler.OpenRPCParam{
 			{Name: "workspace_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "since_seq", Required: false, Schema: map[string]interface{}{"type": "integer", "minimum": 0, "description": "Replay journaled events of the workspace after this sequence"}},
+			{Name: "event_types", Required: false, Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Only receive these event types, e.g. [\"claude_message\", \"pty_permission\"]"}},
+			{Name: "exclude_event_types", Required: false, Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Never receive these event types, e.g. [\"claude_log\", \"file_changed\"]"}},
+			{Name: "session_ids", Required: false, Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Only receive session events of these sessions; events without a session still pass"}},
+			{Name: "projections", Required: false, Schema: map[string]interface{}{"type": "object", "description": "Payload fields per event type: {\"git_diff\": {\"omit\": [\"diff\"]}} or {\"file_changed\": {\"fields\": [\"path\", \"change\"]}}"}},
 		},
 		Result: &handler.OpenRPCResult{
 			Name:   "result",
@@ -72,17 +90,18 @@ func (s *SubscriptionService) RegisterMethods(registry *handler.Registry) {
 
 	registry.RegisterWithMeta("workspace/subscriptions", s.Subscriptions, handler.MethodMeta{
 		Summary:     "List workspace subscriptions",
-		Description: "Returns the list of workspaces this client is subscribed to. Empty list means all events are received.",
+		Description: "Returns the list of workspaces this client is subscribed to and its event filter. Empty list means all events are received.",
 		Params:      []handler.OpenRPCParam{},
 		Result: &handler.OpenRPCResult{
 			Name:   "subscriptions",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("workspace/subscribeAll", s.SubscribeAll, handler.MethodMeta{
 		Summary:     "Subscribe to all workspace events",
-		Description: "Clear workspace filters and receive events from all workspaces (default behavior).",
+		Description: "Clear workspace, event type and session filters and projections, and receive events from all workspaces (default behavior).",
 		Params:      []handler.OpenRPCParam{},
 		Result: &handler.OpenRPCResult{
 			Name:   "result",
@@ -98,7 +117,9 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, params json.RawMess
 	}
 
 	var p struct {
-		WorkspaceID string `json:"workspace_id"`
+		WorkspaceID string  `json:"workspace_id"`
+		SinceSeq    *uint64 `json:"since_seq"`
+		hub.EventFilter
 	}
 	if err := json.Unmarshal(params, &p); err != nil {
 		return nil, message.NewError(message.InvalidParams, "failed to parse params: "+err.Error())
@@ -107,6 +128,9 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, params json.RawMess
 	if p.WorkspaceID == "" {
 		return nil, message.NewError(message.InvalidParams, "workspace_id is required")
 	}
+	if p.SinceSeq != nil && s.replayer == nil {
+		return nil, message.NewError(message.InternalError, "event replay not configured")
+	}
 
 	// Get client ID from context
 	clientID, ok := ctx.Value(handler.ClientIDKey).(string)
@@ -119,7 +143,45 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, params json.RawMess
 		return nil, message.NewError(message.InternalError, "client not found")
 	}
 
-	filtered.SubscribeWorkspace(p.WorkspaceID)
+	// Set the event filter first so a replay is filtered the same way
+	if p.Types != nil || p.ExcludeTypes != nil || p.SessionIDs != nil || p.Projections != nil {
+		filter := filtered.EventFilter()
+		if p.Types != nil {
+			filter.Types = p.Types
+		}
+		if p.ExcludeTypes != nil {
+			filter.ExcludeTypes = p.ExcludeTypes
+		}
+		if p.SessionIDs != nil {
+			filter.SessionIDs = p.SessionIDs
+		}
+		if p.Projections != nil {
+			filter.Projections = p.Projections
+		}
+		filtered.SetEventFilter(filter)
+	}
+
+	result := map[string]interface{}{
+		"success":      true,
+		"workspace_id": p.WorkspaceID,
+	}
+	if p.SinceSeq != nil {
+		// Subscribe and replay in one step of the event loop
+		resumed, err := s.replayer.Resume(filtered, p.WorkspaceID, *p.SinceSeq, func() {
+			filtered.SubscribeWorkspace(p.WorkspaceID)
+		})
+		if err != nil {
+			return nil, message.NewError(message.InternalError, "failed to replay events: "+err.Error())
+		}
+		result["replayed"] = resumed.Replayed
+		result["current_seq"] = resumed.CurrentSeq
+		result["gap"] = resumed.Gap
+	} else {
+		filtered.SubscribeWorkspace(p.WorkspaceID)
+		if s.replayer != nil {
+			result["current_seq"] = s.replayer.CurrentSeq(p.WorkspaceID)
+		}
+	}
 
 	// Start git watcher for this workspace to emit git_status_changed events
 	if s.gitWatcherManager != nil {
@@ -127,11 +189,9 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, params json.RawMess
 		_ = s.gitWatcherManager.StartGitWatcher(p.WorkspaceID)
 	}
 
-	return map[string]interface{}{
-		"success":      true,
-		"workspace_id": p.WorkspaceID,
-		"subscribed":   filtered.GetSubscribedWorkspaces(),
-	}, nil
+	result["subscribed"] = filtered.GetSubscribedWorkspaces()
+	result["filter"] = filtered.EventFilter()
+	return result, nil
 }
 
 // Unsubscribe removes a workspace from the client's subscription filter.
@@ -202,6 +262,7 @@ func (s *SubscriptionService) Subscriptions(ctx context.Context, params json.Raw
 		"workspaces":   subscribed,
 		"is_filtering": filtered.IsFiltering(),
 		"count":        len(subscribed),
+		"filter":       filtered.EventFilter(),
 	}, nil
 }
 
@@ -223,6 +284,7 @@ func (s *SubscriptionService) SubscribeAll(ctx context.Context, params json.RawM
 	}
 
 	filtered.SubscribeAll()
+	filtered.SetEventFilter(hub.EventFilter{})
 
 	// Reset session focus too — "subscribe all" is a full filter reset.
 	// The client can re-establish focus with client/session/focus afterward.
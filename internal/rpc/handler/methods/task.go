@@ -0,0 +1,213 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/domain/task"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/rs/zerolog/log"
+)
+
+// TaskStore persists agent tasks.
+type TaskStore interface {
+	Create(t *task.AgentTask) error
+	GetByID(id string) (*task.AgentTask, error)
+}
+
+// TaskSpawner starts the agent of a task.
+type TaskSpawner interface {
+	SpawnTask(ctx context.Context, taskID string) error
+}
+
+// TaskService handles agent task RPC methods.
+type TaskService struct {
+	store     TaskStore
+	spawner   TaskSpawner
+	resolver  WorkspaceResolver
+	publisher interface{ Publish(events.Event) }
+}
+
+// NewTaskService creates a new task service.
+func NewTaskService(store TaskStore, publisher interface{ Publish(events.Event) }) *TaskService {
+	return &TaskService{store: store, publisher: publisher}
+}
+
+// SetSpawner sets the spawner that starts tasks whose policy allows it.
+func (s *TaskService) SetSpawner(spawner TaskSpawner) {
+	s.spawner = spawner
+}
+
+// SetWorkspaceResolver sets the resolver that maps workspace names and
+// paths to IDs.
+func (s *TaskService) SetWorkspaceResolver(resolver WorkspaceResolver) {
+	s.resolver = resolver
+}
+
+// RegisterMethods registers the task methods with the registry.
+func (s *TaskService) RegisterMethods(r *handler.Registry) {
+	r.RegisterWithMeta("task/create", s.Create, handler.MethodMeta{
+		Summary:     "Create an agent task",
+		Description: "Creates an agent task in a workspace, like the task webhook. Unless autonomy is manual, the task is spawned right away in its own worktree.",
+		Params: []handler.OpenRPCParam{
+			{Name: "workspace_id", Description: "Workspace ID, name or path", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "title", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "description", Required: false, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "prompt", Description: "Instructions for the agent; defaults to one built from title and description", Required: false, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "task_type", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"fix-issue", "implement-cr", "add-test", "refactor", "auto-fix"}, "default": "implement-cr"}},
+			{Name: "severity", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"low", "medium", "high", "critical"}, "default": "medium"}},
+			{Name: "labels", Required: false, Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}},
+			{Name: "autonomy", Description: "manual tasks wait for an explicit spawn", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"manual", "supervised", "semi-auto", "full-auto-bounded"}, "default": "supervised"}},
+			{Name: "agent_type", Required: false, Schema: map[string]interface{}{"type": "string", "enum": []string{"claude", "codex"}, "default": "claude"}},
+			{Name: "created_by", Description: "Who asked for the task, recorded on the task", Required: false, Schema: map[string]interface{}{"type": "string", "default": "rpc"}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "TaskCreateResult",
+			Schema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"id": map[string]interface{}{"type": "string"}, "status": map[string]interface{}{"type": "string"}, "auto_spawned": map[string]interface{}{"type": "boolean"}}},
+		},
+	})
+
+	r.RegisterWithMeta("task/get", s.Get, handler.MethodMeta{
+		Summary:     "Get an agent task",
+		Description: "Returns an agent task with its status, timeline and result.",
+		Params: []handler.OpenRPCParam{
+			{Name: "task_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+		},
+		Result: &handler.OpenRPCResult{
+			Name:   "AgentTask",
+			Schema: map[string]interface{}{"type": "object"},
+		},
+		Concurrency: handler.ConcurrencyParallel,
+	})
+}
+
+// TaskCreateParams for task/create method.
+type TaskCreateParams struct {
+	WorkspaceID string   `json:"workspace_id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Prompt      string   `json:"prompt"`
+	TaskType    string   `json:"task_type"`
+	Severity    string   `json:"severity"`
+	Labels      []string `json:"labels"`
+	Autonomy    string   `json:"autonomy"`
+	AgentType   string   `json:"agent_type"`
+	CreatedBy   string   `json:"created_by"`
+}
+
+// Create creates an agent task and spawns it unless its autonomy is manual.
+func (s *TaskService) Create(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	if s.store == nil {
+		return nil, message.ErrInternalError("agent task system not enabled")
+	}
+
+	var p TaskCreateParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.ErrInvalidParams("failed to parse params: " + err.Error())
+	}
+	if p.WorkspaceID == "" {
+		return nil, message.ErrInvalidParams("workspace_id is required")
+	}
+	if p.Title == "" {
+		return nil, message.ErrInvalidParams("title is required")
+	}
+	if p.TaskType == "" {
+		p.TaskType = string(task.TaskTypeImplementCR)
+	}
+	if p.TaskType == string(task.TaskTypePlanCase) {
+		return nil, message.ErrInvalidParams("plan-case tasks can only be created by the webhook")
+	}
+	if p.CreatedBy == "" {
+		p.CreatedBy = "rpc"
+	}
+
+	workspaceID := p.WorkspaceID
+	if s.resolver != nil {
+		resolved, err := s.resolver.ResolveWorkspaceID(workspaceID)
+		if err != nil {
+			return nil, message.ErrInvalidParams("workspace not found: " + workspaceID)
+		}
+		workspaceID = resolved
+	}
+
+	t := task.NewTask(workspaceID, task.TaskType(p.TaskType), p.Title, p.Description)
+	t.Prompt = p.Prompt
+	t.CreatedBy = p.CreatedBy
+	if p.Severity != "" {
+		t.Severity = task.Severity(p.Severity)
+	}
+	if p.Labels != nil {
+		t.Labels = p.Labels
+	}
+	t.Policy = task.DefaultPolicy()
+	if p.Autonomy != "" {
+		t.Policy.Autonomy = p.Autonomy
+	}
+	if p.AgentType != "" {
+		t.Policy.AgentType = p.AgentType
+	}
+	t.Trigger = &task.Trigger{
+		Type:      "manual",
+		Source:    p.CreatedBy,
+		Timestamp: time.Now().UTC(),
+	}
+	t.AddTimelineEvent("created", "Task created by "+p.CreatedBy, p.CreatedBy)
+
+	if err := s.store.Create(t); err != nil {
+		log.Error().Err(err).Str("title", t.Title).Msg("failed to create task")
+		return nil, message.ErrInternalError("failed to create task: " + err.Error())
+	}
+	log.Info().Str("task_id", t.ID).Str("title", t.Title).Str("created_by", t.CreatedBy).Msg("task created")
+
+	if s.publisher != nil {
+		s.publisher.Publish(events.NewTaskEvent(events.EventTypeTaskCreated, workspaceID, events.TaskEventPayload{
+			TaskID:   t.ID,
+			TaskType: string(t.TaskType),
+			Title:    t.Title,
+			Status:   string(t.Status),
+			Severity: string(t.Severity),
+		}))
+	}
+
+	autoSpawned := false
+	if s.spawner != nil && t.Policy.Autonomy != "manual" {
+		if err := s.spawner.SpawnTask(context.Background(), t.ID); err != nil {
+			log.Warn().Err(err).Str("task_id", t.ID).Msg("task spawn failed (task created but not started)")
+		} else {
+			autoSpawned = true
+		}
+	}
+
+	return map[string]interface{}{
+		"id":           t.ID,
+		"workspace_id": workspaceID,
+		"status":       string(t.Status),
+		"auto_spawned": autoSpawned,
+	}, nil
+}
+
+// Get returns an agent task.
+func (s *TaskService) Get(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	if s.store == nil {
+		return nil, message.ErrInternalError("agent task system not enabled")
+	}
+
+	var p struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.ErrInvalidParams("failed to parse params: " + err.Error())
+	}
+	if p.TaskID == "" {
+		return nil, message.ErrInvalidParams("task_id is required")
+	}
+
+	t, err := s.store.GetByID(p.TaskID)
+	if err != nil {
+		return nil, message.ErrInvalidParams("task not found: " + p.TaskID)
+	}
+	return t, nil
+}
@@ -0,0 +1,111 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/domain/task"
+)
+
+type mockTaskStore struct {
+	tasks map[string]*task.AgentTask
+}
+
+func (m *mockTaskStore) Create(t *task.AgentTask) error {
+	m.tasks[t.ID] = t
+	return nil
+}
+
+func (m *mockTaskStore) GetByID(id string) (*task.AgentTask, error) {
+	t, ok := m.tasks[id]
+	if !ok {
+		return nil, fmt.Errorf("task not found: %s", id)
+	}
+	return t, nil
+}
+
+type mockTaskSpawner struct {
+	spawned []string
+}
+
+func (m *mockTaskSpawner) SpawnTask(ctx context.Context, taskID string) error {
+	m.spawned = append(m.spawned, taskID)
+	return nil
+}
+
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestTaskService_Create(t *testing.T) {
+	store := &mockTaskStore{tasks: map[string]*task.AgentTask{}}
+	spawner := &mockTaskSpawner{}
+	publisher := &recordingPublisher{}
+	service := NewTaskService(store, publisher)
+	service.SetSpawner(spawner)
+	service.SetWorkspaceResolver(&mockWorkspaceResolver{workspaceID: "ws-1"})
+
+	create := func(params map[string]interface{}) (map[string]interface{}, error) {
+		data, _ := json.Marshal(params)
+		result, rpcErr := service.Create(context.Background(), data)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		return result.(map[string]interface{}), nil
+	}
+
+	if _, err := create(map[string]interface{}{"workspace_id": "backend"}); err == nil {
+		t.Fatal("expected an error without a title")
+	}
+
+	result, err := create(map[string]interface{}{
+		"workspace_id": "backend",
+		"title":        "Add tests for the parser",
+		"task_type":    "add-test",
+		"created_by":   "mcp",
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	created := store.tasks[result["id"].(string)]
+	if created == nil || created.WorkspaceID != "ws-1" || created.TaskType != task.TaskTypeAddTest || created.CreatedBy != "mcp" {
+		t.Fatalf("unexpected task %+v", created)
+	}
+	if result["auto_spawned"] != true || len(spawner.spawned) != 1 {
+		t.Fatalf("expected the task to be spawned, got %v", result)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type() != events.EventTypeTaskCreated {
+		t.Fatalf("expected a task_created event, got %v", publisher.events)
+	}
+
+	// Manual tasks wait for an explicit spawn.
+	result, err = create(map[string]interface{}{"workspace_id": "backend", "title": "Later", "autonomy": "manual"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if result["auto_spawned"] != false || len(spawner.spawned) != 1 {
+		t.Fatalf("expected a manual task not to be spawned, got %v", result)
+	}
+}
+
+func TestTaskService_Get(t *testing.T) {
+	store := &mockTaskStore{tasks: map[string]*task.AgentTask{}}
+	existing := task.NewTask("ws-1", task.TaskTypeRefactor, "Split the handler", "")
+	store.tasks[existing.ID] = existing
+	service := NewTaskService(store, nil)
+
+	result, rpcErr := service.Get(context.Background(), json.RawMessage(`{"task_id":"`+existing.ID+`"}`))
+	if rpcErr != nil || result.(*task.AgentTask).ID != existing.ID {
+		t.Fatalf("Get: %v, %v", result, rpcErr)
+	}
+	if _, rpcErr := service.Get(context.Background(), json.RawMessage(`{"task_id":"missing"}`)); rpcErr == nil {
+		t.Fatal("expected an error for a missing task")
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/brianly1003/cdev/internal/adapters/codex"
 	"github.com/brianly1003/cdev/internal/adapters/git"
+	"github.com/brianly1003/cdev/internal/config"
 	"github.com/brianly1003/cdev/internal/domain/events"
 	"github.com/brianly1003/cdev/internal/domain/ports"
 	"github.com/brianly1003/cdev/internal/rpc/handler"
@@ -64,6 +65,7 @@ func (s *WorkspaceConfigService) RegisterMethods(registry *handler.Registry) {
 			Name:   "workspaces",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("workspace/get", s.Get, handler.MethodMeta{
@@ -76,6 +78,7 @@ func (s *WorkspaceConfigService) RegisterMethods(registry *handler.Registry) {
 			Name:   "workspace",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("workspace/add", s.Add, handler.MethodMeta{
@@ -112,6 +115,7 @@ func (s *WorkspaceConfigService) RegisterMethods(registry *handler.Registry) {
 			{Name: "id", Required: true, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "name", Required: false, Schema: map[string]interface{}{"type": "string"}},
 			{Name: "auto_start", Required: false, Schema: map[string]interface{}{"type": "boolean"}},
+			{Name: "tags", Required: false, Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Replaces the workspace tags (used by session/batch_start)."}},
 		},
 		Result: &handler.OpenRPCResult{
 			Name:   "workspace",
@@ -124,11 +128,13 @@ func (s *WorkspaceConfigService) RegisterMethods(registry *handler.Registry) {
 		Description: "Scans directories to find git repositories that can be added as workspaces.",
 		Params: []handler.OpenRPCParam{
 			{Name: "paths", Required: false, Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}},
+			{Name: "fresh", Required: false, Schema: map[string]interface{}{"type": "boolean"}},
 		},
 		Result: &handler.OpenRPCResult{
 			Name:   "repositories",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Progress: true,
 	})
 
 	registry.RegisterWithMeta("workspace/status", s.Status, handler.MethodMeta{
@@ -141,6 +147,7 @@ func (s *WorkspaceConfigService) RegisterMethods(registry *handler.Registry) {
 			Name:   "status",
 			Schema: map[string]interface{}{"type": "object"},
 		},
+		Concurrency: handler.ConcurrencyParallel,
 	})
 
 	registry.RegisterWithMeta("workspace/cache/invalidate", s.InvalidateCache, handler.MethodMeta{
@@ -185,6 +192,9 @@ func (s *WorkspaceConfigService) List(ctx context.Context, params json.RawMessag
 			"auto_start": ws.Definition.AutoStart,
 			"created_at": ws.Definition.CreatedAt,
 		}
+		if len(ws.Definition.Tags) > 0 {
+			info["tags"] = ws.Definition.Tags
+		}
 
 		// Add git info if available
 		if gitStatuses != nil {
@@ -325,6 +335,9 @@ func (s *WorkspaceConfigService) Get(ctx context.Context, params json.RawMessage
 		"auto_start": ws.Definition.AutoStart,
 		"created_at": ws.Definition.CreatedAt,
 	}
+	if len(ws.Definition.Tags) > 0 {
+		info["tags"] = ws.Definition.Tags
+	}
 	// Get session viewers for this workspace (if provider is available)
 	var sessionViewers map[string][]string
 	if s.viewerProvider != nil {
@@ -640,9 +653,10 @@ func (s *WorkspaceConfigService) bindWorkspaceToDevice(ctx context.Context, work
 // Update updates workspace settings.
 func (s *WorkspaceConfigService) Update(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
 	var p struct {
-		ID        string  `json:"id"`
-		Name      *string `json:"name"`
-		AutoStart *bool   `json:"auto_start"`
+		ID        string    `json:"id"`
+		Name      *string   `json:"name"`
+		AutoStart *bool     `json:"auto_start"`
+		Tags      *[]string `json:"tags"`
 	}
 	if err := json.Unmarshal(params, &p); err != nil {
 		return nil, message.NewError(message.InvalidParams, "failed to parse params: "+err.Error())
@@ -664,6 +678,9 @@ func (s *WorkspaceConfigService) Update(ctx context.Context, params json.RawMess
 	if p.AutoStart != nil {
 		ws.Definition.AutoStart = *p.AutoStart
 	}
+	if p.Tags != nil {
+		ws.Definition.Tags = config.NormalizeWorkspaceTags(*p.Tags)
+	}
 
 	if err := s.configManager.UpdateWorkspace(ws); err != nil {
 		return nil, message.NewError(message.InternalError, err.Error())
@@ -674,6 +691,7 @@ func (s *WorkspaceConfigService) Update(ctx context.Context, params json.RawMess
 		"name":       ws.Definition.Name,
 		"path":       ws.Definition.Path,
 		"auto_start": ws.Definition.AutoStart,
+		"tags":       ws.Definition.Tags,
 		"created_at": ws.Definition.CreatedAt,
 	}, nil
 }
@@ -692,15 +710,22 @@ func (s *WorkspaceConfigService) Discover(ctx context.Context, params json.RawMe
 	var result *workspace.DiscoveryResult
 	var err error
 
+	// Only a scan reports progress; cached results return at once
+	progress := handler.ProgressFrom(ctx)
+	progress.Begin("Discovering repositories")
+	ctx = ports.WithProgress(ctx, progress.ReportFunc())
+
 	if p.Fresh {
-		result, err = s.configManager.DiscoverRepositoriesFresh(p.Paths)
+		result, err = s.configManager.DiscoverRepositoriesFresh(ctx, p.Paths)
 	} else {
-		result, err = s.configManager.DiscoverRepositoriesWithResult(p.Paths)
+		result, err = s.configManager.DiscoverRepositoriesWithResult(ctx, p.Paths)
 	}
 
 	if err != nil {
+		progress.End("Discovery failed")
 		return nil, message.NewError(message.InternalError, err.Error())
 	}
+	progress.End(fmt.Sprintf("Found %d repositories", result.Count))
 
 	return map[string]interface{}{
 		"repositories":        result.Repositories,
@@ -36,6 +36,9 @@ type OpenRPCMethod struct {
 	Result      *OpenRPCResult    `json:"result,omitempty"`
 	Errors      []OpenRPCErrorRef `json:"errors,omitempty"`
 	Examples    []OpenRPCExample  `json:"examples,omitempty"`
+	Concurrency string            `json:"x-concurrency"` // ordered, parallel or workspace
+	Progress    bool              `json:"x-progress,omitempty"`
+	Unknown     string            `json:"x-unknown-params,omitempty"` // reject when undeclared params fail
 }
 
 // OpenRPCParam represents a method parameter.
@@ -77,6 +80,11 @@ type MethodMeta struct {
 	Params      []OpenRPCParam
 	Result      *OpenRPCResult
 	Errors      []string // Error names to reference
+	Concurrency Concurrency
+	Progress    bool // Reports $/progress when called with a progress_token
+
+	// UnknownParams says whether requests may carry params not in Params.
+	UnknownParams UnknownParams
 }
 
 // GenerateOpenRPC generates an OpenRPC spec from the registry.
@@ -99,14 +107,26 @@ func (r *Registry) GenerateOpenRPC(info OpenRPCInfo, serverURL string) *OpenRPCS
 	for _, name := range methods {
 		meta := r.GetMeta(name)
 		method := OpenRPCMethod{
-			Name:    name,
-			Summary: meta.Summary,
-			Params:  meta.Params,
-			Result:  meta.Result,
+			Name:        name,
+			Summary:     meta.Summary,
+			Params:      meta.Params,
+			Result:      meta.Result,
+			Concurrency: meta.Concurrency.String(),
 		}
 		if meta.Description != "" {
 			method.Description = meta.Description
 		}
+		if meta.Progress {
+			method.Progress = true
+			method.Params = append(append([]OpenRPCParam{}, meta.Params...), OpenRPCParam{
+				Name:        ProgressTokenParam,
+				Description: "Token to receive $/progress notifications with",
+				Schema:      map[string]interface{}{"type": []string{"string", "integer"}},
+			})
+		}
+		if meta.UnknownParams == UnknownParamsReject {
+			method.Unknown = meta.UnknownParams.String()
+		}
 		for _, errName := range meta.Errors {
 			method.Errors = append(method.Errors, OpenRPCErrorRef{
 				Ref: "#/components/errors/" + errName,
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/rs/zerolog/log"
+)
+
+// ProgressTokenParam is the param a client sets, to a string or a number, to
+// receive $/progress notifications from a method that supports progress.
+const ProgressTokenParam = "progress_token"
+
+// progressInterval is the minimum time between two progress reports.
+const progressInterval = 100 * time.Millisecond
+
+type (
+	notifierKey struct{}
+	progressKey struct{}
+)
+
+// withNotifier returns a context whose requests can send notifications to
+// their connection with notify.
+func withNotifier(ctx context.Context, notify func([]byte)) context.Context {
+	return context.WithValue(ctx, notifierKey{}, notify)
+}
+
+// withProgress returns a context carrying a Progress for the request, if it
+// has a progress token and its connection accepts notifications.
+func withProgress(ctx context.Context, params json.RawMessage) context.Context {
+	notify, ok := ctx.Value(notifierKey{}).(func([]byte))
+	if !ok {
+		return ctx
+	}
+	var p struct {
+		Token *message.ID `json:"progress_token"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil || p.Token == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &Progress{token: p.Token, notify: notify})
+}
+
+// Progress reports the progress of a request to the client that sent it as
+// $/progress notifications. Handlers get it with ProgressFrom; it is nil
+// when the client did not ask for progress, and a nil Progress discards
+// reports.
+type Progress struct {
+	token  *message.ID
+	notify func([]byte)
+
+	mu       sync.Mutex
+	begun    bool
+	ended    bool
+	lastSent time.Time
+	last     message.ProgressValue
+}
+
+// ProgressFrom returns the Progress of the request handled with ctx, or nil.
+func ProgressFrom(ctx context.Context) *Progress {
+	p, _ := ctx.Value(progressKey{}).(*Progress)
+	return p
+}
+
+// Begin starts reporting progress under a title, e.g. "Fetching origin".
+func (p *Progress) Begin(title string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.begun {
+		return
+	}
+	p.begun = true
+	p.send(message.ProgressValue{Kind: message.ProgressBegin, Title: title})
+}
+
+// Report reports a step of the work. pct is between 0 and 100, or negative
+// when unknown. Reports are dropped before Begin, after End, and when they
+// come faster than a client needs them.
+func (p *Progress) Report(msg string, pct int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.begun || p.ended {
+		return
+	}
+	value := message.ProgressValue{Kind: message.ProgressReport, Message: msg, Percentage: percentage(pct)}
+	throttled := p.last.Kind == message.ProgressReport && time.Since(p.lastSent) < progressInterval && pct < 100
+	if sameProgress(value, p.last) || throttled {
+		return
+	}
+	p.send(value)
+}
+
+// ReportFunc returns Report as a function for code reporting progress
+// through a callback, or nil for a nil Progress.
+func (p *Progress) ReportFunc() func(msg string, pct int) {
+	if p == nil {
+		return nil
+	}
+	return p.Report
+}
+
+// End reports that the work is done. It does nothing before Begin.
+func (p *Progress) End(msg string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.begun || p.ended {
+		return
+	}
+	p.ended = true
+	p.send(message.ProgressValue{Kind: message.ProgressEnd, Message: msg})
+}
+
+// send sends a $/progress notification. The caller holds p.mu, which keeps
+// the notifications in order.
+func (p *Progress) send(value message.ProgressValue) {
+	p.last = value
+	p.lastSent = time.Now()
+
+	notification, err := message.NewNotification(message.ProgressMethod, message.ProgressParams{Token: p.token, Value: value})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create progress notification")
+		return
+	}
+	data, err := json.Marshal(notification)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal progress notification")
+		return
+	}
+	p.notify(data)
+}
+
+// percentage returns pct clamped to 100, or nil when it is unknown.
+func percentage(pct int) *int {
+	if pct < 0 {
+		return nil
+	}
+	if pct > 100 {
+		pct = 100
+	}
+	return &pct
+}
+
+func sameProgress(a, b message.ProgressValue) bool {
+	if a.Kind != b.Kind || a.Message != b.Message || (a.Percentage == nil) != (b.Percentage == nil) {
+		return false
+	}
+	return a.Percentage == nil || *a.Percentage == *b.Percentage
+}
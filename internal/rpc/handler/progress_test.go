@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/rpc/message"
+)
+
+func progressRegistry() *Registry {
+	registry := NewRegistry()
+	registry.RegisterWithMeta("test/work", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		progress := ProgressFrom(ctx)
+		progress.Begin("Working")
+		progress.Report("half", 50)
+		progress.Report("half", 50) // Duplicate, dropped
+		progress.Report("all", 100)
+		progress.End("done")
+		return "ok", nil
+	}, MethodMeta{Progress: true})
+	return registry
+}
+
+func TestProgress_Notifications(t *testing.T) {
+	e := NewExecutor(NewDispatcher(progressRegistry()), 1)
+	r := newReplies()
+	e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"test/work","params":{"progress_token":"tok"}}`), r.reply)
+	e.Wait()
+
+	out := r.wait(t, 5)
+	kinds := []string{message.ProgressBegin, message.ProgressReport, message.ProgressReport, message.ProgressEnd}
+	for i, kind := range kinds {
+		var notif struct {
+			Method string                 `json:"method"`
+			Params message.ProgressParams `json:"params"`
+		}
+		if err := json.Unmarshal([]byte(out[i]), &notif); err != nil {
+			t.Fatal(err)
+		}
+		if notif.Method != message.ProgressMethod || notif.Params.Token.String() != "tok" || notif.Params.Value.Kind != kind {
+			t.Errorf("notification %d = %s, want %s for token tok", i, out[i], kind)
+		}
+	}
+	var last struct {
+		Params message.ProgressParams `json:"params"`
+	}
+	_ = json.Unmarshal([]byte(out[2]), &last)
+	if p := last.Params.Value.Percentage; p == nil || *p != 100 {
+		t.Errorf("last report = %s, want 100%%", out[2])
+	}
+	if id := responseID(t, out[4]); id != "1" {
+		t.Errorf("response = %s, want the response last", out[4])
+	}
+}
+
+func TestProgress_WithoutToken(t *testing.T) {
+	e := NewExecutor(NewDispatcher(progressRegistry()), 1)
+	r := newReplies()
+	e.Handle(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"test/work"}`), r.reply)
+	e.Wait()
+
+	if out := r.wait(t, 1); len(out) != 1 {
+		t.Errorf("got %d messages, want only the response: %v", len(out), out)
+	}
+}
+
+func TestProgress_NilIsNoop(t *testing.T) {
+	var p *Progress
+	p.Begin("x")
+	p.Report("y", 1)
+	p.End("z")
+	if p.ReportFunc() != nil {
+		t.Error("ReportFunc of nil Progress should be nil")
+	}
+}
+
+func TestGenerateOpenRPC_Progress(t *testing.T) {
+	spec := progressRegistry().GenerateOpenRPC(OpenRPCInfo{}, "")
+	method := spec.Methods[0]
+	if !method.Progress {
+		t.Error("x-progress not set")
+	}
+	if len(method.Params) != 1 || method.Params[0].Name != ProgressTokenParam {
+		t.Errorf("params = %+v, want progress_token", method.Params)
+	}
+}
@@ -88,7 +88,9 @@ func (r *Registry) Use(mw MiddlewareFunc) {
 }
 
 // Get returns the handler for a method.
-// It applies all registered middleware to the handler.
+// It applies all registered middleware to the handler, around a check of
+// the request params against the params the method declares.
+// Methods registered without params metadata are not checked.
 // Returns nil if the method is not registered.
 func (r *Registry) Get(method string) HandlerFunc {
 	r.mu.RLock()
@@ -99,6 +101,10 @@ func (r *Registry) Get(method string) HandlerFunc {
 		return nil
 	}
 
+	if meta, ok := r.meta[method]; ok && meta.Params != nil {
+		handler = validateParams(meta, handler)
+	}
+
 	// Apply middleware in reverse order (last added = innermost)
 	for i := len(r.middleware) - 1; i >= 0; i-- {
 		handler = r.middleware[i](handler)
@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/brianly1003/cdev/internal/rpc/message"
+)
+
+// UnknownParams says what validation does with params a method does not declare.
+type UnknownParams int
+
+const (
+	// UnknownParamsIgnore passes undeclared params through to the handler,
+	// which ignores them. The default, so older and newer clients keep working.
+	UnknownParamsIgnore UnknownParams = iota
+	// UnknownParamsReject fails requests with undeclared params, for methods
+	// where a misspelt param would silently change what the call does.
+	UnknownParamsReject
+)
+
+// String returns the name used for the policy in the OpenRPC document.
+func (u UnknownParams) String() string {
+	if u == UnknownParamsReject {
+		return "reject"
+	}
+	return "ignore"
+}
+
+// paramError describes a param that does not match its schema.
+type paramError struct {
+	pointer string // JSON Pointer to the offending value
+	reason  string
+}
+
+// toRPC converts the error into an InvalidParams response error.
+func (e *paramError) toRPC() *message.Error {
+	pointer := e.pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return message.NewErrorWithData(message.InvalidParams,
+		fmt.Sprintf("invalid params: %s: %s", pointer, e.reason),
+		map[string]string{"pointer": e.pointer, "reason": e.reason})
+}
+
+// validateParams wraps handler so requests are checked against the params
+// declared in meta before it runs.
+func validateParams(meta MethodMeta, handler HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		if err := checkParams(meta, params); err != nil {
+			return nil, err.toRPC()
+		}
+		return handler(ctx, params)
+	}
+}
+
+// checkParams validates params against the declared params of a method.
+// Params must be an object, or absent. Optional params set to null count as
+// absent, like they do when the handler decodes them.
+func checkParams(meta MethodMeta, params json.RawMessage) *paramError {
+	var value interface{}
+	if trimmed := bytes.TrimSpace(params); len(trimmed) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return &paramError{reason: "malformed JSON"}
+		}
+	}
+	if value == nil {
+		value = map[string]interface{}{}
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return &paramError{reason: "expected object, got " + jsonType(value)}
+	}
+
+	declared := make(map[string]bool, len(meta.Params))
+	for _, param := range meta.Params {
+		declared[param.Name] = true
+		v, present := object[param.Name]
+		if !present || v == nil {
+			if param.Required {
+				return &paramError{pointer: pointerTo("", param.Name), reason: "is required"}
+			}
+			continue
+		}
+		if err := checkValue(v, param.Schema, pointerTo("", param.Name)); err != nil {
+			return err
+		}
+	}
+	if meta.Progress {
+		declared[ProgressTokenParam] = true
+		if v := object[ProgressTokenParam]; v != nil {
+			if err := checkValue(v, map[string]interface{}{"type": []string{"string", "integer"}}, pointerTo("", ProgressTokenParam)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if meta.UnknownParams == UnknownParamsReject {
+		for _, name := range sortedKeys(object) {
+			if !declared[name] {
+				return &paramError{pointer: pointerTo("", name), reason: "unknown param"}
+			}
+		}
+	}
+	return nil
+}
+
+// checkValue validates v against a JSON Schema. It supports the keywords
+// method metadata uses: type, enum, properties, required,
+// additionalProperties, items, minimum, maximum, minLength, maxLength,
+// minItems, maxItems and pattern. Other keywords, $ref included, are not
+// checked.
+func checkValue(v interface{}, schema map[string]interface{}, pointer string) *paramError {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	if types := stringList(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if hasType(v, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return &paramError{pointer: pointer, reason: fmt.Sprintf("expected %s, got %s", strings.Join(types, " or "), jsonType(v))}
+		}
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		if err := checkEnum(v, enum, pointer); err != nil {
+			return err
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if min, ok := number(schema["minLength"]); ok && float64(length) < min {
+			return &paramError{pointer: pointer, reason: fmt.Sprintf("must be at least %v characters", min)}
+		}
+		if max, ok := number(schema["maxLength"]); ok && float64(length) > max {
+			return &paramError{pointer: pointer, reason: fmt.Sprintf("must be at most %v characters", max)}
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				return &paramError{pointer: pointer, reason: "does not match pattern " + pattern}
+			}
+		}
+
+	case json.Number:
+		f, _ := v.Float64()
+		if min, ok := number(schema["minimum"]); ok && f < min {
+			return &paramError{pointer: pointer, reason: fmt.Sprintf("must be at least %v", min)}
+		}
+		if max, ok := number(schema["maximum"]); ok && f > max {
+			return &paramError{pointer: pointer, reason: fmt.Sprintf("must be at most %v", max)}
+		}
+
+	case []interface{}:
+		if min, ok := number(schema["minItems"]); ok && float64(len(v)) < min {
+			return &paramError{pointer: pointer, reason: fmt.Sprintf("must have at least %v items", min)}
+		}
+		if max, ok := number(schema["maxItems"]); ok && float64(len(v)) > max {
+			return &paramError{pointer: pointer, reason: fmt.Sprintf("must have at most %v items", max)}
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := checkValue(item, items, fmt.Sprintf("%s/%d", pointer, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case map[string]interface{}:
+		for _, name := range stringList(schema["required"]) {
+			if _, ok := v[name]; !ok {
+				return &paramError{pointer: pointerTo(pointer, name), reason: "is required"}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for _, name := range sortedKeys(v) {
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				if err := checkValue(v[name], property, pointerTo(pointer, name)); err != nil {
+					return err
+				}
+			} else if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				if _, declared := properties[name]; !declared {
+					return &paramError{pointer: pointerTo(pointer, name), reason: "unknown property"}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkEnum validates that v is one of the values of enum.
+func checkEnum(v interface{}, enum interface{}, pointer string) *paramError {
+	encoded, _ := json.Marshal(v)
+	values, _ := json.Marshal(enum)
+	var allowed []json.RawMessage
+	if err := json.Unmarshal(values, &allowed); err != nil {
+		return nil
+	}
+	for _, a := range allowed {
+		if bytes.Equal(encoded, a) {
+			return nil
+		}
+	}
+	names := make([]string, len(allowed))
+	for i, a := range allowed {
+		names[i] = string(a)
+	}
+	return &paramError{pointer: pointer, reason: "must be one of " + strings.Join(names, ", ")}
+}
+
+// hasType reports whether v is of the JSON Schema type t.
+func hasType(v interface{}, t string) bool {
+	switch t {
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	default:
+		return jsonType(v) == t
+	}
+}
+
+// jsonType returns the JSON type name of a decoded value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// pointerTo appends a property name to a JSON Pointer, escaping it as
+// RFC 6901 requires.
+func pointerTo(pointer, name string) string {
+	name = strings.ReplaceAll(name, "~", "~0")
+	name = strings.ReplaceAll(name, "/", "~1")
+	return pointer + "/" + name
+}
+
+// stringList reads a schema keyword that is a string or a list of strings.
+func stringList(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// number reads a numeric schema keyword.
+func number(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
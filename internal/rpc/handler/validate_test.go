@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/rpc/message"
+)
+
+func validatedRegistry(meta MethodMeta) *Registry {
+	r := NewRegistry()
+	r.RegisterWithMeta("test/method", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		return "ok", nil
+	}, meta)
+	return r
+}
+
+func TestRegistry_ValidatesParams(t *testing.T) {
+	meta := MethodMeta{
+		Params: []OpenRPCParam{
+			{Name: "workspace_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "limit", Schema: map[string]interface{}{"type": "integer", "minimum": 1}},
+			{Name: "mode", Schema: map[string]interface{}{"type": "string", "enum": []string{"new", "continue"}}},
+			{Name: "paths", Schema: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}},
+			{Name: "filter", Schema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{"a/b": map[string]interface{}{"type": "boolean"}},
+				"additionalProperties": false,
+			}},
+		},
+	}
+	handler := validatedRegistry(meta).Get("test/method")
+
+	tests := []struct {
+		name    string
+		params  string
+		valid   bool
+		pointer string
+	}{
+		{"valid", `{"workspace_id":"ws","limit":10,"mode":"new","paths":["a"],"filter":{"a/b":true}}`, true, ""},
+		{"optional null", `{"workspace_id":"ws","limit":null}`, true, ""},
+		{"integral float", `{"workspace_id":"ws","limit":2.0}`, true, ""},
+		{"missing required", `{}`, false, "/workspace_id"},
+		{"absent params", ``, false, "/workspace_id"},
+		{"null required", `{"workspace_id":null}`, false, "/workspace_id"},
+		{"not an object", `["ws"]`, false, ""},
+		{"wrong type", `{"workspace_id":1}`, false, "/workspace_id"},
+		{"fraction for integer", `{"workspace_id":"ws","limit":1.5}`, false, "/limit"},
+		{"below minimum", `{"workspace_id":"ws","limit":0}`, false, "/limit"},
+		{"not in enum", `{"workspace_id":"ws","mode":"old"}`, false, "/mode"},
+		{"array item", `{"workspace_id":"ws","paths":["a",2]}`, false, "/paths/1"},
+		{"nested property", `{"workspace_id":"ws","filter":{"a/b":"yes"}}`, false, "/filter/a~1b"},
+		{"nested unknown", `{"workspace_id":"ws","filter":{"c":true}}`, false, "/filter/c"},
+		{"unknown ignored", `{"workspace_id":"ws","extra":1}`, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := handler(context.Background(), json.RawMessage(tt.params))
+			if tt.valid {
+				if err != nil || result != "ok" {
+					t.Fatalf("got error %v, want the handler to run", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("invalid params reached the handler")
+			}
+			if err.Code != message.InvalidParams {
+				t.Errorf("code = %d, want %d", err.Code, message.InvalidParams)
+			}
+			var data struct{ Pointer string }
+			if e := json.Unmarshal(err.Data, &data); e != nil {
+				t.Fatalf("invalid error data %s: %v", err.Data, e)
+			}
+			if data.Pointer != tt.pointer {
+				t.Errorf("pointer = %q, want %q (%s)", data.Pointer, tt.pointer, err.Message)
+			}
+		})
+	}
+}
+
+func TestRegistry_RejectsUnknownParams(t *testing.T) {
+	handler := validatedRegistry(MethodMeta{
+		Params:        []OpenRPCParam{{Name: "decision", Schema: map[string]interface{}{"type": "string"}}},
+		Progress:      true,
+		UnknownParams: UnknownParamsReject,
+	}).Get("test/method")
+
+	if _, err := handler(context.Background(), json.RawMessage(`{"decision":"allow","progress_token":7}`)); err != nil {
+		t.Errorf("declared params rejected: %v", err)
+	}
+	_, err := handler(context.Background(), json.RawMessage(`{"decision":"allow","scpoe":"session"}`))
+	if err == nil || err.Message != "invalid params: /scpoe: unknown param" {
+		t.Errorf("error = %v, want the unknown param reported", err)
+	}
+	if _, err := handler(context.Background(), json.RawMessage(`{"progress_token":true}`)); err == nil {
+		t.Error("boolean progress_token accepted")
+	}
+}
+
+func TestRegistry_ValidationInsideMiddleware(t *testing.T) {
+	r := validatedRegistry(MethodMeta{
+		Params: []OpenRPCParam{{Name: "id", Required: true, Schema: map[string]interface{}{"type": "string"}}},
+	})
+	var sawError bool
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+			result, err := next(ctx, params)
+			sawError = err != nil
+			return result, err
+		}
+	})
+
+	if _, err := r.Get("test/method")(context.Background(), nil); err == nil {
+		t.Fatal("missing required param accepted")
+	}
+	if !sawError {
+		t.Error("middleware did not see the validation error")
+	}
+}
+
+func TestRegistry_NoParamsMetaNotValidated(t *testing.T) {
+	r := NewRegistry()
+	r.Register("test/plain", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		return "ok", nil
+	})
+
+	if _, err := r.Get("test/plain")(context.Background(), json.RawMessage(`"anything"`)); err != nil {
+		t.Errorf("method without params metadata was validated: %v", err)
+	}
+}
+
+func TestGenerateOpenRPC_UnknownParams(t *testing.T) {
+	r := validatedRegistry(MethodMeta{Params: []OpenRPCParam{}, UnknownParams: UnknownParamsReject})
+	r.RegisterWithMeta("test/other", nil, MethodMeta{})
+
+	for _, method := range r.GenerateOpenRPC(OpenRPCInfo{}, "").Methods {
+		want := ""
+		if method.Name == "test/method" {
+			want = "reject"
+		}
+		if method.Unknown != want {
+			t.Errorf("%s x-unknown-params = %q, want %q", method.Name, method.Unknown, want)
+		}
+	}
+}
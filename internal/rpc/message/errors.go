@@ -21,6 +21,10 @@ const (
 
 	// ServerError codes are reserved for implementation-defined server-errors.
 	// Range: -32000 to -32099
+
+	// RequestCancelled indicates the client cancelled the request with
+	// $/cancelRequest. Same code as the Language Server Protocol.
+	RequestCancelled = -32800
 )
 
 // cdev-specific error codes (-32001 to -32050).
@@ -134,6 +138,11 @@ func ErrInternalError(message string) *Error {
 	return NewError(InternalError, message)
 }
 
+// ErrRequestCancelled creates a request cancelled error.
+func ErrRequestCancelled() *Error {
+	return NewError(RequestCancelled, "Request cancelled")
+}
+
 // cdev-specific error constructors.
 
 // ErrAgentAlreadyRunning creates an agent already running error.
@@ -239,6 +248,8 @@ func ErrorCodeName(code int) string {
 		return "InvalidParams"
 	case InternalError:
 		return "InternalError"
+	case RequestCancelled:
+		return "RequestCancelled"
 	case AgentAlreadyRunning:
 		return "AgentAlreadyRunning"
 	case AgentNotRunning:
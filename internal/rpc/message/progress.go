@@ -0,0 +1,27 @@
+package message
+
+// ProgressMethod is the notification a server sends to report the progress
+// of a request that carried a progress token.
+const ProgressMethod = "$/progress"
+
+// Kinds of progress values, as in the Language Server Protocol.
+const (
+	ProgressBegin  = "begin"
+	ProgressReport = "report"
+	ProgressEnd    = "end"
+)
+
+// ProgressParams are the params of a $/progress notification.
+type ProgressParams struct {
+	Token *ID           `json:"token"` // The progress token of the request
+	Value ProgressValue `json:"value"`
+}
+
+// ProgressValue is one step of the progress of a request: a single begin,
+// any number of reports, then a single end.
+type ProgressValue struct {
+	Kind       string `json:"kind"`            // begin, report or end
+	Title      string `json:"title,omitempty"` // Set on begin
+	Message    string `json:"message,omitempty"`
+	Percentage *int   `json:"percentage,omitempty"` // 0-100, omitted when unknown
+}
@@ -130,6 +130,7 @@ func (s *Server) Broadcast(method string, params interface{}) error {
 type Client struct {
 	transport  transport.Transport
 	dispatcher *handler.Dispatcher
+	executor   *handler.Executor
 
 	// send is a buffered channel for outgoing messages
 	send chan []byte
@@ -144,6 +145,7 @@ func NewClient(t transport.Transport, dispatcher *handler.Dispatcher) *Client {
 	return &Client{
 		transport:  t,
 		dispatcher: dispatcher,
+		executor:   handler.NewExecutor(dispatcher, handler.DefaultWorkers),
 		send:       make(chan []byte, 256),
 		done:       make(chan struct{}),
 	}
@@ -166,6 +168,9 @@ func (c *Client) Serve(ctx context.Context) error {
 
 // readLoop reads and processes incoming messages.
 func (c *Client) readLoop(ctx context.Context) error {
+	// Requests still running when the client goes away are cancelled
+	defer c.executor.Close()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -184,33 +189,21 @@ func (c *Client) readLoop(ctx context.Context) error {
 			}
 
 			// Process request and send response
-			go c.handleRequest(ctx, data)
+			c.handleRequest(ctx, data)
 		}
 	}
 }
 
-// handleRequest processes a single request.
+// handleRequest schedules a request. Its response is sent once it is ready.
 func (c *Client) handleRequest(ctx context.Context, data []byte) {
-	response, err := c.dispatcher.HandleMessage(ctx, data)
-	if err != nil {
-		log.Warn().
-			Str("client_id", c.ID()).
-			Err(err).
-			Msg("failed to handle message")
-		return
-	}
-
-	// Don't send empty responses (notifications don't have responses)
-	if len(response) == 0 {
-		return
-	}
-
-	if err := c.Send(response); err != nil {
-		log.Warn().
-			Str("client_id", c.ID()).
-			Err(err).
-			Msg("failed to send response")
-	}
+	c.executor.Handle(ctx, data, func(response []byte) {
+		if err := c.Send(response); err != nil {
+			log.Warn().
+				Str("client_id", c.ID()).
+				Err(err).
+				Msg("failed to send response")
+		}
+	})
 }
 
 // writeLoop sends messages to the transport.
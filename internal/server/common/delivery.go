@@ -0,0 +1,267 @@
+// Package common provides shared types and utilities for server implementations.
+package common
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/rs/zerolog/log"
+)
+
+// Batching limits for OutboundQueue.
+const (
+	// MaxBatchFrames is the maximum number of notifications sent in one batch frame.
+	MaxBatchFrames = 50
+
+	// MaxBatchBytes bounds the size of a batch frame.
+	MaxBatchBytes = 64 * 1024
+
+	// MaxBatchableFrame is the largest notification that is batched with others.
+	MaxBatchableFrame = 4 * 1024
+)
+
+// DeliveryPolicy controls how a client's queued messages are delivered
+// when the client reads slower than events are produced.
+type DeliveryPolicy struct {
+	// Coalesce replaces a queued state event with a newer one of the same
+	// kind, e.g. the latest git_status_changed of a workspace.
+	Coalesce bool `json:"coalesce"`
+
+	// Batch sends queued small notifications as one JSON-RPC batch (a JSON
+	// array) instead of one frame each. Clients must opt in.
+	Batch bool `json:"batch"`
+}
+
+// DefaultDeliveryPolicy returns the policy of a new client.
+func DefaultDeliveryPolicy() DeliveryPolicy {
+	return DeliveryPolicy{Coalesce: true}
+}
+
+// CoalesceKey returns the key under which an event supersedes older queued
+// events, or "" when every event of its type matters.
+func CoalesceKey(event events.Event) string {
+	switch event.Type() {
+	case events.EventTypeGitStatusChanged:
+		return string(event.Type()) + "|" + event.GetWorkspaceID()
+	case events.EventTypePTYState,
+		events.EventTypePTYSpinner,
+		events.EventTypeClaudeStatus,
+		events.EventTypeSessionResources:
+		return string(event.Type()) + "|" + event.GetWorkspaceID() + "|" + event.GetSessionID()
+	case events.EventTypeHeartbeat:
+		return string(event.Type())
+	}
+	return ""
+}
+
+// Frame is a message waiting in an OutboundQueue.
+type Frame struct {
+	Data         []byte
+	Key          string // Coalescing key, see CoalesceKey
+	Critical     bool   // Never coalesced or dropped; RPC responses are critical
+	Notification bool   // May be batched with other notifications
+
+	lagNotice bool // Placeholder for the stream_lagging notice
+}
+
+// OutboundQueue is a client's send queue. Unlike a plain buffered channel it
+// coalesces superseded state events, batches small notifications, and when
+// it fills up it drops what can be dropped and tells the client to resync
+// instead of silently losing messages.
+//
+// Once full, the queue is lagging: queued and new non-critical frames are
+// dropped until the stream_lagging notice built by lagNotice is written.
+// Critical frames are always queued, beyond the capacity if need be.
+type OutboundQueue struct {
+	id        string
+	capacity  int
+	lagNotice func(dropped int) []byte
+	ready     chan struct{}
+
+	mu        sync.Mutex
+	policy    DeliveryPolicy
+	frames    []Frame
+	lagging   bool
+	dropped   int
+	coalesced uint64
+	closed    bool
+}
+
+// NewOutboundQueue creates a queue holding up to capacity frames.
+// lagNotice encodes the notice sent when frames were dropped.
+func NewOutboundQueue(id string, capacity int, lagNotice func(dropped int) []byte) *OutboundQueue {
+	return &OutboundQueue{
+		id:        id,
+		capacity:  capacity,
+		lagNotice: lagNotice,
+		ready:     make(chan struct{}, 1),
+		policy:    DefaultDeliveryPolicy(),
+	}
+}
+
+// SetPolicy changes the delivery policy. Queued frames are kept.
+func (q *OutboundQueue) SetPolicy(policy DeliveryPolicy) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.policy = policy
+}
+
+// Policy returns the delivery policy.
+func (q *OutboundQueue) Policy() DeliveryPolicy {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.policy
+}
+
+// Push queues a frame. It returns ErrBufferFull when the frame was dropped
+// because the client is lagging, or ErrClosed once the queue is closed.
+func (q *OutboundQueue) Push(frame Frame) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+	if frame.Critical {
+		q.append(frame)
+		return nil
+	}
+	if q.lagging {
+		q.dropped++
+		return ErrBufferFull
+	}
+
+	if q.policy.Coalesce && frame.Key != "" {
+		for i := range q.frames {
+			if q.frames[i].Key == frame.Key && !q.frames[i].Critical {
+				// Move to the back so frames stay in the order they were produced
+				q.frames = append(q.frames[:i], q.frames[i+1:]...)
+				q.coalesced++
+				break
+			}
+		}
+	}
+
+	if len(q.frames) >= q.capacity {
+		kept := q.frames[:0]
+		for _, f := range q.frames {
+			if f.Critical {
+				kept = append(kept, f)
+			}
+		}
+		q.dropped += len(q.frames) - len(kept) + 1
+		q.frames = kept
+		q.lagging = true
+		log.Warn().
+			Str("id", q.id).
+			Int("dropped", q.dropped).
+			Msg("send queue full, client is lagging")
+		q.append(Frame{Critical: true, lagNotice: true})
+		return ErrBufferFull
+	}
+
+	q.append(frame)
+	return nil
+}
+
+// append adds a frame and wakes the writer. Callers hold q.mu.
+func (q *OutboundQueue) append(frame Frame) {
+	q.frames = append(q.frames, frame)
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Ready returns a channel that receives a value when frames were queued.
+// Drain the queue with Next until it returns nil after each receive.
+func (q *OutboundQueue) Ready() <-chan struct{} {
+	return q.ready
+}
+
+// Next removes and returns the next message to write, or nil when the queue
+// is empty. With batching enabled, consecutive small notifications are
+// joined into one JSON array.
+func (q *OutboundQueue) Next() []byte {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.frames) == 0 {
+		return nil
+	}
+	first := q.pop()
+	if first.lagNotice {
+		data := q.lagNotice(q.dropped)
+		q.dropped = 0
+		q.lagging = false
+		return data
+	}
+	if !q.policy.Batch || !batchable(first) {
+		return first.Data
+	}
+
+	batch := [][]byte{first.Data}
+	size := len(first.Data) + 2
+	for len(q.frames) > 0 && len(batch) < MaxBatchFrames {
+		next := q.frames[0]
+		if !batchable(next) || size+len(next.Data)+1 > MaxBatchBytes {
+			break
+		}
+		q.pop()
+		batch = append(batch, next.Data)
+		size += len(next.Data) + 1
+	}
+	if len(batch) == 1 {
+		return first.Data
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(size)
+	buf.WriteByte('[')
+	buf.Write(bytes.Join(batch, []byte{','}))
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
+
+// pop removes the first frame. Callers hold q.mu.
+func (q *OutboundQueue) pop() Frame {
+	frame := q.frames[0]
+	q.frames[0] = Frame{}
+	q.frames = q.frames[1:]
+	return frame
+}
+
+func batchable(frame Frame) bool {
+	return frame.Notification && !frame.lagNotice && len(frame.Data) <= MaxBatchableFrame
+}
+
+// Len returns the number of queued frames.
+func (q *OutboundQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.frames)
+}
+
+// Lagging reports whether frames are being dropped until the client has
+// been told to resync.
+func (q *OutboundQueue) Lagging() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.lagging
+}
+
+// Coalesced returns how many queued frames were replaced by newer ones.
+func (q *OutboundQueue) Coalesced() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.coalesced
+}
+
+// Close drops queued frames and rejects new ones.
+func (q *OutboundQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.frames = nil
+}
@@ -0,0 +1,138 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+)
+
+func testLagNotice(dropped int) []byte {
+	return []byte(fmt.Sprintf(`{"lagging":%d}`, dropped))
+}
+
+func drain(q *OutboundQueue) []string {
+	var out []string
+	for data := q.Next(); data != nil; data = q.Next() {
+		out = append(out, string(data))
+	}
+	return out
+}
+
+func TestOutboundQueue_CoalescesSupersededFrames(t *testing.T) {
+	q := NewOutboundQueue("client-1", 10, testLagNotice)
+
+	_ = q.Push(Frame{Data: []byte("status-1"), Key: "git|ws-1"})
+	_ = q.Push(Frame{Data: []byte("message")})
+	_ = q.Push(Frame{Data: []byte("status-2"), Key: "git|ws-1"})
+	_ = q.Push(Frame{Data: []byte("status-other"), Key: "git|ws-2"})
+
+	got := drain(q)
+	want := []string{"message", "status-2", "status-other"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("frames = %v, want %v", got, want)
+	}
+	if q.Coalesced() != 1 {
+		t.Errorf("coalesced = %d, want 1", q.Coalesced())
+	}
+}
+
+func TestOutboundQueue_NoCoalescingWhenDisabled(t *testing.T) {
+	q := NewOutboundQueue("client-1", 10, testLagNotice)
+	q.SetPolicy(DeliveryPolicy{Coalesce: false})
+
+	_ = q.Push(Frame{Data: []byte("status-1"), Key: "git|ws-1"})
+	_ = q.Push(Frame{Data: []byte("status-2"), Key: "git|ws-1"})
+
+	if got := drain(q); len(got) != 2 {
+		t.Fatalf("frames = %v, want both statuses", got)
+	}
+}
+
+func TestOutboundQueue_LaggingKeepsCriticalFrames(t *testing.T) {
+	q := NewOutboundQueue("client-1", 3, testLagNotice)
+
+	_ = q.Push(Frame{Data: []byte("log-1")})
+	_ = q.Push(Frame{Data: []byte("permission"), Critical: true})
+	_ = q.Push(Frame{Data: []byte("log-2")})
+	if err := q.Push(Frame{Data: []byte("log-3")}); !errors.Is(err, ErrBufferFull) {
+		t.Fatalf("Push() on full queue error = %v, want ErrBufferFull", err)
+	}
+	if !q.Lagging() {
+		t.Fatal("queue should be lagging once full")
+	}
+
+	// While lagging, only critical frames are queued
+	if err := q.Push(Frame{Data: []byte("log-4")}); !errors.Is(err, ErrBufferFull) {
+		t.Fatalf("Push() while lagging error = %v, want ErrBufferFull", err)
+	}
+	if err := q.Push(Frame{Data: []byte("response"), Critical: true}); err != nil {
+		t.Fatalf("Push() critical while lagging error = %v", err)
+	}
+
+	got := drain(q)
+	want := []string{"permission", `{"lagging":4}`, "response"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("frames = %v, want %v", got, want)
+	}
+	if q.Lagging() {
+		t.Error("queue should stop lagging once the notice is written")
+	}
+
+	if err := q.Push(Frame{Data: []byte("log-5")}); err != nil {
+		t.Fatalf("Push() after resync notice error = %v", err)
+	}
+}
+
+func TestOutboundQueue_BatchesSmallNotifications(t *testing.T) {
+	q := NewOutboundQueue("client-1", 10, testLagNotice)
+	q.SetPolicy(DeliveryPolicy{Coalesce: true, Batch: true})
+
+	_ = q.Push(Frame{Data: []byte(`{"n":1}`), Notification: true})
+	_ = q.Push(Frame{Data: []byte(`{"n":2}`), Notification: true})
+	_ = q.Push(Frame{Data: []byte(`{"id":1}`), Critical: true})
+	_ = q.Push(Frame{Data: []byte(`{"n":3}`), Notification: true})
+
+	got := drain(q)
+	want := []string{`[{"n":1},{"n":2}]`, `{"id":1}`, `{"n":3}`}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("frames = %v, want %v", got, want)
+	}
+
+	var batch []map[string]int
+	if err := json.Unmarshal([]byte(got[0]), &batch); err != nil || len(batch) != 2 {
+		t.Errorf("batch frame is not a JSON array of 2: %v", err)
+	}
+}
+
+func TestOutboundQueue_ClosedRejectsFrames(t *testing.T) {
+	q := NewOutboundQueue("client-1", 10, testLagNotice)
+	_ = q.Push(Frame{Data: []byte("message")})
+	q.Close()
+
+	if err := q.Push(Frame{Data: []byte("late"), Critical: true}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Push() after Close error = %v, want ErrClosed", err)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len() after Close = %d, want 0", q.Len())
+	}
+}
+
+func TestCoalesceKey(t *testing.T) {
+	status := events.NewEventWithContext(events.EventTypeGitStatusChanged, nil, "ws-1", "")
+	if CoalesceKey(status) != "git_status_changed|ws-1" {
+		t.Errorf("CoalesceKey(git_status_changed) = %q", CoalesceKey(status))
+	}
+
+	spinner := events.NewEventWithContext(events.EventTypePTYSpinner, nil, "ws-1", "sess-1")
+	other := events.NewEventWithContext(events.EventTypePTYSpinner, nil, "ws-1", "sess-2")
+	if CoalesceKey(spinner) == CoalesceKey(other) {
+		t.Error("spinner events of different sessions must not coalesce")
+	}
+
+	if CoalesceKey(events.NewEvent(events.EventTypeClaudeMessage, nil)) != "" {
+		t.Error("claude_message must not coalesce")
+	}
+}
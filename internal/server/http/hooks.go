@@ -18,10 +18,11 @@ import (
 // PermissionManager interface for checking/storing permission decisions.
 // This allows the hooks handler to use the existing permission memory system.
 type PermissionManager interface {
-	CheckMemory(sessionID, toolName string, toolInput map[string]interface{}) *permission.StoredDecision
+	CheckMemory(sessionID, workspaceID, toolName string, toolInput map[string]interface{}) *permission.StoredDecision
 	StoreDecision(sessionID, workspaceID, pattern string, decision permission.Decision)
 	AddPendingRequest(req *permission.Request)
 	GetAndRemovePendingRequest(toolUseID string) *permission.Request
+	MarkNotified(toolUseID string, devices []string, escalated bool) bool
 }
 
 // WorkspaceResolver resolves workspace ID from a filesystem path.
@@ -29,12 +30,31 @@ type WorkspaceResolver interface {
 	ResolveWorkspaceID(path string) (string, error)
 }
 
+// WorkspacePathResolver returns the root path of a workspace.
+type WorkspacePathResolver interface {
+	GetWorkspacePath(workspaceID string) (string, error)
+}
+
+// PolicyEvaluator evaluates declarative permission policies for a tool call.
+type PolicyEvaluator interface {
+	Evaluate(in permission.PolicyInput) *permission.PolicyDecision
+}
+
+// AuditRecorder persists the outcome of permission requests.
+type AuditRecorder interface {
+	Record(entry permission.AuditEntry) error
+}
+
 // HooksHandler handles incoming Claude hook events.
 type HooksHandler struct {
 	hub               *hub.Hub
 	permissionManager PermissionManager
 	permissionTimeout time.Duration
 	workspaceResolver WorkspaceResolver
+	workspacePaths    WorkspacePathResolver
+	policy            PolicyEvaluator
+	audit             AuditRecorder
+	router            *permission.Router
 }
 
 // NewHooksHandler creates a new HooksHandler.
@@ -55,6 +75,27 @@ func (h *HooksHandler) SetWorkspaceResolver(resolver WorkspaceResolver) {
 	h.workspaceResolver = resolver
 }
 
+// SetWorkspacePathResolver sets the resolver of workspace roots, against
+// which touched paths are assessed.
+func (h *HooksHandler) SetWorkspacePathResolver(resolver WorkspacePathResolver) {
+	h.workspacePaths = resolver
+}
+
+// SetPolicyEvaluator sets the policy engine consulted before memory and mobile.
+func (h *HooksHandler) SetPolicyEvaluator(policy PolicyEvaluator) {
+	h.policy = policy
+}
+
+// SetAuditRecorder sets the audit log that records every permission decision.
+func (h *HooksHandler) SetAuditRecorder(audit AuditRecorder) {
+	h.audit = audit
+}
+
+// SetPermissionRouter sets the multi-device routing rules for permission prompts.
+func (h *HooksHandler) SetPermissionRouter(router *permission.Router) {
+	h.router = router
+}
+
 // SetPermissionTimeout sets the timeout for waiting for mobile responses.
 func (h *HooksHandler) SetPermissionTimeout(timeout time.Duration) {
 	h.permissionTimeout = timeout
@@ -74,6 +115,18 @@ func (h *HooksHandler) resolveWorkspaceID(cwd string) string {
 	return id
 }
 
+// workspaceRoot returns the root path of a workspace, or "" when unknown.
+func (h *HooksHandler) workspaceRoot(workspaceID string) string {
+	if h.workspacePaths == nil || workspaceID == "" {
+		return ""
+	}
+	root, err := h.workspacePaths.GetWorkspacePath(workspaceID)
+	if err != nil {
+		return ""
+	}
+	return root
+}
+
 // ClaudeHookPayload represents the data sent by Claude hooks.
 // Claude sends different structures for different hook types, so we capture
 // both specific fields and the raw payload for flexibility.
@@ -179,7 +232,8 @@ func (h *HooksHandler) HandleHook(w http.ResponseWriter, r *http.Request) {
 
 	case "permission-request":
 		// Blocking permission request - waits for mobile response
-		h.handlePermissionRequest(w, payload)
+		// format=claude returns Claude's hook output so scripts can pass it through as-is
+		h.handlePermissionRequest(w, payload, r.URL.Query().Get("format") == "claude")
 		// Response written by handlePermissionRequest
 		return
 
@@ -250,25 +304,74 @@ func (h *HooksHandler) handleNotification(payload ClaudeHookPayload) {
 // This is the core of the Permission Hook Bridge - it enables mobile permission approval.
 //
 // Flow:
-// 1. Check pattern memory for stored "Allow for Session" decisions
-// 2. If match found, return immediately with stored decision
-// 3. If no match, forward to iOS via pty_permission event
-// 4. Wait for iOS response via permission/respond RPC (with timeout)
-// 5. Return decision to Claude hook script
-func (h *HooksHandler) handlePermissionRequest(w http.ResponseWriter, payload ClaudeHookPayload) {
+// 1. Run policies, workspace rules and pattern memory (permission.RunPrecheck)
+// 2. A policy deny is returned immediately, even in bypass mode
+// 3. Otherwise a stored decision, then a policy allow, is returned
+// 4. Otherwise forward to iOS via pty_permission event
+// 5. Wait for iOS response via permission/respond RPC (with timeout)
+// 6. Return decision to Claude hook script
+func (h *HooksHandler) handlePermissionRequest(w http.ResponseWriter, payload ClaudeHookPayload, claudeOutput bool) {
 	log.Info().
 		Str("session_id", payload.SessionID).
 		Str("tool_name", payload.ToolName).
 		Str("tool_use_id", payload.ToolUseID).
 		Msg("handling permission request from PreToolUse hook")
 
+	// Parse tool_input into map for pattern matching
+	toolInput := make(map[string]interface{})
+	if payload.ToolInput != nil {
+		if inputMap, ok := payload.ToolInput.(map[string]interface{}); ok {
+			toolInput = inputMap
+		}
+	}
+
+	// Resolve workspace ID from cwd
+	workspaceID := h.resolveWorkspaceID(payload.Cwd)
+
+	audit := permission.AuditEntry{
+		ToolUseID:   payload.ToolUseID,
+		SessionID:   payload.SessionID,
+		WorkspaceID: workspaceID,
+		ToolName:    payload.ToolName,
+		ToolInput:   toolInput,
+		Channel:     "hook",
+		RequestedAt: time.Now(),
+	}
+	// 1. Run policies, workspace rules and pattern memory. Hard policy
+	// denies win over everything, even bypass mode; a policy "ask" skips
+	// remembered decisions so a human always decides.
+	pre := permission.RunPrecheck(permission.PrecheckInput{
+		PolicyInput: permission.PolicyInput{
+			ToolName:      payload.ToolName,
+			ToolInput:     toolInput,
+			WorkspaceID:   workspaceID,
+			WorkspacePath: h.workspaceRoot(workspaceID),
+			Cwd:           payload.Cwd,
+			SessionType:   "claude", // Permission hooks are Claude-specific
+		},
+		SessionID: payload.SessionID,
+	}, h.policy, h.permissionManager, h.router)
+	risk := pre.Risk
+	audit.Risk = risk.Level
+
+	if pre.Policy != nil && pre.Policy.Decision == permission.DecisionDeny {
+		h.recordAudit(audit.WithPolicy(pre.Policy))
+		writePolicyDecision(w, claudeOutput, payload, pre.Policy)
+		return
+	}
+
 	if isBypassPermissionMode(payload.PermissionMode) {
 		log.Info().
 			Str("session_id", payload.SessionID).
 			Str("tool_use_id", payload.ToolUseID).
 			Str("permission_mode", payload.PermissionMode).
 			Msg("permission request bypassed by runtime permission mode")
-		writeJSON(w, http.StatusOK, map[string]string{
+		audit.Decision = permission.DecisionAllow
+		audit.Scope = permission.ScopeSession
+		audit.Source = permission.AuditSourceBypass
+		audit.Message = payload.PermissionMode
+		h.recordAudit(audit)
+		writePermissionResult(w, claudeOutput, map[string]interface{}{
 			"decision": "allow",
 			"scope":    "session",
 			"message":  "bypass_permissions",
@@ -276,104 +379,232 @@ func (h *HooksHandler) handlePermissionRequest(w http.ResponseWriter, payload Cl
 		return
 	}
 
-	// If permission manager is not configured, fallback to "ask" (desktop prompt)
-	if h.permissionManager == nil {
-		log.Warn().Msg("permission manager not configured - returning 'ask'")
-		writeJSON(w, http.StatusOK, map[string]string{
-			"decision": "ask",
-			"message":  "permission_manager_not_configured",
+	// 2. A stored decision answers the request (fast path), then a policy allow
+	if pre.Stored != nil {
+		log.Info().
+			Str("session_id", payload.SessionID).
+			Str("workspace_id", workspaceID).
+			Str("pattern", pre.Stored.Pattern).
+			Str("decision", string(pre.Stored.Decision)).
+			Msg("found matching pattern in memory - returning stored decision")
+
+		h.recordAudit(audit.WithStored(pre.Stored))
+
+		writePermissionResult(w, claudeOutput, map[string]interface{}{
+			"decision": string(pre.Stored.Decision),
+			"scope":    string(pre.Stored.EffectiveScope()),
+			"pattern":  pre.Stored.Pattern,
 		})
 		return
 	}
-
-	// Parse tool_input into map for pattern matching
-	toolInput := make(map[string]interface{})
-	if payload.ToolInput != nil {
-		if inputMap, ok := payload.ToolInput.(map[string]interface{}); ok {
-			toolInput = inputMap
-		}
+	if pre.Decided() {
+		h.recordAudit(audit.WithPolicy(pre.Policy))
+		writePolicyDecision(w, claudeOutput, payload, pre.Policy)
+		return
 	}
 
-	// 1. Check pattern memory for stored decisions (fast path)
-	if stored := h.permissionManager.CheckMemory(payload.SessionID, payload.ToolName, toolInput); stored != nil {
-		log.Info().
-			Str("session_id", payload.SessionID).
-			Str("pattern", stored.Pattern).
-			Str("decision", string(stored.Decision)).
-			Msg("found matching pattern in memory - returning stored decision")
-
-		writeJSON(w, http.StatusOK, map[string]interface{}{
-			"decision": string(stored.Decision),
-			"scope":    "session",
-			"pattern":  stored.Pattern,
+	// If permission manager is not configured, fallback to "ask" (desktop prompt)
+	if h.permissionManager == nil {
+		log.Warn().Msg("permission manager not configured - returning 'ask'")
+		audit.Decision = permission.DecisionAsk
+		audit.Source = permission.AuditSourceUnconfigured
+		h.recordAudit(audit)
+		writePermissionResult(w, claudeOutput, map[string]interface{}{
+			"decision": "ask",
+			"message":  "permission_manager_not_configured",
 		})
 		return
 	}
 
-	// 2. No stored decision - need to ask mobile
-	// Resolve workspace ID from cwd
-	workspaceID := h.resolveWorkspaceID(payload.Cwd)
+	// 4. No stored decision - need to ask mobile
 
 	// Create pending request with response channel
+	route := pre.Route
 	req := &permission.Request{
-		ID:           payload.ToolUseID,
-		SessionID:    payload.SessionID,
-		WorkspaceID:  workspaceID,
-		ToolName:     payload.ToolName,
-		ToolInput:    toolInput,
-		ToolUseID:    payload.ToolUseID,
-		CreatedAt:    time.Now(),
-		ResponseChan: make(chan *permission.Response, 1),
+		ID:                payload.ToolUseID,
+		SessionID:         payload.SessionID,
+		WorkspaceID:       workspaceID,
+		ToolName:          payload.ToolName,
+		ToolInput:         toolInput,
+		ToolUseID:         payload.ToolUseID,
+		CreatedAt:         time.Now(),
+		ResponseChan:      make(chan *permission.Response, 1),
+		RequiredApprovals: route.RequiredApprovals,
+		Risk:              &risk,
+		AgentType:         "claude",
+		Cwd:               payload.Cwd,
 	}
 
 	h.permissionManager.AddPendingRequest(req)
 
-	// 3. Publish pty_permission event to iOS
-	permEvent := h.createPermissionEvent(req, payload)
-	h.hub.Publish(permEvent)
+	// 3. Publish pty_permission event to iOS (primary devices first, if routed)
+	h.publishPermission(req, payload, events.PermissionRouting{TargetDevices: route.Devices})
 
 	log.Info().
 		Str("tool_use_id", payload.ToolUseID).
 		Str("tool_name", payload.ToolName).
+		Strs("target_devices", route.Devices).
+		Int("required_approvals", route.RequiredApprovals).
 		Dur("timeout", h.permissionTimeout).
 		Msg("waiting for mobile response")
 
-	// 4. Wait for response with timeout
+	// 4. Wait for response with timeout, escalating to every device if the
+	// primary devices do not answer
 	ctx, cancel := context.WithTimeout(context.Background(), h.permissionTimeout)
 	defer cancel()
 
-	select {
-	case response := <-req.ResponseChan:
-		// Got response from iOS
-		log.Info().
-			Str("tool_use_id", payload.ToolUseID).
-			Str("decision", string(response.Decision)).
-			Str("scope", string(response.Scope)).
-			Msg("received permission response from mobile")
-
-		writeJSON(w, http.StatusOK, map[string]interface{}{
-			"decision": string(response.Decision),
-			"scope":    string(response.Scope),
-			"pattern":  response.Pattern,
-		})
+	var escalate <-chan time.Time
+	if route.EscalateAfter > 0 && route.EscalateAfter < h.permissionTimeout {
+		escalateTimer := time.NewTimer(route.EscalateAfter)
+		defer escalateTimer.Stop()
+		escalate = escalateTimer.C
+	}
 
-	case <-ctx.Done():
-		// Timeout - return "ask" to let Claude prompt on desktop
-		h.permissionManager.GetAndRemovePendingRequest(payload.ToolUseID)
-		log.Warn().
-			Str("tool_use_id", payload.ToolUseID).
-			Dur("timeout", h.permissionTimeout).
-			Msg("permission request timed out - returning 'ask'")
+	for {
+		select {
+		case <-escalate:
+			escalate = nil
+			log.Info().
+				Str("tool_use_id", payload.ToolUseID).
+				Dur("escalate_after", route.EscalateAfter).
+				Msg("primary devices did not answer - escalating permission request to every device")
+			h.publishPermission(req, payload, events.PermissionRouting{Escalated: true})
+
+		case response := <-req.ResponseChan:
+			// Got response from iOS
+			log.Info().
+				Str("tool_use_id", payload.ToolUseID).
+				Str("decision", string(response.Decision)).
+				Str("scope", string(response.Scope)).
+				Str("responded_by", response.RespondedBy).
+				Msg("received permission response from mobile")
+
+			h.recordAudit(audit.WithResponse(response, permission.AuditSourceDevice))
+
+			result := map[string]interface{}{
+				"decision": string(response.Decision),
+				"scope":    string(response.Scope),
+				"pattern":  response.Pattern,
+			}
+			if response.UpdatedInput != nil {
+				result["updated_input"] = response.UpdatedInput
+			}
+			writePermissionResult(w, claudeOutput, result)
+			return
+
+		case <-ctx.Done():
+			// Timeout - return "ask" to let Claude prompt on desktop, unless
+			// the routing rules decide requests nobody answered
+			h.permissionManager.GetAndRemovePendingRequest(payload.ToolUseID)
+			decision := permission.DecisionAsk
+			if routed, ok := h.router.TimeoutDecision(); ok {
+				decision = routed
+			}
+			log.Warn().
+				Str("tool_use_id", payload.ToolUseID).
+				Dur("timeout", h.permissionTimeout).
+				Str("decision", string(decision)).
+				Msg("permission request timed out")
+
+			audit.Decision = decision
+			audit.Source = permission.AuditSourceTimeout
+			audit.Message = "timeout"
+			h.recordAudit(audit)
+
+			result := map[string]interface{}{
+				"decision": string(decision),
+				"message":  "timeout",
+			}
+			if decision == permission.DecisionDeny {
+				result["reason"] = "No paired device answered in time"
+			}
+			writePermissionResult(w, claudeOutput, result)
+			return
+		}
+	}
+}
 
-		writeJSON(w, http.StatusOK, map[string]string{
-			"decision": "ask",
-			"message":  "timeout",
-		})
+// publishPermission sends the pty_permission prompt of a pending request to
+// the routed devices (every device when none are set) and records which
+// devices were notified.
+func (h *HooksHandler) publishPermission(req *permission.Request, payload ClaudeHookPayload, routing events.PermissionRouting) {
+	notified := routing.TargetDevices
+	if len(notified) == 0 {
+		notified = h.router.ConnectedDevices()
+	}
+	h.permissionManager.MarkNotified(req.ToolUseID, notified, routing.Escalated)
+
+	if req.RequiredApprovals > 1 {
+		routing.RequiredApprovals = req.RequiredApprovals
+	}
+	h.hub.Publish(h.createPermissionEvent(req, payload, routing))
+}
+
+// recordAudit stores a decided permission request in the audit log, if configured.
+// Audit failures are logged but never change the decision.
+func (h *HooksHandler) recordAudit(entry permission.AuditEntry) {
+	if h.audit == nil {
+		return
+	}
+	if err := h.audit.Record(entry); err != nil {
+		log.Warn().Err(err).Str("tool_use_id", entry.ToolUseID).Msg("failed to record permission audit entry")
+	}
+}
+
+// writePolicyDecision returns a policy allow/deny to the hook script.
+func writePolicyDecision(w http.ResponseWriter, claudeOutput bool, payload ClaudeHookPayload, policy *permission.PolicyDecision) {
+	log.Info().
+		Str("session_id", payload.SessionID).
+		Str("tool_use_id", payload.ToolUseID).
+		Str("tool_name", payload.ToolName).
+		Str("policy_source", policy.Source).
+		Str("policy_rule", policy.Rule).
+		Str("decision", string(policy.Decision)).
+		Msg("permission request decided by policy")
+
+	writePermissionResult(w, claudeOutput, map[string]interface{}{
+		"decision":    string(policy.Decision),
+		"scope":       "once",
+		"message":     "policy",
+		"reason":      policy.Reason,
+		"policy_rule": policy.Rule,
+	})
+}
+
+// writePermissionResult writes a permission decision to the hook script, either
+// as cdev's result object or, when claudeOutput is set, as Claude's PreToolUse
+// hook output (including any edited tool input).
+func writePermissionResult(w http.ResponseWriter, claudeOutput bool, result map[string]interface{}) {
+	if !claudeOutput {
+		writeJSON(w, http.StatusOK, result)
+		return
 	}
+
+	decision, _ := result["decision"].(string)
+	reason, _ := result["reason"].(string)
+	if reason == "" {
+		switch decision {
+		case "allow":
+			reason = "Approved by cdev"
+		case "deny":
+			reason = "Denied by cdev"
+		}
+	}
+	output := permission.HookOutput{
+		HookSpecificOutput: permission.HookSpecificOutput{
+			HookEventName:            "PreToolUse",
+			PermissionDecision:       decision,
+			PermissionDecisionReason: reason,
+		},
+	}
+	if updated, ok := result["updated_input"].(map[string]interface{}); ok {
+		output.HookSpecificOutput.UpdatedInput = updated
+	}
+	writeJSON(w, http.StatusOK, output)
 }
 
 // createPermissionEvent creates a pty_permission event to send to iOS.
-func (h *HooksHandler) createPermissionEvent(req *permission.Request, payload ClaudeHookPayload) *events.BaseEvent {
+func (h *HooksHandler) createPermissionEvent(req *permission.Request, payload ClaudeHookPayload, routing events.PermissionRouting) *events.BaseEvent {
 	// Generate human-readable description
 	description := permission.GenerateReadableDescription(req.ToolName, req.ToolInput)
 	target := permission.ExtractTarget(req.ToolName, req.ToolInput)
@@ -396,6 +627,9 @@ func (h *HooksHandler) createPermissionEvent(req *permission.Request, payload Cl
 		Options:     options,
 		SessionID:   req.SessionID,
 		WorkspaceID: req.WorkspaceID,
+		Risk:        req.Risk.ToEvent(),
+
+		PermissionRouting: routing,
 	}
 
 	event := events.NewEvent(events.EventTypePTYPermission, eventPayload)
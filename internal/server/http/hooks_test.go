@@ -33,9 +33,14 @@ type hooksPermissionManager struct {
 	checkMemoryResult *permission.StoredDecision
 	addedRequests     []*permission.Request
 	removedToolUseIDs []string
+
+	notified []string
+
+	// response, if set, answers every pending request immediately.
+	response *permission.Response
 }
 
-func (m *hooksPermissionManager) CheckMemory(sessionID, toolName string, toolInput map[string]interface{}) *permission.StoredDecision {
+func (m *hooksPermissionManager) CheckMemory(sessionID, workspaceID, toolName string, toolInput map[string]interface{}) *permission.StoredDecision {
 	return m.checkMemoryResult
 }
 
@@ -44,6 +49,9 @@ func (m *hooksPermissionManager) StoreDecision(sessionID, workspaceID, pattern s
 
 func (m *hooksPermissionManager) AddPendingRequest(req *permission.Request) {
 	m.addedRequests = append(m.addedRequests, req)
+	if m.response != nil {
+		req.ResponseChan <- m.response
+	}
 }
 
 func (m *hooksPermissionManager) GetAndRemovePendingRequest(toolUseID string) *permission.Request {
@@ -51,6 +59,11 @@ func (m *hooksPermissionManager) GetAndRemovePendingRequest(toolUseID string) *p
 	return nil
 }
 
+func (m *hooksPermissionManager) MarkNotified(toolUseID string, devices []string, escalated bool) bool {
+	m.notified = append(m.notified, devices...)
+	return true
+}
+
 // hooksEventCapture is a hub subscriber that captures published events.
 type hooksEventCapture struct {
 	mu     sync.Mutex
@@ -648,3 +661,173 @@ func TestSetWorkspaceResolver(t *testing.T) {
 		t.Error("expected resolver to be set")
 	}
 }
+
+// staticPolicy implements PolicyEvaluator with a fixed decision.
+type staticPolicy struct {
+	decision *permission.PolicyDecision
+	inputs   []permission.PolicyInput
+}
+
+func (p *staticPolicy) Evaluate(in permission.PolicyInput) *permission.PolicyDecision {
+	p.inputs = append(p.inputs, in)
+	return p.decision
+}
+
+func postPermissionRequest(t *testing.T, handler *HooksHandler, payload ClaudeHookPayload) map[string]string {
+	t.Helper()
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, "/api/hooks/permission-request", strings.NewReader(string(body)))
+	w := httptest.NewRecorder()
+	handler.HandleHook(w, req)
+
+	var result map[string]string
+	_ = json.Unmarshal(w.Body.Bytes(), &result)
+	return result
+}
+
+func TestHandleHook_PermissionRequest_PolicyDenyWinsOverMemory(t *testing.T) {
+	handler, h, _ := setupHooksTest(&hooksWorkspaceResolver{
+		resolveFunc: func(path string) (string, error) { return "ws-policy", nil },
+	})
+	defer stopTestHub(t, h)
+
+	pm := &hooksPermissionManager{checkMemoryResult: &permission.StoredDecision{Pattern: "Bash(git push:*)", Decision: permission.DecisionAllow}}
+	handler.SetPermissionManager(pm)
+	policy := &staticPolicy{decision: &permission.PolicyDecision{Decision: permission.DecisionDeny, Reason: "no force push", Rule: "no-force-push", Source: "global"}}
+	handler.SetPolicyEvaluator(policy)
+
+	result := postPermissionRequest(t, handler, ClaudeHookPayload{
+		SessionID:      "sess-policy",
+		Cwd:            "/repo",
+		ToolName:       "Bash",
+		ToolInput:      map[string]interface{}{"command": "git push --force"},
+		ToolUseID:      "tu-policy-1",
+		PermissionMode: "bypassPermissions",
+	})
+
+	if result["decision"] != "deny" || result["reason"] != "no force push" || result["policy_rule"] != "no-force-push" {
+		t.Fatalf("expected policy deny, got %v", result)
+	}
+	if len(policy.inputs) != 1 || policy.inputs[0].WorkspaceID != "ws-policy" || policy.inputs[0].SessionType != "claude" {
+		t.Errorf("unexpected policy input %+v", policy.inputs)
+	}
+	if len(pm.addedRequests) != 0 {
+		t.Error("expected no pending request for a policy deny")
+	}
+}
+
+func TestHandleHook_PermissionRequest_PolicyAllowAfterMemory(t *testing.T) {
+	handler, h, _ := setupHooksTest(nil)
+	defer stopTestHub(t, h)
+
+	pm := &hooksPermissionManager{checkMemoryResult: &permission.StoredDecision{Pattern: "Read(*.go)", Decision: permission.DecisionDeny}}
+	handler.SetPermissionManager(pm)
+	handler.SetPolicyEvaluator(&staticPolicy{decision: &permission.PolicyDecision{Decision: permission.DecisionAllow, Rule: "reads"}})
+
+	payload := ClaudeHookPayload{SessionID: "sess-policy", ToolName: "Read", ToolUseID: "tu-policy-2"}
+	if result := postPermissionRequest(t, handler, payload); result["decision"] != "deny" {
+		t.Fatalf("expected remembered deny to win over policy allow, got %v", result)
+	}
+
+	pm.checkMemoryResult = nil
+	payload.ToolUseID = "tu-policy-3"
+	if result := postPermissionRequest(t, handler, payload); result["decision"] != "allow" || result["policy_rule"] != "reads" {
+		t.Fatalf("expected policy allow, got %v", result)
+	}
+}
+
+func TestHandleHook_PermissionRequest_ClaudeFormat(t *testing.T) {
+	handler, h, _ := setupHooksTest(nil)
+	defer stopTestHub(t, h)
+
+	handler.SetPermissionManager(&hooksPermissionManager{response: &permission.Response{
+		Decision:     permission.DecisionAllow,
+		Scope:        permission.ScopeOnce,
+		UpdatedInput: map[string]interface{}{"command": "make deploy --dry-run"},
+	}})
+
+	post := func(payload ClaudeHookPayload) permission.HookOutput {
+		t.Helper()
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(http.MethodPost, "/api/hooks/permission-request?format=claude", strings.NewReader(string(body)))
+		w := httptest.NewRecorder()
+		handler.HandleHook(w, req)
+		drainHookEvents()
+
+		var output permission.HookOutput
+		if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return output
+	}
+
+	output := post(ClaudeHookPayload{
+		SessionID: "sess-claude",
+		ToolName:  "Bash",
+		ToolUseID: "tu-claude-1",
+		ToolInput: map[string]interface{}{"command": "make deploy"},
+	})
+	out := output.HookSpecificOutput
+	if out.HookEventName != "PreToolUse" || out.PermissionDecision != "allow" || out.UpdatedInput["command"] != "make deploy --dry-run" {
+		t.Fatalf("unexpected hook output: %+v", out)
+	}
+
+	handler.SetPolicyEvaluator(&staticPolicy{decision: &permission.PolicyDecision{Decision: permission.DecisionDeny, Rule: "no-deploy", Reason: "release freeze"}})
+	output = post(ClaudeHookPayload{SessionID: "sess-claude", ToolName: "Bash", ToolUseID: "tu-claude-2"})
+	if out := output.HookSpecificOutput; out.PermissionDecision != "deny" || !strings.Contains(out.PermissionDecisionReason, "release freeze") {
+		t.Fatalf("unexpected policy hook output: %+v", out)
+	}
+}
+
+type hooksConnectedDevices []string
+
+func (d hooksConnectedDevices) ConnectedDevices() []string { return d }
+
+func TestHandleHook_PermissionRequest_RoutedEscalationAndTimeoutDeny(t *testing.T) {
+	handler, h, capture := setupHooksTest(nil)
+	defer stopTestHub(t, h)
+
+	pm := &hooksPermissionManager{}
+	handler.SetPermissionManager(pm)
+	handler.SetPermissionTimeout(150 * time.Millisecond)
+	router := permission.NewRouter(permission.RoutingConfig{
+		Enabled:        true,
+		PrimaryDevices: []string{"phone"},
+		EscalateAfter:  50 * time.Millisecond,
+	})
+	router.SetDeviceLister(hooksConnectedDevices{"phone", "tablet"})
+	handler.SetPermissionRouter(router)
+
+	body, _ := json.Marshal(ClaudeHookPayload{
+		SessionID: "sess-route",
+		ToolName:  "Bash",
+		ToolUseID: "tu-route-1",
+		ToolInput: map[string]interface{}{"command": "ls"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/hooks/permission-request", strings.NewReader(string(body)))
+	w := httptest.NewRecorder()
+	handler.HandleHook(w, req)
+
+	var result map[string]string
+	_ = json.Unmarshal(w.Body.Bytes(), &result)
+	if result["decision"] != "deny" {
+		t.Errorf("expected routed timeout decision 'deny', got %q", result["decision"])
+	}
+
+	drainHookEvents()
+	var prompts []events.PTYPermissionPayload
+	for _, e := range capture.getEvents() {
+		if base, ok := e.(*events.BaseEvent); ok && e.Type() == events.EventTypePTYPermission {
+			prompts = append(prompts, base.Payload.(events.PTYPermissionPayload))
+		}
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected the prompt to be sent twice (primary, then escalated), got %d", len(prompts))
+	}
+	if len(prompts[0].TargetDevices) != 1 || prompts[0].TargetDevices[0] != "phone" || prompts[0].Escalated {
+		t.Errorf("first prompt routing = %+v, want phone only", prompts[0].PermissionRouting)
+	}
+	if len(prompts[1].TargetDevices) != 0 || !prompts[1].Escalated {
+		t.Errorf("second prompt routing = %+v, want escalated to every device", prompts[1].PermissionRouting)
+	}
+}
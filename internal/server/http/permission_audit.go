@@ -0,0 +1,131 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/rs/zerolog/log"
+)
+
+// AuditQuerier answers permission audit log queries.
+type AuditQuerier interface {
+	Query(filter permission.AuditFilter) ([]permission.AuditEntry, int, error)
+}
+
+// PermissionAuditHandler serves the permission decision audit log.
+type PermissionAuditHandler struct {
+	audit AuditQuerier
+}
+
+// NewPermissionAuditHandler creates a new PermissionAuditHandler.
+func NewPermissionAuditHandler(audit AuditQuerier) *PermissionAuditHandler {
+	return &PermissionAuditHandler{audit: audit}
+}
+
+// RegisterRoutes registers permission audit routes on the given mux.
+func (h *PermissionAuditHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/permissions/audit", h.HandleAudit)
+}
+
+// HandleAudit handles GET /api/permissions/audit
+//
+//	@Summary		Query the permission audit log
+//	@Description	Returns recorded permission decisions, newest first. format=jsonl or format=csv downloads an export.
+//	@Tags			permissions
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Filter by workspace ID"
+//	@Param			session_id		query		string	false	"Filter by session ID"
+//	@Param			tool			query		string	false	"Filter by tool name"
+//	@Param			decision		query		string	false	"Filter by decision (allow, deny, ask)"
+//	@Param			source			query		string	false	"Filter by decision source"
+//	@Param			risk			query		string	false	"Filter by risk tier (low, medium, high, critical)"
+//	@Param			device			query		string	false	"Filter by responding device"
+//	@Param			modified		query		bool	false	"Only approvals with edited input"
+//	@Param			since			query		string	false	"RFC3339 lower bound"
+//	@Param			until			query		string	false	"RFC3339 upper bound"
+//	@Param			limit			query		int		false	"Page size (default 100 for json)"
+//	@Param			offset			query		int		false	"Offset"
+//	@Param			format			query		string	false	"json, jsonl or csv"
+//	@Success		200				{object}	map[string]interface{}
+//	@Router			/api/permissions/audit [get]
+func (h *PermissionAuditHandler) HandleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = permission.AuditFormatJSON
+	}
+	if !permission.ValidAuditFormat(format) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "format must be json, jsonl or csv"})
+		return
+	}
+
+	filter := permission.AuditFilter{
+		WorkspaceID: q.Get("workspace_id"),
+		SessionID:   q.Get("session_id"),
+		ToolName:    q.Get("tool"),
+		Decision:    q.Get("decision"),
+		Source:      q.Get("source"),
+		Risk:        q.Get("risk"),
+		RespondedBy: q.Get("device"),
+		Modified:    q.Get("modified") == "true",
+	}
+	for name, dst := range map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": name + " must be a non-negative integer"})
+				return
+			}
+			*dst = n
+		}
+	}
+	for name, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": name + " must be an RFC3339 timestamp"})
+				return
+			}
+			*dst = t
+		}
+	}
+	if format == permission.AuditFormatJSON && filter.Limit == 0 {
+		filter.Limit = 100
+	}
+
+	entries, total, err := h.audit.Query(filter)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to query permission audit log")
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "failed to query audit log"})
+		return
+	}
+
+	if format == permission.AuditFormatJSON {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"entries": entries,
+			"count":   len(entries),
+			"total":   total,
+		})
+		return
+	}
+
+	contentType := "application/x-ndjson"
+	if format == permission.AuditFormatCSV {
+		contentType = "text/csv"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+		"permission-audit-"+time.Now().UTC().Format("20060102-150405")+"."+format))
+	w.WriteHeader(http.StatusOK)
+	if err := permission.WriteAuditExport(w, entries, format); err != nil {
+		log.Warn().Err(err).Msg("failed to write permission audit export")
+	}
+}
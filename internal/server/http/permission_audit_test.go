@@ -0,0 +1,82 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/permission"
+)
+
+func TestPermissionAudit_HookDecisionsAreQueryable(t *testing.T) {
+	handler, h, _ := setupHooksTest(&hooksWorkspaceResolver{
+		resolveFunc: func(path string) (string, error) { return "ws-audit", nil },
+	})
+	defer stopTestHub(t, h)
+
+	audit, err := permission.OpenAuditLog(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatalf("OpenAuditLog: %v", err)
+	}
+	defer func() { _ = audit.Close() }()
+	handler.SetAuditRecorder(audit)
+	handler.SetPermissionManager(&hooksPermissionManager{})
+	handler.SetPolicyEvaluator(&staticPolicy{decision: &permission.PolicyDecision{Decision: permission.DecisionDeny, Rule: "no-rm", Reason: "destructive"}})
+
+	postPermissionRequest(t, handler, ClaudeHookPayload{
+		SessionID: "sess-audit",
+		Cwd:       "/repo",
+		ToolName:  "Bash",
+		ToolInput: map[string]interface{}{"command": "rm -rf /"},
+		ToolUseID: "tu-audit-1",
+	})
+
+	auditHandler := NewPermissionAuditHandler(audit)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/permissions/audit?workspace_id=ws-audit&decision=deny", nil)
+	w := httptest.NewRecorder()
+	auditHandler.HandleAudit(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var result struct {
+		Entries []permission.AuditEntry `json:"entries"`
+		Total   int                     `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if result.Total != 1 || len(result.Entries) != 1 {
+		t.Fatalf("expected one entry, got %+v", result)
+	}
+	entry := result.Entries[0]
+	if entry.Source != permission.AuditSourcePolicy || entry.PolicyRule != "no-rm" || entry.Channel != "hook" || entry.Target != "rm -rf /" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/permissions/audit?format=jsonl", nil)
+	w = httptest.NewRecorder()
+	auditHandler.HandleAudit(w, req)
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(w.Header().Get("Content-Disposition"), "attachment") {
+		t.Error("export should be served as an attachment")
+	}
+	if lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n"); len(lines) != 1 {
+		t.Errorf("expected one jsonl line, got %d", len(lines))
+	}
+
+	for _, query := range []string{"format=xml", "limit=abc", "since=yesterday"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/permissions/audit?"+query, nil)
+		w = httptest.NewRecorder()
+		auditHandler.HandleAudit(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
@@ -38,6 +38,9 @@ import (
 // WebSocketHandler is a function that handles WebSocket connections.
 type WebSocketHandler func(http.ResponseWriter, *http.Request)
 
+// EventStreamHandler is a function that serves events as Server-Sent Events.
+type EventStreamHandler func(http.ResponseWriter, *http.Request)
+
 // Server is the HTTP API server.
 type Server struct {
 	server                 *http.Server
@@ -56,6 +59,8 @@ type Server struct {
 	maxDiffSizeKB          int
 	repoPath               string
 	wsHandler              WebSocketHandler
+	eventStreamHandler     EventStreamHandler
+	mcpHandler             http.Handler
 	rpcRegistry            *handler.Registry
 	imageHandler           *ImageHandler
 	imageStorageManager    *imagestorage.Manager
@@ -215,6 +220,16 @@ func (s *Server) SetTaskHandler(handler *TaskHandler) {
 	log.Info().Msg("agent task routes registered: /api/tasks/*")
 }
 
+// SetPermissionAuditHandler registers the permission audit log routes.
+func (s *Server) SetPermissionAuditHandler(handler *PermissionAuditHandler) {
+	if handler == nil {
+		log.Warn().Msg("permission audit handler is nil, audit routes will not be available")
+		return
+	}
+	handler.RegisterRoutes(s.mux)
+	log.Info().Msg("permission audit routes registered: /api/permissions/audit")
+}
+
 // SetHooksHandler sets up Claude hooks endpoints for receiving events from external Claude sessions.
 // This enables real-time event capture from Claude running in VS Code, Cursor, or terminal.
 func (s *Server) SetHooksHandler(handler *HooksHandler) {
@@ -405,11 +420,11 @@ func timeoutMiddleware(timeout time.Duration, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Skip timeout for certain paths that need longer processing
 		// (e.g., swagger UI, health checks, WebSocket upgrades, debug/pprof endpoints,
-		// permission requests that block waiting for mobile response)
-		if r.URL.Path == "/health" || r.URL.Path == "/ws" ||
+		// permission requests and MCP tool calls that block waiting for mobile response)
+		if r.URL.Path == "/health" || r.URL.Path == "/ws" || r.URL.Path == "/api/events" ||
 			strings.HasPrefix(r.URL.Path, "/swagger/") ||
 			strings.HasPrefix(r.URL.Path, "/debug/") ||
-			r.URL.Path == "/api/hooks/permission-request" {
+			r.URL.Path == "/api/hooks/permission-request" || r.URL.Path == "/mcp" {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -732,6 +747,18 @@ func (s *Server) SetWebSocketHandler(handler WebSocketHandler) {
 	s.wsHandler = handler
 }
 
+// SetEventStreamHandler sets the handler for the /api/events SSE stream.
+// Must be called before Start().
+func (s *Server) SetEventStreamHandler(handler EventStreamHandler) {
+	s.eventStreamHandler = handler
+}
+
+// SetMCPHandler sets the handler for the /mcp Model Context Protocol
+// endpoint. Must be called before Start().
+func (s *Server) SetMCPHandler(handler http.Handler) {
+	s.mcpHandler = handler
+}
+
 func (s *Server) Start() error {
 	// Add WebSocket handler if set (must be done before creating http.Server)
 	if s.wsHandler != nil {
@@ -747,6 +774,14 @@ func (s *Server) Start() error {
 		})
 		log.Debug().Msg("WebSocket handler registered at /ws")
 	}
+	if s.eventStreamHandler != nil {
+		s.mux.HandleFunc("/api/events", s.eventStreamHandler)
+		log.Debug().Msg("Event stream handler registered at /api/events")
+	}
+	if s.mcpHandler != nil {
+		s.mux.Handle("/mcp", s.mcpHandler)
+		log.Debug().Msg("MCP handler registered at /mcp")
+	}
 
 	// Build middleware chain from inside out:
 	// request -> secure transport -> auth -> root redirect -> pair token -> cors -> timeout -> rate limit (optional) -> logging -> mux
@@ -818,6 +853,10 @@ func (s *Server) Stop(ctx context.Context) error {
 		s.imageStorageManager.Close()
 	}
 
+	// Not started, e.g. when serving a local transport only
+	if s.server == nil {
+		return nil
+	}
 	return s.server.Shutdown(ctx)
 }
 
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxHTTPMessageSize bounds the size of a POSTed MCP message.
+const maxHTTPMessageSize = 1 << 20
+
+// ServeHTTP serves the streamable HTTP transport of MCP at a single
+// endpoint. Each POST carries one message or a batch and is answered with
+// a JSON body, or 202 Accepted for notifications. The server keeps no
+// session and never opens a stream, so GET is refused as the protocol
+// allows. Authentication and Origin checks are left to the HTTP server.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxHTTPMessageSize+1))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxHTTPMessageSize {
+		http.Error(w, "Message too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	resp, err := s.HandleMessage(r.Context(), data)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to handle MCP message")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resp)
+}
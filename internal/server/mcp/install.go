@@ -0,0 +1,234 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Clients whose configuration the installer can edit.
+const (
+	ClientClaude = "claude"
+	ClientCodex  = "codex"
+)
+
+// StdioArgs are the arguments an MCP client starts cdev with.
+var StdioArgs = []string{"mcp", "--stdio"}
+
+// Installer registers cdev as an MCP server in the user-scope configuration
+// of Claude Code (~/.claude.json) and Codex (~/.codex/config.toml). Other
+// servers and settings are preserved, and a backup of the previous file is
+// kept next to it.
+type Installer struct {
+	command      string
+	claudeConfig string
+	codexConfig  string
+}
+
+// NewInstaller creates an installer registering command, an absolute path
+// to the cdev binary, under the given home directory.
+func NewInstaller(homeDir, command string) *Installer {
+	return &Installer{
+		command:      command,
+		claudeConfig: filepath.Join(homeDir, ".claude.json"),
+		codexConfig:  filepath.Join(homeDir, ".codex", "config.toml"),
+	}
+}
+
+// ConfigPath returns the configuration file edited for a client.
+func (i *Installer) ConfigPath(client string) string {
+	if client == ClientCodex {
+		return i.codexConfig
+	}
+	return i.claudeConfig
+}
+
+// Install registers cdev in a client's configuration, replacing a previous
+// registration.
+func (i *Installer) Install(client string) error {
+	switch client {
+	case ClientClaude:
+		return i.editClaude(func(servers map[string]interface{}) {
+			servers[ServerName] = map[string]interface{}{
+				"type":    "stdio",
+				"command": i.command,
+				"args":    StdioArgs,
+			}
+		})
+	case ClientCodex:
+		return i.editCodex(true)
+	default:
+		return fmt.Errorf("unknown client %q: expected claude or codex", client)
+	}
+}
+
+// Uninstall removes cdev from a client's configuration. A missing
+// configuration file is not an error.
+func (i *Installer) Uninstall(client string) error {
+	switch client {
+	case ClientClaude:
+		if _, err := os.Stat(i.claudeConfig); os.IsNotExist(err) {
+			return nil
+		}
+		return i.editClaude(func(servers map[string]interface{}) {
+			delete(servers, ServerName)
+		})
+	case ClientCodex:
+		return i.editCodex(false)
+	default:
+		return fmt.Errorf("unknown client %q: expected claude or codex", client)
+	}
+}
+
+// IsInstalled reports whether cdev is registered in a client's configuration.
+func (i *Installer) IsInstalled(client string) bool {
+	data, err := os.ReadFile(i.ConfigPath(client))
+	if err != nil {
+		return false
+	}
+	if client == ClientCodex {
+		_, _, found := findTOMLTable(string(data), codexTable)
+		return found
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return false
+	}
+	servers, _ := config["mcpServers"].(map[string]interface{})
+	_, ok := servers[ServerName]
+	return ok
+}
+
+// editClaude applies edit to the mcpServers object of ~/.claude.json.
+func (i *Installer) editClaude(edit func(servers map[string]interface{})) error {
+	config := map[string]interface{}{}
+	data, err := os.ReadFile(i.claudeConfig)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", i.claudeConfig, err)
+	}
+	if err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", i.claudeConfig, err)
+		}
+	}
+
+	servers, _ := config["mcpServers"].(map[string]interface{})
+	if servers == nil {
+		servers = map[string]interface{}{}
+	}
+	edit(servers)
+	if len(servers) == 0 {
+		delete(config, "mcpServers")
+	} else {
+		config["mcpServers"] = servers
+	}
+
+	out, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return writeConfig(i.claudeConfig, append(out, '\n'))
+}
+
+// codexTable is the TOML table of the cdev server in Codex's config.
+var codexTable = "mcp_servers." + ServerName
+
+// editCodex removes the cdev table from ~/.codex/config.toml, then appends
+// it again when install is set. Codex's config is edited as text so that
+// comments and formatting of the rest of the file survive.
+func (i *Installer) editCodex(install bool) error {
+	data, err := os.ReadFile(i.codexConfig)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", i.codexConfig, err)
+	}
+	if os.IsNotExist(err) && !install {
+		return nil
+	}
+
+	content := removeTOMLTable(string(data), codexTable)
+	if install {
+		args := make([]string, len(StdioArgs))
+		for n, arg := range StdioArgs {
+			args[n] = strconv.Quote(arg)
+		}
+		content = strings.TrimRight(content, "\n")
+		if content != "" {
+			content += "\n\n"
+		}
+		content += fmt.Sprintf("[%s]\ncommand = %s\nargs = [%s]\n",
+			codexTable, strconv.Quote(i.command), strings.Join(args, ", "))
+	}
+	return writeConfig(i.codexConfig, []byte(content))
+}
+
+// findTOMLTable returns the byte range of a table and its sub-tables, from
+// its header to the next unrelated header.
+func findTOMLTable(content, table string) (start, end int, found bool) {
+	offset := 0
+	start = -1
+	for _, line := range strings.SplitAfter(content, "\n") {
+		header := strings.TrimSpace(line)
+		if strings.HasPrefix(header, "[") {
+			name := strings.Trim(header, "[] \t")
+			if i := strings.Index(name, "]"); i >= 0 {
+				name = strings.TrimSpace(name[:i])
+			}
+			inTable := name == table || strings.HasPrefix(name, table+".")
+			if start < 0 && inTable {
+				start = offset
+			} else if start >= 0 && !inTable {
+				return start, offset, true
+			}
+		}
+		offset += len(line)
+	}
+	if start < 0 {
+		return 0, 0, false
+	}
+	return start, len(content), true
+}
+
+// removeTOMLTable removes a table and its sub-tables from content.
+func removeTOMLTable(content, table string) string {
+	start, end, found := findTOMLTable(content, table)
+	if !found {
+		return content
+	}
+	return content[:start] + content[end:]
+}
+
+// writeConfig writes a client configuration atomically, keeping a backup of
+// the previous content.
+func writeConfig(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	mode := os.FileMode(0644)
+	if existing, err := os.ReadFile(path); err == nil {
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		backupPath := path + ".cdev-backup"
+		if err := os.WriteFile(backupPath, existing, mode); err != nil {
+			log.Warn().Err(err).Str("backup", backupPath).Msg("failed to create backup")
+		}
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, mode); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	log.Debug().Str("path", path).Msg("updated MCP client config")
+	return nil
+}
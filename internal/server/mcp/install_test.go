@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstaller_Claude(t *testing.T) {
+	home := t.TempDir()
+	path := filepath.Join(home, ".claude.json")
+	existing := `{"numStartups": 3, "mcpServers": {"other": {"type": "stdio", "command": "other"}}}`
+	if err := os.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+	installer := NewInstaller(home, "/usr/local/bin/cdev")
+
+	if err := installer.Install(ClientClaude); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	if !installer.IsInstalled(ClientClaude) {
+		t.Fatal("IsInstalled = false after Install")
+	}
+
+	var config map[string]interface{}
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("invalid JSON written: %v", err)
+	}
+	servers := config["mcpServers"].(map[string]interface{})
+	cdev := servers["cdev"].(map[string]interface{})
+	if cdev["command"] != "/usr/local/bin/cdev" || config["numStartups"] != float64(3) || servers["other"] == nil {
+		t.Fatalf("unexpected config %s", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want the original 0600", info.Mode().Perm())
+	}
+	if _, err := os.Stat(path + ".cdev-backup"); err != nil {
+		t.Errorf("no backup: %v", err)
+	}
+
+	if err := installer.Uninstall(ClientClaude); err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+	if installer.IsInstalled(ClientClaude) {
+		t.Fatal("IsInstalled = true after Uninstall")
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), `"other"`) {
+		t.Fatalf("Uninstall removed other servers: %s", data)
+	}
+}
+
+func TestInstaller_Codex(t *testing.T) {
+	home := t.TempDir()
+	path := filepath.Join(home, ".codex", "config.toml")
+	_ = os.MkdirAll(filepath.Dir(path), 0755)
+	existing := `model = "o3" # keep this comment
+
+[mcp_servers.cdev]
+command = "/old/cdev"
+args = ["serve"]
+
+[mcp_servers.cdev.env]
+FOO = "bar"
+
+[mcp_servers.other]
+command = "other"
+`
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	installer := NewInstaller(home, "/usr/local/bin/cdev")
+
+	if err := installer.Install(ClientCodex); err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, want := range []string{
+		`model = "o3" # keep this comment`,
+		"[mcp_servers.other]\ncommand = \"other\"",
+		"[mcp_servers.cdev]\ncommand = \"/usr/local/bin/cdev\"\nargs = [\"mcp\", \"--stdio\"]\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("config lacks %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "/old/cdev") || strings.Contains(content, "FOO") || strings.Count(content, "[mcp_servers.cdev]") != 1 {
+		t.Errorf("previous registration not replaced:\n%s", content)
+	}
+
+	if err := installer.Uninstall(ClientCodex); err != nil {
+		t.Fatalf("Uninstall: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if installer.IsInstalled(ClientCodex) || !strings.Contains(string(data), "[mcp_servers.other]") {
+		t.Fatalf("unexpected config after Uninstall:\n%s", data)
+	}
+}
+
+func TestInstaller_UninstallWithoutConfig(t *testing.T) {
+	home := t.TempDir()
+	installer := NewInstaller(home, "/usr/local/bin/cdev")
+	if err := installer.Uninstall(ClientCodex); err != nil {
+		t.Errorf("Uninstall(codex): %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".codex")); !os.IsNotExist(err) {
+		t.Error("Uninstall created the Codex config directory")
+	}
+	if err := installer.Uninstall(ClientClaude); err != nil {
+		t.Errorf("Uninstall(claude): %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".claude.json")); !os.IsNotExist(err) {
+		t.Error("Uninstall created the Claude config")
+	}
+	if err := installer.Install("cursor"); err == nil {
+		t.Error("expected an error for an unknown client")
+	}
+}
@@ -0,0 +1,304 @@
+// Package mcp serves a curated subset of the cdev JSON-RPC registry as a
+// Model Context Protocol server, so agents can query other workspaces and
+// create tasks through cdev.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/brianly1003/cdev/internal/rpc/transport"
+	"github.com/rs/zerolog/log"
+)
+
+// ServerName is the name cdev registers under in MCP client configurations.
+// Tool names seen by the permission policy are mcp__<ServerName>__<tool>.
+const ServerName = "cdev"
+
+// ProtocolVersions are the MCP protocol revisions the server speaks, newest
+// first.
+var ProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// Authorizer decides whether a tool call may run. A nil response lets it run.
+// readOnly reports whether the tool only reads state.
+type Authorizer interface {
+	AuthorizeToolCall(ctx context.Context, channel, workspaceID, toolName string, toolInput map[string]interface{}, readOnly bool) *permission.Response
+}
+
+// WorkspaceResolver maps a workspace ID, name or path to its ID.
+type WorkspaceResolver interface {
+	ResolveWorkspaceID(idOrName string) (string, error)
+}
+
+// Server is an MCP server backed by the cdev method registry.
+type Server struct {
+	methods    *handler.Registry
+	dispatcher *handler.Dispatcher
+	tools      []Tool
+	authorizer Authorizer
+	resolver   WorkspaceResolver
+	version    string
+}
+
+// NewServer creates an MCP server exposing the tools whose methods are
+// registered in methods. Call it once the registry is complete.
+func NewServer(methods *handler.Registry, version string) *Server {
+	s := &Server{
+		methods: methods,
+		version: version,
+	}
+	for _, tool := range curatedTools {
+		if !methods.Has(tool.method) {
+			continue
+		}
+		tool.InputSchema = inputSchema(methods.GetMeta(tool.method), tool)
+		s.tools = append(s.tools, tool)
+	}
+
+	protocol := handler.NewRegistry()
+	protocol.Register("initialize", s.initialize)
+	protocol.Register("notifications/initialized", s.initialized)
+	protocol.Register("ping", s.ping)
+	protocol.Register("tools/list", s.listTools)
+	protocol.Register("tools/call", s.callTool)
+	s.dispatcher = handler.NewDispatcher(protocol)
+	return s
+}
+
+// SetAuthorizer sets the permission check run before each tool call.
+func (s *Server) SetAuthorizer(authorizer Authorizer) {
+	s.authorizer = authorizer
+}
+
+// SetWorkspaceResolver lets tools accept workspace names and paths.
+func (s *Server) SetWorkspaceResolver(resolver WorkspaceResolver) {
+	s.resolver = resolver
+}
+
+// Tools returns the tools the server exposes.
+func (s *Server) Tools() []Tool {
+	return s.tools
+}
+
+// HandleMessage handles a single MCP message or a batch and returns the
+// response, or nil when there is nothing to answer.
+func (s *Server) HandleMessage(ctx context.Context, data []byte) ([]byte, error) {
+	return s.dispatcher.HandleMessage(ctx, data)
+}
+
+// ServeTransport serves MCP over a transport, e.g. stdin/stdout for
+// `cdev mcp --stdio`. Messages are handled concurrently, so a tool call
+// waiting for a permission decision does not hold up pings. It blocks until
+// the transport reaches EOF and the pending responses are written, or ctx is
+// cancelled.
+func (s *Server) ServeTransport(ctx context.Context, t transport.Transport) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		data, err := t.Read(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, transport.ErrTransportClosed) {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := s.HandleMessage(ctx, data)
+			if err != nil {
+				log.Warn().Err(err).Msg("failed to handle MCP message")
+				return
+			}
+			if resp == nil {
+				return
+			}
+			if err := t.Write(ctx, resp); err != nil {
+				log.Debug().Err(err).Msg("failed to write MCP response")
+			}
+		}()
+	}
+}
+
+// InitializeParams for the initialize request.
+type InitializeParams struct {
+	ProtocolVersion string `json:"protocolVersion"`
+	ClientInfo      struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"clientInfo"`
+}
+
+func (s *Server) initialize(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	var p InitializeParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, message.ErrInvalidParams("failed to parse params: " + err.Error())
+		}
+	}
+
+	// Answer with the requested revision when supported, else the newest
+	version := ProtocolVersions[0]
+	for _, v := range ProtocolVersions {
+		if v == p.ProtocolVersion {
+			version = v
+			break
+		}
+	}
+
+	log.Info().
+		Str("client", p.ClientInfo.Name).
+		Str("client_version", p.ClientInfo.Version).
+		Str("protocol_version", version).
+		Msg("MCP client initialized")
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools": map[string]interface{}{"listChanged": false},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    ServerName,
+			"version": s.version,
+		},
+		"instructions": "cdev manages the workspaces of this machine. Use its tools to look at other workspaces' files, git state and agent session history, and to create agent tasks. Workspaces are referred to by ID, name or path; list_workspaces lists them.",
+	}, nil
+}
+
+func (s *Server) initialized(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	return nil, nil
+}
+
+func (s *Server) ping(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	return map[string]interface{}{}, nil
+}
+
+func (s *Server) listTools(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	return map[string]interface{}{"tools": s.tools}, nil
+}
+
+// CallToolParams for the tools/call request.
+type CallToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// CallToolResult is the result of tools/call. Failures of the tool itself
+// are results with IsError set, so the agent sees them.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError"`
+}
+
+// Content is a text content block.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func toolError(text string) *CallToolResult {
+	return &CallToolResult{Content: []Content{{Type: "text", Text: text}}, IsError: true}
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+	var p CallToolParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, message.ErrInvalidParams("failed to parse params: " + err.Error())
+	}
+	tool := s.tool(p.Name)
+	if tool == nil {
+		return nil, message.ErrInvalidParams("unknown tool: " + p.Name)
+	}
+
+	args := map[string]interface{}{}
+	if len(p.Arguments) > 0 && string(p.Arguments) != "null" {
+		if err := json.Unmarshal(p.Arguments, &args); err != nil {
+			return nil, message.ErrInvalidParams("arguments must be an object")
+		}
+	}
+
+	workspaceID, _ := args["workspace_id"].(string)
+	if workspaceID != "" && s.resolver != nil {
+		resolved, err := s.resolver.ResolveWorkspaceID(workspaceID)
+		if err != nil {
+			return toolError("workspace not found: " + workspaceID), nil
+		}
+		workspaceID = resolved
+		args["workspace_id"] = resolved
+	}
+
+	toolName := "mcp__" + ServerName + "__" + tool.Name
+	policyAllowed := false
+	if s.authorizer != nil {
+		resp := s.authorizer.AuthorizeToolCall(ctx, ServerName, workspaceID, toolName, args, tool.readOnly())
+		if resp != nil && resp.Decision != permission.DecisionAllow {
+			reason := resp.Message
+			if reason == "" {
+				reason = "denied"
+			}
+			log.Info().Str("tool", toolName).Str("workspace_id", workspaceID).Str("reason", reason).Msg("MCP tool call refused")
+			return toolError("permission denied: " + reason), nil
+		}
+		policyAllowed = resp != nil && resp.PolicyRule != ""
+	}
+
+	// Only the tool's own arguments reach the method
+	methodArgs := map[string]interface{}{}
+	for _, name := range tool.params {
+		if value, ok := args[name]; ok {
+			methodArgs[name] = value
+		}
+	}
+	for name, value := range tool.fixed {
+		methodArgs[name] = value
+	}
+	if !policyAllowed {
+		for name, value := range tool.capped {
+			methodArgs[name] = value
+		}
+	}
+	methodParams, err := json.Marshal(methodArgs)
+	if err != nil {
+		return nil, message.ErrInternalError("failed to encode arguments")
+	}
+
+	call := s.methods.Get(tool.method)
+	if call == nil {
+		return toolError("tool unavailable: " + tool.Name), nil
+	}
+	result, rpcErr := call(ctx, methodParams)
+	if rpcErr != nil {
+		return toolError(rpcErr.Message), nil
+	}
+
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, message.ErrInternalError("failed to encode result")
+	}
+	return &CallToolResult{Content: []Content{{Type: "text", Text: string(text)}}}, nil
+}
+
+// readOnly reports whether the tool only reads state.
+func (t *Tool) readOnly() bool {
+	return t.Annotations != nil && t.Annotations.ReadOnlyHint
+}
+
+func (s *Server) tool(name string) *Tool {
+	for i := range s.tools {
+		if s.tools[i].Name == name {
+			return &s.tools[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/permission"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/brianly1003/cdev/internal/rpc/transport"
+)
+
+// newTestServer returns a server over a registry with git/status and
+// task/create, whose handlers echo their params.
+func newTestServer() *Server {
+	registry := handler.NewRegistry()
+	echo := func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		var p map[string]interface{}
+		_ = json.Unmarshal(params, &p)
+		if p["workspace_id"] == "broken" {
+			return nil, message.ErrInternalError("not a git repository")
+		}
+		return p, nil
+	}
+	registry.RegisterWithMeta("git/status", echo, handler.MethodMeta{
+		Params: []handler.OpenRPCParam{
+			{Name: "workspace_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+		},
+	})
+	registry.RegisterWithMeta("task/create", echo, handler.MethodMeta{
+		Params: []handler.OpenRPCParam{
+			{Name: "workspace_id", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "title", Required: true, Schema: map[string]interface{}{"type": "string"}},
+			{Name: "autonomy", Schema: map[string]interface{}{"type": "string"}},
+			{Name: "created_by", Schema: map[string]interface{}{"type": "string"}},
+		},
+	})
+	return NewServer(registry, "test")
+}
+
+func call(t *testing.T, s *Server, method string, params interface{}) map[string]interface{} {
+	t.Helper()
+	req, _ := message.NewRequest(message.NumberID(1), method, params)
+	data, _ := json.Marshal(req)
+	resp, err := s.HandleMessage(context.Background(), data)
+	if err != nil {
+		t.Fatalf("HandleMessage: %v", err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(resp, &out); err != nil {
+		t.Fatalf("invalid response %q: %v", resp, err)
+	}
+	return out
+}
+
+func callTool(t *testing.T, s *Server, name string, args map[string]interface{}) (string, bool) {
+	t.Helper()
+	resp := call(t, s, "tools/call", map[string]interface{}{"name": name, "arguments": args})
+	result, ok := resp["result"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("tools/call %s failed: %v", name, resp)
+	}
+	content := result["content"].([]interface{})[0].(map[string]interface{})
+	return content["text"].(string), result["isError"] == true
+}
+
+type mockResolver struct{}
+
+func (mockResolver) ResolveWorkspaceID(idOrName string) (string, error) {
+	if idOrName == "backend" {
+		return "ws-1", nil
+	}
+	return "", fmt.Errorf("workspace not found")
+}
+
+type mockAuthorizer struct {
+	toolName    string
+	workspaceID string
+	readOnly    bool
+	response    *permission.Response
+}
+
+func (m *mockAuthorizer) AuthorizeToolCall(ctx context.Context, channel, workspaceID, toolName string, toolInput map[string]interface{}, readOnly bool) *permission.Response {
+	m.toolName = toolName
+	m.workspaceID = workspaceID
+	m.readOnly = readOnly
+	return m.response
+}
+
+func TestServer_Initialize(t *testing.T) {
+	s := newTestServer()
+
+	result := call(t, s, "initialize", map[string]interface{}{"protocolVersion": "2025-03-26"})["result"].(map[string]interface{})
+	if result["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v, want the requested one", result["protocolVersion"])
+	}
+	if _, ok := result["capabilities"].(map[string]interface{})["tools"]; !ok {
+		t.Errorf("capabilities = %v, want tools", result["capabilities"])
+	}
+
+	result = call(t, s, "initialize", map[string]interface{}{"protocolVersion": "1999-01-01"})["result"].(map[string]interface{})
+	if result["protocolVersion"] != ProtocolVersions[0] {
+		t.Errorf("protocolVersion = %v, want the newest for an unknown revision", result["protocolVersion"])
+	}
+
+	resp, _ := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	if resp != nil {
+		t.Errorf("notification answered with %s", resp)
+	}
+}
+
+func TestServer_ListTools(t *testing.T) {
+	s := newTestServer()
+
+	tools := call(t, s, "tools/list", nil)["result"].(map[string]interface{})["tools"].([]interface{})
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.(map[string]interface{})["name"].(string))
+	}
+	// Only tools whose method is registered are listed
+	if strings.Join(names, ",") != "git_status,create_task" {
+		t.Fatalf("tools = %v, want git_status and create_task", names)
+	}
+
+	schema := tools[1].(map[string]interface{})["inputSchema"].(map[string]interface{})
+	properties := schema["properties"].(map[string]interface{})
+	if _, ok := properties["created_by"]; ok {
+		t.Error("created_by is set by the server and must not be an argument")
+	}
+	if fmt.Sprint(schema["required"]) != "[workspace_id title]" {
+		t.Errorf("required = %v, want workspace_id and title", schema["required"])
+	}
+}
+
+func TestServer_CallTool(t *testing.T) {
+	s := newTestServer()
+	s.SetWorkspaceResolver(mockResolver{})
+
+	text, isError := callTool(t, s, "create_task", map[string]interface{}{
+		"workspace_id": "backend",
+		"title":        "Follow up",
+		"created_by":   "someone",
+		"unknown":      true,
+	})
+	if isError {
+		t.Fatalf("create_task failed: %s", text)
+	}
+	var params map[string]interface{}
+	_ = json.Unmarshal([]byte(text), &params)
+	if params["workspace_id"] != "ws-1" || params["created_by"] != "mcp" || params["unknown"] != nil {
+		t.Errorf("method params = %v, want the resolved workspace, created_by mcp and no unknown argument", params)
+	}
+
+	if text, isError := callTool(t, s, "git_status", map[string]interface{}{"workspace_id": "nowhere"}); !isError || !strings.Contains(text, "workspace not found") {
+		t.Errorf("unknown workspace: %q, isError=%v", text, isError)
+	}
+
+	s.SetWorkspaceResolver(nil)
+	if text, isError := callTool(t, s, "git_status", map[string]interface{}{"workspace_id": "broken"}); !isError || text != "not a git repository" {
+		t.Errorf("method error: %q, isError=%v", text, isError)
+	}
+
+	if resp := call(t, s, "tools/call", map[string]interface{}{"name": "run_shell"}); resp["error"] == nil {
+		t.Errorf("unknown tool answered with %v", resp)
+	}
+}
+
+func TestServer_CallTool_Authorizer(t *testing.T) {
+	s := newTestServer()
+	authorizer := &mockAuthorizer{}
+	s.SetAuthorizer(authorizer)
+
+	if _, isError := callTool(t, s, "git_status", map[string]interface{}{"workspace_id": "ws-1"}); isError {
+		t.Fatal("call refused without a decision")
+	}
+	if authorizer.toolName != "mcp__cdev__git_status" || authorizer.workspaceID != "ws-1" || !authorizer.readOnly {
+		t.Errorf("authorized %s in %s (read-only %v), want read-only mcp__cdev__git_status in ws-1", authorizer.toolName, authorizer.workspaceID, authorizer.readOnly)
+	}
+
+	authorizer.response = &permission.Response{Decision: permission.DecisionDeny, Message: "not today"}
+	text, isError := callTool(t, s, "git_status", map[string]interface{}{"workspace_id": "ws-1"})
+	if !isError || text != "permission denied: not today" {
+		t.Errorf("denied call: %q, isError=%v", text, isError)
+	}
+
+	// Tasks are manual unless a policy rule allowed the call.
+	autonomy := func() interface{} {
+		t.Helper()
+		text, isError := callTool(t, s, "create_task", map[string]interface{}{"workspace_id": "ws-1", "title": "t", "autonomy": "full-auto-bounded"})
+		if isError {
+			t.Fatalf("create_task refused: %s", text)
+		}
+		var params map[string]interface{}
+		_ = json.Unmarshal([]byte(text), &params)
+		return params["autonomy"]
+	}
+	authorizer.response = &permission.Response{Decision: permission.DecisionAllow}
+	if got := autonomy(); got != "manual" || authorizer.readOnly {
+		t.Errorf("autonomy = %v after an approval (read-only %v), want manual", got, authorizer.readOnly)
+	}
+	authorizer.response = &permission.Response{Decision: permission.DecisionAllow, PolicyRule: "tasks"}
+	if got := autonomy(); got != "full-auto-bounded" {
+		t.Errorf("autonomy = %v after a policy allow, want the requested one", got)
+	}
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	s := newTestServer()
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" || !strings.Contains(rec.Body.String(), `"result":{}`) {
+		t.Errorf("ping: %d %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := post(`{"jsonrpc":"2.0","method":"notifications/initialized"}`); rec.Code != http.StatusAccepted {
+		t.Errorf("notification: status %d, want 202", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mcp", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status %d, want 405", rec.Code)
+	}
+}
+
+func TestServer_ServeTransport(t *testing.T) {
+	s := newTestServer()
+	input := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}` + "\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}` + "\n"
+	var output bytes.Buffer
+	stdio := transport.NewStdioTransportWithIO(strings.NewReader(input), &output)
+
+	if err := s.ServeTransport(context.Background(), stdio); err != nil {
+		t.Fatalf("ServeTransport() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d responses, want 2: %q", len(lines), output.String())
+	}
+}
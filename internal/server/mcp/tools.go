@@ -0,0 +1,158 @@
+package mcp
+
+import (
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+)
+
+// Tool is an MCP tool backed by a registry method. Its arguments are the
+// method's params; the input schema is built from the method's metadata.
+type Tool struct {
+	Name        string                 `json:"name"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	Annotations *ToolAnnotations       `json:"annotations,omitempty"`
+
+	method string
+	params []string               // Method params exposed as arguments
+	fixed  map[string]interface{} // Method params set by the server
+	capped map[string]interface{} // Method params set by the server unless a policy rule allowed the call
+}
+
+// ToolAnnotations are hints about a tool's behaviour.
+type ToolAnnotations struct {
+	ReadOnlyHint bool `json:"readOnlyHint"`
+}
+
+var readOnly = &ToolAnnotations{ReadOnlyHint: true}
+
+// curatedTools are the registry methods agents may call. Anything that
+// starts agents, writes files or changes git state stays out, except
+// create_task, whose tasks run in their own worktree. Its tasks are manual,
+// so no agent starts on them, unless a policy rule allows the call.
+var curatedTools = []Tool{
+	{
+		Name:        "list_workspaces",
+		Title:       "List workspaces",
+		Description: "Lists the workspaces cdev manages, with their IDs, names and paths.",
+		Annotations: readOnly,
+		method:      "workspace/list",
+	},
+	{
+		Name:        "search_files",
+		Title:       "Search repository files",
+		Description: "Searches the files of the repository cdev indexes by name, with fuzzy, exact, prefix or extension matching.",
+		Annotations: readOnly,
+		method:      "repository/search",
+		params:      []string{"query", "mode", "limit", "offset", "extensions"},
+	},
+	{
+		Name:        "list_files",
+		Title:       "List workspace files",
+		Description: "Lists the files and directories of a directory in a workspace.",
+		Annotations: readOnly,
+		method:      "workspace/files/list",
+		params:      []string{"workspace_id", "directory", "limit", "offset"},
+	},
+	{
+		Name:        "read_file",
+		Title:       "Read a workspace file",
+		Description: "Reads a file of any workspace. The path is relative to the workspace root.",
+		Annotations: readOnly,
+		method:      "workspace/file/get",
+		params:      []string{"workspace_id", "path", "max_size_kb"},
+	},
+	{
+		Name:        "git_status",
+		Title:       "Git status",
+		Description: "Returns the branch and the staged, unstaged and untracked files of a workspace.",
+		Annotations: readOnly,
+		method:      "git/status",
+		params:      []string{"workspace_id"},
+	},
+	{
+		Name:        "git_diff",
+		Title:       "Git diff",
+		Description: "Returns the uncommitted changes of a workspace, or the staged ones.",
+		Annotations: readOnly,
+		method:      "git/diff",
+		params:      []string{"workspace_id", "staged"},
+	},
+	{
+		Name:        "git_log",
+		Title:       "Git log",
+		Description: "Returns the commit history of a workspace, optionally for a branch or a path.",
+		Annotations: readOnly,
+		method:      "git/log",
+		params:      []string{"workspace_id", "limit", "skip", "branch", "path"},
+	},
+	{
+		Name:        "session_history",
+		Title:       "Agent session history",
+		Description: "Lists the past Claude or Codex sessions of a workspace, newest first.",
+		Annotations: readOnly,
+		method:      "workspace/session/history",
+		params:      []string{"workspace_id", "limit", "agent_type"},
+	},
+	{
+		Name:        "session_messages",
+		Title:       "Agent session messages",
+		Description: "Returns the messages of a past session of a workspace, to see what was asked and done.",
+		Annotations: readOnly,
+		method:      "workspace/session/messages",
+		params:      []string{"workspace_id", "session_id", "limit", "offset", "order", "agent_type"},
+	},
+	{
+		Name:        "create_task",
+		Title:       "Create an agent task",
+		Description: "Creates a follow-up task for an agent in a workspace. The task is manual unless the cdev permission policy allows this tool; with another autonomy, cdev starts an agent on it in a separate worktree.",
+		method:      "task/create",
+		params:      []string{"workspace_id", "title", "description", "prompt", "task_type", "severity", "labels", "autonomy", "agent_type"},
+		fixed:       map[string]interface{}{"created_by": "mcp"},
+		capped:      map[string]interface{}{"autonomy": "manual"},
+	},
+	{
+		Name:        "get_task",
+		Title:       "Get an agent task",
+		Description: "Returns the status, timeline and result of an agent task.",
+		Annotations: readOnly,
+		method:      "task/get",
+		params:      []string{"task_id"},
+	},
+}
+
+// inputSchema builds a tool's JSON Schema from the params of its method.
+func inputSchema(meta handler.MethodMeta, tool Tool) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, name := range tool.params {
+		for _, param := range meta.Params {
+			if param.Name != name {
+				continue
+			}
+			schema := map[string]interface{}{}
+			for k, v := range param.Schema {
+				schema[k] = v
+			}
+			if param.Description != "" {
+				schema["description"] = param.Description
+			}
+			if name == "workspace_id" {
+				schema["description"] = "Workspace ID, name or path"
+			}
+			properties[name] = schema
+			if param.Required {
+				required = append(required, name)
+			}
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,427 @@
+package unified
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/domain/ports"
+	"github.com/brianly1003/cdev/internal/hub"
+	"github.com/brianly1003/cdev/internal/rpc/transport"
+	"github.com/brianly1003/cdev/internal/security"
+	"github.com/brianly1003/cdev/internal/server/common"
+	"github.com/rs/zerolog/log"
+)
+
+// sseRetry is the reconnect delay suggested to SSE clients.
+const sseRetry = 3 * time.Second
+
+// EventReplayer replays journaled events to a reconnecting subscriber.
+type EventReplayer interface {
+	Resume(sub ports.Subscriber, workspaceID string, sinceSeq uint64, attach func()) (hub.ResumeResult, error)
+	CurrentSeq(workspaceID string) uint64
+}
+
+// GitWatcherStarter starts the git watcher of a workspace so its
+// subscribers receive git_status_changed events. Watchers are released
+// through ClientDisconnectHandler.
+type GitWatcherStarter interface {
+	StartGitWatcher(workspaceID string) error
+}
+
+// SetEventReplayer sets the event hub used to resume event streams.
+func (s *Server) SetEventReplayer(replayer EventReplayer) {
+	s.replayer = replayer
+}
+
+// SetGitWatcherStarter sets the git watcher manager used by event streams.
+func (s *Server) SetGitWatcherStarter(starter GitWatcherStarter) {
+	s.gitWatchers = starter
+}
+
+// HandleEventStream serves hub events as Server-Sent Events, for consumers
+// that cannot hold a WebSocket. It streams the same events as /ws, with the
+// same authentication, and is configured with query parameters:
+//
+//	workspace_id         Workspaces to stream (repeatable or comma separated; default all)
+//	event_types          Only these event types
+//	exclude_event_types  Never these event types
+//	session_ids          Only session events of these sessions
+//	since_seq            Replay events after this seq (one workspace_id only)
+//
+// With workspace_id set, each event's SSE id is a resume cursor holding the
+// latest seq per workspace ("ws-1=42,ws-2=17"). A client reconnecting with
+// Last-Event-ID gets the events it missed replayed first. When the client
+// falls behind, a stream_lagging event is sent and the stream is closed so
+// the client reconnects and resumes.
+func (s *Server) HandleEventStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.hub == nil {
+		http.Error(w, "Event hub not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var authPayload *security.TokenPayload
+	if s.requireAuth {
+		payload, err := s.validateTokenFromRequest(r)
+		if err != nil {
+			log.Warn().Err(err).Str("remote_addr", r.RemoteAddr).Msg("event stream authentication failed")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		authPayload = payload
+	}
+
+	query := r.URL.Query()
+	workspaces := queryList(query["workspace_id"])
+	filter := hub.EventFilter{
+		SessionIDs: queryList(query["session_ids"]),
+	}
+	for _, t := range queryList(query["event_types"]) {
+		filter.Types = append(filter.Types, events.EventType(t))
+	}
+	for _, t := range queryList(query["exclude_event_types"]) {
+		filter.ExcludeTypes = append(filter.ExcludeTypes, events.EventType(t))
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("last_event_id")
+	}
+	cursor, err := parseStreamCursor(lastEventID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if v := query.Get("since_seq"); v != "" {
+		seq, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			http.Error(w, "since_seq must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		if len(workspaces) != 1 {
+			http.Error(w, "since_seq requires exactly one workspace_id", http.StatusBadRequest)
+			return
+		}
+		if _, ok := cursor[workspaces[0]]; !ok {
+			cursor[workspaces[0]] = seq
+		}
+	}
+	if len(workspaces) > 0 && len(cursor) > 0 && s.replayer == nil {
+		http.Error(w, "Event replay not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds()); err != nil {
+		return
+	}
+	if err := rc.Flush(); err != nil {
+		log.Warn().Err(err).Msg("event stream: response does not support flushing")
+		return
+	}
+
+	stream := newEventStream(len(workspaces) > 0)
+	filtered := hub.NewFilteredSubscriber(stream)
+	filtered.SetEventFilter(filter)
+	if authPayload != nil {
+		filtered.SetDeviceID(authPayload.DeviceID)
+	}
+
+	s.mu.Lock()
+	s.streams[stream.ID()] = stream
+	s.mu.Unlock()
+	defer s.removeStream(stream.ID(), workspaces)
+
+	if err := s.subscribeStream(filtered, stream, workspaces, cursor); err != nil {
+		log.Warn().Err(err).Str("stream_id", stream.ID()).Msg("failed to subscribe event stream")
+		return
+	}
+
+	log.Info().
+		Str("stream_id", stream.ID()).
+		Str("remote_addr", r.RemoteAddr).
+		Strs("workspaces", workspaces).
+		Int("resumed", len(cursor)).
+		Msg("event stream connected")
+
+	stream.serve(w, rc, r.Context().Done())
+}
+
+// subscribeStream subscribes a stream to the hub. Workspaces are resumed
+// one at a time in the event loop, so every event is delivered once and in
+// order; workspaces without a cursor start at the current seq.
+func (s *Server) subscribeStream(filtered *hub.FilteredSubscriber, stream *eventStream, workspaces []string, cursor map[string]uint64) error {
+	for _, workspaceID := range workspaces {
+		if s.gitWatchers != nil {
+			_ = s.gitWatchers.StartGitWatcher(workspaceID)
+		}
+	}
+
+	if len(workspaces) == 0 || s.replayer == nil {
+		for _, workspaceID := range workspaces {
+			filtered.SubscribeWorkspace(workspaceID)
+		}
+		s.hub.Subscribe(filtered)
+		return nil
+	}
+
+	for _, workspaceID := range workspaces {
+		since, ok := cursor[workspaceID]
+		if !ok {
+			since = s.replayer.CurrentSeq(workspaceID)
+		}
+		stream.advance(workspaceID, since)
+		if _, err := s.replayer.Resume(filtered, workspaceID, since, func() {
+			filtered.SubscribeWorkspace(workspaceID)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeStream unsubscribes a finished stream and releases its git watchers.
+func (s *Server) removeStream(id string, workspaces []string) {
+	s.hub.Unsubscribe(id)
+
+	s.mu.Lock()
+	stream, ok := s.streams[id]
+	delete(s.streams, id)
+	s.mu.Unlock()
+	if ok {
+		_ = stream.Close()
+	}
+
+	// Release the git watchers started by subscribeStream
+	if s.gitWatchers != nil && s.disconnectHandler != nil && len(workspaces) > 0 {
+		s.disconnectHandler.OnClientDisconnect(id, workspaces)
+	}
+
+	log.Info().Str("stream_id", id).Msg("event stream disconnected")
+}
+
+// eventStream is a hub subscriber that writes events as SSE frames.
+type eventStream struct {
+	id        string
+	queue     *common.OutboundQueue
+	resumable bool // Frames carry a resume cursor as their id
+	lagged    atomic.Bool
+	done      chan struct{}
+
+	mu     sync.Mutex
+	cursor map[string]uint64 // Latest seq queued per workspace
+	closed bool
+}
+
+func newEventStream(resumable bool) *eventStream {
+	c := &eventStream{
+		id:        transport.GenerateID(),
+		resumable: resumable,
+		done:      make(chan struct{}),
+		cursor:    make(map[string]uint64),
+	}
+	c.queue = common.NewOutboundQueue(c.id, common.SendBufferSize, c.lagNotice)
+	return c
+}
+
+// ID returns the stream ID.
+func (c *eventStream) ID() string {
+	return c.id
+}
+
+// Send queues an event. Frames are never critical: a lagging stream drops
+// everything, is closed, and resumes from the id of the last frame written.
+func (c *eventStream) Send(event events.Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return fmt.Errorf("event stream closed")
+	}
+
+	params, err := eventParams(event)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	id := ""
+	if c.resumable {
+		if base, ok := event.(*events.BaseEvent); ok && base.WorkspaceID != "" && base.Seq > c.cursor[base.WorkspaceID] {
+			c.cursor[base.WorkspaceID] = base.Seq
+		}
+		id = formatStreamCursor(c.cursor)
+	}
+
+	_ = c.queue.Push(common.Frame{
+		Data: sseFrame(id, string(event.Type()), data),
+		Key:  common.CoalesceKey(event),
+	})
+	return nil
+}
+
+// advance sets the cursor of a workspace before its events are replayed.
+func (c *eventStream) advance(workspaceID string, seq uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if seq > c.cursor[workspaceID] {
+		c.cursor[workspaceID] = seq
+	}
+}
+
+// lagNotice encodes the stream_lagging event. It has no id, so the client
+// resumes from the last event it received.
+func (c *eventStream) lagNotice(dropped int) []byte {
+	c.lagged.Store(true)
+	event := events.NewStreamLaggingEvent(dropped)
+	params, err := eventParams(event)
+	if err != nil {
+		return nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil
+	}
+	return sseFrame("", string(event.Type()), data)
+}
+
+// serve writes queued frames until the client goes away, the stream is
+// closed or it fell behind.
+func (c *eventStream) serve(w http.ResponseWriter, rc *http.ResponseController, clientGone <-chan struct{}) {
+	keepAlive := time.NewTicker(common.HeartbeatInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-clientGone:
+			return
+		case <-c.done:
+			return
+		case <-c.queue.Ready():
+			for data := c.queue.Next(); data != nil; data = c.queue.Next() {
+				if _, err := w.Write(data); err != nil {
+					return
+				}
+				if c.lagged.Load() {
+					_ = rc.Flush()
+					return
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			// Comment line keeps proxies from closing an idle stream
+			if _, err := w.Write([]byte(": keep-alive\n\n")); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Close ends the stream.
+func (c *eventStream) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	c.queue.Close()
+	return nil
+}
+
+// Done returns a channel that's closed when the stream is closed.
+func (c *eventStream) Done() <-chan struct{} {
+	return c.done
+}
+
+// sseFrame encodes one SSE event. data is single-line JSON.
+func sseFrame(id, eventType string, data []byte) []byte {
+	var buf bytes.Buffer
+	if id != "" {
+		buf.WriteString("id: ")
+		buf.WriteString(id)
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("event: ")
+	buf.WriteString(eventType)
+	buf.WriteString("\ndata: ")
+	buf.Write(data)
+	buf.WriteString("\n\n")
+	return buf.Bytes()
+}
+
+// formatStreamCursor encodes the latest seq per workspace as an SSE id.
+func formatStreamCursor(cursor map[string]uint64) string {
+	ids := make([]string, 0, len(cursor))
+	for workspaceID := range cursor {
+		ids = append(ids, workspaceID)
+	}
+	sort.Strings(ids)
+
+	parts := make([]string, 0, len(ids))
+	for _, workspaceID := range ids {
+		parts = append(parts, workspaceID+"="+strconv.FormatUint(cursor[workspaceID], 10))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseStreamCursor decodes an SSE id written by formatStreamCursor.
+func parseStreamCursor(value string) (map[string]uint64, error) {
+	cursor := make(map[string]uint64)
+	if strings.TrimSpace(value) == "" {
+		return cursor, nil
+	}
+	for _, part := range strings.Split(value, ",") {
+		i := strings.LastIndex(part, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid Last-Event-ID %q", value)
+		}
+		seq, err := strconv.ParseUint(part[i+1:], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Last-Event-ID %q", value)
+		}
+		cursor[strings.TrimSpace(part[:i])] = seq
+	}
+	return cursor, nil
+}
+
+// queryList flattens repeated and comma-separated query values.
+func queryList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// Ensure eventStream implements ports.Subscriber
+var _ ports.Subscriber = (*eventStream)(nil)
@@ -0,0 +1,139 @@
+package unified
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/hub"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+)
+
+// readSSE collects the id and event lines of an SSE response.
+func readSSE(t *testing.T, resp *http.Response, frames chan<- [2]string) {
+	t.Helper()
+	scanner := bufio.NewScanner(resp.Body)
+	var id, event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case line == "" && event != "":
+			frames <- [2]string{id, event}
+			id, event = "", ""
+		}
+	}
+	close(frames)
+}
+
+func TestHandleEventStream_ResumesFromLastEventID(t *testing.T) {
+	eventHub := hub.New()
+	_ = eventHub.Start()
+	defer func() { _ = eventHub.Stop() }()
+
+	server := NewServer("localhost", 0, handler.NewDispatcher(handler.NewRegistry()), eventHub)
+	server.SetEventReplayer(eventHub)
+	ts := httptest.NewServer(http.HandlerFunc(server.HandleEventStream))
+	defer ts.Close()
+
+	for i := 0; i < 3; i++ {
+		eventHub.Publish(events.NewEventWithContext(events.EventTypeClaudeMessage, map[string]string{"n": "x"}, "ws-a", ""))
+	}
+	eventHub.Publish(events.NewEventWithContext(events.EventTypeClaudeMessage, nil, "ws-b", ""))
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"?workspace_id=ws-a", nil)
+	req.Header.Set("Last-Event-ID", "ws-a=1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	frames := make(chan [2]string, 10)
+	go readSSE(t, resp, frames)
+
+	next := func() [2]string {
+		select {
+		case frame := <-frames:
+			return frame
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for event")
+			return [2]string{}
+		}
+	}
+
+	if got := next(); got != [2]string{"ws-a=2", "claude_message"} {
+		t.Errorf("first frame = %v, want replayed seq 2", got)
+	}
+	if got := next(); got != [2]string{"ws-a=3", "claude_message"} {
+		t.Errorf("second frame = %v, want replayed seq 3", got)
+	}
+
+	eventHub.Publish(events.NewEventWithContext(events.EventTypeClaudeMessage, nil, "ws-b", "")) // Filtered out
+	eventHub.Publish(events.NewEventWithContext(events.EventTypeGitStatusChanged, nil, "ws-a", ""))
+	if got := next(); got != [2]string{"ws-a=4", "git_status_changed"} {
+		t.Errorf("live frame = %v, want seq 4", got)
+	}
+}
+
+func TestHandleEventStream_RejectsBadParams(t *testing.T) {
+	eventHub := hub.New()
+	_ = eventHub.Start()
+	defer func() { _ = eventHub.Stop() }()
+
+	server := NewServer("localhost", 0, handler.NewDispatcher(handler.NewRegistry()), eventHub)
+	server.SetEventReplayer(eventHub)
+
+	for _, target := range []string{
+		"/api/events?since_seq=3",
+		"/api/events?workspace_id=ws-a,ws-b&since_seq=3",
+		"/api/events?workspace_id=ws-a&since_seq=abc",
+		"/api/events?workspace_id=ws-a&last_event_id=garbage",
+	} {
+		rec := httptest.NewRecorder()
+		server.HandleEventStream(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", target, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	server.HandleEventStream(rec, httptest.NewRequest(http.MethodPost, "/api/events", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
+
+func TestStreamCursor_RoundTrip(t *testing.T) {
+	cursor := map[string]uint64{"ws-b": 17, "ws-a": 42}
+	id := formatStreamCursor(cursor)
+	if id != "ws-a=42,ws-b=17" {
+		t.Fatalf("formatStreamCursor() = %q", id)
+	}
+
+	parsed, err := parseStreamCursor(id)
+	if err != nil {
+		t.Fatalf("parseStreamCursor() error = %v", err)
+	}
+	if len(parsed) != 2 || parsed["ws-a"] != 42 || parsed["ws-b"] != 17 {
+		t.Errorf("parseStreamCursor() = %v", parsed)
+	}
+
+	if _, err := parseStreamCursor("ws-a=x"); err == nil {
+		t.Error("expected an error for a non-numeric seq")
+	}
+}
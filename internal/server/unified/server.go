@@ -71,6 +71,7 @@ type Server struct {
 	mu              sync.RWMutex
 	clients         map[string]*UnifiedClient
 	filteredClients map[string]*hub.FilteredSubscriber // Workspace-filtered subscribers
+	streams         map[string]*eventStream            // SSE event streams
 
 	// Session focus tracking (multi-device awareness)
 	sessionFocusMu sync.RWMutex
@@ -79,6 +80,10 @@ type Server struct {
 	// Disconnect handler for cleanup (git watchers, session streamers, etc.)
 	disconnectHandler ClientDisconnectHandler
 
+	// Event streams resume through the hub and start git watchers
+	replayer    EventReplayer
+	gitWatchers GitWatcherStarter
+
 	// Heartbeat
 	heartbeatDone chan struct{}
 	heartbeatSeq  int64
@@ -103,6 +108,7 @@ func NewServer(host string, port int, dispatcher *handler.Dispatcher, eventHub p
 		hub:             eventHub,
 		clients:         make(map[string]*UnifiedClient),
 		filteredClients: make(map[string]*hub.FilteredSubscriber),
+		streams:         make(map[string]*eventStream),
 		sessionFocus:    make(map[string]*SessionFocus),
 		heartbeatDone:   make(chan struct{}),
 		startTime:       time.Now(),
@@ -204,6 +210,9 @@ func (s *Server) Stop(ctx context.Context) error {
 		_ = client.Close()
 	}
 	s.clients = make(map[string]*UnifiedClient)
+	for _, stream := range s.streams {
+		_ = stream.Close()
+	}
 	s.mu.Unlock()
 
 	// Only shutdown HTTP server if it was started
@@ -292,6 +301,10 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 	// Wrap in FilteredSubscriber for workspace filtering support
 	filtered := hub.NewFilteredSubscriber(client)
+	if authPayload != nil {
+		// Permission prompts routed to specific devices are filtered by device ID
+		filtered.SetDeviceID(authPayload.DeviceID)
+	}
 
 	s.mu.Lock()
 	s.clients[client.ID()] = client
@@ -351,7 +364,7 @@ func (s *Server) Broadcast(data []byte) {
 	defer s.mu.RUnlock()
 
 	for _, client := range s.clients {
-		client.SendRaw(data)
+		client.sendFrame(common.Frame{Data: data})
 	}
 }
 
@@ -362,6 +375,23 @@ func (s *Server) ClientCount() int {
 	return len(s.clients)
 }
 
+// ConnectedDevices returns the IDs of the paired devices with an open
+// connection. Clients connected without a device token are not included.
+func (s *Server) ConnectedDevices() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	devices := make([]string, 0, len(s.filteredClients))
+	for _, filtered := range s.filteredClients {
+		if id := filtered.DeviceID(); id != "" && !seen[id] {
+			seen[id] = true
+			devices = append(devices, id)
+		}
+	}
+	return devices
+}
+
 // GetClient returns a client by ID for sending direct messages.
 func (s *Server) GetClient(id string) *UnifiedClient {
 	s.mu.RLock()
@@ -369,6 +399,32 @@ func (s *Server) GetClient(id string) *UnifiedClient {
 	return s.clients[id]
 }
 
+// DeliveryPolicyResult is the result of a delivery policy change.
+type DeliveryPolicyResult struct {
+	common.DeliveryPolicy
+	Lagging bool `json:"lagging"`
+}
+
+// SetDeliveryPolicy changes the delivery policy of a client. Nil options
+// are left unchanged.
+func (s *Server) SetDeliveryPolicy(clientID string, coalesce, batch *bool) (interface{}, error) {
+	client := s.GetClient(clientID)
+	if client == nil {
+		return nil, fmt.Errorf("client not found: %s", clientID)
+	}
+
+	policy := client.DeliveryPolicy()
+	if coalesce != nil {
+		policy.Coalesce = *coalesce
+	}
+	if batch != nil {
+		policy.Batch = *batch
+	}
+	client.SetDeliveryPolicy(policy)
+
+	return &DeliveryPolicyResult{DeliveryPolicy: policy, Lagging: client.Lagging()}, nil
+}
+
 // FocusChangeResult is the result of a focus change operation.
 type FocusChangeResult struct {
 	WorkspaceID  string   `json:"workspace_id"`
@@ -684,9 +740,11 @@ func (s *Server) validateTokenFromRequest(r *http.Request) (*security.TokenPaylo
 type UnifiedClient struct {
 	id           string
 	conn         *websocket.Conn
-	send         chan []byte
+	transport    transport.Transport // Set instead of conn for stdio clients
+	queue        *common.OutboundQueue
 	done         chan struct{}
 	dispatcher   *handler.Dispatcher
+	executor     *handler.Executor // Runs the client's requests; nil without a dispatcher
 	onClose      func(id string)
 	authPayload  *security.TokenPayload
 	rateLimiter  *httpMiddleware.RateLimiter
@@ -704,16 +762,20 @@ func NewUnifiedClient(
 	rateLimitKey string,
 	onClose func(id string),
 ) *UnifiedClient {
-	return &UnifiedClient{
+	c := &UnifiedClient{
 		id:           transport.GenerateID(),
 		conn:         conn,
-		send:         make(chan []byte, common.SendBufferSize),
 		done:         make(chan struct{}),
 		dispatcher:   dispatcher,
 		onClose:      onClose,
 		rateLimiter:  rateLimiter,
 		rateLimitKey: rateLimitKey,
 	}
+	if dispatcher != nil {
+		c.executor = handler.NewExecutor(dispatcher, handler.DefaultWorkers)
+	}
+	c.queue = common.NewOutboundQueue(c.id, common.SendBufferSize, c.lagNotice)
+	return c
 }
 
 // ID returns the client ID.
@@ -728,12 +790,24 @@ func (c *UnifiedClient) SetAuthPayload(payload *security.TokenPayload) {
 
 // Start starts the client's read and write loops.
 func (c *UnifiedClient) Start() {
+	if c.transport != nil {
+		eof := make(chan struct{})
+		go c.transportWritePump(eof)
+		go c.transportReadPump(eof)
+		return
+	}
 	go c.writePump()
 	go c.readPump()
 }
 
-// SendRaw queues raw bytes to be sent.
+// SendRaw queues raw bytes to be sent. They are never coalesced or
+// dropped, so use it for RPC responses.
 func (c *UnifiedClient) SendRaw(data []byte) {
+	c.sendFrame(common.Frame{Data: data, Critical: true})
+}
+
+// sendFrame queues a frame unless the client is closed.
+func (c *UnifiedClient) sendFrame(frame common.Frame) {
 	c.mu.Lock()
 	if c.closed {
 		c.mu.Unlock()
@@ -741,11 +815,33 @@ func (c *UnifiedClient) SendRaw(data []byte) {
 	}
 	c.mu.Unlock()
 
-	select {
-	case c.send <- data:
-	default:
-		log.Warn().Str("client_id", c.id).Msg("send buffer full")
+	_ = c.queue.Push(frame)
+}
+
+// SetDeliveryPolicy changes how queued events are delivered to the client.
+func (c *UnifiedClient) SetDeliveryPolicy(policy common.DeliveryPolicy) {
+	c.queue.SetPolicy(policy)
+}
+
+// DeliveryPolicy returns the client's delivery policy.
+func (c *UnifiedClient) DeliveryPolicy() common.DeliveryPolicy {
+	return c.queue.Policy()
+}
+
+// Lagging reports whether the client fell behind and is dropping events.
+func (c *UnifiedClient) Lagging() bool {
+	return c.queue.Lagging()
+}
+
+// lagNotice encodes the stream_lagging notification sent after events
+// were dropped.
+func (c *UnifiedClient) lagNotice(dropped int) []byte {
+	data, err := c.encodeNotification(events.NewStreamLaggingEvent(dropped))
+	if err != nil {
+		log.Warn().Err(err).Str("client_id", c.id).Msg("failed to encode stream_lagging")
+		return nil
 	}
+	return data
 }
 
 // Close closes the client connection.
@@ -759,11 +855,20 @@ func (c *UnifiedClient) Close() error {
 	c.mu.Unlock()
 
 	close(c.done)
+	c.queue.Close()
+
+	// Cancel the requests still running for this client
+	if c.executor != nil {
+		c.executor.Close()
+	}
 
 	// Close the websocket connection to unblock readPump
 	if c.conn != nil {
 		_ = c.conn.Close()
 	}
+	if c.transport != nil {
+		_ = c.transport.Close()
+	}
 
 	return nil
 }
@@ -787,24 +892,56 @@ func (c *UnifiedClient) Send(event events.Event) error {
 }
 
 // sendJSONRPCNotification sends an event as a JSON-RPC notification.
+// State events may be coalesced and non-critical events dropped when the
+// client falls behind; see common.OutboundQueue.
 func (c *UnifiedClient) sendJSONRPCNotification(event events.Event) error {
-	method := "event/" + string(event.Type())
+	data, err := c.encodeNotification(event)
+	if err != nil {
+		return err
+	}
+	c.sendFrame(common.Frame{
+		Data:         data,
+		Key:          common.CoalesceKey(event),
+		Critical:     events.IsCritical(event.Type()),
+		Notification: true,
+	})
+	return nil
+}
+
+// encodeNotification encodes an event as a JSON-RPC notification.
+func (c *UnifiedClient) encodeNotification(event events.Event) ([]byte, error) {
+	params, err := eventParams(event)
+	if err != nil {
+		return nil, err
+	}
+
+	notification, err := message.NewNotification("event/"+string(event.Type()), params)
+	if err != nil {
+		return nil, err
+	}
 
+	return json.Marshal(notification)
+}
+
+// eventParams returns the notification params of an event: the payload
+// fields with the routing context (workspace, session, agent type,
+// timestamp and seq) merged in.
+func eventParams(event events.Event) (interface{}, error) {
 	// Extract payload and routing context from event.
 	// Keep payload fields at top level for backward compatibility with existing clients.
 	data, err := event.ToJSON()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var eventData map[string]interface{}
 	if err := json.Unmarshal(data, &eventData); err != nil {
-		return err
+		return nil, err
 	}
 
 	var params interface{}
 	if payloadMap, ok := eventData["payload"].(map[string]interface{}); ok {
-		merged := make(map[string]interface{}, len(payloadMap)+4)
+		merged := make(map[string]interface{}, len(payloadMap)+5)
 		for k, v := range payloadMap {
 			merged[k] = v
 		}
@@ -820,10 +957,13 @@ func (c *UnifiedClient) sendJSONRPCNotification(event events.Event) error {
 		if ts, ok := eventData["timestamp"]; ok {
 			merged["timestamp"] = ts
 		}
+		if seq, ok := eventData["seq"]; ok {
+			merged["seq"] = seq
+		}
 		params = merged
 	} else {
 		// Fallback for non-object payloads.
-		envelope := make(map[string]interface{}, 5)
+		envelope := make(map[string]interface{}, 6)
 		if payload, ok := eventData["payload"]; ok {
 			envelope["payload"] = payload
 		}
@@ -839,21 +979,13 @@ func (c *UnifiedClient) sendJSONRPCNotification(event events.Event) error {
 		if ts, ok := eventData["timestamp"]; ok {
 			envelope["timestamp"] = ts
 		}
+		if seq, ok := eventData["seq"]; ok {
+			envelope["seq"] = seq
+		}
 		params = envelope
 	}
 
-	notification, err := message.NewNotification(method, params)
-	if err != nil {
-		return err
-	}
-
-	notifData, err := json.Marshal(notification)
-	if err != nil {
-		return err
-	}
-
-	c.SendRaw(notifData)
-	return nil
+	return params, nil
 }
 
 // readPump reads messages from the WebSocket connection.
@@ -935,18 +1067,9 @@ func (c *UnifiedClient) handleJSONRPC(data []byte) {
 	if c.authPayload != nil {
 		ctx = context.WithValue(ctx, handler.AuthPayloadKey, c.authPayload)
 	}
-	response, err := c.dispatcher.HandleMessage(ctx, data)
-	if err != nil {
-		log.Warn().Err(err).Str("client_id", c.id).Msg("JSON-RPC dispatch error")
-		return
-	}
-
-	// Don't send empty responses (notifications)
-	if len(response) == 0 {
-		return
-	}
 
-	c.SendRaw(response)
+	// Requests run concurrently; each response is queued when it is ready
+	c.executor.Handle(ctx, data, c.SendRaw)
 }
 
 // writePump sends messages to the WebSocket connection.
@@ -964,15 +1087,13 @@ func (c *UnifiedClient) writePump() {
 		case <-c.done:
 			return
 
-		case data, ok := <-c.send:
-			if !ok {
-				return
-			}
-
-			_ = c.conn.SetWriteDeadline(time.Now().Add(common.WriteWait))
-			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
-				log.Debug().Err(err).Str("client_id", c.id).Msg("write error")
-				return
+		case <-c.queue.Ready():
+			for data := c.queue.Next(); data != nil; data = c.queue.Next() {
+				_ = c.conn.SetWriteDeadline(time.Now().Add(common.WriteWait))
+				if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+					log.Debug().Err(err).Str("client_id", c.id).Msg("write error")
+					return
+				}
 			}
 
 		case <-ticker.C:
@@ -88,45 +88,49 @@ func TestUnifiedClient_Send_IncludesEventContextInParams(t *testing.T) {
 	})
 	event.SetContext("ws-123", "sess-456")
 	event.SetAgentType("codex")
+	event.Seq = 42
 
 	if err := client.Send(event); err != nil {
 		t.Fatalf("Send() error = %v", err)
 	}
 
-	select {
-	case data := <-client.send:
-		var notif map[string]interface{}
-		if err := json.Unmarshal(data, &notif); err != nil {
-			t.Fatalf("failed to parse notification: %v", err)
-		}
-
-		if notif["method"] != "event/claude_message" {
-			t.Fatalf("method = %v, want event/claude_message", notif["method"])
-		}
-
-		params, ok := notif["params"].(map[string]interface{})
-		if !ok {
-			t.Fatalf("params is not an object: %T", notif["params"])
-		}
-
-		if params["workspace_id"] != "ws-123" {
-			t.Fatalf("workspace_id = %v, want ws-123", params["workspace_id"])
-		}
-		if params["session_id"] != "sess-456" {
-			t.Fatalf("session_id = %v, want sess-456", params["session_id"])
-		}
-		if params["agent_type"] != "codex" {
-			t.Fatalf("agent_type = %v, want codex", params["agent_type"])
-		}
-		if params["role"] != "assistant" {
-			t.Fatalf("role = %v, want assistant", params["role"])
-		}
-		if _, ok := params["timestamp"]; !ok {
-			t.Fatal("timestamp missing in params")
-		}
-	default:
+	data := client.queue.Next()
+	if data == nil {
 		t.Fatal("expected notification in client send queue")
 	}
+
+	var notif map[string]interface{}
+	if err := json.Unmarshal(data, &notif); err != nil {
+		t.Fatalf("failed to parse notification: %v", err)
+	}
+
+	if notif["method"] != "event/claude_message" {
+		t.Fatalf("method = %v, want event/claude_message", notif["method"])
+	}
+
+	params, ok := notif["params"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("params is not an object: %T", notif["params"])
+	}
+
+	if params["workspace_id"] != "ws-123" {
+		t.Fatalf("workspace_id = %v, want ws-123", params["workspace_id"])
+	}
+	if params["session_id"] != "sess-456" {
+		t.Fatalf("session_id = %v, want sess-456", params["session_id"])
+	}
+	if params["agent_type"] != "codex" {
+		t.Fatalf("agent_type = %v, want codex", params["agent_type"])
+	}
+	if params["role"] != "assistant" {
+		t.Fatalf("role = %v, want assistant", params["role"])
+	}
+	if _, ok := params["timestamp"]; !ok {
+		t.Fatal("timestamp missing in params")
+	}
+	if params["seq"] != float64(42) {
+		t.Fatalf("seq = %v, want 42", params["seq"])
+	}
 }
 
 func TestServer_Broadcast(t *testing.T) {
@@ -233,6 +237,43 @@ func TestServer_WebSocketAuth_AllowsAccessToken(t *testing.T) {
 	_ = ws.Close()
 }
 
+func TestServer_ConnectedDevices(t *testing.T) {
+	hub := testutil.NewMockEventHub()
+	_ = hub.Start()
+	defer func() { _ = hub.Stop() }()
+
+	dispatcher := handler.NewDispatcher(handler.NewRegistry())
+	server := NewServer("127.0.0.1", 0, dispatcher, hub)
+	tokenManager := newTestTokenManager(t)
+	server.SetSecurity(tokenManager, nil, true)
+
+	pair, err := tokenManager.GenerateTokenPairWithDeviceID("iphone-1")
+	if err != nil {
+		t.Fatalf("failed to generate token pair: %v", err)
+	}
+
+	testServer := httptest.NewServer(http.HandlerFunc(server.HandleWebSocket))
+	defer testServer.Close()
+
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+pair.AccessToken)
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(testServer.URL, "http"), headers)
+	if err != nil {
+		t.Fatalf("expected successful connection, got %v", err)
+	}
+	defer func() { _ = ws.Close() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for server.ClientCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	devices := server.ConnectedDevices()
+	if len(devices) != 1 || devices[0] != "iphone-1" {
+		t.Fatalf("expected [iphone-1], got %v", devices)
+	}
+}
+
 func TestServer_WebSocketAuth_RejectsPairingToken(t *testing.T) {
 	hub := testutil.NewMockEventHub()
 	_ = hub.Start()
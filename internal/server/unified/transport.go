@@ -0,0 +1,127 @@
+package unified
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/brianly1003/cdev/internal/hub"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/transport"
+	"github.com/brianly1003/cdev/internal/server/common"
+	"github.com/rs/zerolog/log"
+)
+
+// NewTransportClient creates a client served over a transport instead of a
+// WebSocket connection, e.g. stdin/stdout for `cdev rpc --stdio`.
+func NewTransportClient(t transport.Transport, dispatcher *handler.Dispatcher) *UnifiedClient {
+	c := &UnifiedClient{
+		id:         t.ID(),
+		transport:  t,
+		done:       make(chan struct{}),
+		dispatcher: dispatcher,
+	}
+	if dispatcher != nil {
+		c.executor = handler.NewExecutor(dispatcher, handler.DefaultWorkers)
+	}
+	c.queue = common.NewOutboundQueue(c.id, common.SendBufferSize, c.lagNotice)
+	return c
+}
+
+// ServeTransport serves a single local client over a transport. The client
+// gets the complete method registry, workspace filtering and delivery
+// policies like a WebSocket client. No token is checked: whoever owns the
+// transport already runs as the local user.
+//
+// It blocks until the transport reaches EOF, fails, or ctx is cancelled.
+func (s *Server) ServeTransport(ctx context.Context, t transport.Transport) error {
+	client := NewTransportClient(t, s.dispatcher)
+	filtered := hub.NewFilteredSubscriber(client)
+
+	s.mu.Lock()
+	s.clients[client.ID()] = client
+	s.filteredClients[client.ID()] = filtered
+	s.mu.Unlock()
+
+	if s.hub != nil {
+		s.hub.Subscribe(filtered)
+	}
+
+	log.Info().Str("client_id", client.ID()).Msg("client connected (transport)")
+
+	client.Start()
+
+	var err error
+	select {
+	case <-client.Done():
+	case <-ctx.Done():
+		err = ctx.Err()
+		_ = client.Close()
+	}
+
+	if s.hub != nil {
+		s.hub.Unsubscribe(client.ID())
+	}
+	s.removeClient(client.ID())
+	return err
+}
+
+// transportReadPump reads messages from the transport. At EOF it hands over
+// to transportWritePump, which writes the pending responses before closing,
+// once the requests still running are answered.
+func (c *UnifiedClient) transportReadPump(eof chan<- struct{}) {
+	defer close(eof)
+	defer func() {
+		if c.executor != nil {
+			c.executor.Wait()
+		}
+	}()
+
+	for {
+		data, err := c.transport.Read(context.Background())
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, transport.ErrTransportClosed) {
+				log.Warn().Err(err).Str("client_id", c.id).Msg("transport read error")
+			}
+			return
+		}
+
+		// The dispatcher answers malformed messages with a parse error,
+		// which a local tool can act on
+		c.handleJSONRPC(data)
+	}
+}
+
+// transportWritePump sends queued messages to the transport.
+func (c *UnifiedClient) transportWritePump(eof <-chan struct{}) {
+	defer func() {
+		_ = c.Close()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+
+		case <-c.queue.Ready():
+			if !c.flushTransport() {
+				return
+			}
+
+		case <-eof:
+			c.flushTransport()
+			return
+		}
+	}
+}
+
+// flushTransport writes the queued messages. It returns false on a write error.
+func (c *UnifiedClient) flushTransport() bool {
+	for data := c.queue.Next(); data != nil; data = c.queue.Next() {
+		if err := c.transport.Write(context.Background(), data); err != nil {
+			log.Debug().Err(err).Str("client_id", c.id).Msg("transport write error")
+			return false
+		}
+	}
+	return true
+}
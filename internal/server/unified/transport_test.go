@@ -0,0 +1,124 @@
+package unified
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/hub"
+	"github.com/brianly1003/cdev/internal/rpc/handler"
+	"github.com/brianly1003/cdev/internal/rpc/message"
+	"github.com/brianly1003/cdev/internal/rpc/transport"
+)
+
+// newTransportTestServer returns a server whose test/client method reports
+// whether the calling client has a filtered subscriber.
+func newTransportTestServer(eventHub *hub.Hub) *Server {
+	registry := handler.NewRegistry()
+	var server *Server
+	registry.Register("test/client", func(ctx context.Context, params json.RawMessage) (interface{}, *message.Error) {
+		clientID, _ := ctx.Value(handler.ClientIDKey).(string)
+		return map[string]interface{}{
+			"client_id":  clientID,
+			"subscribed": server.GetFilteredSubscriber(clientID) != nil,
+		}, nil
+	})
+	server = NewServer("localhost", 0, handler.NewDispatcher(registry), eventHub)
+	return server
+}
+
+func TestServer_ServeTransport(t *testing.T) {
+	eventHub := hub.New()
+	_ = eventHub.Start()
+	defer func() { _ = eventHub.Stop() }()
+	server := newTransportTestServer(eventHub)
+
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+	stdio := transport.NewStdioTransportWithIO(stdinR, stdoutW)
+
+	served := make(chan error, 1)
+	go func() { served <- server.ServeTransport(context.Background(), stdio) }()
+
+	lines := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(stdoutR)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	next := func() map[string]interface{} {
+		t.Helper()
+		select {
+		case line := <-lines:
+			var msg map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &msg); err != nil {
+				t.Fatalf("invalid JSON %q: %v", line, err)
+			}
+			return msg
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for output")
+			return nil
+		}
+	}
+
+	_, _ = stdinW.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"test/client"}` + "\n"))
+	resp := next()
+	result, _ := resp["result"].(map[string]interface{})
+	if result["client_id"] != "stdio" || result["subscribed"] != true {
+		t.Fatalf("response = %v, want stdio client with a filtered subscriber", resp)
+	}
+	if server.ClientCount() != 1 {
+		t.Errorf("ClientCount() = %d, want 1", server.ClientCount())
+	}
+
+	eventHub.Publish(events.NewEventWithContext(events.EventTypeClaudeMessage, map[string]string{"text": "hi"}, "ws-a", ""))
+	if got := next()["method"]; got != "event/claude_message" {
+		t.Errorf("notification method = %v, want event/claude_message", got)
+	}
+
+	_, _ = stdinW.Write([]byte("not json\n"))
+	if resp := next(); resp["error"] == nil {
+		t.Errorf("response to malformed input = %v, want a parse error", resp)
+	}
+
+	_ = stdinW.Close()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("ServeTransport() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ServeTransport did not return at EOF")
+	}
+	if server.ClientCount() != 0 || server.GetFilteredSubscriber("stdio") != nil {
+		t.Error("client still registered after EOF")
+	}
+}
+
+func TestServer_ServeTransport_FlushesResponsesAtEOF(t *testing.T) {
+	eventHub := hub.New()
+	_ = eventHub.Start()
+	defer func() { _ = eventHub.Stop() }()
+	server := newTransportTestServer(eventHub)
+
+	input := `{"jsonrpc":"2.0","id":1,"method":"test/client"}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"test/client"}` + "\n"
+	var output bytes.Buffer
+	stdio := transport.NewStdioTransportWithIO(strings.NewReader(input), &output)
+
+	if err := server.ServeTransport(context.Background(), stdio); err != nil {
+		t.Fatalf("ServeTransport() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d responses, want 2: %q", len(lines), output.String())
+	}
+}
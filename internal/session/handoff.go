@@ -0,0 +1,369 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brianly1003/cdev/internal/adapters/codex"
+	"github.com/brianly1003/cdev/internal/adapters/sessioncache"
+)
+
+// Handoff document limits. The document is sent as the first prompt of the
+// target session, so it must stay well inside any runtime's prompt budget.
+const (
+	DefaultHandoffMaxChars = 24000
+
+	handoffUserTextLimit      = 2000
+	handoffAssistantTextLimit = 1500
+	handoffToolTextLimit      = 200
+	handoffMaxMessages        = 5000
+)
+
+// Handoff transcript entry roles.
+const (
+	HandoffRoleUser      = "user"
+	HandoffRoleAssistant = "assistant"
+	HandoffRoleTool      = "tool"
+	HandoffRoleError     = "error"
+	HandoffRoleSummary   = "summary"
+)
+
+// HandoffEntry is one condensed step of a conversation.
+type HandoffEntry struct {
+	Role string
+	Text string
+}
+
+// HandoffTranscript is a runtime-neutral view of a conversation, reduced to
+// what another agent needs to pick the task up: what was asked, what was
+// said, which tools ran and which files were touched. Tool output is dropped.
+type HandoffTranscript struct {
+	Entries []HandoffEntry
+	Files   []string
+}
+
+// HandoffSource identifies the session a handoff document was built from.
+type HandoffSource struct {
+	AgentType   string
+	SessionID   string
+	ProjectPath string
+}
+
+// FirstUserText returns the first user message, which is usually the task.
+func (t HandoffTranscript) FirstUserText() string {
+	for _, entry := range t.Entries {
+		if entry.Role == HandoffRoleUser {
+			return entry.Text
+		}
+	}
+	return ""
+}
+
+// HandoffTranscriptFromElements condenses Claude session elements.
+func HandoffTranscriptFromElements(elements []sessioncache.Element) HandoffTranscript {
+	b := newHandoffBuilder()
+	for _, el := range elements {
+		switch el.Type {
+		case sessioncache.ElementTypeUserInput:
+			var c sessioncache.UserInputContent
+			if json.Unmarshal(el.Content, &c) == nil {
+				b.add(HandoffRoleUser, c.Text)
+			}
+		case sessioncache.ElementTypeAssistantText:
+			var c sessioncache.AssistantTextContent
+			if json.Unmarshal(el.Content, &c) == nil {
+				b.add(HandoffRoleAssistant, c.Text)
+			}
+		case sessioncache.ElementTypeToolCall:
+			var c sessioncache.ToolCallContent
+			if json.Unmarshal(el.Content, &c) == nil {
+				b.addTool(c.Tool, c.Display, c.Params)
+			}
+		case sessioncache.ElementTypeToolResult:
+			var c sessioncache.ToolResultContent
+			if json.Unmarshal(el.Content, &c) == nil && c.IsError {
+				b.add(HandoffRoleError, c.ToolName+": "+c.Summary)
+			}
+		case sessioncache.ElementTypeDiff:
+			var c sessioncache.DiffContent
+			if json.Unmarshal(el.Content, &c) == nil {
+				b.addFile(c.FilePath)
+			}
+		case sessioncache.ElementTypeContextCompaction:
+			var c sessioncache.ContextCompactionContent
+			if json.Unmarshal(el.Content, &c) == nil {
+				b.add(HandoffRoleSummary, c.Summary)
+			}
+		}
+	}
+	return b.transcript()
+}
+
+// HandoffTranscriptFromCodex condenses a Codex conversation item stream.
+func HandoffTranscriptFromCodex(items []codex.ConversationItem) HandoffTranscript {
+	b := newHandoffBuilder()
+	for _, item := range items {
+		if item.IsContextCompaction {
+			for _, block := range item.Content {
+				b.add(HandoffRoleSummary, block.Text)
+			}
+			continue
+		}
+		for _, block := range item.Content {
+			switch block.Type {
+			case "text":
+				if item.Role == "user" {
+					b.add(HandoffRoleUser, block.Text)
+				} else {
+					b.add(HandoffRoleAssistant, block.Text)
+				}
+			case "tool_use":
+				b.addTool(block.ToolName, "", block.ToolInput)
+			case "tool_result":
+				if block.IsError {
+					b.add(HandoffRoleError, firstLine(block.Content))
+				}
+			}
+		}
+	}
+	return b.transcript()
+}
+
+// BuildHandoffDocument renders a transcript as the opening prompt of a session
+// on another runtime. When the conversation does not fit in maxChars, the
+// oldest entries are dropped; the original request is always kept.
+// nextStep is appended as the instruction to act on; it may be empty.
+func BuildHandoffDocument(t HandoffTranscript, src HandoffSource, nextStep string, maxChars int) string {
+	if maxChars <= 0 {
+		maxChars = DefaultHandoffMaxChars
+	}
+
+	var head strings.Builder
+	fmt.Fprintf(&head, "# Handoff from %s session %s\n\n", src.AgentType, src.SessionID)
+	head.WriteString("This task was started with another coding agent and is being continued here. ")
+	head.WriteString("Below is a condensed record of that conversation; tool output has been omitted, ")
+	head.WriteString("so re-check the workspace before relying on details.\n")
+	if src.ProjectPath != "" {
+		fmt.Fprintf(&head, "\nWorking directory: %s\n", src.ProjectPath)
+	}
+	if goal := t.FirstUserText(); goal != "" {
+		fmt.Fprintf(&head, "\n## Original request\n\n%s\n", truncateRunes(goal, handoffUserTextLimit))
+	}
+	if len(t.Files) > 0 {
+		head.WriteString("\n## Files touched\n\n")
+		for _, file := range t.Files {
+			fmt.Fprintf(&head, "- %s\n", file)
+		}
+	}
+
+	var tail strings.Builder
+	tail.WriteString("\n## Next step\n\n")
+	if next := strings.TrimSpace(nextStep); next != "" {
+		tail.WriteString(next)
+	} else {
+		tail.WriteString("Review the current state of the workspace and continue the task from where the previous session stopped.")
+	}
+	tail.WriteString("\n")
+
+	lines := make([]string, 0, len(t.Entries))
+	for _, entry := range t.Entries {
+		lines = append(lines, formatHandoffEntry(entry))
+	}
+
+	omittedNote := "_(%d earlier entries omitted)_\n"
+	budget := maxChars - head.Len() - tail.Len() - len("\n## Conversation\n\n") - len(omittedNote) - 8
+	kept := 0
+	used := 0
+	for i := len(lines) - 1; i >= 0; i-- {
+		if used+len(lines[i])+1 > budget {
+			break
+		}
+		used += len(lines[i]) + 1
+		kept++
+	}
+
+	var doc strings.Builder
+	doc.WriteString(head.String())
+	if len(lines) > 0 {
+		doc.WriteString("\n## Conversation\n\n")
+		if omitted := len(lines) - kept; omitted > 0 {
+			fmt.Fprintf(&doc, omittedNote, omitted)
+		}
+		for _, line := range lines[len(lines)-kept:] {
+			doc.WriteString(line)
+			doc.WriteString("\n")
+		}
+	}
+	doc.WriteString(tail.String())
+	return doc.String()
+}
+
+// ClaudeHandoffTranscript reads a Claude session and condenses it for a handoff.
+func (m *Manager) ClaudeHandoffTranscript(workspaceID, sessionID string) (HandoffTranscript, error) {
+	var raw []json.RawMessage
+	for offset := 0; offset < handoffMaxMessages; {
+		page, err := m.GetSessionMessages(workspaceID, sessionID, 500, offset, "asc")
+		if err != nil {
+			return HandoffTranscript{}, err
+		}
+		for _, msg := range page.Messages {
+			wrapper := map[string]interface{}{
+				"type":      msg.Type,
+				"uuid":      msg.UUID,
+				"timestamp": msg.Timestamp,
+				"message":   msg.Message,
+			}
+			if data, err := json.Marshal(wrapper); err == nil {
+				raw = append(raw, data)
+			}
+		}
+		if !page.HasMore || len(page.Messages) == 0 {
+			break
+		}
+		offset += len(page.Messages)
+	}
+	if len(raw) == 0 {
+		return HandoffTranscript{}, fmt.Errorf("session not found: %s", sessionID)
+	}
+
+	elements, err := sessioncache.ParseSessionToElements(raw, sessionID)
+	if err != nil {
+		return HandoffTranscript{}, err
+	}
+	return HandoffTranscriptFromElements(elements), nil
+}
+
+type handoffBuilder struct {
+	entries []HandoffEntry
+	files   map[string]bool
+}
+
+func newHandoffBuilder() *handoffBuilder {
+	return &handoffBuilder{files: make(map[string]bool)}
+}
+
+func (b *handoffBuilder) add(role, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	entry := HandoffEntry{Role: role, Text: text}
+	// Transcripts can repeat a block verbatim; one copy is enough.
+	if n := len(b.entries); n > 0 && b.entries[n-1] == entry {
+		return
+	}
+	b.entries = append(b.entries, entry)
+}
+
+func (b *handoffBuilder) addFile(path string) {
+	if path = strings.TrimSpace(path); path != "" {
+		b.files[path] = true
+	}
+}
+
+// addTool records a tool call as a one-line summary, and the file it wrote if
+// it is an editing tool.
+func (b *handoffBuilder) addTool(tool, display string, params map[string]interface{}) {
+	if tool == "" {
+		return
+	}
+	detail := display
+	if detail == "" {
+		detail = tool
+		if arg := handoffToolArgument(params); arg != "" {
+			detail += ": " + arg
+		}
+	}
+	b.add(HandoffRoleTool, firstLine(detail))
+
+	switch tool {
+	case "Edit", "Write", "MultiEdit", "NotebookEdit":
+		if path, ok := params["file_path"].(string); ok {
+			b.addFile(path)
+		}
+	case "apply_patch":
+		for _, value := range params {
+			if patch, ok := value.(string); ok {
+				for _, path := range patchFiles(patch) {
+					b.addFile(path)
+				}
+			}
+		}
+	}
+}
+
+func (b *handoffBuilder) transcript() HandoffTranscript {
+	files := make([]string, 0, len(b.files))
+	for path := range b.files {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return HandoffTranscript{Entries: b.entries, Files: files}
+}
+
+// handoffToolArgument picks the most descriptive argument of a tool call.
+func handoffToolArgument(params map[string]interface{}) string {
+	for _, key := range []string{"command", "cmd", "file_path", "path", "pattern", "url", "description"} {
+		switch v := params[key].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case []interface{}:
+			parts := make([]string, 0, len(v))
+			for _, part := range v {
+				parts = append(parts, fmt.Sprint(part))
+			}
+			if len(parts) > 0 {
+				return strings.Join(parts, " ")
+			}
+		}
+	}
+	return ""
+}
+
+// patchFiles returns the files named in an apply_patch body.
+func patchFiles(patch string) []string {
+	var files []string
+	for _, line := range strings.Split(patch, "\n") {
+		for _, prefix := range []string{"*** Update File: ", "*** Add File: ", "*** Delete File: "} {
+			if strings.HasPrefix(line, prefix) {
+				files = append(files, strings.TrimSpace(strings.TrimPrefix(line, prefix)))
+			}
+		}
+	}
+	return files
+}
+
+func formatHandoffEntry(entry HandoffEntry) string {
+	switch entry.Role {
+	case HandoffRoleUser:
+		return "**User:** " + truncateRunes(entry.Text, handoffUserTextLimit)
+	case HandoffRoleAssistant:
+		return "**Assistant:** " + truncateRunes(entry.Text, handoffAssistantTextLimit)
+	case HandoffRoleError:
+		return "- failed: " + truncateRunes(entry.Text, handoffToolTextLimit)
+	case HandoffRoleSummary:
+		return "**Earlier context (compacted):** " + truncateRunes(entry.Text, handoffAssistantTextLimit)
+	default:
+		return "- " + truncateRunes(entry.Text, handoffToolTextLimit)
+	}
+}
+
+func firstLine(text string) string {
+	text = strings.TrimSpace(text)
+	if idx := strings.IndexAny(text, "\r\n"); idx >= 0 {
+		return strings.TrimSpace(text[:idx])
+	}
+	return text
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-3]) + "..."
+}
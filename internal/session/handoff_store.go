@@ -0,0 +1,203 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/google/uuid"
+)
+
+const (
+	handoffStoreVersion  = 1
+	handoffStoreMaxLinks = 1000
+)
+
+// HandoffLink connects a session to the session on another runtime that
+// continued its conversation.
+type HandoffLink struct {
+	ID              string    `json:"id"`
+	WorkspaceID     string    `json:"workspace_id"`
+	SourceSessionID string    `json:"source_session_id"`
+	SourceAgent     string    `json:"source_agent"`
+	TargetSessionID string    `json:"target_session_id"`
+	TargetAgent     string    `json:"target_agent"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// HandoffStore persists handoff links so history can navigate between the
+// sessions on both sides of a handoff across restarts.
+// A store with an empty path is kept in memory only.
+type HandoffStore struct {
+	mu   sync.Mutex
+	path string
+
+	Version int           `json:"version"`
+	Links   []HandoffLink `json:"links"`
+}
+
+// LoadHandoffStore loads handoff links from disk or returns an empty store.
+func LoadHandoffStore(path string) (*HandoffStore, error) {
+	store := &HandoffStore{path: path, Version: handoffStoreVersion}
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return store, err
+	}
+
+	var stored HandoffStore
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return store, err
+	}
+	if stored.Version != handoffStoreVersion {
+		// Ignore incompatible versions but keep an empty store.
+		return store, nil
+	}
+	store.Links = stored.Links
+	return store, nil
+}
+
+// Add records a link, evicting the oldest once the store is full.
+func (s *HandoffStore) Add(link HandoffLink) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Links = append(s.Links, link)
+	if over := len(s.Links) - handoffStoreMaxLinks; over > 0 {
+		s.Links = append([]HandoffLink(nil), s.Links[over:]...)
+	}
+	return s.saveLocked()
+}
+
+// All returns a copy of every stored link, oldest first.
+func (s *HandoffStore) All() []HandoffLink {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]HandoffLink(nil), s.Links...)
+}
+
+// RemapSession replaces a temporary session ID with its real ID.
+func (s *HandoffStore) RemapSession(oldID, newID string) error {
+	if oldID == "" || newID == "" || oldID == newID {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for i := range s.Links {
+		if s.Links[i].SourceSessionID == oldID {
+			s.Links[i].SourceSessionID = newID
+			changed = true
+		}
+		if s.Links[i].TargetSessionID == oldID {
+			s.Links[i].TargetSessionID = newID
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return s.saveLocked()
+}
+
+func (s *HandoffStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.path)
+}
+
+// SetHandoffStore replaces the store used to persist handoff links.
+func (m *Manager) SetHandoffStore(store *HandoffStore) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handoffs = store
+}
+
+func (m *Manager) handoffStore() *HandoffStore {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.handoffs == nil {
+		m.handoffs, _ = LoadHandoffStore("")
+	}
+	return m.handoffs
+}
+
+// RecordHandoff stores a link between a source session and the session on
+// another runtime that continues it, and publishes a session_handoff event.
+func (m *Manager) RecordHandoff(link HandoffLink) (HandoffLink, error) {
+	if link.SourceSessionID == "" || link.TargetSessionID == "" {
+		return HandoffLink{}, fmt.Errorf("source and target session IDs are required")
+	}
+
+	link.ID = uuid.New().String()
+	link.SourceSessionID = m.resolveSessionID(link.SourceSessionID)
+	link.TargetSessionID = m.resolveSessionID(link.TargetSessionID)
+	link.CreatedAt = time.Now().UTC()
+
+	if err := m.handoffStore().Add(link); err != nil {
+		return HandoffLink{}, fmt.Errorf("failed to save handoff: %w", err)
+	}
+
+	m.PublishEvent(events.NewSessionHandoffEvent(link.WorkspaceID, link.TargetSessionID, events.SessionHandoffPayload{
+		HandoffID:       link.ID,
+		SourceSessionID: link.SourceSessionID,
+		SourceAgent:     link.SourceAgent,
+		TargetSessionID: link.TargetSessionID,
+		TargetAgent:     link.TargetAgent,
+	}))
+	return link, nil
+}
+
+// HandoffLinks returns the links on either side of which sessionID appears.
+// Session IDs are reported in canonical form, so a link recorded against a
+// temporary Claude session ID is found under the real ID once resolved.
+func (m *Manager) HandoffLinks(sessionID string) []HandoffLink {
+	canonicalID := m.resolveSessionID(sessionID)
+	var result []HandoffLink
+	for _, link := range m.handoffStore().All() {
+		link.SourceSessionID = m.resolveSessionID(link.SourceSessionID)
+		link.TargetSessionID = m.resolveSessionID(link.TargetSessionID)
+		if link.SourceSessionID == canonicalID || link.TargetSessionID == canonicalID {
+			result = append(result, link)
+		}
+	}
+	return result
+}
+
+// RemapHandoffSession moves handoff links from a temporary session ID to the
+// real one, for runtimes whose aliases are not tracked by the manager.
+func (m *Manager) RemapHandoffSession(oldID, newID string) {
+	if err := m.handoffStore().RemapSession(oldID, newID); err != nil {
+		m.logger.Warn("Failed to remap handoff session",
+			"old_session_id", oldID,
+			"new_session_id", newID,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,185 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/adapters/codex"
+	"github.com/brianly1003/cdev/internal/adapters/sessioncache"
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/gitutil"
+	"github.com/brianly1003/cdev/internal/testutil"
+)
+
+func handoffElement(t *testing.T, elementType sessioncache.ElementType, content interface{}) sessioncache.Element {
+	t.Helper()
+	data, err := json.Marshal(content)
+	if err != nil {
+		t.Fatalf("marshal content: %v", err)
+	}
+	return sessioncache.Element{Type: elementType, Content: data}
+}
+
+func TestHandoffTranscriptFromElements(t *testing.T) {
+	elements := []sessioncache.Element{
+		handoffElement(t, sessioncache.ElementTypeUserInput, sessioncache.UserInputContent{Text: "Fix the flaky test"}),
+		handoffElement(t, sessioncache.ElementTypeThinking, sessioncache.ThinkingContent{Text: "hmm"}),
+		handoffElement(t, sessioncache.ElementTypeToolCall, sessioncache.ToolCallContent{
+			Tool: "Edit", Display: "Edit(main_test.go)", Params: map[string]interface{}{"file_path": "main_test.go"},
+		}),
+		handoffElement(t, sessioncache.ElementTypeToolResult, sessioncache.ToolResultContent{ToolName: "Bash", IsError: true, Summary: "exit 1"}),
+		handoffElement(t, sessioncache.ElementTypeDiff, sessioncache.DiffContent{FilePath: "helper.go"}),
+		handoffElement(t, sessioncache.ElementTypeAssistantText, sessioncache.AssistantTextContent{Text: "Done."}),
+	}
+
+	transcript := HandoffTranscriptFromElements(elements)
+
+	want := []HandoffEntry{
+		{Role: HandoffRoleUser, Text: "Fix the flaky test"},
+		{Role: HandoffRoleTool, Text: "Edit(main_test.go)"},
+		{Role: HandoffRoleError, Text: "Bash: exit 1"},
+		{Role: HandoffRoleAssistant, Text: "Done."},
+	}
+	if len(transcript.Entries) != len(want) {
+		t.Fatalf("entries = %+v, want %+v", transcript.Entries, want)
+	}
+	for i := range want {
+		if transcript.Entries[i] != want[i] {
+			t.Fatalf("entry %d = %+v, want %+v", i, transcript.Entries[i], want[i])
+		}
+	}
+	if strings.Join(transcript.Files, ",") != "helper.go,main_test.go" {
+		t.Fatalf("files = %v", transcript.Files)
+	}
+}
+
+func TestHandoffTranscriptFromCodex(t *testing.T) {
+	items := []codex.ConversationItem{
+		{Role: "user", Content: []events.ClaudeMessageContent{{Type: "text", Text: "Add a flag"}}},
+		{Role: "assistant", Content: []events.ClaudeMessageContent{{Type: "thinking", Text: "plan"}}},
+		{Role: "assistant", Content: []events.ClaudeMessageContent{{
+			Type: "tool_use", ToolName: "exec_command", ToolInput: map[string]interface{}{"cmd": "go test ./..."},
+		}}},
+		{Role: "assistant", Content: []events.ClaudeMessageContent{{
+			Type: "tool_use", ToolName: "apply_patch", ToolInput: map[string]interface{}{"input": "*** Begin Patch\n*** Update File: cmd/flags.go\n*** End Patch"},
+		}}},
+		{Role: "assistant", Content: []events.ClaudeMessageContent{{Type: "text", Text: "Added."}}},
+	}
+
+	transcript := HandoffTranscriptFromCodex(items)
+
+	if len(transcript.Entries) != 4 {
+		t.Fatalf("entries = %+v", transcript.Entries)
+	}
+	if transcript.Entries[1].Text != "exec_command: go test ./..." {
+		t.Fatalf("tool entry = %q", transcript.Entries[1].Text)
+	}
+	if len(transcript.Files) != 1 || transcript.Files[0] != "cmd/flags.go" {
+		t.Fatalf("files = %v", transcript.Files)
+	}
+}
+
+func TestBuildHandoffDocument_KeepsGoalAndRecentEntries(t *testing.T) {
+	transcript := HandoffTranscript{Files: []string{"a.go"}}
+	transcript.Entries = append(transcript.Entries, HandoffEntry{Role: HandoffRoleUser, Text: "Original goal"})
+	for i := 0; i < 200; i++ {
+		transcript.Entries = append(transcript.Entries, HandoffEntry{Role: HandoffRoleAssistant, Text: strings.Repeat("x", 100)})
+	}
+	transcript.Entries = append(transcript.Entries, HandoffEntry{Role: HandoffRoleAssistant, Text: "Latest step"})
+
+	doc := BuildHandoffDocument(transcript, HandoffSource{AgentType: "claude", SessionID: "s1"}, "Run the tests", 4000)
+
+	if len(doc) > 4000 {
+		t.Fatalf("document length = %d, want <= 4000", len(doc))
+	}
+	for _, want := range []string{"Handoff from claude session s1", "## Original request\n\nOriginal goal", "- a.go", "earlier entries omitted", "Latest step", "## Next step\n\nRun the tests"} {
+		if !strings.Contains(doc, want) {
+			t.Fatalf("document missing %q:\n%s", want, doc)
+		}
+	}
+}
+
+func TestHandoffStore_PersistsAndRemaps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "handoffs.json")
+	store, err := LoadHandoffStore(path)
+	if err != nil {
+		t.Fatalf("LoadHandoffStore() error = %v", err)
+	}
+	if err := store.Add(HandoffLink{ID: "h1", SourceSessionID: "claude-1", TargetSessionID: "tmp-codex"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := store.RemapSession("tmp-codex", "codex-1"); err != nil {
+		t.Fatalf("RemapSession() error = %v", err)
+	}
+
+	reloaded, err := LoadHandoffStore(path)
+	if err != nil {
+		t.Fatalf("reload error = %v", err)
+	}
+	links := reloaded.All()
+	if len(links) != 1 || links[0].TargetSessionID != "codex-1" {
+		t.Fatalf("links = %+v", links)
+	}
+}
+
+func TestManagerRecordHandoff_LinksBothSessions(t *testing.T) {
+	manager := newSessionTestManager(t, "ws-1", t.TempDir())
+	manager.sessionAliases["tmp-claude"] = "claude-real"
+
+	link, err := manager.RecordHandoff(HandoffLink{
+		WorkspaceID:     "ws-1",
+		SourceSessionID: "codex-1",
+		SourceAgent:     "codex",
+		TargetSessionID: "tmp-claude",
+		TargetAgent:     "claude",
+	})
+	if err != nil {
+		t.Fatalf("RecordHandoff() error = %v", err)
+	}
+	if link.ID == "" || link.TargetSessionID != "claude-real" {
+		t.Fatalf("link = %+v", link)
+	}
+
+	for _, id := range []string{"codex-1", "claude-real", "tmp-claude"} {
+		if links := manager.HandoffLinks(id); len(links) != 1 || links[0].ID != link.ID {
+			t.Fatalf("HandoffLinks(%q) = %+v", id, links)
+		}
+	}
+	if links := manager.HandoffLinks("other"); len(links) != 0 {
+		t.Fatalf("unexpected links for unrelated session: %+v", links)
+	}
+
+	hub := manager.hub.(*testutil.MockEventHub)
+	published := hub.PublishedEvents()
+	if len(published) == 0 || published[len(published)-1].Type() != events.EventTypeSessionHandoff {
+		t.Fatal("expected session_handoff event")
+	}
+}
+
+func TestManagerClaudeHandoffTranscript(t *testing.T) {
+	workspacePath := t.TempDir()
+	manager := newSessionTestManager(t, "ws-1", workspacePath)
+
+	sessionsDir := getSessionsDir(gitutil.NormalizePath(workspacePath))
+	if err := os.MkdirAll(sessionsDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	lines := []string{
+		`{"type":"user","uuid":"u1","sessionId":"s1","timestamp":"2026-01-01T00:00:00Z","message":{"role":"user","content":"Rename the package"}}`,
+		`{"type":"assistant","uuid":"a1","sessionId":"s1","timestamp":"2026-01-01T00:00:01Z","message":{"role":"assistant","content":[{"type":"text","text":"Renamed it."}]}}`,
+	}
+	if err := os.WriteFile(filepath.Join(sessionsDir, "s1.jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write session: %v", err)
+	}
+
+	transcript, err := manager.ClaudeHandoffTranscript("ws-1", "s1")
+	if err != nil {
+		t.Fatalf("ClaudeHandoffTranscript() error = %v", err)
+	}
+	if transcript.FirstUserText() != "Rename the package" || len(transcript.Entries) != 2 {
+		t.Fatalf("transcript = %+v", transcript)
+	}
+}
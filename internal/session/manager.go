@@ -22,6 +22,7 @@ import (
 	"github.com/brianly1003/cdev/internal/domain/ports"
 	"github.com/brianly1003/cdev/internal/gitutil"
 	"github.com/brianly1003/cdev/internal/pathutil"
+	"github.com/brianly1003/cdev/internal/permission"
 	"github.com/brianly1003/cdev/internal/sync"
 	"github.com/brianly1003/cdev/internal/workspace"
 	"github.com/fsnotify/fsnotify"
@@ -64,6 +65,8 @@ type Manager struct {
 	cfg                     *config.Config
 	logger                  *slog.Logger
 	historicalPathResolver  historicalSessionProjectPathResolver
+	agentProcesses          agentProcessProvider
+	agentResourceStreaks    map[int]int // PID of an agent process -> samples in a row over a limit; resource monitor only
 
 	// Configuration
 	idleTimeout time.Duration
@@ -87,6 +90,9 @@ type Manager struct {
 	sessionFileWatchers   map[string]sessionFileWatcherEntry // temporary session ID -> watcher entry
 	sessionFileWatchersMu sync.Mutex
 
+	// Cross-runtime handoff links
+	handoffs *HandoffStore
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	mu     sync.RWMutex
@@ -145,6 +151,9 @@ func (m *Manager) Start() error {
 	// Start idle session monitor
 	go m.idleMonitor()
 
+	// Sample agent process trees for session/state and resource limits
+	go m.resourceMonitor()
+
 	return nil
 }
 
@@ -406,6 +415,15 @@ func (m *Manager) ResolveSessionID(sessionID string) string {
 	return m.resolveSessionID(sessionID)
 }
 
+// SetAgentProcessProvider installs a provider of agent processes that run
+// outside the manager's sessions, such as Codex PTY sessions, so the resource
+// monitor samples and limits them too.
+func (m *Manager) SetAgentProcessProvider(provider agentProcessProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.agentProcesses = provider
+}
+
 // SetHistoricalSessionProjectPathResolver installs a fallback resolver for
 // historical session project paths that are no longer discoverable from the
 // live workspace layout, such as cleaned-up task worktrees.
@@ -726,6 +744,13 @@ func (m *Manager) ListWorkspaces() []*workspace.Workspace {
 // It automatically uses the most recent historical session ID from ~/.claude/projects/
 // so that session/send with mode "continue" will properly resume the conversation.
 func (m *Manager) StartSession(workspaceID string) (*Session, error) {
+	return m.StartSessionWithProfile(workspaceID, "")
+}
+
+// StartSessionWithProfile starts a new Claude session for a workspace using a
+// named launch profile from the workspace definition. An empty profileName
+// uses the global Claude configuration, like StartSession.
+func (m *Manager) StartSessionWithProfile(workspaceID, profileName string) (*Session, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -735,9 +760,21 @@ func (m *Manager) StartSession(workspaceID string) (*Session, error) {
 		return nil, fmt.Errorf("workspace not found: %s", workspaceID)
 	}
 
+	// Resolve the launch profile before creating anything so a bad profile
+	// (e.g. a missing secret) fails the start cleanly.
+	projectPath := ws.Definition.Path
+	var launch *LaunchSettings
+	if profileName != "" {
+		var err error
+		if launch, err = resolveLaunchProfile(&ws.Definition, profileName); err != nil {
+			return nil, err
+		}
+		projectPath = launch.WorkDir
+	}
+
 	// Get the most recent historical session ID for this workspace
 	// This allows session/send with mode "continue" to resume the conversation
-	sessionID := m.getMostRecentHistoricalSessionID(ws.Definition.Path)
+	sessionID := m.getMostRecentHistoricalSessionID(projectPath)
 	if sessionID == "" {
 		// No historical session found, generate a new UUID
 		sessionID = uuid.New().String()
@@ -753,7 +790,8 @@ func (m *Manager) StartSession(workspaceID string) (*Session, error) {
 	}
 
 	session := NewSession(sessionID, workspaceID)
-	session.ProjectPath = ws.Definition.Path
+	session.ProjectPath = projectPath
+	session.Profile = profileName
 	session.SetStatus(StatusStarting)
 
 	// Create Claude manager for this session
@@ -763,11 +801,14 @@ func (m *Manager) StartSession(workspaceID string) (*Session, error) {
 		m.cfg.Claude.Args,
 		m.cfg.Claude.TimeoutMinutes,
 		m.cfg.Claude.SkipPermissions,
-		ws.Definition.Path,
+		projectPath,
 		workspaceID,
 		sessionID,
 		&m.cfg.Logging.Rotation,
 	)
+	if launch != nil {
+		launch.applyToClaude(claudeManager)
+	}
 	session.SetClaudeManager(claudeManager)
 
 	// Create git tracker for this workspace
@@ -797,7 +838,7 @@ func (m *Manager) StartSession(workspaceID string) (*Session, error) {
 
 	// Store session
 	m.sessions[sessionID] = session
-	m.sessionProjectPaths[sessionID] = ws.Definition.Path
+	m.sessionProjectPaths[sessionID] = projectPath
 	session.SetStatus(StatusRunning)
 
 	// Auto-activate this session for the workspace
@@ -807,7 +848,8 @@ func (m *Manager) StartSession(workspaceID string) (*Session, error) {
 		"session_id", sessionID,
 		"workspace_id", workspaceID,
 		"workspace_name", ws.Definition.Name,
-		"path", ws.Definition.Path,
+		"path", projectPath,
+		"profile", profileName,
 	)
 
 	return session, nil
@@ -2192,6 +2234,75 @@ func (m *Manager) RespondToPermission(sessionID string, allow bool) error {
 	return cm.SendResponse(toolUseID, "no", false)
 }
 
+// RespondToPermissionWithEdit approves a pending permission request with
+// edited tool input. Claude cannot run an edited call from its own prompt, so
+// the original call is rejected and Claude is told to retry with the edited
+// input: in PTY mode the prompt is dismissed and the instruction typed in, in
+// stream mode it is returned as the tool result.
+func (m *Manager) RespondToPermissionWithEdit(sessionID string, updatedInput map[string]interface{}) error {
+	session, err := m.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session.GetStatus() != StatusRunning {
+		return fmt.Errorf("session not running: %s", sessionID)
+	}
+
+	cm := session.ClaudeManager()
+	if cm == nil {
+		return fmt.Errorf("no Claude manager for session: %s", sessionID)
+	}
+
+	session.UpdateLastActive()
+
+	if cm.IsPTYMode() {
+		prompt := cm.GetPendingPTYPermission()
+		if prompt == nil {
+			return fmt.Errorf("no pending permission request")
+		}
+		toolName, original := ptyPermissionToolInput(prompt)
+		if toolName == "" {
+			return fmt.Errorf("%s prompts cannot be approved with edited input", prompt.Type)
+		}
+		if err := permission.ValidateUpdatedInput(toolName, original, updatedInput); err != nil {
+			return err
+		}
+
+		// Escape picks "No, and tell Claude what to do differently"
+		if err := cm.SendPTYInput("\x1b"); err != nil {
+			return err
+		}
+		// Give Claude a moment to return to its input prompt
+		time.Sleep(300 * time.Millisecond)
+		return cm.SendPTYInput(permission.EditInstruction(toolName, updatedInput))
+	}
+
+	toolUseID, toolName := cm.GetPendingToolUse()
+	if toolUseID == "" {
+		return fmt.Errorf("no pending permission request")
+	}
+	if err := permission.ValidateUpdatedInput(toolName, nil, updatedInput); err != nil {
+		return err
+	}
+	return cm.SendResponse(toolUseID, permission.EditInstruction(toolName, updatedInput), true)
+}
+
+// ptyPermissionToolInput maps a parsed PTY permission prompt to the tool it
+// asks for and the input visible in the prompt. Prompts that cannot be
+// edited return an empty tool name.
+func ptyPermissionToolInput(prompt *claude.PTYPermissionPrompt) (string, map[string]interface{}) {
+	switch prompt.Type {
+	case claude.PermissionTypeBashCommand:
+		return "Bash", map[string]interface{}{"command": prompt.Target}
+	case claude.PermissionTypeWriteFile:
+		return "Write", map[string]interface{}{"file_path": prompt.Target}
+	case claude.PermissionTypeEditFile:
+		return "Edit", map[string]interface{}{"file_path": prompt.Target}
+	}
+	return "", nil
+}
+
 // RespondToQuestion responds to an interactive question in a session.
 func (m *Manager) RespondToQuestion(sessionID string, response string) error {
 	session, err := m.GetSession(sessionID)
@@ -2392,13 +2503,15 @@ func (m *Manager) GitPush(workspaceID string, force bool, setUpstream bool, remo
 	return tracker.Push(m.ctx, force, setUpstream, remote, branch)
 }
 
-// GitPull pulls changes from remote for a workspace.
-func (m *Manager) GitPull(workspaceID string, rebase bool) (*git.PullResult, error) {
+// GitPull pulls changes from remote for a workspace, reporting the
+// progress of the transfer to progress if it is not nil. Cancelling ctx
+// stops the pull.
+func (m *Manager) GitPull(ctx context.Context, workspaceID string, rebase bool, progress ports.ProgressFunc) (*git.PullResult, error) {
 	tracker, err := m.getGitTracker(workspaceID)
 	if err != nil {
 		return nil, err
 	}
-	return tracker.Pull(m.ctx, rebase)
+	return tracker.Pull(ports.WithProgress(ctx, progress), rebase)
 }
 
 // GitBranches lists branches for a workspace.
@@ -2439,13 +2552,15 @@ func (m *Manager) GitDeleteBranch(workspaceID string, branch string, force bool)
 	return tracker.DeleteBranch(m.ctx, branch, force)
 }
 
-// GitFetch fetches from a remote for a workspace.
-func (m *Manager) GitFetch(workspaceID string, remote string, prune bool) (*git.FetchResult, error) {
+// GitFetch fetches from a remote for a workspace, reporting the progress
+// of the transfer to progress if it is not nil. Cancelling ctx stops the
+// fetch.
+func (m *Manager) GitFetch(ctx context.Context, workspaceID string, remote string, prune bool, progress ports.ProgressFunc) (*git.FetchResult, error) {
 	tracker, err := m.getGitTracker(workspaceID)
 	if err != nil {
 		return nil, err
 	}
-	return tracker.Fetch(m.ctx, remote, prune)
+	return tracker.Fetch(ports.WithProgress(ctx, progress), remote, prune)
 }
 
 // GitLog returns the commit log for a workspace.
@@ -2627,6 +2742,10 @@ type HistoryInfo struct {
 	Status       string     `json:"status,omitempty"`
 	StartedAt    *time.Time `json:"started_at,omitempty"`
 	LastActive   *time.Time `json:"last_active,omitempty"`
+
+	// Handoffs links this session to sessions on other runtimes that
+	// continued it, or that it continues.
+	Handoffs []HandoffLink `json:"handoffs,omitempty"`
 }
 
 // ListHistory returns historical Claude sessions for a workspace.
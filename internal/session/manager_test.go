@@ -1,7 +1,9 @@
 package session
 
 import (
+	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 )
@@ -180,3 +182,28 @@ func TestDeleteHistorySession_NonExistent(t *testing.T) {
 	}
 }
 
+func TestManager_GitFetchPullFollowRequestContext(t *testing.T) {
+	workspaceID := "workspace-fetch"
+	remote := initSnapshotWorkspace(t)
+	workspacePath := initSnapshotWorkspace(t)
+	cmd := exec.Command("git", "remote", "add", "origin", remote)
+	cmd.Dir = workspacePath
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git remote add: %v\n%s", err, out)
+	}
+	manager := newSessionTestManager(t, workspaceID, workspacePath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if result, err := manager.GitFetch(ctx, workspaceID, "origin", false, nil); err == nil && result.Success {
+		t.Error("GitFetch with a cancelled context should fail")
+	}
+	if result, err := manager.GitPull(ctx, workspaceID, false, nil); err == nil && result.Success {
+		t.Error("GitPull with a cancelled context should fail")
+	}
+
+	result, err := manager.GitFetch(context.Background(), workspaceID, "origin", false, nil)
+	if err != nil || !result.Success {
+		t.Fatalf("GitFetch = %+v, %v; want success", result, err)
+	}
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/brianly1003/cdev/internal/adapters/claude"
+	"github.com/brianly1003/cdev/internal/config"
+)
+
+// LaunchSettings is a workspace launch profile resolved for a single session:
+// env references are expanded and the working directory is made absolute.
+type LaunchSettings struct {
+	Profile *config.LaunchProfile
+	WorkDir string
+	Env     []string
+}
+
+// ResolveLaunchProfile resolves a named launch profile of a workspace.
+// It fails if the profile does not exist or references unset env variables.
+func (m *Manager) ResolveLaunchProfile(workspaceID, profileName string) (*LaunchSettings, error) {
+	m.mu.RLock()
+	ws, ok := m.workspaces[workspaceID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("workspace not found: %s", workspaceID)
+	}
+	return resolveLaunchProfile(&ws.Definition, profileName)
+}
+
+// LaunchProfiles returns the launch profiles of every registered workspace,
+// keyed by workspace ID. Workspaces without profiles are omitted.
+func (m *Manager) LaunchProfiles() map[string][]config.LaunchProfile {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make(map[string][]config.LaunchProfile)
+	for id, ws := range m.workspaces {
+		if len(ws.Definition.Profiles) > 0 {
+			result[id] = append([]config.LaunchProfile(nil), ws.Definition.Profiles...)
+		}
+	}
+	return result
+}
+
+func resolveLaunchProfile(def *config.WorkspaceDefinition, profileName string) (*LaunchSettings, error) {
+	profile, ok := def.FindLaunchProfile(profileName)
+	if !ok {
+		return nil, fmt.Errorf("launch profile not found: %s", profileName)
+	}
+
+	workDir, err := profile.ResolveWorkDir(def.Path)
+	if err != nil {
+		return nil, err
+	}
+	env, err := profile.ResolveEnv(os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LaunchSettings{
+		Profile: profile,
+		WorkDir: workDir,
+		Env:     env,
+	}, nil
+}
+
+// ClaudeArgs returns the Claude CLI arguments for the profile.
+func (l *LaunchSettings) ClaudeArgs() []string {
+	args := append([]string(nil), l.Profile.Args...)
+	if l.Profile.Model != "" {
+		args = append(args, "--model", l.Profile.Model)
+	}
+	if len(l.Profile.AllowedTools) > 0 {
+		args = append(args, "--allowedTools", strings.Join(l.Profile.AllowedTools, ","))
+	}
+	return args
+}
+
+// applyToClaude configures a session's Claude manager with the profile.
+func (l *LaunchSettings) applyToClaude(cm *claude.Manager) {
+	cm.SetWorkDir(l.WorkDir)
+	cm.SetLaunchArgs(l.ClaudeArgs())
+	cm.SetLaunchEnv(l.Env)
+	cm.SetLaunchPermissionMode(l.Profile.PermissionMode)
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/config"
+)
+
+func TestStartSessionWithProfile_AppliesWorkDirAndProfile(t *testing.T) {
+	workspaceID := "workspace-profiles"
+	workspacePath := t.TempDir()
+	if err := os.Mkdir(filepath.Join(workspacePath, "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	manager := newSessionTestManager(t, workspaceID, workspacePath)
+
+	ws, err := manager.GetWorkspace(workspaceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CDEV_TEST_PROFILE_KEY", "secret")
+	ws.Definition.Profiles = []config.LaunchProfile{
+		{Name: "frontend", WorkDir: "web", Model: "sonnet", Env: []string{"API_KEY=${CDEV_TEST_PROFILE_KEY}"}},
+		{Name: "broken", Env: []string{"API_KEY=${CDEV_TEST_PROFILE_UNSET}"}},
+	}
+
+	sess, err := manager.StartSessionWithProfile(workspaceID, "frontend")
+	if err != nil {
+		t.Fatalf("StartSessionWithProfile failed: %v", err)
+	}
+	if sess.ProjectPath != filepath.Join(workspacePath, "web") {
+		t.Fatalf("ProjectPath = %q, want profile work_dir", sess.ProjectPath)
+	}
+	if info := sess.ToInfo(); info.Profile != "frontend" {
+		t.Fatalf("Info.Profile = %q, want frontend", info.Profile)
+	}
+
+	if _, err := manager.StartSessionWithProfile(workspaceID, "broken"); err == nil || !strings.Contains(err.Error(), "CDEV_TEST_PROFILE_UNSET") {
+		t.Fatalf("expected unset env error, got %v", err)
+	}
+	if _, err := manager.StartSessionWithProfile(workspaceID, "missing"); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+
+	profiles := manager.LaunchProfiles()
+	if len(profiles[workspaceID]) != 2 {
+		t.Fatalf("LaunchProfiles()[%s] = %+v", workspaceID, profiles[workspaceID])
+	}
+}
+
+func TestLaunchSettingsClaudeArgs(t *testing.T) {
+	settings := &LaunchSettings{Profile: &config.LaunchProfile{
+		Args:         []string{"--verbose"},
+		Model:        "opus",
+		AllowedTools: []string{"Read", "Grep"},
+	}}
+
+	got := strings.Join(settings.ClaudeArgs(), " ")
+	if want := "--verbose --model opus --allowedTools Read,Grep"; got != want {
+		t.Fatalf("ClaudeArgs() = %q, want %q", got, want)
+	}
+}
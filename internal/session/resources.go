@@ -0,0 +1,196 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/brianly1003/cdev/internal/adapters/procstat"
+	"github.com/brianly1003/cdev/internal/config"
+	"github.com/brianly1003/cdev/internal/domain/events"
+)
+
+// AgentProcess is an agent process that runs outside the manager's sessions,
+// such as a Codex PTY session.
+type AgentProcess struct {
+	WorkspaceID string
+	SessionID   string
+	PID         int
+	Stop        func() error // Stops the session when it stays over a limit
+}
+
+type agentProcessProvider interface {
+	AgentProcesses() []AgentProcess
+}
+
+// resourceMonitor periodically samples the agent process tree of each running
+// session, and of those set with SetAgentProcessProvider. It publishes
+// session_resources events and stops sessions that stay over a limit.
+func (m *Manager) resourceMonitor() {
+	if m.cfg == nil || !m.cfg.Resources.Enabled || m.cfg.Resources.IntervalSeconds <= 0 {
+		return
+	}
+
+	sampler := procstat.NewSampler()
+	if _, err := sampler.Sample(os.Getpid()); errors.Is(err, procstat.ErrUnsupported) {
+		m.logger.Info("Resource monitoring not supported on this platform")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(m.cfg.Resources.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.sampleSessionResources(sampler)
+		}
+	}
+}
+
+// sampleSessionResources takes one resource sample of every session.
+func (m *Manager) sampleSessionResources(sampler *procstat.Sampler) {
+	m.mu.RLock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	seen := make(map[*Session]bool, len(m.sessions))
+	for _, sess := range m.sessions {
+		if !seen[sess] {
+			seen[sess] = true
+			sessions = append(sessions, sess)
+		}
+	}
+	provider := m.agentProcesses
+	m.mu.RUnlock()
+
+	for _, sess := range sessions {
+		pid := 0
+		if cm := sess.ClaudeManager(); cm != nil {
+			pid = cm.PID()
+		}
+		if pid <= 0 {
+			if prev := sess.clearResources(); prev != nil {
+				sampler.Forget(prev.PID)
+			}
+			continue
+		}
+
+		usage, err := sampler.Sample(pid)
+		if err != nil {
+			if errors.Is(err, procstat.ErrProcessNotFound) {
+				sampler.Forget(pid)
+				sess.clearResources()
+			}
+			continue
+		}
+
+		m.applyResourceSample(sampler, sess.WorkspaceID, sess.GetID(), usage,
+			func(overLimit bool) int { return sess.recordResources(usage, overLimit) },
+			func() error {
+				m.mu.Lock()
+				defer m.mu.Unlock()
+				return m.stopSessionInternal(sess)
+			})
+	}
+
+	if provider != nil {
+		m.sampleAgentProcesses(sampler, provider.AgentProcesses())
+	}
+}
+
+// sampleAgentProcesses takes one resource sample of every agent process that
+// runs outside the manager's sessions.
+func (m *Manager) sampleAgentProcesses(sampler *procstat.Sampler, processes []AgentProcess) {
+	running := make(map[int]bool, len(processes))
+	for _, proc := range processes {
+		if proc.PID <= 0 {
+			continue
+		}
+		running[proc.PID] = true
+
+		usage, err := sampler.Sample(proc.PID)
+		if err != nil {
+			if errors.Is(err, procstat.ErrProcessNotFound) {
+				delete(running, proc.PID)
+			}
+			continue
+		}
+
+		if m.agentResourceStreaks == nil {
+			m.agentResourceStreaks = make(map[int]int)
+		}
+		pid := proc.PID
+		m.applyResourceSample(sampler, proc.WorkspaceID, proc.SessionID, usage,
+			func(overLimit bool) int {
+				if !overLimit {
+					m.agentResourceStreaks[pid] = 0
+					return 0
+				}
+				m.agentResourceStreaks[pid]++
+				return m.agentResourceStreaks[pid]
+			},
+			proc.Stop)
+	}
+
+	// Forget processes that have exited.
+	for pid := range m.agentResourceStreaks {
+		if !running[pid] {
+			delete(m.agentResourceStreaks, pid)
+			sampler.Forget(pid)
+		}
+	}
+}
+
+// applyResourceSample records a sample with record, stops the session with
+// stop when it has been over a limit for the configured number of samples,
+// and publishes the sample as a session_resources event.
+func (m *Manager) applyResourceSample(sampler *procstat.Sampler, workspaceID, sessionID string, usage *procstat.Usage, record func(overLimit bool) int, stop func() error) {
+	limits := m.cfg.Resources
+	exceeded := exceededResourceLimit(&limits, usage)
+	streak := record(exceeded != "")
+	stopped := exceeded != "" && streak >= limits.LimitSamples && stop != nil
+
+	if stopped {
+		m.logger.Warn("Stopping session over resource limit",
+			"session_id", sessionID,
+			"workspace_id", workspaceID,
+			"limit", exceeded,
+			"pid", usage.PID,
+			"cpu_percent", usage.CPUPercent,
+			"rss_bytes", usage.RSSBytes,
+			"open_files", usage.OpenFiles,
+			"processes", usage.Processes,
+		)
+		_ = stop()
+		sampler.Forget(usage.PID)
+	}
+
+	m.PublishEvent(events.NewSessionResourcesEvent(workspaceID, sessionID, events.SessionResourcesPayload{
+		PID:           usage.PID,
+		Processes:     usage.Processes,
+		CPUPercent:    usage.CPUPercent,
+		RSSBytes:      usage.RSSBytes,
+		OpenFiles:     usage.OpenFiles,
+		SampledAt:     usage.SampledAt,
+		LimitExceeded: exceeded,
+		Stopped:       stopped,
+	}))
+}
+
+// exceededResourceLimit returns a description of the first configured limit
+// the sample is over, or "" if it is within all limits.
+func exceededResourceLimit(limits *config.ResourcesConfig, usage *procstat.Usage) string {
+	switch {
+	case limits.MaxCPUPercent > 0 && usage.CPUPercent > limits.MaxCPUPercent:
+		return fmt.Sprintf("max_cpu_percent=%g", limits.MaxCPUPercent)
+	case limits.MaxRSSMB > 0 && usage.RSSBytes > uint64(limits.MaxRSSMB)*1024*1024:
+		return fmt.Sprintf("max_rss_mb=%d", limits.MaxRSSMB)
+	case limits.MaxOpenFiles > 0 && usage.OpenFiles > limits.MaxOpenFiles:
+		return fmt.Sprintf("max_open_files=%d", limits.MaxOpenFiles)
+	case limits.MaxProcesses > 0 && usage.Processes > limits.MaxProcesses:
+		return fmt.Sprintf("max_processes=%d", limits.MaxProcesses)
+	}
+	return ""
+}
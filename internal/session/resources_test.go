@@ -0,0 +1,114 @@
+package session
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/brianly1003/cdev/internal/adapters/procstat"
+	"github.com/brianly1003/cdev/internal/config"
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/brianly1003/cdev/internal/testutil"
+)
+
+func TestExceededResourceLimit(t *testing.T) {
+	limits := &config.ResourcesConfig{MaxCPUPercent: 150, MaxRSSMB: 512, MaxProcesses: 20}
+
+	tests := []struct {
+		name  string
+		usage procstat.Usage
+		want  string
+	}{
+		{"within limits", procstat.Usage{CPUPercent: 80, RSSBytes: 100 << 20, Processes: 3}, ""},
+		{"cpu", procstat.Usage{CPUPercent: 220}, "max_cpu_percent=150"},
+		{"rss", procstat.Usage{RSSBytes: 600 << 20}, "max_rss_mb=512"},
+		{"processes", procstat.Usage{Processes: 40}, "max_processes=20"},
+		{"open files unlimited", procstat.Usage{OpenFiles: 100000}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exceededResourceLimit(limits, &tt.usage); got != tt.want {
+				t.Errorf("exceededResourceLimit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionRecordResources(t *testing.T) {
+	sess := NewSession("s1", "ws1")
+	usage := &procstat.Usage{PID: 42, Processes: 3}
+
+	if got := sess.recordResources(usage, true); got != 1 {
+		t.Fatalf("streak = %d, want 1", got)
+	}
+	if got := sess.recordResources(usage, true); got != 2 {
+		t.Fatalf("streak = %d, want 2", got)
+	}
+	if got := sess.recordResources(usage, false); got != 0 {
+		t.Fatalf("streak after recovery = %d, want 0", got)
+	}
+
+	if state := sess.ToRuntimeState(); state.Resources != usage {
+		t.Fatalf("RuntimeState.Resources = %+v, want latest sample", state.Resources)
+	}
+
+	if prev := sess.clearResources(); prev != usage {
+		t.Fatalf("clearResources() = %+v, want previous sample", prev)
+	}
+	if sess.Resources() != nil {
+		t.Fatal("Resources() should be nil after clear")
+	}
+}
+
+type agentProcessFunc func() []AgentProcess
+
+func (f agentProcessFunc) AgentProcesses() []AgentProcess { return f() }
+
+func TestSampleSessionResources_AgentProcesses(t *testing.T) {
+	sampler := procstat.NewSampler()
+	if _, err := sampler.Sample(os.Getpid()); errors.Is(err, procstat.ErrUnsupported) {
+		t.Skip("resource sampling not supported on this platform")
+	}
+
+	hub := testutil.NewMockEventHub()
+	m := NewManager(hub, newSessionTestConfig(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	m.cfg.Resources = config.ResourcesConfig{Enabled: true, MaxRSSMB: 1, LimitSamples: 2}
+
+	stops := 0
+	m.SetAgentProcessProvider(agentProcessFunc(func() []AgentProcess {
+		return []AgentProcess{{
+			WorkspaceID: "ws-1",
+			SessionID:   "codex-1",
+			PID:         os.Getpid(),
+			Stop:        func() error { stops++; return nil },
+		}}
+	}))
+
+	m.sampleSessionResources(sampler)
+	if stops != 0 {
+		t.Fatal("stopped after the first sample over the limit")
+	}
+	m.sampleSessionResources(sampler)
+	if stops != 1 {
+		t.Fatalf("stops = %d, want 1 after two samples over the limit", stops)
+	}
+
+	var payloads []events.SessionResourcesPayload
+	for _, event := range hub.PublishedEvents() {
+		if event.Type() == events.EventTypeSessionResources && event.GetSessionID() == "codex-1" {
+			payloads = append(payloads, event.(*events.BaseEvent).Payload.(events.SessionResourcesPayload))
+		}
+	}
+	if len(payloads) != 2 || payloads[0].LimitExceeded != "max_rss_mb=1" || payloads[0].Stopped || !payloads[1].Stopped {
+		t.Fatalf("session_resources payloads = %+v", payloads)
+	}
+
+	// Exited processes are forgotten.
+	m.SetAgentProcessProvider(agentProcessFunc(func() []AgentProcess { return nil }))
+	m.sampleSessionResources(sampler)
+	if len(m.agentResourceStreaks) != 0 {
+		t.Fatalf("streaks kept for exited processes: %v", m.agentResourceStreaks)
+	}
+}
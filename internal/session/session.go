@@ -6,6 +6,7 @@ import (
 
 	"github.com/brianly1003/cdev/internal/adapters/claude"
 	"github.com/brianly1003/cdev/internal/adapters/git"
+	"github.com/brianly1003/cdev/internal/adapters/procstat"
 	"github.com/brianly1003/cdev/internal/adapters/watcher"
 	"github.com/brianly1003/cdev/internal/sync"
 )
@@ -26,6 +27,7 @@ type Session struct {
 	ID          string    `json:"id"`
 	WorkspaceID string    `json:"workspace_id"`
 	ProjectPath string    `json:"project_path,omitempty"`
+	Profile     string    `json:"profile,omitempty"` // Launch profile the session was started with
 	Status      Status    `json:"status"`
 	StartedAt   time.Time `json:"started_at"`
 	LastActive  time.Time `json:"last_active"`
@@ -36,6 +38,10 @@ type Session struct {
 	fileWatcher   *watcher.Watcher
 	gitTracker    *git.Tracker
 
+	// Resource monitoring (latest sample and consecutive over-limit count)
+	resources       *procstat.Usage
+	overLimitStreak int
+
 	mu sync.RWMutex
 }
 
@@ -153,6 +159,38 @@ func (s *Session) SetGitTracker(t *git.Tracker) {
 	s.gitTracker = t
 }
 
+// Resources returns the latest resource sample of the session's process tree.
+func (s *Session) Resources() *procstat.Usage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.resources
+}
+
+// recordResources stores a resource sample and returns how many samples in a
+// row have been over a limit, including this one.
+func (s *Session) recordResources(usage *procstat.Usage, overLimit bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources = usage
+	if overLimit {
+		s.overLimitStreak++
+	} else {
+		s.overLimitStreak = 0
+	}
+	return s.overLimitStreak
+}
+
+// clearResources drops the resource sample once the agent process has exited
+// and returns the previous sample, if any.
+func (s *Session) clearResources() *procstat.Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.resources
+	s.resources = nil
+	s.overLimitStreak = 0
+	return prev
+}
+
 // ToInfo returns a serializable session info.
 func (s *Session) ToInfo() *Info {
 	s.mu.RLock()
@@ -161,6 +199,7 @@ func (s *Session) ToInfo() *Info {
 		ID:          s.ID,
 		WorkspaceID: s.WorkspaceID,
 		ProjectPath: s.ProjectPath,
+		Profile:     s.Profile,
 		Status:      s.Status,
 		StartedAt:   s.StartedAt,
 		LastActive:  s.LastActive,
@@ -173,6 +212,7 @@ type Info struct {
 	ID          string    `json:"id"`
 	WorkspaceID string    `json:"workspace_id"`
 	ProjectPath string    `json:"project_path,omitempty"`
+	Profile     string    `json:"profile,omitempty"`
 	Status      Status    `json:"status"`
 	StartedAt   time.Time `json:"started_at"`
 	LastActive  time.Time `json:"last_active"`
@@ -192,6 +232,10 @@ type RuntimeState struct {
 	LastActive  time.Time `json:"last_active"`
 	Error       string    `json:"error,omitempty"`
 
+	// Agent process tree
+	PID       int             `json:"pid,omitempty"`
+	Resources *procstat.Usage `json:"resources,omitempty"` // Latest sample, nil when not monitored
+
 	// Claude state
 	ClaudeState      string `json:"claude_state"`      // idle, running, error, waiting
 	ClaudeSessionID  string `json:"claude_session_id"` // For conversation continuity
@@ -213,10 +257,12 @@ func (s *Session) ToRuntimeState() *RuntimeState {
 		StartedAt:   s.StartedAt,
 		LastActive:  s.LastActive,
 		Error:       s.Error,
+		Resources:   s.resources,
 	}
 
 	// Get Claude manager state if available
 	if s.claudeManager != nil {
+		state.PID = s.claudeManager.PID()
 		state.ClaudeState = string(s.claudeManager.State())
 		state.ClaudeSessionID = s.claudeManager.ClaudeSessionID()
 		state.IsRunning = s.claudeManager.IsRunning()
@@ -0,0 +1,284 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brianly1003/cdev/internal/adapters/git"
+	"github.com/brianly1003/cdev/internal/domain/events"
+	"github.com/google/uuid"
+)
+
+// CaptureTurnSnapshot records the working tree of a session's project before a
+// prompt turn so a bad agent edit can be rolled back later.
+// It is a no-op (nil snapshot, nil error) when snapshots are disabled.
+// workspaceID may be empty when the session is already known to the manager.
+func (m *Manager) CaptureTurnSnapshot(workspaceID, sessionID, label string) (*git.Snapshot, error) {
+	if m.cfg == nil || !m.cfg.Git.Snapshots.Enabled {
+		return nil, nil
+	}
+
+	sessionID = m.resolveSessionID(sessionID)
+	tracker, workspaceID, err := m.snapshotTracker(workspaceID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, created, err := tracker.CreateSnapshot(m.ctx, sessionID, label, m.cfg.Git.Snapshots.MaxPerSession)
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		m.logger.Debug("Captured session snapshot",
+			"session_id", sessionID,
+			"workspace_id", workspaceID,
+			"snapshot_id", snapshot.ID,
+		)
+		m.PublishEvent(events.NewSessionSnapshotEvent(workspaceID, sessionID, events.SessionSnapshotPayload{
+			Action:     "created",
+			SnapshotID: snapshot.ID,
+			Label:      snapshot.Label,
+			Commit:     snapshot.Commit,
+		}))
+	}
+
+	return snapshot, nil
+}
+
+// CaptureStartSnapshot records the working tree of a workspace before a new
+// session is launched, while the session has no ID yet. The snapshot is kept
+// under the returned pending ID until AdoptStartSnapshot files it under the
+// session. It is a no-op ("", nil error) when snapshots are disabled.
+func (m *Manager) CaptureStartSnapshot(workspaceID, label string) (string, error) {
+	if m.cfg == nil || !m.cfg.Git.Snapshots.Enabled {
+		return "", nil
+	}
+	if strings.TrimSpace(workspaceID) == "" {
+		return "", fmt.Errorf("workspace_id is required")
+	}
+
+	tracker, err := m.getGitTracker(workspaceID)
+	if err != nil {
+		return "", err
+	}
+	pendingID := "pending-" + uuid.NewString()
+	if _, _, err := tracker.CreateSnapshot(m.ctx, pendingID, label, m.cfg.Git.Snapshots.MaxPerSession); err != nil {
+		return "", err
+	}
+	return pendingID, nil
+}
+
+// AdoptStartSnapshot files the snapshot taken by CaptureStartSnapshot under the
+// session that was launched, or discards it when sessionID is empty because
+// the launch failed.
+func (m *Manager) AdoptStartSnapshot(workspaceID, pendingID, sessionID string) error {
+	if pendingID == "" {
+		return nil
+	}
+	tracker, err := m.getGitTracker(workspaceID)
+	if err != nil {
+		return err
+	}
+	if sessionID == "" {
+		return tracker.DeleteSnapshots(m.ctx, pendingID)
+	}
+
+	sessionID = m.resolveSessionID(sessionID)
+	snapshots, err := tracker.MoveSnapshots(m.ctx, pendingID, sessionID)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		m.logger.Debug("Captured session snapshot",
+			"session_id", sessionID,
+			"workspace_id", workspaceID,
+			"snapshot_id", snapshot.ID,
+		)
+		m.PublishEvent(events.NewSessionSnapshotEvent(workspaceID, sessionID, events.SessionSnapshotPayload{
+			Action:     "created",
+			SnapshotID: snapshot.ID,
+			Label:      snapshot.Label,
+			Commit:     snapshot.Commit,
+		}))
+	}
+	return nil
+}
+
+// ListSnapshots returns all snapshots recorded for a session, newest first.
+// Snapshots captured under a temporary session ID are included once the real
+// ID has been resolved.
+func (m *Manager) ListSnapshots(workspaceID, sessionID string) ([]git.Snapshot, error) {
+	canonicalID := m.resolveSessionID(sessionID)
+	tracker, _, err := m.snapshotTracker(workspaceID, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []git.Snapshot
+	for _, id := range m.snapshotSessionIDs(canonicalID) {
+		snapshots, err := tracker.ListSnapshots(m.ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, snapshots...)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+
+	if result == nil {
+		result = []git.Snapshot{}
+	}
+	return result, nil
+}
+
+// DiffSnapshots compares two snapshots of a session, or a snapshot against the
+// current working tree when toID is empty.
+func (m *Manager) DiffSnapshots(workspaceID, sessionID, fromID, toID string) (*git.SnapshotDiffResult, error) {
+	canonicalID := m.resolveSessionID(sessionID)
+	tracker, _, err := m.snapshotTracker(workspaceID, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromSession, err := m.snapshotOwner(tracker, canonicalID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	toSession := fromSession
+	if toID != "" && toID != git.SnapshotWorkingTree {
+		if toSession, err = m.snapshotOwner(tracker, canonicalID, toID); err != nil {
+			return nil, err
+		}
+	}
+
+	return tracker.DiffSnapshots(m.ctx, fromSession, fromID, toSession, toID, m.maxSnapshotDiffKB())
+}
+
+// RestoreSnapshot rolls a session's working tree back to a snapshot without
+// touching the index, HEAD or branch history. The replaced state is kept as a
+// new snapshot so the restore itself can be undone.
+func (m *Manager) RestoreSnapshot(workspaceID, sessionID, snapshotID string) (*git.SnapshotRestoreResult, error) {
+	canonicalID := m.resolveSessionID(sessionID)
+	tracker, workspaceID, err := m.snapshotTracker(workspaceID, canonicalID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := m.snapshotOwner(tracker, canonicalID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSnapshots := 0
+	if m.cfg != nil {
+		maxSnapshots = m.cfg.Git.Snapshots.MaxPerSession
+	}
+	result, err := tracker.RestoreSnapshot(m.ctx, owner, snapshotID, maxSnapshots)
+	if err != nil {
+		return nil, err
+	}
+
+	m.logger.Info("Restored session snapshot",
+		"session_id", canonicalID,
+		"workspace_id", workspaceID,
+		"snapshot_id", snapshotID,
+		"restored", len(result.Restored),
+		"deleted", len(result.Deleted),
+	)
+
+	payload := events.SessionSnapshotPayload{
+		Action:     "restored",
+		SnapshotID: snapshotID,
+	}
+	if result.Backup != nil {
+		payload.BackupID = result.Backup.ID
+	}
+	m.PublishEvent(events.NewSessionSnapshotEvent(workspaceID, canonicalID, payload))
+
+	return result, nil
+}
+
+// snapshotTracker returns a git tracker rooted at the session's project path
+// (which may be a worktree) and the workspace the session belongs to.
+func (m *Manager) snapshotTracker(workspaceID, sessionID string) (*git.Tracker, string, error) {
+	if strings.TrimSpace(sessionID) == "" {
+		return nil, "", fmt.Errorf("session_id is required")
+	}
+
+	m.mu.RLock()
+	session := m.sessions[sessionID]
+	if workspaceID == "" {
+		workspaceID = m.activeSessionWorkspaces[sessionID]
+	}
+	m.mu.RUnlock()
+
+	if session != nil {
+		if workspaceID == "" {
+			workspaceID = session.WorkspaceID
+		}
+		if tracker := session.GitTracker(); tracker != nil && tracker.IsGitRepo() {
+			return tracker, workspaceID, nil
+		}
+	}
+
+	if workspaceID == "" {
+		workspaceID = m.findWorkspaceForSession(sessionID)
+	}
+
+	if projectPath := m.projectPathForSession(sessionID); projectPath != "" {
+		tracker := git.NewTracker(projectPath, m.cfg.Git.Command, nil)
+		if tracker.IsGitRepo() {
+			return tracker, workspaceID, nil
+		}
+	}
+
+	if workspaceID == "" {
+		return nil, "", fmt.Errorf("session not found: %s", sessionID)
+	}
+	tracker, err := m.getGitTracker(workspaceID)
+	if err != nil {
+		return nil, "", err
+	}
+	return tracker, workspaceID, nil
+}
+
+// snapshotSessionIDs returns the canonical session ID followed by every
+// temporary ID that was later resolved to it.
+func (m *Manager) snapshotSessionIDs(canonicalID string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := []string{canonicalID}
+	for aliasID := range m.sessionAliases {
+		if aliasID != canonicalID && m.resolveSessionIDLocked(aliasID) == canonicalID {
+			ids = append(ids, aliasID)
+		}
+	}
+	return ids
+}
+
+// snapshotOwner finds which session ID namespace (canonical or alias) holds a snapshot.
+func (m *Manager) snapshotOwner(tracker *git.Tracker, canonicalID, snapshotID string) (string, error) {
+	for _, id := range m.snapshotSessionIDs(canonicalID) {
+		snapshots, err := tracker.ListSnapshots(m.ctx, id)
+		if err != nil {
+			return "", err
+		}
+		for _, snapshot := range snapshots {
+			if snapshot.ID == snapshotID {
+				return id, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("snapshot not found: %s", snapshotID)
+}
+
+func (m *Manager) maxSnapshotDiffKB() int {
+	if m.cfg == nil || m.cfg.Limits.MaxDiffSizeKB <= 0 {
+		return 500
+	}
+	return m.cfg.Limits.MaxDiffSizeKB
+}
@@ -0,0 +1,164 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func initSnapshotWorkspace(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestSnapshots_CaptureListRestoreAcrossSessionAlias(t *testing.T) {
+	workspaceID := "workspace-snapshots"
+	workspacePath := initSnapshotWorkspace(t)
+	manager := newSessionTestManager(t, workspaceID, workspacePath)
+	manager.cfg.Git.Snapshots.Enabled = true
+	manager.cfg.Git.Snapshots.MaxPerSession = 10
+
+	sess, err := manager.StartNewSession(workspaceID)
+	if err != nil {
+		t.Fatalf("StartNewSession failed: %v", err)
+	}
+	temporaryID := sess.GetID()
+
+	file := filepath.Join(workspacePath, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := manager.CaptureTurnSnapshot(workspaceID, temporaryID, "before: first prompt")
+	if err != nil {
+		t.Fatalf("CaptureTurnSnapshot failed: %v", err)
+	}
+	if snapshot == nil {
+		t.Fatal("CaptureTurnSnapshot returned nil snapshot with snapshots enabled")
+	}
+
+	realID := "550e8400-e29b-41d4-a716-446655440010"
+	manager.updateSessionID(workspaceID, temporaryID, realID)
+
+	list, err := manager.ListSnapshots(workspaceID, realID)
+	if err != nil {
+		t.Fatalf("ListSnapshots failed: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != snapshot.ID {
+		t.Fatalf("ListSnapshots(realID) = %+v, want snapshot captured under temporary ID", list)
+	}
+
+	if err := os.WriteFile(file, []byte("package broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := manager.RestoreSnapshot(workspaceID, realID, snapshot.ID)
+	if err != nil {
+		t.Fatalf("RestoreSnapshot failed: %v", err)
+	}
+	if result.Backup == nil {
+		t.Fatal("RestoreSnapshot should capture a backup snapshot")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "package main\n" {
+		t.Fatalf("main.go = %q after restore, want original content", data)
+	}
+}
+
+func TestCaptureTurnSnapshot_DisabledIsNoop(t *testing.T) {
+	workspaceID := "workspace-snapshots-disabled"
+	manager := newSessionTestManager(t, workspaceID, t.TempDir())
+
+	snapshot, err := manager.CaptureTurnSnapshot(workspaceID, "any-session", "before: prompt")
+	if err != nil || snapshot != nil {
+		t.Fatalf("CaptureTurnSnapshot() = %v, %v; want nil, nil when disabled", snapshot, err)
+	}
+}
+
+func TestStartSnapshot_AdoptedBySession(t *testing.T) {
+	workspaceID := "workspace-start-snapshots"
+	workspacePath := initSnapshotWorkspace(t)
+	manager := newSessionTestManager(t, workspaceID, workspacePath)
+	manager.cfg.Git.Snapshots.Enabled = true
+
+	file := filepath.Join(workspacePath, "main.go")
+	if err := os.WriteFile(file, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pendingID, err := manager.CaptureStartSnapshot(workspaceID, "before: first prompt")
+	if err != nil || pendingID == "" {
+		t.Fatalf("CaptureStartSnapshot() = %q, %v", pendingID, err)
+	}
+
+	// The agent edits the tree before its session ID is known.
+	if err := os.WriteFile(file, []byte("package broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sessionID := "550e8400-e29b-41d4-a716-446655440011"
+	if err := manager.AdoptStartSnapshot(workspaceID, pendingID, sessionID); err != nil {
+		t.Fatalf("AdoptStartSnapshot failed: %v", err)
+	}
+
+	list, err := manager.ListSnapshots(workspaceID, sessionID)
+	if err != nil {
+		t.Fatalf("ListSnapshots failed: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("ListSnapshots() = %+v, want the start snapshot", list)
+	}
+	if _, err := manager.RestoreSnapshot(workspaceID, sessionID, list[0].ID); err != nil {
+		t.Fatalf("RestoreSnapshot failed: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "package main\n" {
+		t.Fatalf("main.go = %q after restore, want the pre-start content", data)
+	}
+}
+
+func TestStartSnapshot_DiscardedWithoutSession(t *testing.T) {
+	workspaceID := "workspace-start-snapshots-failed"
+	workspacePath := initSnapshotWorkspace(t)
+	manager := newSessionTestManager(t, workspaceID, workspacePath)
+	manager.cfg.Git.Snapshots.Enabled = true
+
+	pendingID, err := manager.CaptureStartSnapshot(workspaceID, "before: prompt")
+	if err != nil {
+		t.Fatalf("CaptureStartSnapshot failed: %v", err)
+	}
+	if err := manager.AdoptStartSnapshot(workspaceID, pendingID, ""); err != nil {
+		t.Fatalf("AdoptStartSnapshot failed: %v", err)
+	}
+
+	tracker, err := manager.getGitTracker(workspaceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := tracker.ListSnapshots(manager.ctx, pendingID); len(list) != 0 {
+		t.Fatalf("pending snapshot kept after a failed start: %+v", list)
+	}
+}
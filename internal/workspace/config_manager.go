@@ -323,23 +323,25 @@ func (m *ConfigManager) DiscoverRepositories(searchPaths []string) ([]Discovered
 }
 
 // DiscoverRepositoriesWithResult returns the full discovery result including cache metadata.
-func (m *ConfigManager) DiscoverRepositoriesWithResult(searchPaths []string) (*DiscoveryResult, error) {
+// A scan reports its progress to the ports.ProgressFunc of ctx.
+func (m *ConfigManager) DiscoverRepositoriesWithResult(ctx context.Context, searchPaths []string) (*DiscoveryResult, error) {
 	// Update configured paths before discovery
 	m.mu.RLock()
 	m.updateDiscoveryConfiguredPaths()
 	m.mu.RUnlock()
 
-	return m.repoDiscovery.Discover(context.Background(), searchPaths)
+	return m.repoDiscovery.Discover(ctx, searchPaths)
 }
 
 // DiscoverRepositoriesFresh forces a fresh scan, ignoring cache.
-func (m *ConfigManager) DiscoverRepositoriesFresh(searchPaths []string) (*DiscoveryResult, error) {
+// The scan reports its progress to the ports.ProgressFunc of ctx.
+func (m *ConfigManager) DiscoverRepositoriesFresh(ctx context.Context, searchPaths []string) (*DiscoveryResult, error) {
 	// Update configured paths before discovery
 	m.mu.RLock()
 	m.updateDiscoveryConfiguredPaths()
 	m.mu.RUnlock()
 
-	return m.repoDiscovery.DiscoverFresh(context.Background(), searchPaths)
+	return m.repoDiscovery.DiscoverFresh(ctx, searchPaths)
 }
 
 // InvalidateDiscoveryCache clears the discovery cache.
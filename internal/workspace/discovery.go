@@ -4,6 +4,7 @@ package workspace
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -13,6 +14,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/brianly1003/cdev/internal/domain/ports"
 	"github.com/rs/zerolog/log"
 )
 
@@ -272,6 +274,14 @@ func (d *RepoDiscovery) parallelScan(ctx context.Context, searchPaths []string)
 				close(done)
 				return
 			default:
+				countMu.Lock()
+				scanned := scannedCount
+				countMu.Unlock()
+				resultsMu.Lock()
+				found := len(results)
+				resultsMu.Unlock()
+				ports.ReportProgress(ctx, fmt.Sprintf("Scanned %d directories, found %d repositories", scanned, found), -1)
+
 				// Check if queue is empty AND no workers are processing
 				queueLen := len(dirQueue)
 				processing := atomic.LoadInt64(&inFlight)